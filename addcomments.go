@@ -0,0 +1,155 @@
+package yamlc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AddComments 解析任意YAML文档，在指定路径上添加注释，文档原有的内容和注释保持不变
+// 适用于不是由Go结构体生成的YAML。路径写法与 WithComment 相同：以 "." 分隔，
+// 列表元素使用下标（如 "servers.0.host"），支持通配符（如 "servers.*.host"）和去掉下标的通用路径
+// style 决定注释位置：StyleInline、StyleCompact 写在行尾，StyleSmart 对标量写在行尾、其余写在上方，
+// StyleHelmDocs 使用 "# -- @param 路径 " 前缀，StyleMinimal 不添加注释，其余风格写在字段上方
+func AddComments(data []byte, comments map[string]string, style CommentStyle) ([]byte, error) {
+	if err := validateCommentMap(comments, "comments"); err != nil {
+		return nil, err
+	}
+	if style == StyleMinimal || len(comments) == 0 {
+		return data, nil
+	}
+
+	docs, err := decodeDocumentNodes(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return data, nil
+	}
+
+	options := &Options{Comments: []map[string]string{comments}}
+	for _, doc := range docs {
+		for _, node := range doc.Content {
+			addNodeComments(node, "", style, options)
+		}
+	}
+	return encodeDocumentNodes(docs, detectIndent(data))
+}
+
+// decodeDocumentNodes 将YAML流解析为各个文档的节点树
+func decodeDocumentNodes(data []byte) ([]*yaml.Node, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	var docs []*yaml.Node
+	for {
+		var doc yaml.Node
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				return docs, nil
+			}
+			return nil, fmt.Errorf("YAML parsing error: %w", err)
+		}
+		docs = append(docs, &doc)
+	}
+}
+
+// encodeDocumentNodes 将节点树重新编码为YAML流，节点上的注释随之输出
+func encodeDocumentNodes(docs []*yaml.Node, indent int) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(indent)
+	for _, doc := range docs {
+		if err := encoder.Encode(doc); err != nil {
+			return nil, fmt.Errorf("failed to encode YAML: %w", err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// addNodeComments 递归遍历节点，为命中注释映射的键和列表元素添加注释
+func addNodeComments(node *yaml.Node, fieldPath string, style CommentStyle, options *Options) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			keyPath := buildFieldPath(fieldPath, key.Value)
+			if comment, ok := lookupComment(keyPath, options); ok {
+				attachComment(key, value, comment, keyPath, style)
+			}
+			addNodeComments(value, keyPath, style, options)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			itemPath := buildFieldPath(fieldPath, strconv.Itoa(i))
+			if comment, ok := lookupComment(itemPath, options); ok {
+				attachComment(item, item, comment, itemPath, style)
+			}
+			addNodeComments(item, itemPath, style, options)
+		}
+	}
+}
+
+// attachComment 按风格把注释挂到节点上；已有的行尾注释不会被覆盖，此时改为写在上方
+func attachComment(key, value *yaml.Node, comment string, fieldPath string, style CommentStyle) {
+	comment = strings.TrimSpace(comment)
+	if comment == "" {
+		return
+	}
+
+	scalar := value.Kind == yaml.ScalarNode || value.Kind == yaml.AliasNode
+	inline := style == StyleInline || style == StyleCompact || (style == StyleSmart && scalar)
+	if inline && !strings.Contains(comment, "\n") && key.LineComment == "" && value.LineComment == "" {
+		if scalar {
+			value.LineComment = "# " + comment
+		} else {
+			key.LineComment = "# " + comment
+		}
+		return
+	}
+
+	lines := strings.Split(comment, "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if style == StyleHelmDocs && i == 0 {
+			line = helmDocsComment(fieldPath, line)
+		}
+		lines[i] = strings.TrimRight("# "+line, " ")
+	}
+	head := strings.Join(lines, "\n")
+	if key.HeadComment != "" {
+		head = key.HeadComment + "\n" + head
+	}
+	key.HeadComment = head
+}
+
+// detectIndent 从顶层键下第一处缩进的内容推断缩进宽度，无法推断时使用2
+func detectIndent(data []byte) int {
+	afterTopKey := false
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		width := len(line) - len(trimmed)
+		if width > 0 && afterTopKey {
+			return width
+		}
+		afterTopKey = width == 0 && strings.HasSuffix(strings.TrimSpace(stripLineComment(trimmed)), ":")
+	}
+	return 2
+}
+
+// stripLineComment 去掉行尾的 " #" 注释（不处理引号内的 "#"，仅用于推断缩进）
+func stripLineComment(line string) string {
+	if i := strings.Index(line, " #"); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
@@ -0,0 +1,82 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+)
+
+const addCommentsInput = `# 原有的文档注释
+
+name: demo # 原有注释
+server:
+    host: localhost
+    port: 8080
+servers:
+    - host: a
+    - host: b
+`
+
+func TestAddComments(t *testing.T) {
+	comments := map[string]string{
+		"name":           "名称",
+		"server":         "服务配置",
+		"server.port":    "端口",
+		"servers.*.host": "主机",
+		"servers.1.host": "备用主机",
+	}
+
+	data, err := AddComments([]byte(addCommentsInput), comments, StyleTop)
+	if err != nil {
+		t.Fatalf("AddComments failed: %v", err)
+	}
+	yamlStr := string(data)
+	for _, expected := range []string{
+		"# 原有的文档注释\n",
+		"# 名称\nname: demo # 原有注释\n",
+		"# 服务配置\nserver:\n",
+		"    # 端口\n    port: 8080\n",
+		"    - # 主机\n      host: a\n",
+		"    - # 备用主机\n      host: b\n",
+	} {
+		if !strings.Contains(yamlStr, expected) {
+			t.Errorf("expected %q in:\n%s", expected, yamlStr)
+		}
+	}
+	if err := ValidateYAML(data); err != nil {
+		t.Errorf("invalid YAML: %v", err)
+	}
+
+	// 行尾风格：已有行尾注释的字段改为写在上方
+	data, err = AddComments([]byte(addCommentsInput), comments, StyleInline)
+	if err != nil {
+		t.Fatalf("AddComments failed: %v", err)
+	}
+	yamlStr = string(data)
+	for _, expected := range []string{
+		"# 名称\nname: demo # 原有注释\n",
+		"server: # 服务配置\n",
+		"    port: 8080 # 端口\n",
+	} {
+		if !strings.Contains(yamlStr, expected) {
+			t.Errorf("expected %q in:\n%s", expected, yamlStr)
+		}
+	}
+
+	data, err = AddComments([]byte("a:\n  b: 1\n"), map[string]string{"a": "说明\n第二行", "a.b": "值"}, StyleHelmDocs)
+	if err != nil {
+		t.Fatalf("AddComments failed: %v", err)
+	}
+	if expected := "# -- @param a 说明\n# 第二行\na:\n  # -- @param a.b 值\n  b: 1\n"; string(data) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, data)
+	}
+
+	if data, err := AddComments([]byte(addCommentsInput), comments, StyleMinimal); err != nil || string(data) != addCommentsInput {
+		t.Errorf("minimal style should leave the document unchanged: %v", err)
+	}
+	if _, err := AddComments([]byte("a: [1\n"), comments, StyleTop); err == nil {
+		t.Error("expected error for invalid YAML")
+	}
+	if _, err := AddComments([]byte("a: 1\n"), map[string]string{"": "x"}, StyleTop); err == nil {
+		t.Error("expected error for empty path")
+	}
+}
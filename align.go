@@ -0,0 +1,93 @@
+package yamlc
+
+import (
+	"strings"
+)
+
+// WithCommentColumn 将所有行尾注释的 "#" 对齐到固定的显示列（从0开始计数），包括嵌套层级
+// 内容超过该列的行，注释与内容之间保留一个空格
+func WithCommentColumn(col int) Option {
+	return func(o *Options) {
+		o.CommentColumn = col
+	}
+}
+
+// commentedLine 拆分后的带行尾注释的行
+type commentedLine struct {
+	content string
+	comment string
+}
+
+// splitTrailingComment 拆分行尾注释，返回去掉尾部空白的内容和以 "#" 开头的注释
+// 引号内的 "#" 以及整行注释不算行尾注释
+func splitTrailingComment(line string) (commentedLine, bool) {
+	inSingle, inDouble := false, false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case inDouble:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inDouble = false
+			}
+		case inSingle:
+			if c == '\'' {
+				// '' 是单引号字符串中的转义
+				if i+1 < len(line) && line[i+1] == '\'' {
+					i++
+				} else {
+					inSingle = false
+				}
+			}
+		case c == '"':
+			inDouble = true
+		case c == '\'':
+			inSingle = true
+		case c == '#' && i > 0 && (line[i-1] == ' ' || line[i-1] == '\t'):
+			content := strings.TrimRight(line[:i], " \t")
+			if strings.TrimSpace(content) == "" {
+				return commentedLine{}, false
+			}
+			return commentedLine{content: content, comment: line[i:]}, true
+		}
+	}
+	return commentedLine{}, false
+}
+
+// forEachCommentedLine 遍历文档中带行尾注释的行，跳过块标量的内容行
+func forEachCommentedLine(lines []string, fn func(i int, line commentedLine)) {
+	inBlock := blockContentLines(lines)
+	for i, line := range lines {
+		if inBlock[i] {
+			continue
+		}
+		if parsed, ok := splitTrailingComment(line); ok {
+			fn(i, parsed)
+		}
+	}
+}
+
+// alignTrailingComments 将所有行尾注释对齐到指定显示列
+func alignTrailingComments(content string, column int) string {
+	lines := strings.Split(content, "\n")
+	forEachCommentedLine(lines, func(i int, line commentedLine) {
+		padding := column - getDisplayWidth(line.content)
+		if padding < 1 {
+			padding = 1
+		}
+		lines[i] = line.content + strings.Repeat(" ", padding) + line.comment
+	})
+	return strings.Join(lines, "\n")
+}
+
+// documentCommentColumn 计算整个文档统一的注释列：最宽的带注释行内容之后空两格
+func documentCommentColumn(content string) int {
+	column := 0
+	forEachCommentedLine(strings.Split(content, "\n"), func(i int, line commentedLine) {
+		if width := getDisplayWidth(line.content) + 2; width > column {
+			column = width
+		}
+	})
+	return column
+}
@@ -0,0 +1,79 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitTrailingComment(t *testing.T) {
+	testCases := []struct {
+		line    string
+		content string
+		comment string
+		ok      bool
+	}{
+		{"name: 张三   # 用户姓名", "name: 张三", "# 用户姓名", true},
+		{"  # 整行注释", "", "", false},
+		{`url: "http://a/#frag" # 地址`, `url: "http://a/#frag"`, "# 地址", true},
+		{`note: 'it''s # not' # 备注`, `note: 'it''s # not'`, "# 备注", true},
+		{"color: red#blue", "", "", false},
+	}
+
+	for _, tc := range testCases {
+		got, ok := splitTrailingComment(tc.line)
+		if ok != tc.ok || got.content != tc.content || got.comment != tc.comment {
+			t.Errorf("splitTrailingComment(%q) = (%q, %q, %v), expected (%q, %q, %v)",
+				tc.line, got.content, got.comment, ok, tc.content, tc.comment, tc.ok)
+		}
+	}
+}
+
+func TestWithCommentColumn(t *testing.T) {
+	user := createTestUser()
+
+	data, err := Gen(user, WithStyle(StyleInline), WithCommentColumn(40))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+
+	count := 0
+	for _, line := range strings.Split(string(data), "\n") {
+		parsed, ok := splitTrailingComment(line)
+		if !ok {
+			continue
+		}
+		count++
+		if width := getDisplayWidth(line) - getDisplayWidth(parsed.comment); width != 40 {
+			t.Errorf("comment should start at column 40, got %d in line %q", width, line)
+		}
+	}
+	if count == 0 {
+		t.Fatal("no inline comments found")
+	}
+}
+
+func TestInlineGlobalAlignment(t *testing.T) {
+	user := createTestUser()
+
+	for _, style := range []CommentStyle{StyleInline, StyleSmart} {
+		data, err := Gen(user, WithStyle(style))
+		if err != nil {
+			t.Fatalf("Gen failed: %v", err)
+		}
+
+		column := -1
+		for _, line := range strings.Split(string(data), "\n") {
+			parsed, ok := splitTrailingComment(line)
+			if !ok {
+				continue
+			}
+			width := getDisplayWidth(line) - getDisplayWidth(parsed.comment)
+			if column == -1 {
+				column = width
+			} else if width != column {
+				t.Errorf("style %s: comment column %d differs from %d in line %q",
+					GetStyleString(int(style)), width, column, line)
+			}
+		}
+	}
+}
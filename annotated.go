@@ -0,0 +1,91 @@
+package yamlc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// getDefaultValue 获取字段的默认值说明（yamlc标签中的default=，或独立的default标签）
+func getDefaultValue(field FieldInfo) (string, bool) {
+	if value, ok := getTagValue(field.FieldType, "default"); ok {
+		return sanitizeComment(value), true
+	}
+	if value, ok := field.FieldType.Tag.Lookup("default"); ok {
+		return sanitizeComment(value), true
+	}
+	return "", false
+}
+
+// getEnvName 获取字段对应的环境变量名（yamlc标签中的env=，或独立的env标签）
+func getEnvName(field FieldInfo) string {
+	if env, ok := getTagValue(field.FieldType, "env"); ok {
+		return sanitizeComment(env)
+	}
+	return sanitizeComment(field.FieldType.Tag.Get("env"))
+}
+
+// getAllowedValues 获取字段的可选值（yamlc标签中的enum=，多个值以 | 分隔）
+func getAllowedValues(field FieldInfo) []string {
+	enum, ok := getTagValue(field.FieldType, "enum")
+	if !ok {
+		return nil
+	}
+	var values []string
+	for _, value := range strings.Split(enum, "|") {
+		if value = sanitizeComment(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// setFieldTags 按字段标签填充必填、默认值、可选值和环境变量信息
+func setFieldTags(field *FieldInfo) {
+	field.IsRequired = isRequiredField(field.FieldType)
+	field.Default, _ = getDefaultValue(*field)
+	field.Enum = getAllowedValues(*field)
+	field.EnvVar = getEnvName(*field)
+}
+
+// generateAnnotatedStyleField 生成标注风格字段：注释下方逐行列出类型、默认值、可选值、环境变量、是否必填和版本信息
+// 非首个列表元素内不再重复元数据，按头顶风格输出；映射的条目只输出注释
+func generateAnnotatedStyleField(result *strings.Builder, field FieldInfo, indentStr string, options *Options) error {
+	if options.indexedOnly {
+		return generateTopStyleField(result, field, indentStr, options)
+	}
+
+	if field.Comment != "" {
+		result.WriteString(fmt.Sprintf("%s# %s\n", indentStr, field.Comment))
+	}
+	if len(field.FieldType.Index) == 0 {
+		// 映射的条目不是结构体字段，没有可标注的元数据，只输出注释和键
+		result.WriteString(fmt.Sprintf("%s%s:", indentStr, field.Name))
+		return generateFieldValue(result, field, indentStr, options)
+	}
+	annotation := func(key, value string) {
+		result.WriteString(fmt.Sprintf("%s#   %s: %s\n", indentStr, key, value))
+	}
+	annotation("type", fieldTypeString(field, options))
+	if value, ok := getDefaultValue(field); ok {
+		annotation("default", value)
+	}
+	if values := getAllowedValues(field); len(values) > 0 {
+		annotation("allowed", strings.Join(values, ", "))
+	}
+	if env := getEnvName(field); env != "" {
+		annotation("env", env)
+	}
+	annotation("required", fmt.Sprintf("%t", isRequiredField(field.FieldType)))
+	if since := getSince(field.FieldType); since != "" {
+		annotation("since", since)
+	}
+	if version, replacement, ok := getRemoved(field.FieldType); ok {
+		annotation("removed", removedNote(version, replacement))
+	}
+	if field.Example != "" {
+		annotation("example", field.Example)
+	}
+
+	result.WriteString(fmt.Sprintf("%s%s:", indentStr, field.Name))
+	return generateFieldValue(result, field, indentStr, options)
+}
@@ -0,0 +1,86 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+type annotatedServer struct {
+	Port  int    `yaml:"port" comment:"监听端口" yamlc:"default=8080,env=APP_PORT,required"`
+	Level string `yaml:"level" comment:"日志级别" default:"info" yamlc:"enum=debug|info|warn"`
+	Name  string `yaml:"name" yamlc:"example=api"`
+}
+
+type annotatedConfig struct {
+	Server  annotatedServer   `yaml:"server" comment:"服务"`
+	Workers []annotatedServer `yaml:"workers" comment:"工作进程"`
+}
+
+func TestAnnotatedStyle(t *testing.T) {
+	cfg := annotatedConfig{
+		Server:  annotatedServer{Port: 80, Level: "warn", Name: "web"},
+		Workers: []annotatedServer{{Port: 1}, {Port: 2}},
+	}
+
+	data, err := Gen(cfg, WithStyle(StyleAnnotated))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	yamlStr := string(data)
+	for _, expected := range []string{
+		"  # 监听端口\n  #   type: int\n  #   default: 8080\n  #   env: APP_PORT\n  #   required: true\n  port: 80\n",
+		"  # 日志级别\n  #   type: string\n  #   default: info\n  #   allowed: debug, info, warn\n  #   required: false\n  level: warn\n",
+		"  #   type: string\n  #   required: false\n  #   example: api\n  name: web\n",
+		"# 服务\n#   type: yamlc.annotatedServer\n#   required: true\nserver:\n",
+	} {
+		if !strings.Contains(yamlStr, expected) {
+			t.Errorf("expected %q in:\n%s", expected, yamlStr)
+		}
+	}
+	// 非首个列表元素不重复元数据
+	if strings.Count(yamlStr, "#   default: 8080") != 2 {
+		t.Errorf("metadata should appear once for server and once for the first worker:\n%s", yamlStr)
+	}
+
+	var decoded annotatedConfig
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded.Server != cfg.Server || len(decoded.Workers) != 2 || decoded.Workers[1].Port != 2 {
+		t.Errorf("annotated style did not round-trip: %+v", decoded)
+	}
+
+	if style, ok := parseStyle("annotated"); !ok || style != StyleAnnotated {
+		t.Errorf("parseStyle(annotated) = %v, %v", style, ok)
+	}
+}
+
+func TestAnnotatedStyleMapEntries(t *testing.T) {
+	type Config struct {
+		Labels  map[string]string          `yaml:"labels" comment:"标签"`
+		Servers map[string]annotatedServer `yaml:"servers"`
+	}
+	cfg := Config{
+		Labels:  map[string]string{"app": "web"},
+		Servers: map[string]annotatedServer{"api": {Port: 80}},
+	}
+
+	data, err := Gen(cfg, WithStyle(StyleAnnotated), WithComment(map[string]string{"labels.app": "应用名"}))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	yamlStr := string(data)
+	for _, expected := range []string{
+		"labels:\n  # 应用名\n  app: web\n",
+		"servers:\n  api:\n    # 监听端口\n    #   type: int\n",
+	} {
+		if !strings.Contains(yamlStr, expected) {
+			t.Errorf("expected %q in:\n%s", expected, yamlStr)
+		}
+	}
+	if strings.Count(yamlStr, "#   type:") != 5 {
+		t.Errorf("map entries should not have metadata blocks:\n%s", yamlStr)
+	}
+}
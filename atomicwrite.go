@@ -0,0 +1,111 @@
+package yamlc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WithBackup WriteFileAtomic 覆盖已有文件前，将原文件保存为 文件名+suffix（如 ".bak"）
+func WithBackup(suffix string) Option {
+	return func(o *Options) {
+		o.Backup = suffix
+	}
+}
+
+// WriteFileAtomic 使用默认配置原子地写入文件
+func WriteFileAtomic(filename string, v interface{}, perm os.FileMode, opts ...Option) error {
+	return Default().WriteFileAtomic(filename, v, perm, opts...)
+}
+
+// WriteFileAtomic 按该配置生成YAML，先写入同目录下的临时文件并落盘，再重命名覆盖目标文件，
+// 写入过程中崩溃不会留下内容不完整的配置。perm 为新文件的权限；设置 WithBackup 时保留原文件的副本，
+// 文件已存在时按 WithOverwritePolicy 的策略处理
+func (c *Config) WriteFileAtomic(filename string, v interface{}, perm os.FileMode, opts ...Option) error {
+	if filename == "" {
+		return fmt.Errorf("filename cannot be empty")
+	}
+	options := c.newOptions(opts...)
+	if write, err := shouldWrite(filename, os.ReadFile, options); !write || err != nil {
+		return err
+	}
+	return writeFileAtomic(filename, v, perm, options)
+}
+
+// writeFileAtomic 生成内容并原子地写入文件
+func writeFileAtomic(filename string, v interface{}, perm os.FileMode, options *Options) error {
+	data, err := generate(v, options)
+	if err != nil {
+		return err
+	}
+	return writeDataAtomic(filename, data, perm, options)
+}
+
+// writeDataAtomic 将已生成的内容原子地写入文件，按选项追加校验和注释并备份原文件
+func writeDataAtomic(filename string, data []byte, perm os.FileMode, options *Options) error {
+	dir, base := filepath.Split(filename)
+	if dir == "" {
+		dir = "."
+	}
+	tmp, err := os.CreateTemp(dir, "."+base+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file for %q: %w", filename, err)
+	}
+	tmpName := tmp.Name()
+	committed := false
+	defer func() {
+		if !committed {
+			tmp.Close()
+			os.Remove(tmpName)
+		}
+	}()
+
+	if err := writeData(tmp, fileContent(data, options)); err != nil {
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		return fmt.Errorf("failed to set permissions on %q: %w", tmpName, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("failed to sync %q: %w", tmpName, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close %q: %w", tmpName, err)
+	}
+
+	if options.Backup != "" {
+		if err := backupFile(filename, filename+options.Backup); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(tmpName, filename); err != nil {
+		return fmt.Errorf("failed to rename %q to %q: %w", tmpName, filename, err)
+	}
+	committed = true
+
+	// 目录落盘使重命名持久化，部分平台不支持，忽略错误
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
+
+// backupFile 将已有文件复制为备份，保留原权限；文件不存在时不做处理
+func backupFile(filename, backup string) error {
+	info, err := os.Stat(filename)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stat %q: %w", filename, err)
+	}
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read %q for backup: %w", filename, err)
+	}
+	if err := os.WriteFile(backup, content, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write backup %q: %w", backup, err)
+	}
+	return nil
+}
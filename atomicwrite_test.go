@@ -0,0 +1,52 @@
+package yamlc
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "server.yaml")
+
+	first := configServer{Host: "localhost", Port: 8080}
+	if err := WriteFileAtomic(filename, first, 0600); err != nil {
+		t.Fatalf("WriteFileAtomic failed: %v", err)
+	}
+	info, err := os.Stat(filename)
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected mode 0600, got %v", info.Mode().Perm())
+	}
+	firstData, _ := os.ReadFile(filename)
+	if expected, _ := Gen(first); !bytes.Equal(firstData, expected) {
+		t.Errorf("unexpected content:\n%s", firstData)
+	}
+
+	second := configServer{Host: "example.com", Port: 9090}
+	if err := WriteFileAtomic(filename, second, 0644, WithBackup(".bak")); err != nil {
+		t.Fatalf("WriteFileAtomic failed: %v", err)
+	}
+	if backup, err := os.ReadFile(filename + ".bak"); err != nil || !bytes.Equal(backup, firstData) {
+		t.Errorf("expected backup of previous version: %v\n%s", err, backup)
+	}
+	if data, _ := os.ReadFile(filename); !bytes.Contains(data, []byte("example.com")) {
+		t.Errorf("expected new content:\n%s", data)
+	}
+
+	// 生成失败时原文件不变，也不留下临时文件
+	if err := WriteFileAtomic(filename, nil, 0644); err == nil {
+		t.Error("expected error for nil value")
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("expected only the file and its backup, got %d entries", len(entries))
+	}
+	if err := WriteFileAtomic(filepath.Join(dir, "missing", "a.yaml"), first, 0644); err == nil {
+		t.Error("expected error for missing directory")
+	}
+}
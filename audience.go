@@ -0,0 +1,112 @@
+package yamlc
+
+import (
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 内置受众，按可见范围从小到大排列：internal 可以看到 public 字段，debug 可以看到全部字段
+const (
+	AudiencePublic   = "public"
+	AudienceInternal = "internal"
+	AudienceDebug    = "debug"
+)
+
+// audienceRanks 内置受众的可见级别
+var audienceRanks = map[string]int{
+	AudiencePublic:   0,
+	AudienceInternal: 1,
+	AudienceDebug:    2,
+}
+
+// WithAudience 按受众过滤字段：字段通过 expose= 标签声明可见范围，未声明的字段对所有受众可见
+// 字段可通过 comment_<受众> 标签（如 comment_internal）为该受众提供单独的注释。未设置受众时输出全部字段
+func WithAudience(audience string) Option {
+	return func(o *Options) {
+		o.Audience = audience
+	}
+}
+
+// isHiddenField 判断字段是否对当前受众隐藏，或在只输出必填字段时被省略
+func isHiddenField(field reflect.StructField, options *Options) bool {
+	if isOptionalField(field, options) {
+		return true
+	}
+	if options.Audience == "" {
+		return false
+	}
+	expose, ok := getTagValue(field, "expose")
+	if !ok {
+		return false
+	}
+	return !audienceSees(options.Audience, expose)
+}
+
+// audienceSees 判断受众能否看到指定可见范围的字段；自定义的受众名只能看到同名的字段
+func audienceSees(audience, expose string) bool {
+	audienceRank, audienceKnown := audienceRanks[audience]
+	exposeRank, exposeKnown := audienceRanks[expose]
+	if audienceKnown && exposeKnown {
+		return exposeRank <= audienceRank
+	}
+	return audience == expose
+}
+
+// getAudienceComment 获取字段为当前受众单独提供的注释（comment_<受众> 标签）
+func getAudienceComment(field reflect.StructField, options *Options) (string, bool) {
+	if options.Audience == "" {
+		return "", false
+	}
+	comment, ok := field.Tag.Lookup("comment_" + options.Audience)
+	return comment, ok && comment != ""
+}
+
+// audienceNode 在yaml节点树上去掉对当前受众隐藏或被省略的字段，用于不经过字段渲染的最小风格
+func audienceNode(node *yaml.Node, val reflect.Value, options *Options) {
+	if options.Audience == "" && !options.RequiredOnly {
+		return
+	}
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return
+		}
+		val = val.Elem()
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) > 0 {
+			audienceNode(node.Content[0], val, options)
+		}
+	case yaml.MappingNode:
+		content := node.Content[:0]
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			switch val.Kind() {
+			case reflect.Struct:
+				if fieldType, field, ok := findYAMLField(val, key.Value); ok {
+					if isHiddenField(fieldType, options) {
+						continue
+					}
+					audienceNode(value, field, options)
+				}
+			case reflect.Map:
+				if val.Type().Key().Kind() == reflect.String {
+					audienceNode(value, val.MapIndex(reflect.ValueOf(key.Value).Convert(val.Type().Key())), options)
+				}
+			}
+			content = append(content, key, value)
+		}
+		node.Content = content
+	case yaml.SequenceNode:
+		if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+			return
+		}
+		for i, item := range node.Content {
+			if i < val.Len() {
+				audienceNode(item, val.Index(i), options)
+			}
+		}
+	}
+}
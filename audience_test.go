@@ -0,0 +1,51 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithAudience(t *testing.T) {
+	type Service struct {
+		Port    int    `yaml:"port" comment:"服务端口" comment_internal:"服务端口，修改后需要同步负载均衡配置"`
+		Workers int    `yaml:"workers" yamlc:"expose=internal" comment:"工作线程数"`
+		Pprof   bool   `yaml:"pprof" yamlc:"expose=debug" comment:"性能分析"`
+		Tenant  string `yaml:"tenant" yamlc:"expose=saas" comment:"租户"`
+	}
+	svc := Service{Port: 8080, Workers: 4, Pprof: true, Tenant: "acme"}
+
+	testCases := []struct {
+		audience string
+		present  []string
+		absent   []string
+	}{
+		{"", []string{"# 服务端口\nport: 8080", "workers: 4", "pprof: true", "tenant: acme"}, nil},
+		{AudiencePublic, []string{"# 服务端口\nport: 8080"}, []string{"workers", "pprof", "tenant"}},
+		{AudienceInternal, []string{"# 服务端口，修改后需要同步负载均衡配置\n", "workers: 4"}, []string{"pprof", "tenant"}},
+		{AudienceDebug, []string{"workers: 4", "pprof: true"}, []string{"tenant"}},
+		{"saas", []string{"port: 8080", "tenant: acme"}, []string{"workers", "pprof"}},
+	}
+
+	for _, tc := range testCases {
+		for _, style := range []CommentStyle{StyleTop, StyleMinimal} {
+			data, err := Gen(svc, WithStyle(style), WithAudience(tc.audience))
+			if err != nil {
+				t.Fatalf("Gen failed: %v", err)
+			}
+			yamlStr := string(data)
+			for _, expected := range tc.present {
+				if style == StyleMinimal {
+					expected = expected[strings.LastIndex(expected, "\n")+1:]
+				}
+				if !strings.Contains(yamlStr, expected) {
+					t.Errorf("audience %q: expected %q in:\n%s", tc.audience, expected, yamlStr)
+				}
+			}
+			for _, unexpected := range tc.absent {
+				if strings.Contains(yamlStr, unexpected) {
+					t.Errorf("audience %q: unexpected %q in:\n%s", tc.audience, unexpected, yamlStr)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package yamlc
+
+import "strings"
+
+// BlankLinePolicy 字段之间空行的策略，与注释风格相互独立
+type BlankLinePolicy int
+
+const (
+	// BlankLinesAuto 由注释风格决定（默认）
+	BlankLinesAuto BlankLinePolicy = iota
+	// BlankLinesNone 不输出空行
+	BlankLinesNone
+	// BlankLinesBetweenTopLevel 只在顶层字段之间添加空行
+	BlankLinesBetweenTopLevel
+	// BlankLinesBetweenSections 在分节横幅之前以及含子节点的字段前后添加空行
+	BlankLinesBetweenSections
+	// BlankLinesBetweenAllFields 在同一层级的所有字段之间添加空行（列表元素之间除外）
+	BlankLinesBetweenAllFields
+)
+
+// WithBlankLines 设置字段之间空行的策略，覆盖注释风格自带的间距
+func WithBlankLines(policy BlankLinePolicy) Option {
+	return func(o *Options) {
+		o.BlankLines = policy
+	}
+}
+
+// applyBlankLines 去掉块标量之外的空行，再按策略在字段（连同其上方的注释）之前插入空行
+func applyBlankLines(content string, policy BlankLinePolicy) string {
+	if policy == BlankLinesAuto {
+		return content
+	}
+
+	all := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	allInBlock := blockContentLines(all)
+	var lines []string
+	var inBlock []bool
+	for i, line := range all {
+		if allInBlock[i] || strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+			inBlock = append(inBlock, allInBlock[i])
+		}
+	}
+
+	isComment := func(i int) bool {
+		return !inBlock[i] && strings.HasPrefix(strings.TrimSpace(lines[i]), "#")
+	}
+	// nextContent 返回第 i 行之后第一个非注释、非块内容的行
+	nextContent := func(i int) int {
+		for j := i + 1; j < len(lines); j++ {
+			if !inBlock[j] && !isComment(j) {
+				return j
+			}
+		}
+		return -1
+	}
+
+	var out []string
+	commentStart := -1
+	prevColumn := -1
+	for i, line := range lines {
+		if inBlock[i] {
+			out = append(out, line)
+			continue
+		}
+		if isComment(i) {
+			if commentStart < 0 {
+				commentStart = len(out)
+			}
+			out = append(out, line)
+			continue
+		}
+
+		column, dash := keyColumn(line)
+		if !dash && prevColumn >= column {
+			blank := false
+			switch policy {
+			case BlankLinesBetweenTopLevel:
+				blank = column == 0
+			case BlankLinesBetweenAllFields:
+				blank = true
+			case BlankLinesBetweenSections:
+				next := nextContent(i)
+				hasChildren := next >= 0 && keyColumnOf(lines[next]) > column
+				blank = hasChildren || prevColumn > column || hasSectionBanner(out, commentStart)
+			}
+			if blank {
+				at := len(out)
+				if commentStart >= 0 {
+					at = commentStart
+				}
+				out = append(out[:at], append([]string{""}, out[at:]...)...)
+			}
+		}
+		commentStart = -1
+		prevColumn = column
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n") + "\n"
+}
+
+// keyColumn 获取行中键所在的列，列表项（"- key"）以 "-" 之后的内容为准，并返回是否为列表项
+func keyColumn(line string) (int, bool) {
+	column := len(line) - len(strings.TrimLeft(line, " "))
+	rest := line[column:]
+	dash := false
+	for strings.HasPrefix(rest, "- ") || rest == "-" {
+		dash = true
+		trimmed := strings.TrimLeft(strings.TrimPrefix(rest, "-"), " ")
+		column += len(rest) - len(trimmed)
+		rest = trimmed
+	}
+	return column, dash
+}
+
+// keyColumnOf 获取行中键所在的列
+func keyColumnOf(line string) int {
+	column, _ := keyColumn(line)
+	return column
+}
+
+// hasSectionBanner 判断字段上方的注释中是否包含分节横幅
+func hasSectionBanner(out []string, commentStart int) bool {
+	if commentStart < 0 {
+		return false
+	}
+	for _, line := range out[commentStart:] {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "# ---- ") && strings.HasSuffix(trimmed, " ----") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package yamlc
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+type blankServer struct {
+	Host string `yaml:"host" comment:"主机"`
+	Port int    `yaml:"port" comment:"端口"`
+}
+
+type blankConfig struct {
+	Name    string        `yaml:"name" comment:"名称"`
+	Version int           `yaml:"version"`
+	Server  blankServer   `yaml:"server" comment:"服务"`
+	Backups []blankServer `yaml:"backups"`
+	Script  string        `yaml:"script" yamlc:"literal"`
+	Debug   bool          `yaml:"debug" yamlc:"section=Misc"`
+}
+
+func TestWithBlankLines(t *testing.T) {
+	cfg := blankConfig{
+		Name:    "demo",
+		Version: 1,
+		Server:  blankServer{Host: "a", Port: 1},
+		Backups: []blankServer{{Host: "b", Port: 2}, {Host: "c", Port: 3}},
+		Script:  "echo 1\n\necho 2\n",
+	}
+
+	testCases := []struct {
+		policy   BlankLinePolicy
+		expected string
+	}{
+		{BlankLinesNone, "# 名称\nname: demo\nversion: 1\n# 服务\nserver:\n  # 主机\n  host: a\n  # 端口\n  port: 1\nbackups:\n    # 主机\n    # 端口\n  - host: b\n    port: 2\n  - host: c\n    port: 3\nscript: |\n  echo 1\n\n  echo 2\n# ---- Misc ----\ndebug: false\n"},
+		{BlankLinesBetweenTopLevel, "# 名称\nname: demo\n\nversion: 1\n\n# 服务\nserver:\n  # 主机\n  host: a\n  # 端口\n  port: 1\n\nbackups:\n    # 主机\n    # 端口\n  - host: b\n    port: 2\n  - host: c\n    port: 3\n\nscript: |\n  echo 1\n\n  echo 2\n\n# ---- Misc ----\ndebug: false\n"},
+		{BlankLinesBetweenSections, "# 名称\nname: demo\nversion: 1\n\n# 服务\nserver:\n  # 主机\n  host: a\n  # 端口\n  port: 1\n\nbackups:\n    # 主机\n    # 端口\n  - host: b\n    port: 2\n  - host: c\n    port: 3\n\nscript: |\n  echo 1\n\n  echo 2\n\n# ---- Misc ----\ndebug: false\n"},
+		{BlankLinesBetweenAllFields, "# 名称\nname: demo\n\nversion: 1\n\n# 服务\nserver:\n  # 主机\n  host: a\n\n  # 端口\n  port: 1\n\nbackups:\n    # 主机\n    # 端口\n  - host: b\n\n    port: 2\n  - host: c\n\n    port: 3\n\nscript: |\n  echo 1\n\n  echo 2\n\n# ---- Misc ----\ndebug: false\n"},
+	}
+
+	for _, tc := range testCases {
+		data, err := Gen(cfg, WithStyle(StyleSpaced), WithBlankLines(tc.policy))
+		if err != nil {
+			t.Fatalf("Gen failed: %v", err)
+		}
+		if string(data) != tc.expected {
+			t.Errorf("policy %d: unexpected output:\n%s\nexpected:\n%s", tc.policy, data, tc.expected)
+		}
+		var decoded blankConfig
+		if err := yaml.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unmarshal failed: %v", err)
+		}
+		if decoded.Script != cfg.Script || decoded.Server != cfg.Server {
+			t.Errorf("policy %d: block scalar content changed: %q", tc.policy, decoded.Script)
+		}
+	}
+}
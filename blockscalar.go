@@ -0,0 +1,284 @@
+package yamlc
+
+import (
+	"reflect"
+	"strings"
+)
+
+// BlockStyle 多行字符串的块标量风格
+type BlockStyle int
+
+const (
+	// BlockLiteral 字面块（|），原样保留换行
+	BlockLiteral BlockStyle = iota
+	// BlockFolded 折叠块（>），单个换行在读取时折叠为空格
+	BlockFolded
+)
+
+// LongStringStyle 超长单行字符串的输出方式
+type LongStringStyle int
+
+const (
+	// LongStringPlain 保持单行输出（默认）
+	LongStringPlain LongStringStyle = iota
+	// LongStringFolded 超过折叠宽度时以折叠块（>）按单词换行输出
+	LongStringFolded
+)
+
+// DefaultFoldWidth 默认的折叠宽度
+const DefaultFoldWidth = 80
+
+// WithLongStringStyle 设置超长单行字符串的输出方式，字段也可通过 folded 标签单独开启
+func WithLongStringStyle(style LongStringStyle) Option {
+	return func(o *Options) {
+		o.LongStringStyle = style
+	}
+}
+
+// WithFoldWidth 设置折叠块换行的目标宽度（含缩进）
+func WithFoldWidth(width int) Option {
+	return func(o *Options) {
+		o.FoldWidth = width
+	}
+}
+
+// getFoldWidth 获取折叠宽度
+func getFoldWidth(options *Options) int {
+	if options.FoldWidth > 0 {
+		return options.FoldWidth
+	}
+	return DefaultFoldWidth
+}
+
+// shouldFold 判断单行字符串是否需要折叠：开启折叠且内容行超出宽度，并且存在可断行的空格
+func shouldFold(str string, indentStr string, options *Options) bool {
+	if options.LongStringStyle != LongStringFolded && options.blockStyle != BlockFolded {
+		return false
+	}
+	if getDisplayWidth(indentStr)+getDisplayWidth(str) <= getFoldWidth(options) {
+		return false
+	}
+	return len(wrapLine(str, getFoldWidth(options)-getDisplayWidth(indentStr))) > 1
+}
+
+// getBlockStyle 获取字段通过 literal/folded 标签指定的块标量风格
+func getBlockStyle(field reflect.StructField) (BlockStyle, bool) {
+	switch {
+	case hasTagFlag(field, "folded"):
+		return BlockFolded, true
+	case hasTagFlag(field, "literal"):
+		return BlockLiteral, true
+	}
+	return BlockLiteral, false
+}
+
+// blockScalar 将字符串渲染为块标量，内容行使用 indentStr 缩进；折叠块按 width（含缩进）在空格处换行
+// 无法用块标量无损表示的字符串（含回车、行尾空白、首行缩进等）返回false，由调用方回退为引号字符串
+func blockScalar(str string, style BlockStyle, indentStr string, width int) (string, bool) {
+	body := strings.TrimRight(str, "\n")
+	trailing := len(str) - len(body)
+	if body == "" || trailing > 1 || strings.Contains(str, "\r") {
+		return "", false
+	}
+
+	lines := strings.Split(body, "\n")
+	for _, line := range lines {
+		if strings.TrimRight(line, " \t") != line {
+			return "", false
+		}
+	}
+	// 首个非空行以空白开头时需要缩进指示符，不做处理
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		if isBlank(line[0]) {
+			return "", false
+		}
+		break
+	}
+
+	header := "|"
+	if style == BlockFolded && canFold(lines) {
+		header = ">"
+		lines = foldLines(lines, width-getDisplayWidth(indentStr))
+	}
+	if trailing == 0 {
+		header += "-"
+	}
+
+	var result strings.Builder
+	result.WriteString(header)
+	for _, line := range lines {
+		result.WriteString("\n")
+		if line != "" {
+			result.WriteString(indentStr + line)
+		}
+	}
+	return result.String(), true
+}
+
+// canFold 判断内容能否用折叠块表示：开头为空行或存在缩进行时折叠语义不同，改用字面块
+func canFold(lines []string) bool {
+	if lines[0] == "" {
+		return false
+	}
+	for _, line := range lines {
+		if line != "" && isBlank(line[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// foldLines 转换为折叠块的内容行：原文中连续n个换行需要写成n个空行，超宽的行在空格处换行
+func foldLines(lines []string, width int) []string {
+	folded := make([]string, 0, len(lines)*2)
+	for i, line := range lines {
+		if i > 0 && line != "" {
+			folded = append(folded, "")
+		}
+		folded = append(folded, wrapLine(line, width)...)
+	}
+	return folded
+}
+
+// wrapLine 在单个空格处将行拆分为不超过 width 显示宽度的多行（单词过长时允许超出）
+// 折叠块读取时相邻行以一个空格连接，因此只在前后都不是空白的单个空格处断开
+func wrapLine(line string, width int) []string {
+	var words []string
+	start := 0
+	for i := 1; i < len(line)-1; i++ {
+		if line[i] == ' ' && !isBlank(line[i-1]) && !isBlank(line[i+1]) {
+			words = append(words, line[start:i])
+			start = i + 1
+		}
+	}
+	words = append(words, line[start:])
+
+	var wrapped []string
+	current := words[0]
+	for _, word := range words[1:] {
+		if getDisplayWidth(current)+1+getDisplayWidth(word) > width {
+			wrapped = append(wrapped, current)
+			current = word
+			continue
+		}
+		current += " " + word
+	}
+	return append(wrapped, current)
+}
+
+// isBlockScalarHeader 判断行内容是否以块标量指示符结尾（| 或 >，可带保留/缩进指示符）
+func isBlockScalarHeader(content string) bool {
+	content = strings.TrimSpace(content)
+	idx := strings.LastIndexAny(content, " ")
+	last := content[idx+1:]
+	if last == "" || (last[0] != '|' && last[0] != '>') {
+		return false
+	}
+	return strings.Trim(last[1:], "+-0123456789") == ""
+}
+
+// splitBlockScalar 将块标量拆为首行（指示符）和内容行，便于在首行后追加行尾注释
+// 非块标量时 body 为空
+func splitBlockScalar(value string) (head, body string) {
+	idx := strings.Index(value, "\n")
+	if idx < 0 || !isBlockScalarHeader(value[:idx]) {
+		return value, ""
+	}
+	return value[:idx], value[idx:]
+}
+
+// blockContentLines 标记文档中属于块标量内容的行（含内容之间的空行，不含末尾空行）
+func blockContentLines(lines []string) []bool {
+	inBlock := make([]bool, len(lines))
+	blockIndent := -1
+	for i, line := range lines {
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if blockIndent >= 0 {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			if indent > blockIndent {
+				inBlock[i] = true
+				// 内容行之前的空行同样属于块内容
+				for j := i - 1; j >= 0 && !inBlock[j] && strings.TrimSpace(lines[j]) == ""; j-- {
+					inBlock[j] = true
+				}
+				continue
+			}
+			blockIndent = -1
+		}
+
+		content := line
+		if parsed, ok := splitTrailingComment(line); ok {
+			content = parsed.content
+		}
+		if isBlockScalarHeader(content) {
+			blockIndent = blockParentIndent(content, indent)
+		}
+	}
+	return inBlock
+}
+
+// scanBlockLines 逐行调用 fn，并与 blockContentLines 一样标记块标量内容行，不需要先拆分整段内容
+func scanBlockLines(content string, fn func(line string, inBlock bool)) {
+	blockIndent := -1
+	// 块标量之后的空行在遇到下一个非空行时才能确定是否属于块内容
+	var blanks []string
+	for rest, more := content, true; more; {
+		var line string
+		line, rest, more = strings.Cut(rest, "\n")
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if blockIndent >= 0 {
+			if strings.TrimSpace(line) == "" {
+				blanks = append(blanks, line)
+				continue
+			}
+			inBlock := indent > blockIndent
+			for _, blank := range blanks {
+				fn(blank, inBlock)
+			}
+			blanks = blanks[:0]
+			if inBlock {
+				fn(line, true)
+				continue
+			}
+			blockIndent = -1
+		}
+
+		fn(line, false)
+		content := line
+		if parsed, ok := splitTrailingComment(line); ok {
+			content = parsed.content
+		}
+		if isBlockScalarHeader(content) {
+			blockIndent = blockParentIndent(content, indent)
+		}
+	}
+	for _, blank := range blanks {
+		fn(blank, false)
+	}
+}
+
+// blockParentIndent 块标量所属节点的缩进：列表项中的键（"- key: |"）以键所在列为准
+func blockParentIndent(content string, indent int) int {
+	parent := indent
+	rest := content[indent:]
+	for strings.HasPrefix(rest, "- ") {
+		trimmed := strings.TrimLeft(rest[1:], " ")
+		if trimmed != "" && (trimmed[0] == '|' || trimmed[0] == '>') {
+			// "- |" 的内容只需比 "-" 更深
+			return parent
+		}
+		parent += len(rest) - len(trimmed)
+		rest = trimmed
+	}
+	return parent
+}
+
+// isBlank 判断字节是否为空格或制表符
+func isBlank(c byte) bool {
+	return c == ' ' || c == '\t'
+}
@@ -0,0 +1,169 @@
+package yamlc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+type blockJob struct {
+	Script string `yaml:"script" comment:"执行脚本"`
+	Note   string `yaml:"note" yamlc:"folded" comment:"说明"`
+}
+
+type blockConfig struct {
+	Cert  string     `yaml:"cert" comment:"证书"`
+	Query string     `yaml:"query" yamlc:"literal" comment:"查询语句"`
+	Lines []string   `yaml:"lines" comment:"多行列表"`
+	Jobs  []blockJob `yaml:"jobs" comment:"任务"`
+	Name  string     `yaml:"name" comment:"名称"`
+}
+
+func TestBlockScalar(t *testing.T) {
+	testCases := []struct {
+		str      string
+		style    BlockStyle
+		expected string
+		ok       bool
+	}{
+		{"a\nb\n", BlockLiteral, "|\n  a\n  b", true},
+		{"a\n\nb", BlockLiteral, "|-\n  a\n\n  b", true},
+		{"a\nb\n\nc", BlockFolded, ">-\n  a\n\n  b\n\n\n  c", true},
+		{"a\n  b", BlockFolded, "|-\n  a\n    b", true},
+		{"a \nb", BlockLiteral, "", false},
+		{"  a\nb", BlockLiteral, "", false},
+		{"a\n\n", BlockLiteral, "", false},
+		{"a\r\nb", BlockLiteral, "", false},
+	}
+
+	for _, tc := range testCases {
+		got, ok := blockScalar(tc.str, tc.style, "  ", DefaultFoldWidth)
+		if ok != tc.ok || got != tc.expected {
+			t.Errorf("blockScalar(%q) = (%q, %v), expected (%q, %v)", tc.str, got, ok, tc.expected, tc.ok)
+		}
+	}
+}
+
+func TestMultilineStringRoundTrip(t *testing.T) {
+	cfg := blockConfig{
+		Cert:  "-----BEGIN CERTIFICATE-----\nMIIB\n\n# not a comment\n-----END CERTIFICATE-----\n",
+		Query: "SELECT *\nFROM users\nWHERE id = 1",
+		Lines: []string{"x\ny", "z"},
+		Jobs: []blockJob{
+			{Script: "make\nmake install", Note: "第一段\n续行\n\n第二段"},
+			{Script: "echo 1\n  echo 2", Note: "single"},
+		},
+		Name: "demo",
+	}
+
+	for _, style := range GetAllStyle() {
+		data, err := Gen(cfg, WithStyle(style))
+		if err != nil {
+			t.Errorf("style %s failed: %v", GetStyleString(int(style)), err)
+			continue
+		}
+		var decoded blockConfig
+		if err := yaml.Unmarshal(data, &decoded); err != nil {
+			t.Errorf("style %s produced invalid yaml: %v\n%s", GetStyleString(int(style)), err, data)
+			continue
+		}
+		if !reflect.DeepEqual(decoded, cfg) {
+			t.Errorf("style %s did not round-trip:\n%s", GetStyleString(int(style)), data)
+		}
+	}
+
+	data, err := Gen(cfg, WithStyle(StyleTop))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	yamlStr := string(data)
+	for _, expected := range []string{
+		"cert: |\n  -----BEGIN CERTIFICATE-----\n",
+		"query: |-\n  SELECT *\n",
+		"    note: >-\n      第一段\n\n      续行\n",
+	} {
+		if !strings.Contains(yamlStr, expected) {
+			t.Errorf("expected %q in:\n%s", expected, yamlStr)
+		}
+	}
+}
+
+func TestBlockScalarInlineComment(t *testing.T) {
+	cfg := blockConfig{Query: "SELECT 1\nFROM dual", Name: "demo"}
+
+	data, err := Gen(cfg, WithStyle(StyleCompact))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if !strings.Contains(string(data), "query: |- # 查询语句\n  SELECT 1\n  FROM dual\n") {
+		t.Errorf("comment should follow the block indicator:\n%s", data)
+	}
+
+	if err := ValidateStructure(data); err != nil {
+		t.Errorf("ValidateStructure should skip block content: %v", err)
+	}
+}
+
+func TestFoldedLongString(t *testing.T) {
+	type Doc struct {
+		Summary string `yaml:"summary" yamlc:"folded" comment:"摘要"`
+		Title   string `yaml:"title" comment:"标题"`
+	}
+	doc := Doc{
+		Summary: "yamlc generates commented YAML from Go structs so that configuration files stay readable for the people who maintain them",
+		Title:   "a long title that is only folded when the long string style is enabled for the whole document",
+	}
+
+	data, err := Gen(doc, WithFoldWidth(40))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	yamlStr := string(data)
+	if !strings.Contains(yamlStr, "summary: >-\n  yamlc generates commented YAML from Go\n") {
+		t.Errorf("expected folded summary:\n%s", yamlStr)
+	}
+	if !strings.Contains(yamlStr, "title: "+doc.Title+"\n") {
+		t.Errorf("title should stay on one line without WithLongStringStyle:\n%s", yamlStr)
+	}
+	for _, line := range strings.Split(yamlStr, "\n") {
+		if strings.HasPrefix(line, "  ") && getDisplayWidth(line) > 40 {
+			t.Errorf("folded line exceeds width: %q", line)
+		}
+	}
+
+	data, err = Gen(doc, WithFoldWidth(40), WithLongStringStyle(LongStringFolded))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if !strings.Contains(string(data), "title: >-\n") {
+		t.Errorf("expected folded title:\n%s", data)
+	}
+	var decoded Doc
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded != doc {
+		t.Errorf("folded strings did not round-trip: %+v", decoded)
+	}
+}
+
+func TestWrapLine(t *testing.T) {
+	testCases := []struct {
+		line     string
+		width    int
+		expected []string
+	}{
+		{"aa bb cc", 5, []string{"aa bb", "cc"}},
+		{"aa  bb cc", 5, []string{"aa  bb", "cc"}},
+		{"averylongword b", 5, []string{"averylongword", "b"}},
+		{"没有空格的中文长句子", 4, []string{"没有空格的中文长句子"}},
+	}
+
+	for _, tc := range testCases {
+		if got := wrapLine(tc.line, tc.width); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("wrapLine(%q, %d) = %q, expected %q", tc.line, tc.width, got, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package yamlc
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// bytesFormats format= 标签支持的字节切片编码：base64（默认，带 !!binary 标签）、hex、string
+var bytesFormats = map[string]bool{
+	"base64": true,
+	"hex":    true,
+	"string": true,
+}
+
+// binaryTag base64编码的二进制数据标签，yaml.v3 解码到字符串时得到原始字节
+const binaryTag = "!!binary"
+
+// isByteSlice 判断值是否为非空的字节切片（[]byte 及以其为底层类型的类型）
+func isByteSlice(val reflect.Value) bool {
+	return val.Kind() == reflect.Slice && val.Type().Elem().Kind() == reflect.Uint8 && val.Len() > 0
+}
+
+// generateBytes 生成字节切片的YAML值，与空切片一样以空格开头
+// 默认输出为 !!binary 标签的base64；format=hex 输出十六进制字符串，format=string 在内容为合法UTF-8时按字符串输出
+func generateBytes(val reflect.Value, fieldPath string, indent int, options *Options) (string, error) {
+	data := val.Bytes()
+	switch options.valueFormat {
+	case "hex":
+		return " " + quoteString(hex.EncodeToString(data), options) + "\n", nil
+	case "string":
+		if utf8.Valid(data) {
+			str, err := generateString(reflect.ValueOf(string(data)), fieldPath, indent, options)
+			if err != nil {
+				return "", err
+			}
+			return " " + str + "\n", nil
+		}
+	}
+	return " " + binaryTag + " " + base64.StdEncoding.EncodeToString(data) + "\n", nil
+}
+
+// formatNode 在yaml节点树上按 format= 标签和浮点数格式输出值：字节切片从整数序列替换为编码后的标量，整数按指定进制输出，
+// 浮点数按 WithFloatFormat 或 precision= 标签格式化，严格模式下 NaN 和 ±Inf 返回错误，用于不经过字段渲染的最小风格
+func formatNode(node *yaml.Node, val reflect.Value, format string, options *Options) error {
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+
+	switch node.Kind {
+	case yaml.ScalarNode:
+		formatIntNode(node, val, format)
+		return formatFloatNode(node, val, options)
+	case yaml.DocumentNode:
+		if len(node.Content) > 0 {
+			return formatNode(node.Content[0], val, format, options)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			switch val.Kind() {
+			case reflect.Struct:
+				if fieldType, field, ok := findYAMLField(val, key); ok {
+					fieldOptions := options
+					if precision, ok := getPrecisionFormat(fieldType); ok && !hasChildren(field) && precision != options.FloatFormat {
+						copied := *options
+						copied.FloatFormat = precision
+						fieldOptions = &copied
+					}
+					if err := formatNode(node.Content[i+1], field, getValueFormat(fieldType), fieldOptions); err != nil {
+						return err
+					}
+				}
+			case reflect.Map:
+				if val.Type().Key().Kind() == reflect.String {
+					if err := formatNode(node.Content[i+1], val.MapIndex(reflect.ValueOf(key).Convert(val.Type().Key())), "", options); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	case yaml.SequenceNode:
+		if isByteSlice(val) {
+			data := val.Bytes()
+			switch {
+			case format == "hex":
+				*node = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: hex.EncodeToString(data)}
+			case format == "string" && utf8.Valid(data):
+				*node = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: string(data)}
+			default:
+				*node = yaml.Node{Kind: yaml.ScalarNode, Tag: binaryTag, Value: base64.StdEncoding.EncodeToString(data)}
+			}
+			return nil
+		}
+		if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+			return nil
+		}
+		for i, item := range node.Content {
+			if i < val.Len() {
+				if err := formatNode(item, val.Index(i), "", options); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// decodeBytesNode 在解码前将字节切片字段的标量节点还原为整数序列，yaml.v3 不能把 !!binary 或字符串直接解码到 []byte
+// !!binary 按base64解码，format=hex 的字段按十六进制解码，其他字符串取原始内容
+func decodeBytesNode(node *yaml.Node, typ reflect.Type, fieldPath string, format string) error {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) > 0 {
+			return decodeBytesNode(node.Content[0], typ, fieldPath, format)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			childPath := buildFieldPath(fieldPath, key)
+			switch typ.Kind() {
+			case reflect.Struct:
+				if fieldType, _, ok := findYAMLField(reflect.New(typ).Elem(), key); ok {
+					if err := decodeBytesNode(node.Content[i+1], fieldType.Type, childPath, getValueFormat(fieldType)); err != nil {
+						return err
+					}
+				}
+			case reflect.Map:
+				if err := decodeBytesNode(node.Content[i+1], typ.Elem(), childPath, ""); err != nil {
+					return err
+				}
+			}
+		}
+	case yaml.SequenceNode:
+		if typ.Kind() != reflect.Slice && typ.Kind() != reflect.Array {
+			return nil
+		}
+		for i, item := range node.Content {
+			if err := decodeBytesNode(item, typ.Elem(), buildFieldPath(fieldPath, strconv.Itoa(i)), ""); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		if typ.Kind() != reflect.Slice || typ.Elem().Kind() != reflect.Uint8 || node.Tag == "!!null" {
+			return nil
+		}
+		data := []byte(node.Value)
+		var err error
+		switch {
+		case node.Tag == binaryTag:
+			data, err = base64.StdEncoding.DecodeString(node.Value)
+		case format == "hex":
+			data, err = hex.DecodeString(node.Value)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid binary value for field %q: %w", fieldPath, err)
+		}
+		items := make([]*yaml.Node, len(data))
+		for i, b := range data {
+			items[i] = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(int(b))}
+		}
+		*node = yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: items, Line: node.Line, Column: node.Column}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package yamlc
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestByteSlices(t *testing.T) {
+	type Keys struct {
+		Secret []byte   `yaml:"secret" comment:"密钥"`
+		Digest []byte   `yaml:"digest" yamlc:"format=hex" comment:"摘要"`
+		Banner []byte   `yaml:"banner" yamlc:"format=string" comment:"横幅"`
+		Empty  []byte   `yaml:"empty"`
+		Chain  [][]byte `yaml:"chain"`
+	}
+	keys := Keys{
+		Secret: []byte{0xde, 0xad, 0xbe, 0xef, 0x00},
+		Digest: []byte{0x01, 0xab},
+		Banner: []byte("hello: world"),
+		Chain:  [][]byte{[]byte("a"), []byte("bc")},
+	}
+
+	data, err := Gen(keys, WithStyle(StyleTop))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	for _, expected := range []string{
+		"secret: !!binary 3q2+7wA=\n",
+		"digest: 01ab\n",
+		"banner: 'hello: world'\n",
+		"empty: []\n",
+		"  - !!binary YQ==\n",
+	} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("expected %q in:\n%s", expected, data)
+		}
+	}
+
+	// 各风格的生成结果都能解码回原值
+	for _, style := range GetAllStyle() {
+		data, err := Gen(keys, WithStyle(style), WithFlowThreshold(4))
+		if err != nil {
+			t.Fatalf("style %s failed: %v", GetStyleString(int(style)), err)
+		}
+		decoded, err := Unmarshal[Keys](data)
+		if err != nil {
+			t.Fatalf("style %s: Unmarshal failed: %v\n%s", GetStyleString(int(style)), err, data)
+		}
+		// 空切片生成为 [] 或被注释掉，解码结果为空切片或 nil
+		if len(decoded.Empty) == 0 {
+			decoded.Empty = nil
+		}
+		if !reflect.DeepEqual(decoded, keys) {
+			t.Errorf("style %s did not round-trip: %+v\n%s", GetStyleString(int(style)), decoded, data)
+		}
+	}
+	if err := CheckInvariants(Keys{Secret: keys.Secret, Digest: keys.Digest}); err != nil {
+		t.Errorf("CheckInvariants failed: %v", err)
+	}
+}
+
+func TestByteSlicesManager(t *testing.T) {
+	type Keys struct {
+		Secret []byte `yaml:"secret"`
+		Digest []byte `yaml:"digest" yamlc:"format=hex"`
+	}
+	keys := &Keys{Secret: []byte{0xde, 0xad, 0x00}, Digest: []byte{0x01, 0xab}}
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	manager, err := NewManager(path, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	*keys = Keys{}
+	if err := manager.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !bytes.Equal(keys.Secret, []byte{0xde, 0xad, 0x00}) || !bytes.Equal(keys.Digest, []byte{0x01, 0xab}) {
+		t.Errorf("unexpected loaded value: %+v", keys)
+	}
+
+	if _, err := Unmarshal[Keys]([]byte("digest: zz\n")); err == nil || !strings.Contains(err.Error(), "digest") {
+		t.Errorf("expected invalid hex error, got %v", err)
+	}
+}
@@ -0,0 +1,16 @@
+package yamlc
+
+// WithChecksumFooter 在生成内容末尾追加 "# yamlc-sha256: ..." 注释，值为其余内容规范化后的 SHA-256，
+// 格式与 OverwriteIfUnmodified 写入的校验和相同，可用 VerifyChecksum 判断文件生成后是否被手动修改过。
+// 规范化忽略换行符风格、行尾空白和末尾空行，编辑器的自动整理不会被视为修改
+func WithChecksumFooter() Option {
+	return func(o *Options) {
+		o.ChecksumFooter = true
+	}
+}
+
+// VerifyChecksum 判断内容末尾的校验和注释是否与其余内容一致，即文件生成后未被修改
+// WithChecksumFooter、OverwriteIfUnmodified 以及早期版本写入的 "# yamlc-checksum: sha256:..." 校验和均可识别；没有校验和时返回 false
+func VerifyChecksum(data []byte) bool {
+	return verifyChecksum(data)
+}
@@ -0,0 +1,110 @@
+package yamlc
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWithChecksumFooter(t *testing.T) {
+	cfg := managedConfig{Host: "localhost", Port: 8080}
+
+	data, err := Gen(cfg, WithStyle(StyleTop), WithChecksumFooter(), WithDocumentEnd())
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if last := lines[len(lines)-1]; !strings.HasPrefix(last, "# yamlc-sha256: ") || len(last) != len("# yamlc-sha256: ")+64 {
+		t.Errorf("unexpected checksum line %q in:\n%s", last, data)
+	}
+	if !VerifyChecksum(data) {
+		t.Errorf("checksum should verify:\n%s", data)
+	}
+
+	// 换行符风格、行尾空白和末尾空行不算修改
+	normalized := bytes.ReplaceAll(data, []byte("\n"), []byte("  \r\n"))
+	if !VerifyChecksum(append(normalized, '\n')) {
+		t.Errorf("whitespace-only changes should still verify:\n%q", normalized)
+	}
+
+	edited := bytes.Replace(data, []byte("port: 8080"), []byte("port: 9090"), 1)
+	if VerifyChecksum(edited) {
+		t.Error("edited content should not verify")
+	}
+
+	plain, err := Gen(cfg)
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if VerifyChecksum(plain) {
+		t.Error("content without checksum should not verify")
+	}
+
+	// 早期版本写入的校验和格式仍然识别
+	legacy := strings.Replace(string(data), "# yamlc-sha256: ", "# yamlc-checksum: sha256:", 1)
+	if !VerifyChecksum([]byte(legacy)) {
+		t.Errorf("legacy checksum should verify:\n%s", legacy)
+	}
+}
+
+func TestChecksumFooterOverwriteIfUnmodified(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "app.yaml")
+	opts := []Option{WithChecksumFooter(), WithOverwritePolicy(OverwriteIfUnmodified)}
+
+	if err := WriteFile(filename, managedConfig{Port: 1}, opts...); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	data, _ := os.ReadFile(filename)
+	if bytes.Count(data, []byte(checksumPrefix)) != 1 || !VerifyChecksum(data) {
+		t.Errorf("expected a single checksum footer:\n%s", data)
+	}
+
+	if err := WriteFile(filename, managedConfig{Port: 2}, opts...); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	data, _ = os.ReadFile(filename)
+	if !bytes.Contains(data, []byte("port: 2")) {
+		t.Errorf("unmodified file should be overwritten:\n%s", data)
+	}
+
+	// 不带 WithChecksumFooter 写入的文件使用同一格式，两种写法可以互相识别
+	if err := WriteFile(filename, managedConfig{Port: 3}, WithOverwritePolicy(OverwriteIfUnmodified)); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	policyOnly, _ := os.ReadFile(filename)
+	footer, err := Gen(managedConfig{Port: 3}, WithChecksumFooter())
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if !bytes.Equal(policyOnly, footer) {
+		t.Errorf("checksum formats differ:\n%s\n---\n%s", policyOnly, footer)
+	}
+}
+
+func TestManagerEdited(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "app.yaml")
+	manager, err := NewManager(filename, &managedConfig{Port: 8080}, WithChecksumFooter())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	if edited, err := manager.Edited(); err != nil || edited {
+		t.Errorf("missing file should not count as edited: %v", err)
+	}
+
+	if _, err := manager.EnsureExists(); err != nil {
+		t.Fatalf("EnsureExists failed: %v", err)
+	}
+	if edited, err := manager.Edited(); err != nil || edited {
+		t.Errorf("generated file should not count as edited: %v", err)
+	}
+
+	data, _ := os.ReadFile(filename)
+	if err := os.WriteFile(filename, bytes.Replace(data, []byte("8080"), []byte("9090"), 1), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if edited, err := manager.Edited(); err != nil || !edited {
+		t.Errorf("expected edited file to be detected: %v", err)
+	}
+}
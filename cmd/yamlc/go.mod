@@ -0,0 +1,19 @@
+module binrc.com/pkg/yamlc/cmd/yamlc
+
+go 1.22.0
+
+require (
+	binrc.com/pkg/yamlc v0.0.0-00010101000000-000000000000
+	golang.org/x/tools v0.26.0
+)
+
+require (
+	github.com/fsnotify/fsnotify v1.9.0 // indirect
+	golang.org/x/mod v0.21.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+	golang.org/x/sys v0.26.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// 本地开发时使用仓库中的yamlc
+replace binrc.com/pkg/yamlc => ../../
@@ -0,0 +1,286 @@
+// Command yamlc 在命令行中使用yamlc：按Go结构体生成带注释的配置、转换注释风格、检查和比较配置文件
+//
+// 用法:
+//
+//	yamlc gen --type ./internal/config.Config --style inline --out config.yaml
+//	yamlc restyle --style top --in config.yaml --out config.yaml
+//	yamlc lint [--type ./internal/config.Config] config.yaml ...
+//	yamlc diff --type ./internal/config.Config [--unified] config.yaml
+//
+// --type 的格式为 "包路径.类型名"，包路径可以是导入路径或相对目录，由 go/packages 加载。gen、diff 和带 --type 的 lint
+// 会在临时目录中生成一个小程序，以 -overlay 的方式放在该包所在目录下构建并运行来取得结构体的值（不会在包目录中写入文件），
+// 因此该包所在的模块需要依赖 binrc.com/pkg/yamlc；
+// 包中存在无参数的 New<Type> 或 Default<Type> 函数时用其返回值作为默认值，否则使用零值
+//
+// 本命令是独立的模块（依赖 golang.org/x/tools），不会增加yamlc库使用者的依赖
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"binrc.com/pkg/yamlc"
+)
+
+// errFailed 检查未通过或存在差异，详情已输出，只需以非0状态退出
+var errFailed = errors.New("failed")
+
+func main() {
+	os.Exit(run(os.Args[1:], streams{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}))
+}
+
+// streams 命令使用的标准输入输出
+type streams struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// run 执行命令并返回退出状态
+func run(args []string, s streams) int {
+	if len(args) == 0 {
+		usage(s.stderr)
+		return 2
+	}
+
+	var err error
+	switch cmd, args := args[0], args[1:]; cmd {
+	case "gen", "generate":
+		err = s.runGen(args)
+	case "restyle":
+		err = s.runRestyle(args)
+	case "lint":
+		err = s.runLint(args)
+	case "diff":
+		err = s.runDiff(args)
+	case "help", "-h", "--help":
+		usage(s.stdout)
+		return 0
+	default:
+		fmt.Fprintf(s.stderr, "yamlc: unknown command %q\n", cmd)
+		usage(s.stderr)
+		return 2
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		return 2
+	case errors.Is(err, errFailed):
+		return 1
+	case errors.As(err, &exitErr):
+		// 运行器已输出错误信息
+		return exitErr.ExitCode()
+	default:
+		fmt.Fprintln(s.stderr, "yamlc:", err)
+		return 1
+	}
+}
+
+// usage 输出命令列表
+func usage(w io.Writer) {
+	fmt.Fprint(w, `usage: yamlc <command> [flags]
+
+commands:
+  gen      generate commented YAML from a Go struct
+  restyle  convert the comment style of a YAML file
+  lint     check YAML syntax, structure and optionally fields against a struct
+  diff     report drift between a Go struct and a config file
+
+run "yamlc <command> -h" for the flags of each command
+`)
+}
+
+// runGen 按结构体生成带注释的YAML
+func (s streams) runGen(args []string) error {
+	fs := flag.NewFlagSet("gen", flag.ContinueOnError)
+	fs.SetOutput(s.stderr)
+	typeSpec := fs.String("type", "", `struct to generate from, as "pkg.Type" (required)`)
+	style := fs.String("style", "smart", "comment style: "+styleNames())
+	out := fs.String("out", "", "output file (default stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *typeSpec == "" {
+		return fmt.Errorf("gen: --type is required")
+	}
+	if err := checkStyle(*style); err != nil {
+		return err
+	}
+
+	data, err := runStruct(*typeSpec, "gen", *style, s.stderr)
+	if err != nil {
+		return err
+	}
+	return s.writeOutput(*out, data)
+}
+
+// runRestyle 转换YAML文件的注释风格
+func (s streams) runRestyle(args []string) error {
+	fs := flag.NewFlagSet("restyle", flag.ContinueOnError)
+	fs.SetOutput(s.stderr)
+	style := fs.String("style", "smart", "comment style: "+styleNames())
+	in := fs.String("in", "", "input YAML file (default stdin)")
+	out := fs.String("out", "", "output file (default stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := checkStyle(*style); err != nil {
+		return err
+	}
+
+	data, err := s.readInput(*in)
+	if err != nil {
+		return err
+	}
+	data, err = yamlc.Restyle(data, yamlc.GetStyleFromString(*style))
+	if err != nil {
+		return err
+	}
+	return s.writeOutput(*out, data)
+}
+
+// runLint 检查YAML文件，指定 --type 时同时检查未知字段和类型不匹配
+func (s streams) runLint(args []string) error {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	fs.SetOutput(s.stderr)
+	typeSpec := fs.String("type", "", `struct to check fields against, as "pkg.Type"`)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	files := fs.Args()
+	if len(files) == 0 {
+		return fmt.Errorf("lint: no files given")
+	}
+
+	failed := false
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %q: %w", file, err)
+		}
+		if err := lintData(data); err != nil {
+			fmt.Fprintf(s.stderr, "%s: %v\n", file, err)
+			failed = true
+			continue
+		}
+		if *typeSpec == "" {
+			continue
+		}
+		if _, err := runStruct(*typeSpec, "lint", file, s.stderr); err != nil {
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) {
+				return err
+			}
+			failed = true
+		}
+	}
+	if failed {
+		return errFailed
+	}
+	return nil
+}
+
+// lintData 检查YAML语法和缩进结构，缩进宽度按文件内容推断
+func lintData(data []byte) error {
+	if err := yamlc.ValidateYAML(data); err != nil {
+		return err
+	}
+	return yamlc.ValidateStructureIndent(data, indentWidth(data))
+}
+
+// indentWidth 推断文件的缩进宽度：非注释行中最小的非0缩进，没有缩进时为默认宽度
+func indentWidth(data []byte) int {
+	width := 0
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if indent := len(line) - len(trimmed); indent > 0 && (width == 0 || indent < width) {
+			width = indent
+		}
+	}
+	if width == 0 {
+		return yamlc.DefaultIndent
+	}
+	return width
+}
+
+// runDiff 比较结构体与配置文件，存在差异时以状态1退出
+func (s streams) runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.SetOutput(s.stderr)
+	typeSpec := fs.String("type", "", `struct to compare with, as "pkg.Type" (required)`)
+	unified := fs.Bool("unified", false, "print a unified diff of the file and the generated YAML")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *typeSpec == "" {
+		return fmt.Errorf("diff: --type is required")
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("diff: expected exactly one config file")
+	}
+
+	mode := "diff"
+	if *unified {
+		mode = "diff-unified"
+	}
+	data, err := runStruct(*typeSpec, mode, fs.Arg(0), s.stderr)
+	s.stdout.Write(data)
+	return err
+}
+
+// styleNames 内置风格名称列表
+func styleNames() string {
+	names := make([]string, 0, len(yamlc.GetAllStyle()))
+	for _, style := range yamlc.GetAllStyle() {
+		names = append(names, yamlc.GetStyleString(int(style)))
+	}
+	return strings.Join(names, ", ")
+}
+
+// checkStyle 检查风格名称是否存在
+func checkStyle(name string) error {
+	for _, style := range yamlc.GetAllStyle() {
+		if strings.EqualFold(yamlc.GetStyleString(int(style)), name) {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown style %q, expected one of: %s", name, styleNames())
+}
+
+// readInput 读取输入文件，未指定时读取标准输入
+func (s streams) readInput(in string) ([]byte, error) {
+	var data []byte
+	var err error
+	if in == "" {
+		data, err = io.ReadAll(s.stdin)
+	} else {
+		data, err = os.ReadFile(in)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input: %w", err)
+	}
+	return data, nil
+}
+
+// writeOutput 写出结果，未指定文件时写到标准输出
+func (s streams) writeOutput(out string, data []byte) error {
+	if out == "" {
+		_, err := s.stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(out, data, 0644); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
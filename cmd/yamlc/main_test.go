@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runCommand 执行命令，返回退出状态、标准输出和标准错误
+func runCommand(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(args, streams{stdin: strings.NewReader(stdin), stdout: &stdout, stderr: &stderr})
+	return code, stdout.String(), stderr.String()
+}
+
+// writeFile 在临时目录中写入文件并返回路径
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// nestedYAML 生成按指定宽度缩进、嵌套 depth 层的YAML
+func nestedYAML(width, depth int) string {
+	var b strings.Builder
+	for level := 0; level < depth; level++ {
+		b.WriteString(strings.Repeat(" ", level*width) + fmt.Sprintf("level%d:\n", level))
+	}
+	b.WriteString(strings.Repeat(" ", depth*width) + "value: 1\n")
+	b.WriteString("other: 2\n")
+	return b.String()
+}
+
+func TestGen(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a runner program")
+	}
+	out := filepath.Join(t.TempDir(), "config.yaml")
+	code, _, stderr := runCommand(t, "", "gen", "--type", "./testdata/app.Config", "--style", "top", "--out", out)
+	if code != 0 {
+		t.Fatalf("gen exited with %d: %s", code, stderr)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "# 名称\nname: demo\n") || !strings.Contains(string(data), "  # 端口\n  port: 8080\n") {
+		t.Errorf("unexpected output:\n%s", data)
+	}
+
+	// 运行器不应在目标包目录中留下文件
+	entries, err := os.ReadDir("testdata/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("runner left files in the package directory: %v", entries)
+	}
+}
+
+func TestGenErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{"gen"},
+		{"gen", "--type", "./testdata/app.Missing"},
+		{"gen", "--type", "./testdata/app.config"},
+		{"gen", "--type", "./testdata/app.Config", "--style", "nope"},
+	} {
+		if code, _, stderr := runCommand(t, "", args...); code != 1 || !strings.HasPrefix(stderr, "yamlc: ") {
+			t.Errorf("%v: expected an error, got %d: %s", args, code, stderr)
+		}
+	}
+}
+
+func TestRestyle(t *testing.T) {
+	code, stdout, stderr := runCommand(t, "name: demo # 名称\n", "restyle", "--style", "top")
+	if code != 0 {
+		t.Fatalf("restyle exited with %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, "# 名称\nname: demo\n") {
+		t.Errorf("unexpected output:\n%s", stdout)
+	}
+}
+
+func TestLint(t *testing.T) {
+	for _, width := range []int{2, 4} {
+		file := writeFile(t, "nested.yaml", nestedYAML(width, 8))
+		if code, _, stderr := runCommand(t, "", "lint", file); code != 0 {
+			t.Errorf("width %d: lint should pass for deeply nested YAML, got %d: %s", width, code, stderr)
+		}
+	}
+
+	invalid := writeFile(t, "invalid.yaml", "a:\n  b: 1\n   c: 2\n")
+	code, _, stderr := runCommand(t, "", "lint", invalid)
+	if code != 1 || !strings.HasPrefix(stderr, invalid+": ") {
+		t.Errorf("lint should report the invalid file, got %d: %s", code, stderr)
+	}
+}
+
+func TestLintType(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a runner program")
+	}
+	valid := writeFile(t, "valid.yaml", "name: demo\nserver:\n  host: localhost\n  port: 8080\n")
+	if code, _, stderr := runCommand(t, "", "lint", "--type", "./testdata/app.Config", valid); code != 0 {
+		t.Errorf("lint should pass, got %d: %s", code, stderr)
+	}
+	unknown := writeFile(t, "unknown.yaml", "name: demo\nserver:\n  hostname: localhost\n")
+	if code, _, stderr := runCommand(t, "", "lint", "--type", "./testdata/app.Config", unknown); code != 1 || !strings.Contains(stderr, "hostname") {
+		t.Errorf("lint should report the unknown field, got %d: %s", code, stderr)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a runner program")
+	}
+	same := writeFile(t, "same.yaml", "name: demo\nserver:\n  host: localhost\n  port: 8080\n")
+	if code, stdout, stderr := runCommand(t, "", "diff", "--type", "./testdata/app.Config", same); code != 0 {
+		t.Errorf("diff should report no changes, got %d: %s%s", code, stdout, stderr)
+	}
+
+	changed := writeFile(t, "changed.yaml", "name: demo\nserver:\n  host: localhost\n")
+	code, stdout, stderr := runCommand(t, "", "diff", "--type", "./testdata/app.Config", changed)
+	if code != 1 || !strings.Contains(stdout, "server.port") {
+		t.Errorf("diff should report the missing field, got %d: %s%s", code, stdout, stderr)
+	}
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/template"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// target --type 指定的结构体
+type target struct {
+	pkgPath string
+	dir     string // 包所在目录
+	init    string // 运行器中取得结构体指针的表达式
+}
+
+// runnerTemplate 在目标包目录中临时运行的程序，按模式调用yamlc并把结果写到标准输出
+var runnerTemplate = template.Must(template.New("runner").Parse(`// Code generated by yamlc. DO NOT EDIT.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"binrc.com/pkg/yamlc"
+	target {{printf "%q" .pkgPath}}
+)
+
+func main() {
+	v := {{.init}}
+	if err := run(v, os.Args[1], os.Args[2]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(v interface{}, mode, arg string) error {
+	if mode == "gen" {
+		data, err := yamlc.Gen(v, yamlc.WithStyle(yamlc.GetStyleFromString(arg)))
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	data, err := os.ReadFile(arg)
+	if err != nil {
+		return err
+	}
+	if mode == "lint" {
+		if err := yamlc.ValidateAgainstStruct(data, v); err != nil {
+			return fmt.Errorf("%s: %w", arg, err)
+		}
+		return nil
+	}
+
+	report, err := yamlc.Diff(v, data)
+	if err != nil {
+		return err
+	}
+	if mode == "diff-unified" {
+		fmt.Print(report.Unified())
+	} else {
+		fmt.Print(report.String())
+	}
+	if report.HasChanges() {
+		os.Exit(1)
+	}
+	return nil
+}
+`))
+
+// runStruct 加载 typeSpec 指定的结构体，构建并运行临时程序，返回其标准输出
+// 临时程序以状态1退出时返回 *exec.ExitError，其错误信息已写到 stderr
+func runStruct(typeSpec, mode, arg string, stderr io.Writer) ([]byte, error) {
+	t, err := loadTarget(typeSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	var source bytes.Buffer
+	if err := runnerTemplate.Execute(&source, map[string]string{"pkgPath": t.pkgPath, "init": t.init}); err != nil {
+		return nil, fmt.Errorf("failed to render runner: %w", err)
+	}
+	dir, err := os.MkdirTemp("", "yamlc-run-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create runner directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	runner := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(runner, source.Bytes(), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write runner: %w", err)
+	}
+	// 运行器通过 -overlay 虚拟放在包目录下，使 internal 包也可以导入，而包目录中不会写入任何文件
+	pkgDir := filepath.Base(dir)
+	overlay, err := json.Marshal(map[string]map[string]string{
+		"Replace": {filepath.Join(t.dir, pkgDir, "main.go"): runner},
+	})
+	if err != nil {
+		return nil, err
+	}
+	overlayFile := filepath.Join(dir, "overlay.json")
+	if err := os.WriteFile(overlayFile, overlay, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write overlay: %w", err)
+	}
+
+	if mode != "gen" {
+		if arg, err = filepath.Abs(arg); err != nil {
+			return nil, err
+		}
+	}
+	// 先构建再运行，而不是 go run，使运行器的退出状态不附带 go 命令自己的输出
+	binary := filepath.Join(dir, "runner")
+	if runtime.GOOS == "windows" {
+		binary += ".exe"
+	}
+	build := exec.Command("go", "build", "-overlay", overlayFile, "-o", binary, "./"+pkgDir)
+	build.Dir = t.dir
+	build.Stderr = stderr
+	if err := build.Run(); err != nil {
+		return nil, fmt.Errorf("failed to build runner for %s: %w", t.pkgPath, err)
+	}
+
+	cmd := exec.Command(binary, mode, arg)
+	cmd.Stderr = stderr
+	return cmd.Output()
+}
+
+// loadTarget 用 go/packages 加载 "包路径.类型名" 中的包（只解析源文件，不依赖编译器导出数据的格式），
+// 检查类型是结构体并查找构造函数
+func loadTarget(typeSpec string) (*target, error) {
+	pattern, name := ".", typeSpec
+	if i := strings.LastIndex(typeSpec, "."); i >= 0 {
+		pattern, name = typeSpec[:i], typeSpec[i+1:]
+	}
+	if pattern == "" || !token.IsIdentifier(name) {
+		return nil, fmt.Errorf("invalid type %q, expected \"pkg.Type\"", typeSpec)
+	}
+	if !token.IsExported(name) {
+		return nil, fmt.Errorf("type %s is not exported", name)
+	}
+
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax}, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load package %q: %w", pattern, err)
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("pattern %q matched %d packages, expected one", pattern, len(pkgs))
+	}
+	pkg := pkgs[0]
+	if len(pkg.Errors) > 0 {
+		return nil, fmt.Errorf("failed to load package %q: %v", pattern, pkg.Errors[0])
+	}
+	if pkg.Name == "main" {
+		return nil, fmt.Errorf("package %q is a main package and cannot be imported", pkg.PkgPath)
+	}
+	if len(pkg.GoFiles) == 0 {
+		return nil, fmt.Errorf("package %q has no Go files", pkg.PkgPath)
+	}
+
+	spec := findType(pkg.Syntax, name)
+	if spec == nil {
+		return nil, fmt.Errorf("type %s not found in package %q", name, pkg.PkgPath)
+	}
+	if spec.TypeParams != nil {
+		return nil, fmt.Errorf("type %s.%s is generic", pkg.Name, name)
+	}
+	if _, ok := spec.Type.(*ast.StructType); !ok {
+		return nil, fmt.Errorf("type %s.%s is not a struct", pkg.Name, name)
+	}
+
+	return &target{
+		pkgPath: pkg.PkgPath,
+		dir:     filepath.Dir(pkg.GoFiles[0]),
+		init:    initExpr(pkg.Syntax, name),
+	}, nil
+}
+
+// findType 在包的源文件中查找类型声明
+func findType(files []*ast.File, name string) *ast.TypeSpec {
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, s := range gen.Specs {
+				if spec := s.(*ast.TypeSpec); spec.Name.Name == name && !spec.Assign.IsValid() {
+					return spec
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// initExpr 返回取得结构体指针的表达式：优先调用无参数、返回 T 或 *T 的 New<Type>/Default<Type>，否则取零值
+func initExpr(files []*ast.File, name string) string {
+	for _, prefix := range []string{"New", "Default"} {
+		for _, file := range files {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Recv != nil || fn.Name.Name != prefix+name || fn.Type.TypeParams != nil {
+					continue
+				}
+				if len(fn.Type.Params.List) != 0 || fn.Type.Results == nil || len(fn.Type.Results.List) != 1 ||
+					len(fn.Type.Results.List[0].Names) > 1 {
+					continue
+				}
+				switch result := fn.Type.Results.List[0].Type.(type) {
+				case *ast.Ident:
+					if result.Name == name {
+						return fmt.Sprintf("func() *target.%s { v := target.%s(); return &v }()", name, fn.Name.Name)
+					}
+				case *ast.StarExpr:
+					if ident, ok := result.X.(*ast.Ident); ok && ident.Name == name {
+						return fmt.Sprintf("target.%s()", fn.Name.Name)
+					}
+				}
+			}
+		}
+	}
+	return fmt.Sprintf("&target.%s{}", name)
+}
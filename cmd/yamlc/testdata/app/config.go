@@ -0,0 +1,19 @@
+// Package app 供命令行测试加载的配置结构体
+package app
+
+// Server 服务地址
+type Server struct {
+	Host string `yaml:"host" yamlc:"comment=主机"`
+	Port int    `yaml:"port" yamlc:"comment=端口"`
+}
+
+// Config 测试配置
+type Config struct {
+	Name   string `yaml:"name" yamlc:"comment=名称"`
+	Server Server `yaml:"server" yamlc:"comment=服务"`
+}
+
+// NewConfig 返回默认配置
+func NewConfig() *Config {
+	return &Config{Name: "demo", Server: Server{Host: "localhost", Port: 8080}}
+}
@@ -0,0 +1,56 @@
+// Command yamlcgen 从带注释的YAML文件生成Go结构体定义
+//
+// 用法:
+//
+//	yamlcgen -in config.yaml -out config_gen.go -package config -type Config
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"binrc.com/pkg/yamlc/yamlcgen"
+)
+
+func main() {
+	in := flag.String("in", "", "input YAML file (default stdin)")
+	out := flag.String("out", "", "output Go file (default stdout)")
+	pkg := flag.String("package", yamlcgen.DefaultPackage, "package name of the generated code")
+	typeName := flag.String("type", yamlcgen.DefaultTypeName, "name of the top-level struct")
+	flag.Parse()
+
+	if err := run(*in, *out, *pkg, *typeName); err != nil {
+		fmt.Fprintln(os.Stderr, "yamlcgen:", err)
+		os.Exit(1)
+	}
+}
+
+// run 读取输入、生成代码并写出
+func run(in, out, pkg, typeName string) error {
+	var data []byte
+	var err error
+	if in == "" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(in)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read input: %w", err)
+	}
+
+	source, err := yamlcgen.Generate(data, pkg, typeName)
+	if err != nil {
+		return err
+	}
+
+	if out == "" {
+		_, err = os.Stdout.Write(source)
+		return err
+	}
+	if err := os.WriteFile(out, source, 0644); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
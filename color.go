@@ -0,0 +1,180 @@
+package yamlc
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ColorMode Fprint 输出ANSI颜色的方式
+type ColorMode int
+
+const (
+	// ColorAuto 写入终端且未设置 NO_COLOR、TERM 不为 dumb 时使用颜色（默认）
+	ColorAuto ColorMode = iota
+	// ColorAlways 总是使用颜色
+	ColorAlways
+	// ColorNever 不使用颜色
+	ColorNever
+)
+
+// 各部分使用的ANSI颜色
+const (
+	colorKey     = "\x1b[36m"
+	colorValue   = "\x1b[32m"
+	colorComment = "\x1b[90m"
+	colorMarker  = "\x1b[35m"
+	colorReset   = "\x1b[0m"
+)
+
+// WithColor 设置 Fprint 是否使用ANSI颜色，对 Gen、Write 等其他输出没有影响
+func WithColor(mode ColorMode) Option {
+	return func(o *Options) {
+		o.Color = mode
+	}
+}
+
+// Fprint 使用默认配置将带颜色的YAML写入w
+func Fprint(w io.Writer, v interface{}, opts ...Option) error {
+	return Default().Fprint(w, v, opts...)
+}
+
+// Fprint 按该配置生成YAML并以不同颜色区分键、值和注释后写入w，用于命令行的 --dry-run 和预览
+// 是否使用颜色由 WithColor 决定，去掉颜色后的内容与 Gen 的结果相同
+func (c *Config) Fprint(w io.Writer, v interface{}, opts ...Option) error {
+	if w == nil {
+		return fmt.Errorf("writer cannot be nil")
+	}
+	options := c.newOptions(opts...)
+	data, err := generate(v, options)
+	if err != nil {
+		return err
+	}
+	if useColor(w, options.Color) {
+		data = []byte(colorize(string(data)))
+	}
+	return writeData(w, data)
+}
+
+// useColor 判断是否使用颜色
+func useColor(w io.Writer, mode ColorMode) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if _, ok := os.LookupEnv("NO_COLOR"); ok || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// colorize 逐行为YAML文本加上颜色，注释与引号的识别方式与 StripComments 相同
+func colorize(text string) string {
+	scanner := &commentScanner{blockIndent: -1}
+	var b strings.Builder
+	b.Grow(len(text) * 2)
+	for _, line := range strings.SplitAfter(text, "\n") {
+		content := strings.TrimRight(line, "\r\n")
+		newline := line[len(content):]
+
+		trimmed := strings.TrimLeft(content, " \t")
+		indent := len(content) - len(trimmed)
+		if scanner.blockIndent >= 0 {
+			if trimmed == "" || indent > scanner.blockIndent {
+				b.WriteString(content[:indent])
+				writeColored(&b, colorValue, trimmed)
+				b.WriteString(newline)
+				continue
+			}
+			scanner.blockIndent = -1
+		}
+
+		inQuote := scanner.quote != 0
+		code := scanner.stripLine(content)
+		comment := content[len(code):]
+		if !inQuote && isBlockScalarHeader(strings.TrimRight(code, " \t")) {
+			scanner.blockIndent = indent
+		}
+
+		switch {
+		case inQuote:
+			b.WriteString(code[:indent])
+			writeColored(&b, colorValue, code[indent:])
+		case code == "---" || code == "..." || strings.HasPrefix(code, "%"):
+			writeColored(&b, colorMarker, code)
+		default:
+			colorizeEntry(&b, code)
+		}
+		writeColored(&b, colorComment, comment)
+		b.WriteString(newline)
+	}
+	return b.String()
+}
+
+// colorizeEntry 为一行中注释以外的部分加上颜色：列表标记保持原样，"键:" 使用键的颜色，其余为值
+func colorizeEntry(b *strings.Builder, code string) {
+	rest := strings.TrimLeft(code, " \t")
+	b.WriteString(code[:len(code)-len(rest)])
+	for rest == "-" || strings.HasPrefix(rest, "- ") {
+		b.WriteString("-")
+		rest = rest[1:]
+		trimmed := strings.TrimLeft(rest, " ")
+		b.WriteString(rest[:len(rest)-len(trimmed)])
+		rest = trimmed
+	}
+
+	if end := keyEnd(rest); end > 0 {
+		writeColored(b, colorKey, rest[:end])
+		b.WriteString(":")
+		rest = rest[end+1:]
+	}
+	value := strings.TrimLeft(rest, " ")
+	b.WriteString(rest[:len(rest)-len(value)])
+	trailing := value[len(strings.TrimRight(value, " \t")):]
+	writeColored(b, colorValue, value[:len(value)-len(trailing)])
+	b.WriteString(trailing)
+}
+
+// keyEnd 返回映射键之后冒号的位置，不是 "键: 值" 形式时返回-1
+func keyEnd(s string) int {
+	if s == "" || s[0] == '[' || s[0] == '{' {
+		return -1
+	}
+	var quote byte
+	if s[0] == '"' || s[0] == '\'' {
+		quote = s[0]
+	}
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if quote != 0 {
+			if quote == '"' && c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		if c == ':' && (i+1 == len(s) || s[i+1] == ' ' || s[i+1] == '\t') {
+			return i
+		}
+	}
+	return -1
+}
+
+// writeColored 写入带颜色的文本，空文本不写入颜色代码
+func writeColored(b *strings.Builder, color, text string) {
+	if text == "" {
+		return
+	}
+	b.WriteString(color)
+	b.WriteString(text)
+	b.WriteString(colorReset)
+}
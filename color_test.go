@@ -0,0 +1,73 @@
+package yamlc
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+type colorConfig struct {
+	Name    string   `yaml:"name"    yamlc:"comment=服务名称"`
+	URL     string   `yaml:"url"     yamlc:"comment=地址"`
+	Motd    string   `yaml:"motd"    yamlc:"comment=欢迎语"`
+	Servers []string `yaml:"servers" yamlc:"comment=服务器列表"`
+}
+
+func TestFprint(t *testing.T) {
+	cfg := &colorConfig{Name: "demo", URL: "http://a#b", Motd: "line1\nline2", Servers: []string{"a", "b"}}
+
+	for _, style := range []CommentStyle{StyleTop, StyleInline} {
+		var buf bytes.Buffer
+		if err := Fprint(&buf, cfg, WithStyle(style), WithColor(ColorAlways), WithDocumentStart()); err != nil {
+			t.Fatalf("Fprint failed: %v", err)
+		}
+		plain, err := Gen(cfg, WithStyle(style), WithDocumentStart())
+		if err != nil {
+			t.Fatalf("Gen failed: %v", err)
+		}
+		colored := buf.String()
+		if got := ansiPattern.ReplaceAllString(colored, ""); got != string(plain) {
+			t.Errorf("stripped output differs from Gen:\n%s\nwant:\n%s", got, plain)
+		}
+		for _, want := range []string{
+			colorKey + "name" + colorReset + ":",
+			colorValue + "demo" + colorReset,
+			colorComment + "# 服务名称",
+			colorMarker + "---" + colorReset,
+		} {
+			if !strings.Contains(colored, want) {
+				t.Errorf("expected %q in output:\n%q", want, colored)
+			}
+		}
+		if strings.Contains(colored, colorComment+"#b") {
+			t.Errorf("value colored as comment:\n%q", colored)
+		}
+		if !strings.Contains(colored, colorValue+"line2"+colorReset) {
+			t.Errorf("block scalar content not colored as value:\n%q", colored)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := Fprint(&buf, cfg, WithColor(ColorNever)); err != nil {
+		t.Fatalf("Fprint failed: %v", err)
+	}
+	if ansiPattern.MatchString(buf.String()) {
+		t.Errorf("unexpected color codes with ColorNever:\n%q", buf.String())
+	}
+
+	// 写入非终端时自动模式不使用颜色
+	buf.Reset()
+	if err := Fprint(&buf, cfg); err != nil {
+		t.Fatalf("Fprint failed: %v", err)
+	}
+	if ansiPattern.MatchString(buf.String()) {
+		t.Errorf("unexpected color codes with ColorAuto:\n%q", buf.String())
+	}
+
+	if err := Fprint(nil, cfg); err == nil {
+		t.Error("expected error for nil writer")
+	}
+}
@@ -0,0 +1,25 @@
+package yamlc
+
+import "sort"
+
+// CommentLayer 带优先级的注释映射层
+type CommentLayer struct {
+	// Priority 优先级，越大越优先
+	Priority int
+	// Comments 字段路径（支持通配符）到注释的映射
+	Comments map[string]string
+}
+
+// WithCommentsLayer 添加一层按字段路径设置的注释，priority 越大越优先，相同优先级时后添加的优先
+// 所有注释层都优先于结构体标签中的注释，适合组合平台默认注释、团队覆盖和单个实例的注释
+func WithCommentsLayer(priority int, comments map[string]string) Option {
+	return func(o *Options) {
+		// 保持按优先级从高到低排列，相同优先级时新添加的排在前面
+		i := sort.Search(len(o.CommentLayers), func(i int) bool {
+			return o.CommentLayers[i].Priority <= priority
+		})
+		o.CommentLayers = append(o.CommentLayers, CommentLayer{})
+		copy(o.CommentLayers[i+1:], o.CommentLayers[i:])
+		o.CommentLayers[i] = CommentLayer{Priority: priority, Comments: comments}
+	}
+}
@@ -0,0 +1,81 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+)
+
+type layeredServer struct {
+	Host string `yaml:"host" comment:"标签注释"`
+	Port int    `yaml:"port" comment:"端口"`
+}
+
+type layeredConfig struct {
+	Servers []layeredServer `yaml:"servers"`
+	Name    string          `yaml:"name" comment:"名称"`
+}
+
+func TestWithCommentsLayer(t *testing.T) {
+	cfg := layeredConfig{Servers: []layeredServer{{Host: "a", Port: 1}}, Name: "demo"}
+
+	platform := map[string]string{"servers.*.host": "平台默认", "servers.*.port": "平台端口", "name": "平台名称"}
+	team := map[string]string{"servers.*.host": "团队覆盖"}
+	instance := map[string]string{"servers.0.host": "实例注释"}
+
+	// 添加顺序与优先级无关
+	data, err := Gen(cfg,
+		WithCommentsLayer(100, instance),
+		WithCommentsLayer(0, platform),
+		WithCommentsLayer(10, team),
+	)
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	yamlStr := string(data)
+	for _, expected := range []string{"# 实例注释", "# 平台端口", "# 平台名称"} {
+		if !strings.Contains(yamlStr, expected) {
+			t.Errorf("expected %q in:\n%s", expected, yamlStr)
+		}
+	}
+
+	// 高优先级层的通配符优先于低优先级层的精确路径
+	data, err = Gen(cfg, WithCommentsLayer(0, instance), WithCommentsLayer(10, team))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if !strings.Contains(string(data), "# 团队覆盖") || strings.Contains(string(data), "实例注释") {
+		t.Errorf("expected team layer to win:\n%s", data)
+	}
+}
+
+func TestWithCommentLaterWins(t *testing.T) {
+	cfg := layeredConfig{Name: "demo"}
+	data, err := Gen(cfg,
+		WithComment(map[string]string{"name": "先添加"}),
+		WithComment(map[string]string{"name": "后添加"}),
+	)
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if !strings.Contains(string(data), "# 后添加\nname: demo") {
+		t.Errorf("expected later comment to win:\n%s", data)
+	}
+
+	// 直接设置的 Comments 优先级低于注释层，其中后面的映射优先
+	options := newOptions(WithComment(map[string]string{"name": "注释层"}))
+	options.Comments = []map[string]string{{"name": "旧"}, {"name": "新", "port": "端口"}}
+	if comment, _ := lookupComment("name", options); comment != "注释层" {
+		t.Errorf("expected comment layer to win, got %q", comment)
+	}
+	if comment, _ := lookupComment("port", options); comment != "端口" {
+		t.Errorf("expected comment from Comments, got %q", comment)
+	}
+	options.CommentLayers = nil
+	if comment, _ := lookupComment("name", options); comment != "新" {
+		t.Errorf("expected later Comments map to win, got %q", comment)
+	}
+
+	if err := ValidateOptions(newOptions(WithCommentsLayer(1, nil))); err == nil {
+		t.Error("expected error for nil comment layer")
+	}
+}
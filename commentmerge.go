@@ -0,0 +1,38 @@
+package yamlc
+
+// CommentMergePolicy 选项中的注释与结构体标签中的注释同时存在时的合并方式
+type CommentMergePolicy int
+
+const (
+	// CommentReplace 选项中的注释替换标签注释（默认）
+	CommentReplace CommentMergePolicy = iota
+	// CommentAppend 选项中的注释追加在标签注释之后
+	CommentAppend
+	// CommentPrepend 选项中的注释放在标签注释之前
+	CommentPrepend
+)
+
+// WithCommentMergePolicy 设置选项中的注释（WithComment、注释层、受众注释）与标签注释的合并方式，
+// 如在标签文档之后追加 "(overridden by ops)"
+func WithCommentMergePolicy(policy CommentMergePolicy) Option {
+	return func(o *Options) {
+		o.CommentMerge = policy
+	}
+}
+
+// mergeComment 按合并方式组合选项中的注释和标签注释
+func mergeComment(optionComment, tagComment string, options *Options) string {
+	if optionComment == "" || tagComment == "" {
+		if options.CommentMerge == CommentReplace {
+			return optionComment
+		}
+		return optionComment + tagComment
+	}
+	switch options.CommentMerge {
+	case CommentAppend:
+		return tagComment + " " + optionComment
+	case CommentPrepend:
+		return optionComment + " " + tagComment
+	}
+	return optionComment
+}
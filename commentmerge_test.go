@@ -0,0 +1,58 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithCommentMergePolicy(t *testing.T) {
+	type Config struct {
+		Replicas int    `yaml:"replicas" comment:"副本数"`
+		Region   string `yaml:"region"`
+	}
+	cfg := Config{Replicas: 3, Region: "cn"}
+	comments := map[string]string{"replicas": "(overridden by ops)", "region": "部署区域"}
+
+	tests := []struct {
+		name     string
+		policy   CommentMergePolicy
+		expected string
+	}{
+		{"replace", CommentReplace, "# (overridden by ops)\nreplicas: 3\n"},
+		{"append", CommentAppend, "# 副本数 (overridden by ops)\nreplicas: 3\n"},
+		{"prepend", CommentPrepend, "# (overridden by ops) 副本数\nreplicas: 3\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Gen(cfg, WithComment(comments), WithCommentMergePolicy(tt.policy))
+			if err != nil {
+				t.Fatalf("Gen failed: %v", err)
+			}
+			yamlStr := string(data)
+			if !strings.Contains(yamlStr, tt.expected) {
+				t.Errorf("expected %q in:\n%s", tt.expected, yamlStr)
+			}
+			// 没有标签注释的字段不受合并方式影响
+			if !strings.Contains(yamlStr, "# 部署区域\nregion: cn\n") {
+				t.Errorf("expected option comment for region in:\n%s", yamlStr)
+			}
+		})
+	}
+
+	// 空的选项注释在替换方式下去掉标签注释，合并方式下保留标签注释
+	suppress := map[string]string{"replicas": ""}
+	data, err := Gen(cfg, WithComment(suppress))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if strings.Contains(string(data), "副本数") {
+		t.Errorf("expected tag comment to be replaced:\n%s", data)
+	}
+	data, err = Gen(cfg, WithComment(suppress), WithCommentMergePolicy(CommentAppend))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if !strings.Contains(string(data), "# 副本数\nreplicas: 3\n") {
+		t.Errorf("expected tag comment to be kept:\n%s", data)
+	}
+}
@@ -0,0 +1,123 @@
+package yamlc
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+)
+
+// Config 持有一组默认选项的生成配置，创建后不可修改，可在多个goroutine中并发使用
+// 不同的服务或租户使用各自的 Config，互不影响；包级函数使用默认配置
+type Config struct {
+	options []Option
+	// style SetGlobalStyle 设置的风格，在 options 之后应用；重复设置时替换该值，不追加选项
+	style *CommentStyle
+}
+
+// defaultConfig 包级函数使用的默认配置
+var defaultConfig atomic.Pointer[Config]
+
+func init() {
+	defaultConfig.Store(NewConfig())
+}
+
+// NewConfig 创建以 opts 为默认选项的配置
+func NewConfig(opts ...Option) *Config {
+	return &Config{options: append([]Option(nil), opts...)}
+}
+
+// Default 返回包级函数使用的默认配置
+func Default() *Config {
+	return defaultConfig.Load()
+}
+
+// SetDefault 替换包级函数使用的默认配置，c 为nil时恢复为没有默认选项的配置
+func SetDefault(c *Config) {
+	if c == nil {
+		c = NewConfig()
+	}
+	defaultConfig.Store(c)
+}
+
+// With 返回在当前默认选项之后追加 opts 的新配置，原配置不变
+func (c *Config) With(opts ...Option) *Config {
+	options := make([]Option, 0, len(c.options)+len(opts)+1)
+	options = append(options, c.options...)
+	if c.style != nil {
+		// 先前设置的风格需保持在 opts 之前，opts 中的 WithStyle 仍然生效
+		options = append(options, WithStyle(*c.style))
+	}
+	return &Config{options: append(options, opts...)}
+}
+
+// withStyle 返回替换了风格的新配置，默认选项不变
+func (c *Config) withStyle(style CommentStyle) *Config {
+	return &Config{options: c.options, style: &style}
+}
+
+// Style 返回该配置的注释风格
+func (c *Config) Style() CommentStyle {
+	return c.newOptions().Style
+}
+
+// Gen 按该配置生成YAML内容，opts 在默认选项之后应用
+func (c *Config) Gen(v interface{}, opts ...Option) ([]byte, error) {
+	return generate(v, c.newOptions(opts...))
+}
+
+// Write 按该配置生成YAML内容并写入到io.Writer
+func (c *Config) Write(w io.Writer, v interface{}, opts ...Option) error {
+	if w == nil {
+		return fmt.Errorf("writer cannot be nil")
+	}
+
+	data, err := c.Gen(v, opts...)
+	if err != nil {
+		return err
+	}
+	return writeData(w, data)
+}
+
+// WriteFile 按该配置生成YAML内容并写入到文件，文件已存在时按 WithOverwritePolicy 的策略处理
+func (c *Config) WriteFile(filename string, v interface{}, opts ...Option) error {
+	if filename == "" {
+		return fmt.Errorf("filename cannot be empty")
+	}
+
+	options := c.newOptions(opts...)
+	if write, err := shouldWrite(filename, os.ReadFile, options); !write || err != nil {
+		return err
+	}
+	data, err := generate(v, options)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file %q: %w", filename, err)
+	}
+	defer file.Close()
+
+	return writeData(file, fileContent(data, options))
+}
+
+// newOptions 以该配置的默认选项构建选项，opts 在默认选项之后应用
+func (c *Config) newOptions(opts ...Option) *Options {
+	options := &Options{
+		Style:    GlobalCommentStyle,
+		Comments: make([]map[string]string, 0),
+	}
+	for _, opt := range c.options {
+		opt(options)
+	}
+	if c.style != nil {
+		options.Style = *c.style
+	}
+	startWarnings(options)
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
@@ -0,0 +1,133 @@
+package yamlc
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+type configServer struct {
+	Host string `yaml:"host" comment:"主机"`
+	Port int    `yaml:"port" comment:"端口"`
+}
+
+func TestConfig(t *testing.T) {
+	server := configServer{Host: "localhost", Port: 8080}
+	inline := NewConfig(WithStyle(StyleInline))
+	minimal := NewConfig(WithStyle(StyleMinimal))
+
+	expectedInline, err := Gen(server, WithStyle(StyleInline))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	expectedMinimal, err := Gen(server, WithStyle(StyleMinimal))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+
+	// 不同配置并发生成，互不影响
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if data, err := inline.Gen(server); err != nil || !bytes.Equal(data, expectedInline) {
+				t.Errorf("inline config mismatch: %v\n%s", err, data)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if data, err := minimal.Gen(server); err != nil || !bytes.Equal(data, expectedMinimal) {
+				t.Errorf("minimal config mismatch: %v\n%s", err, data)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 调用时的选项在默认选项之后应用
+	data, err := inline.Gen(server, WithStyle(StyleMinimal))
+	if err != nil || !bytes.Equal(data, expectedMinimal) {
+		t.Errorf("per-call options should override defaults: %v\n%s", err, data)
+	}
+
+	derived := inline.With(WithStyle(StyleTop))
+	if derived.Style() != StyleTop || inline.Style() != StyleInline {
+		t.Errorf("With should not modify the original config")
+	}
+
+	var buf bytes.Buffer
+	if err := inline.Write(&buf, server); err != nil || !bytes.Equal(buf.Bytes(), expectedInline) {
+		t.Errorf("Write mismatch: %v", err)
+	}
+	filename := filepath.Join(t.TempDir(), "server.yaml")
+	if err := minimal.WriteFile(filename, server); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if content, err := os.ReadFile(filename); err != nil || !bytes.Equal(content, expectedMinimal) {
+		t.Errorf("WriteFile mismatch: %v", err)
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	original := Default()
+	defer SetDefault(original)
+
+	SetDefault(NewConfig(WithStyle(StyleCompact), WithIndent(4)))
+	if GetStyle() != StyleCompact {
+		t.Errorf("expected compact default style, got %s", GetStyleString(int(GetStyle())))
+	}
+
+	// SetGlobalStyle 只替换风格，保留其余默认选项
+	SetGlobalStyle(StyleTop)
+	data, err := Gen(map[string]configServer{"a": {Host: "h", Port: 1}})
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if !bytes.Contains(data, []byte("\n    host: h\n")) {
+		t.Errorf("expected default indent to be kept:\n%s", data)
+	}
+
+	SetDefault(nil)
+	if GetStyle() != StyleTop {
+		t.Errorf("expected top style after reset")
+	}
+}
+
+func TestSetGlobalStyleReplaces(t *testing.T) {
+	original := Default()
+	defer SetDefault(original)
+
+	SetDefault(NewConfig(WithIndent(4)))
+	for i := 0; i < 100; i++ {
+		SetGlobalStyle(CommentStyle(i % 2))
+	}
+	if n := len(Default().options); n != 1 {
+		t.Errorf("SetGlobalStyle should replace the style, got %d default options", n)
+	}
+	if GetStyle() != StyleInline {
+		t.Errorf("expected inline style, got %s", GetStyleString(int(GetStyle())))
+	}
+
+	// 之后追加的 WithStyle 优先于 SetGlobalStyle 设置的风格
+	if style := Default().With(WithStyle(StyleCompact)).Style(); style != StyleCompact {
+		t.Errorf("expected compact style, got %s", GetStyleString(int(style)))
+	}
+	if style := Default().With(WithIndent(2)).Style(); style != StyleInline {
+		t.Errorf("expected inline style to be kept, got %s", GetStyleString(int(style)))
+	}
+}
+
+func TestGlobalCommentStyleDeprecated(t *testing.T) {
+	original := GlobalCommentStyle
+	defer func() { GlobalCommentStyle = original }()
+
+	GlobalCommentStyle = StyleVerbose
+	if style := NewConfig().Style(); style != StyleVerbose {
+		t.Errorf("expected GlobalCommentStyle to be used, got %s", GetStyleString(int(style)))
+	}
+	if style := NewConfig(WithStyle(StyleTop)).Style(); style != StyleTop {
+		t.Errorf("WithStyle should take precedence, got %s", GetStyleString(int(style)))
+	}
+}
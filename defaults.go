@@ -0,0 +1,42 @@
+package yamlc
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// markDefaultFields 在注释默认值风格下标记取默认值的标量字段，这些字段整行注释输出
+// 默认值优先取 default 标签，其次取类型实现 Defaulter 时设置的值，否则为零值
+func markDefaultFields(val reflect.Value, fields []FieldInfo, options *Options) {
+	if options.Style != StyleCommentedDefaults {
+		return
+	}
+	defaults := defaultInstance(val.Type())
+	for i := range fields {
+		field := &fields[i]
+		if field.secret || field.substituted || field.HasChildren || len(field.FieldType.Index) == 0 {
+			continue
+		}
+		field.atDefault = isDefaultValue(*field, defaults.FieldByIndex(field.FieldType.Index))
+	}
+}
+
+// defaultInstance 创建类型的默认实例，实现 Defaulter 时调用 SetDefaults
+func defaultInstance(typ reflect.Type) reflect.Value {
+	value := reflect.New(typ)
+	if defaulter, ok := value.Interface().(Defaulter); ok {
+		defaulter.SetDefaults()
+	}
+	return value.Elem()
+}
+
+// isDefaultValue 判断字段值是否等于默认值
+func isDefaultValue(field FieldInfo, defaultValue reflect.Value) bool {
+	if !field.Field.IsValid() || !field.Field.CanInterface() {
+		return false
+	}
+	if tag, ok := getDefaultValue(field); ok {
+		return fmt.Sprint(field.Field.Interface()) == tag
+	}
+	return reflect.DeepEqual(field.Field.Interface(), defaultValue.Interface())
+}
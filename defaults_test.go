@@ -0,0 +1,61 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type defaultsLimits struct {
+	Timeout time.Duration `yaml:"timeout" comment:"超时" yamlc:"default=30s"`
+	Retries int           `yaml:"retries" comment:"重试次数"`
+}
+
+type defaultsConfig struct {
+	Workers int            `yaml:"workers" comment:"工作进程数"`
+	Bind    string         `yaml:"bind" comment:"监听地址"`
+	Level   string         `yaml:"level" comment:"日志级别"`
+	Limits  defaultsLimits `yaml:"limits" comment:"限制"`
+}
+
+func (c *defaultsConfig) SetDefaults() {
+	c.Workers = 4
+	c.Bind = "127.0.0.1"
+}
+
+func TestCommentedDefaultsStyle(t *testing.T) {
+	cfg := defaultsConfig{
+		Workers: 4,
+		Bind:    "0.0.0.0",
+		Limits:  defaultsLimits{Timeout: 30 * time.Second, Retries: 3},
+	}
+
+	data, err := Gen(cfg, WithStyle(StyleCommentedDefaults))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	yamlStr := string(data)
+	for _, expected := range []string{
+		"# 工作进程数\n# workers: 4\n",
+		"# 监听地址\nbind: 0.0.0.0\n",
+		"# 日志级别\n# level: \"\"\n",
+		"  # 超时\n  # timeout: 30000000000\n",
+		"  # 重试次数\n  retries: 3\n",
+	} {
+		if !strings.Contains(yamlStr, expected) {
+			t.Errorf("expected %q in:\n%s", expected, yamlStr)
+		}
+	}
+
+	// 注释掉的字段读取时回到默认值
+	decoded := defaultsConfig{}
+	decoded.SetDefaults()
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded.Workers != 4 || decoded.Bind != "0.0.0.0" || decoded.Limits.Retries != 3 {
+		t.Errorf("unexpected decoded config: %+v", decoded)
+	}
+}
@@ -0,0 +1,39 @@
+package yamlc
+
+// WithStylePerDepth 按嵌套层级设置注释风格，0表示顶层字段，每进入一层结构体或映射加1（列表不计层级）
+// 未列出的层级沿用上一层的风格；字段级风格覆盖（style= 标签或 WithFieldStyleOverride）优先
+func WithStylePerDepth(styles map[int]CommentStyle) Option {
+	return func(o *Options) {
+		if o.StylePerDepth == nil {
+			o.StylePerDepth = make(map[int]CommentStyle)
+		}
+		for depth, style := range styles {
+			o.StylePerDepth[depth] = style
+		}
+	}
+}
+
+// levelOptions 返回当前结构体或映射层级使用的选项，应用该层级的风格并将层级加1
+func levelOptions(options *Options) *Options {
+	level := *options
+	if style, ok := options.StylePerDepth[options.level]; ok && !options.styleFixed {
+		level.Style = style
+	}
+	level.level++
+	return &level
+}
+
+// usesStyle 判断文档中是否会用到指定风格之一
+func usesStyle(options *Options, styles ...CommentStyle) bool {
+	for _, style := range styles {
+		if options.Style == style {
+			return true
+		}
+		for _, depthStyle := range options.StylePerDepth {
+			if depthStyle == style {
+				return true
+			}
+		}
+	}
+	return false
+}
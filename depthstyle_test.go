@@ -0,0 +1,53 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+type depthDatabase struct {
+	Host string `yaml:"host" comment:"主机"`
+	Port int    `yaml:"port" comment:"端口"`
+}
+
+type depthConfig struct {
+	Name     string          `yaml:"name" comment:"名称"`
+	Database depthDatabase   `yaml:"database" comment:"数据库"`
+	Replicas []depthDatabase `yaml:"replicas" comment:"副本"`
+	Cache    depthDatabase   `yaml:"cache" comment:"缓存" yamlc:"style=top"`
+}
+
+func TestWithStylePerDepth(t *testing.T) {
+	cfg := depthConfig{
+		Name:     "demo",
+		Database: depthDatabase{Host: "db", Port: 5432},
+		Replicas: []depthDatabase{{Host: "r1", Port: 5432}},
+		Cache:    depthDatabase{Host: "redis", Port: 6379},
+	}
+
+	data, err := Gen(cfg, WithStylePerDepth(map[int]CommentStyle{0: StyleSpaced, 1: StyleInline}))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	yamlStr := string(data)
+	for _, expected := range []string{
+		"# 名称\nname: demo\n\n# 数据库\ndatabase:\n",
+		"  host: db      # 主机\n",
+		"  - host: r1    # 主机\n",
+		"  # 主机\n  host: redis\n",
+	} {
+		if !strings.Contains(yamlStr, expected) {
+			t.Errorf("expected %q in:\n%s", expected, yamlStr)
+		}
+	}
+
+	var decoded depthConfig
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded.Database != cfg.Database || decoded.Cache != cfg.Cache || decoded.Replicas[0] != cfg.Replicas[0] {
+		t.Errorf("per-depth styles did not round-trip: %+v", decoded)
+	}
+}
@@ -0,0 +1,354 @@
+package yamlc
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DiffKind 差异类型
+type DiffKind int
+
+const (
+	// DiffAdded 结构体中有、文件中没有的键（如新版本增加的配置项）
+	DiffAdded DiffKind = iota
+	// DiffRemoved 文件中有、结构体中没有的键（如已废弃的配置项）
+	DiffRemoved
+	// DiffChanged 两边都有但值不同
+	DiffChanged
+)
+
+// String 返回差异类型的名称
+func (k DiffKind) String() string {
+	switch k {
+	case DiffAdded:
+		return "added"
+	case DiffRemoved:
+		return "removed"
+	case DiffChanged:
+		return "changed"
+	}
+	return "unknown"
+}
+
+// DiffEntry 一条差异
+type DiffEntry struct {
+	Kind DiffKind
+	// Path 字段路径，列表元素使用下标，如 "servers.0.port"
+	Path string
+	// Old 文件中的值，DiffAdded 时为nil
+	Old interface{}
+	// New 结构体中的值，DiffRemoved 时为nil
+	New interface{}
+}
+
+// String 返回差异的单行描述
+func (e DiffEntry) String() string {
+	switch e.Kind {
+	case DiffAdded:
+		return fmt.Sprintf("+ %s: %s", e.Path, diffValueString(e.New))
+	case DiffRemoved:
+		return fmt.Sprintf("- %s: %s", e.Path, diffValueString(e.Old))
+	}
+	return fmt.Sprintf("~ %s: %s -> %s", e.Path, diffValueString(e.Old), diffValueString(e.New))
+}
+
+// DiffReport 结构体与配置文件之间的差异
+type DiffReport struct {
+	Entries []DiffEntry
+
+	fileData   []byte
+	structData []byte
+}
+
+// HasChanges 是否存在差异
+func (r *DiffReport) HasChanges() bool {
+	return len(r.Entries) > 0
+}
+
+// String 按行列出所有差异，"+" 为新增，"-" 为删除，"~" 为修改
+func (r *DiffReport) String() string {
+	var b strings.Builder
+	for _, entry := range r.Entries {
+		b.WriteString(entry.String())
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Unified 以统一diff格式输出文件内容与结构体生成内容之间的差异，没有差异时返回空字符串
+func (r *DiffReport) Unified() string {
+	return unifiedDiff(r.fileData, r.structData, "file", "struct")
+}
+
+// Diff 比较内存中的结构体（如带有新默认值的配置）与磁盘上的配置文件，报告新增、删除和修改的键，用于安全升级配置
+// 值的比较先将文件解码为结构体的类型再重新生成，因此 "30s" 与 30000000000 这类写法不同但含义相同的值不算修改；
+// 敏感字段按原值比较，差异条目和 Unified 中结构体一侧的值仍然屏蔽
+func Diff(v interface{}, data []byte) (*DiffReport, error) {
+	if v == nil {
+		return nil, ErrNilInput
+	}
+	structData, err := Gen(v, WithStyle(StyleTop))
+	if err != nil {
+		return nil, err
+	}
+	maskedTree, err := decodeTree(structData)
+	if err != nil {
+		return nil, err
+	}
+	rawData, err := Gen(v, WithStyle(StyleTop), withRevealedSecrets())
+	if err != nil {
+		return nil, err
+	}
+	structTree, err := decodeTree(rawData)
+	if err != nil {
+		return nil, err
+	}
+	fileTree, err := decodeTree(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// 文件按结构体类型解码后重新生成，用于比较值
+	normalizedTree := fileTree
+	typ := reflect.TypeOf(v)
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	decoded := reflect.New(typ)
+	if err := decodeYAML(data, decoded.Interface(), &Options{}); err == nil {
+		if normalized, err := Gen(decoded.Interface(), WithStyle(StyleTop), withRevealedSecrets()); err == nil {
+			if tree, err := decodeTree(normalized); err == nil {
+				normalizedTree = tree
+			}
+		}
+	}
+
+	report := &DiffReport{fileData: data, structData: structData}
+	diffTrees(structTree, maskedTree, fileTree, normalizedTree, "", report)
+	return report, nil
+}
+
+// withRevealedSecrets 敏感字段输出原值，用于比较而不是展示
+func withRevealedSecrets() Option {
+	return func(o *Options) {
+		o.revealSecrets = true
+	}
+}
+
+// decodeTree 将YAML解码为通用的值树，空文档视为空映射
+func decodeTree(data []byte) (interface{}, error) {
+	var tree interface{}
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("YAML parsing error: %w", err)
+	}
+	if tree == nil {
+		tree = map[string]interface{}{}
+	}
+	return tree, nil
+}
+
+// diffTrees 递归比较结构体的值树和文件的值树；masked 为结构体屏蔽敏感字段后的值树，与原值不同的值是敏感值，
+// 差异中两边都以屏蔽后的值展示；normalized 为文件按结构体类型规范化后的值，用于判断是否修改
+func diffTrees(structValue, masked, fileValue, normalized interface{}, fieldPath string, report *DiffReport) {
+	structMap, structIsMap := structValue.(map[string]interface{})
+	fileMap, fileIsMap := fileValue.(map[string]interface{})
+	if structIsMap && fileIsMap {
+		maskedMap, maskedIsMap := masked.(map[string]interface{})
+		normalizedMap, _ := normalized.(map[string]interface{})
+		for _, key := range unionKeys(structMap, fileMap) {
+			keyPath := buildFieldPath(fieldPath, key)
+			newValue, inStruct := structMap[key]
+			oldValue, inFile := fileMap[key]
+			// 整个映射是敏感值时，其中的键都以同一个占位文本展示
+			maskedValue := masked
+			if maskedIsMap {
+				maskedValue = maskedMap[key]
+			}
+			switch {
+			case !inFile:
+				report.Entries = append(report.Entries, DiffEntry{Kind: DiffAdded, Path: keyPath, New: maskedValue})
+			case !inStruct:
+				report.Entries = append(report.Entries, DiffEntry{Kind: DiffRemoved, Path: keyPath, Old: oldValue})
+			default:
+				normalizedValue, ok := normalizedMap[key]
+				if !ok {
+					normalizedValue = oldValue
+				}
+				diffTrees(newValue, maskedValue, oldValue, normalizedValue, keyPath, report)
+			}
+		}
+		return
+	}
+
+	structList, structIsList := structValue.([]interface{})
+	maskedList, maskedIsList := masked.([]interface{})
+	fileList, fileIsList := fileValue.([]interface{})
+	normalizedList, normalizedIsList := normalized.([]interface{})
+	if structIsList && fileIsList && normalizedIsList && len(structList) == len(fileList) && len(fileList) == len(normalizedList) {
+		for i := range structList {
+			maskedValue := masked
+			if maskedIsList {
+				maskedValue = maskedList[i]
+			}
+			diffTrees(structList[i], maskedValue, fileList[i], normalizedList[i], buildFieldPath(fieldPath, strconv.Itoa(i)), report)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(structValue, normalized) {
+		entry := DiffEntry{Kind: DiffChanged, Path: fieldPath, Old: fileValue, New: structValue}
+		if !reflect.DeepEqual(structValue, masked) {
+			entry.Old, entry.New = masked, masked
+		}
+		report.Entries = append(report.Entries, entry)
+	}
+}
+
+// unionKeys 两个映射的键的并集，按字典序排列
+func unionKeys(a, b map[string]interface{}) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// diffValueString 将值格式化为单行YAML
+func diffValueString(v interface{}) string {
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	node.Style |= yaml.FlowStyle
+	data, err := yaml.Marshal(&node)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// unifiedDiff 按行比较两段文本，输出带3行上下文的统一diff
+func unifiedDiff(a, b []byte, nameA, nameB string) string {
+	linesA := splitLines(a)
+	linesB := splitLines(b)
+
+	// 最长公共子序列
+	lcs := make([][]int, len(linesA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(linesB)+1)
+	}
+	for i := len(linesA) - 1; i >= 0; i-- {
+		for j := len(linesB) - 1; j >= 0; j-- {
+			if linesA[i] == linesB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	type diffLine struct {
+		op   byte
+		text string
+		a, b int // 该行之前已消耗的行数
+	}
+	var ops []diffLine
+	i, j := 0, 0
+	for i < len(linesA) || j < len(linesB) {
+		switch {
+		case i < len(linesA) && j < len(linesB) && linesA[i] == linesB[j]:
+			ops = append(ops, diffLine{' ', linesA[i], i, j})
+			i++
+			j++
+		case i < len(linesA) && (j == len(linesB) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffLine{'-', linesA[i], i, j})
+			i++
+		default:
+			ops = append(ops, diffLine{'+', linesB[j], i, j})
+			j++
+		}
+	}
+
+	const context = 3
+	var out strings.Builder
+	for start := 0; start < len(ops); {
+		// 找到下一处改动
+		for start < len(ops) && ops[start].op == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+		from := start - context
+		if from < 0 {
+			from = 0
+		}
+		// 改动之间的相同行不超过 2*context 时合并为一个块
+		end := start
+		for k := start; k < len(ops); k++ {
+			if ops[k].op != ' ' {
+				end = k + 1
+			} else if k-end+1 > 2*context {
+				break
+			}
+		}
+		to := end + context
+		if to > len(ops) {
+			to = len(ops)
+		}
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", nameA, nameB)
+		}
+		countA, countB := 0, 0
+		for _, op := range ops[from:to] {
+			if op.op != '+' {
+				countA++
+			}
+			if op.op != '-' {
+				countB++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(ops[from].a, countA), hunkRange(ops[from].b, countB))
+		for _, op := range ops[from:to] {
+			out.WriteByte(op.op)
+			out.WriteString(op.text)
+			out.WriteString("\n")
+		}
+		start = to
+	}
+	return out.String()
+}
+
+// hunkRange 统一diff块头中的行范围
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return strconv.Itoa(start + 1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// splitLines 按行拆分，忽略末尾的换行
+func splitLines(data []byte) []string {
+	text := strings.TrimSuffix(string(data), "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
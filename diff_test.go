@@ -0,0 +1,104 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type diffConfig struct {
+	Name    string        `yaml:"name" comment:"名称"`
+	Port    int           `yaml:"port" comment:"端口"`
+	Timeout time.Duration `yaml:"timeout" comment:"超时"`
+	Tags    []string      `yaml:"tags" comment:"标签"`
+}
+
+func TestDiff(t *testing.T) {
+	current := diffConfig{Name: "demo", Port: 9090, Timeout: 30 * time.Second, Tags: []string{"a", "b"}}
+	file := `# 名称
+name: demo
+# 端口
+port: 8080
+timeout: 30s
+tags: [a, c]
+legacy: true
+`
+	report, err := Diff(current, []byte(file))
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	expected := "- legacy: true\n~ port: 8080 -> 9090\n~ tags.1: c -> b\n"
+	if report.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, report.String())
+	}
+	if !report.HasChanges() || report.Entries[0].Kind != DiffRemoved || report.Entries[1].Kind.String() != "changed" {
+		t.Errorf("unexpected entries: %+v", report.Entries)
+	}
+
+	unified := report.Unified()
+	for _, expected := range []string{"--- file\n+++ struct\n", "-port: 8080\n", "+port: 9090\n", "-legacy: true\n"} {
+		if !strings.Contains(unified, expected) {
+			t.Errorf("expected %q in:\n%s", expected, unified)
+		}
+	}
+
+	// 新增的键
+	report, err = Diff(current, []byte("name: demo\n"))
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	for _, path := range []string{"port", "tags", "timeout"} {
+		if !strings.Contains(report.String(), "+ "+path+": ") {
+			t.Errorf("expected %s to be added:\n%s", path, report)
+		}
+	}
+
+	// 与自身生成的内容没有差异
+	data, err := Gen(current)
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if report, err := Diff(&current, data); err != nil || report.HasChanges() || report.Unified() != "" {
+		t.Errorf("expected no changes, got %v: %v", report, err)
+	}
+
+	if _, err := Diff(current, []byte("a: [1\n")); err == nil {
+		t.Error("expected error for invalid YAML")
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n"
+	expected := "--- a\n+++ b\n@@ -1,6 +1,6 @@\n 1\n 2\n-3\n+three\n 4\n 5\n 6\n@@ -10,3 +10,4 @@\n 10\n 11\n 12\n+13\n"
+	if got := unifiedDiff([]byte(a), []byte(b), "a", "b"); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestDiffSecrets(t *testing.T) {
+	type Config struct {
+		User     string `yaml:"user"`
+		Password string `yaml:"password" yamlc:"secret"`
+	}
+	current := Config{User: "admin", Password: "new-secret"}
+
+	report, err := Diff(current, []byte("user: admin\npassword: old-secret\n"))
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if report.String() != "~ password: '*****' -> '*****'\n" {
+		t.Errorf("expected masked change for password, got:\n%s", report)
+	}
+	if strings.Contains(report.String(), "new-secret") || strings.Contains(report.Unified(), "new-secret") {
+		t.Errorf("secret leaked in rendered diff:\n%s\n%s", report, report.Unified())
+	}
+
+	report, err = Diff(current, []byte("user: admin\npassword: new-secret\n"))
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if report.HasChanges() {
+		t.Errorf("expected no changes for the same secret, got:\n%s", report)
+	}
+}
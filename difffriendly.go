@@ -0,0 +1,15 @@
+package yamlc
+
+// WithDiffFriendly 输出便于git diff的内容：不做对齐补齐、键按字母排序、列表逐项输出、字符串统一加双引号、
+// 长字符串不折叠，值长度变化时重新生成的配置只产生最小的差异
+func WithDiffFriendly() Option {
+	return func(o *Options) {
+		o.diffFriendly = true
+		o.FieldOrder = OrderAlphabetical
+		o.FlowThreshold = 0
+		o.QuoteStyle = QuoteAlways
+		o.LongStringStyle = LongStringPlain
+		o.ValueAlignment = false
+		o.CommentColumn = 0
+	}
+}
@@ -0,0 +1,41 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithDiffFriendly(t *testing.T) {
+	type Config struct {
+		Name  string   `yaml:"name" comment:"名称"`
+		Alpha int      `yaml:"alpha" comment:"首项"`
+		Tags  []string `yaml:"tags" comment:"标签"`
+	}
+
+	gen := func(name string) []string {
+		data, err := Gen(Config{Name: name, Alpha: 1, Tags: []string{"a", "b"}},
+			WithStyle(StyleInline), WithFlowThreshold(5), WithDiffFriendly())
+		if err != nil {
+			t.Fatalf("Gen failed: %v", err)
+		}
+		return strings.Split(string(data), "\n")
+	}
+
+	before := gen("short")
+	expected := "alpha: 1 # 首项\nname: \"short\" # 名称\ntags: # 标签\n  - \"a\"\n  - \"b\""
+	if got := strings.TrimSpace(strings.Join(before, "\n")); got != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", got, expected)
+	}
+
+	// 只有值变化的那一行不同
+	after := gen("a much longer name than before")
+	changed := 0
+	for i := range before {
+		if before[i] != after[i] {
+			changed++
+		}
+	}
+	if len(before) != len(after) || changed != 1 {
+		t.Errorf("expected exactly one changed line, got %d:\n%s", changed, strings.Join(after, "\n"))
+	}
+}
@@ -0,0 +1,208 @@
+package yamlc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DocDrift 结构体字段的Go文档注释与注释标签（yamlc:"comment=" 或 comment 标签）不一致之处
+type DocDrift struct {
+	// Path 字段在YAML中的路径，如 "database.host"
+	Path string
+	// Position 字段在源码中的位置，如 "config.go:12"
+	Position string
+	// DocComment 字段的Go文档注释（已去掉开头的字段名），为空表示没有
+	DocComment string
+	// TagComment 字段标签中的注释，为空表示没有
+	TagComment string
+}
+
+// String 返回可读的不一致说明
+func (d DocDrift) String() string {
+	switch {
+	case d.TagComment == "":
+		return fmt.Sprintf("%s: %s: missing tag comment (doc: %q)", d.Position, d.Path, d.DocComment)
+	case d.DocComment == "":
+		return fmt.Sprintf("%s: %s: missing doc comment (tag: %q)", d.Position, d.Path, d.TagComment)
+	}
+	return fmt.Sprintf("%s: %s: doc comment %q differs from tag comment %q", d.Position, d.Path, d.DocComment, d.TagComment)
+}
+
+// CheckDocSync 比较结构体字段的Go文档注释与注释标签，返回不一致之处，供同时维护两者的团队在CI中检查
+// pkgPath 为包目录，或可由 go list 解析的导入路径（不引入 x/tools 依赖）；同一包中定义的嵌套结构体一并检查
+// 比较前去掉文档注释开头的字段名、合并多行并忽略末尾的句号；两者都没有的字段不报告
+func CheckDocSync(pkgPath, typeName string) ([]DocDrift, error) {
+	if pkgPath == "" || typeName == "" {
+		return nil, fmt.Errorf("package path and type name cannot be empty")
+	}
+	fset := token.NewFileSet()
+	files, err := parsePackageFiles(fset, pkgPath)
+	if err != nil {
+		return nil, err
+	}
+
+	types := make(map[string]*ast.StructType)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, s := range gen.Specs {
+				spec := s.(*ast.TypeSpec)
+				if st, ok := spec.Type.(*ast.StructType); ok && !spec.Assign.IsValid() {
+					types[spec.Name.Name] = st
+				}
+			}
+		}
+	}
+	st, ok := types[typeName]
+	if !ok {
+		return nil, fmt.Errorf("struct type %s not found in package %q", typeName, pkgPath)
+	}
+
+	var drifts []DocDrift
+	checkStructDocs(fset, st, "", types, map[string]bool{typeName: true}, &drifts)
+	return drifts, nil
+}
+
+// parsePackageFiles 解析包的非测试源文件（含注释）
+func parsePackageFiles(fset *token.FileSet, pkgPath string) ([]*ast.File, error) {
+	dir := pkgPath
+	var names []string
+	if info, err := os.Stat(pkgPath); err == nil && info.IsDir() {
+		matches, err := filepath.Glob(filepath.Join(pkgPath, "*.go"))
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			if !strings.HasSuffix(match, "_test.go") {
+				names = append(names, filepath.Base(match))
+			}
+		}
+	} else {
+		var stderr bytes.Buffer
+		cmd := exec.Command("go", "list", "-json", "--", pkgPath)
+		cmd.Stderr = &stderr
+		output, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load package %q: %s", pkgPath, strings.TrimSpace(stderr.String()))
+		}
+		var pkg struct {
+			Dir     string
+			GoFiles []string
+		}
+		if err := json.Unmarshal(output, &pkg); err != nil {
+			return nil, fmt.Errorf("failed to decode package %q: %w", pkgPath, err)
+		}
+		dir, names = pkg.Dir, pkg.GoFiles
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("package %q has no Go files", pkgPath)
+	}
+
+	sort.Strings(names)
+	files := make([]*ast.File, 0, len(names))
+	for _, name := range names {
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse package %q: %w", pkgPath, err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// checkStructDocs 检查结构体的各字段，并递归检查字段类型中引用的同包结构体
+func checkStructDocs(fset *token.FileSet, st *ast.StructType, fieldPath string, types map[string]*ast.StructType, visiting map[string]bool, drifts *[]DocDrift) {
+	for _, field := range st.Fields.List {
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			if unquoted, err := strconv.Unquote(field.Tag.Value); err == nil {
+				tag = reflect.StructTag(unquoted)
+			}
+		}
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			structField := reflect.StructField{Name: name.Name, Tag: tag}
+			key := getFieldName(structField)
+			if key == "-" {
+				continue
+			}
+			childPath := buildFieldPath(fieldPath, key)
+
+			doc := normalizeDocComment(fieldDocText(field), name.Name)
+			tagComment := normalizeDocComment(getTagComment(structField), "")
+			if doc != tagComment {
+				position := fset.Position(name.Pos())
+				*drifts = append(*drifts, DocDrift{
+					Path:       childPath,
+					Position:   fmt.Sprintf("%s:%d", filepath.Base(position.Filename), position.Line),
+					DocComment: doc,
+					TagComment: tagComment,
+				})
+			}
+
+			if typeName := structTypeName(field.Type); typeName != "" && !visiting[typeName] {
+				if nested, ok := types[typeName]; ok {
+					visiting[typeName] = true
+					checkStructDocs(fset, nested, childPath, types, visiting, drifts)
+					delete(visiting, typeName)
+				}
+			}
+		}
+	}
+}
+
+// fieldDocText 字段的文档注释，没有时取行尾注释
+func fieldDocText(field *ast.Field) string {
+	if field.Doc != nil {
+		return field.Doc.Text()
+	}
+	if field.Comment != nil {
+		return field.Comment.Text()
+	}
+	return ""
+}
+
+// normalizeDocComment 规范化注释以便比较：去掉开头的字段名，合并空白，去掉末尾的句号
+func normalizeDocComment(text, fieldName string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if fieldName != "" {
+		if rest := strings.TrimPrefix(text, fieldName+" "); rest != text {
+			text = rest
+		}
+	}
+	return strings.TrimRight(text, ".。")
+}
+
+// structTypeName 字段类型（含指针、切片、数组、映射的元素）引用的同包类型名
+func structTypeName(expr ast.Expr) string {
+	for {
+		switch t := expr.(type) {
+		case *ast.Ident:
+			return t.Name
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.ArrayType:
+			expr = t.Elt
+		case *ast.MapType:
+			expr = t.Value
+		default:
+			return ""
+		}
+	}
+}
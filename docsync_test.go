@@ -0,0 +1,71 @@
+package yamlc
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const docSyncSource = `package config
+
+// Config 配置
+type Config struct {
+	// Name 服务名
+	Name string ` + "`yaml:\"name\" yamlc:\"comment=服务名\"`" + `
+	// Port 监听端口。
+	Port int ` + "`yaml:\"port\" comment:\"端口\"`" + `
+	// Database 数据库
+	Database *Database ` + "`yaml:\"database\"`" + `
+	Debug bool ` + "`yaml:\"debug\" yamlc:\"comment=调试模式\"`" + `
+	Ignored string ` + "`yaml:\"-\"`" + `
+	internal string
+}
+
+// Database 数据库配置
+type Database struct {
+	Host string ` + "`yaml:\"host\" yamlc:\"comment=主机\"`" + ` // 主机
+	Pool int    ` + "`yaml:\"pool\" yamlc:\"comment=连接池大小\"`" + `
+}
+`
+
+func TestCheckDocSync(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.go"), []byte(docSyncSource), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	drifts, err := CheckDocSync(dir, "Config")
+	if err != nil {
+		t.Fatalf("CheckDocSync failed: %v", err)
+	}
+	expected := []DocDrift{
+		{Path: "port", Position: "config.go:8", DocComment: "监听端口", TagComment: "端口"},
+		{Path: "database", Position: "config.go:10", DocComment: "数据库"},
+		{Path: "database.pool", Position: "config.go:19", TagComment: "连接池大小"},
+		{Path: "debug", Position: "config.go:11", TagComment: "调试模式"},
+	}
+	if !reflect.DeepEqual(drifts, expected) {
+		t.Errorf("unexpected drifts:\n%v", drifts)
+	}
+	if s := drifts[0].String(); s != `config.go:8: port: doc comment "监听端口" differs from tag comment "端口"` {
+		t.Errorf("unexpected message: %s", s)
+	}
+	if s := drifts[1].String(); !strings.Contains(s, "missing tag comment") {
+		t.Errorf("unexpected message: %s", s)
+	}
+}
+
+func TestCheckDocSyncErrors(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.go"), []byte(docSyncSource), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if _, err := CheckDocSync(dir, "Missing"); err == nil {
+		t.Error("expected error for unknown type")
+	}
+	if _, err := CheckDocSync(t.TempDir(), "Config"); err == nil {
+		t.Error("expected error for empty package")
+	}
+}
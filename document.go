@@ -0,0 +1,155 @@
+package yamlc
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Version yamlc 版本号，用于生成横幅
+const Version = "1.1.0"
+
+// timeNow 获取当前时间，测试时可替换
+var timeNow = time.Now
+
+// WithHeader 在文档顶部输出注释块，如许可证声明、"请勿手动编辑"提示
+func WithHeader(lines ...string) Option {
+	return func(o *Options) {
+		o.Header = append(o.Header, lines...)
+	}
+}
+
+// WithGeneratedBanner 在文档顶部输出 "# Generated by yamlc vX.Y on <date> from <type>" 横幅
+func WithGeneratedBanner() Option {
+	return func(o *Options) {
+		o.GeneratedBanner = true
+	}
+}
+
+// WithReproducible 可复现模式：横幅中省略时间戳，保证重复生成的内容完全一致
+func WithReproducible() Option {
+	return func(o *Options) {
+		o.Reproducible = true
+	}
+}
+
+// WithFooter 在文档底部输出注释块
+func WithFooter(lines ...string) Option {
+	return func(o *Options) {
+		o.Footer = append(o.Footer, lines...)
+	}
+}
+
+// WithDocumentStart 在文档开头输出 "---" 标记
+func WithDocumentStart() Option {
+	return func(o *Options) {
+		o.DocumentStart = true
+	}
+}
+
+// WithDocumentEnd 在文档末尾输出 "..." 标记，便于拼接成YAML流
+func WithDocumentEnd() Option {
+	return func(o *Options) {
+		o.DocumentEnd = true
+	}
+}
+
+// WithYAMLDirective 在文档开头输出 "%YAML <version>" 指令（如 "1.2"），指令之后必然跟随 "---"
+// 注意 yaml.v3 解析时只接受 "1.1"，"1.2" 面向更严格的解析器
+func WithYAMLDirective(version string) Option {
+	return func(o *Options) {
+		o.YAMLVersion = version
+	}
+}
+
+// decorateDocument 为生成的内容添加文档级的头部和尾部
+func decorateDocument(content []byte, v interface{}, options *Options) []byte {
+	if len(options.Header) == 0 && len(options.Footer) == 0 && !options.GeneratedBanner && options.Profile == "" {
+		return content
+	}
+	header := options.Header
+	if options.Profile != "" {
+		header = append([]string{profileHeader(options.Profile)}, header...)
+	}
+
+	var buf bytes.Buffer
+	if options.GeneratedBanner {
+		buf.WriteString(commentBlock([]string{generatedBanner(v, options)}))
+		if len(header) == 0 {
+			buf.WriteString("\n")
+		}
+	}
+	if len(header) > 0 {
+		buf.WriteString(commentBlock(header))
+		buf.WriteString("\n")
+	}
+
+	if len(options.Footer) > 0 {
+		// 尾部注释与正文之间保留一个空行
+		buf.Write(bytes.TrimRight(content, "\n"))
+		buf.WriteString("\n\n")
+		buf.WriteString(commentBlock(options.Footer))
+	} else {
+		buf.Write(content)
+	}
+	return buf.Bytes()
+}
+
+// markDocument 为文档添加 %YAML 指令和开始/结束标记，头部注释位于 "---" 之后
+func markDocument(content []byte, options *Options) []byte {
+	if options.YAMLVersion == "" && !options.DocumentStart && !options.DocumentEnd {
+		return content
+	}
+
+	var buf bytes.Buffer
+	if options.YAMLVersion != "" {
+		buf.WriteString("%YAML " + options.YAMLVersion + "\n")
+	}
+	if options.YAMLVersion != "" || options.DocumentStart {
+		buf.WriteString(documentSeparator)
+	}
+	buf.Write(content)
+	if options.DocumentEnd {
+		if len(content) > 0 && !bytes.HasSuffix(content, []byte("\n")) {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(documentEndMarker)
+	}
+	return buf.Bytes()
+}
+
+// generatedBanner 生成横幅文本
+func generatedBanner(v interface{}, options *Options) string {
+	typ := reflect.TypeOf(v)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	typeName := "<nil>"
+	if typ != nil {
+		typeName = typ.String()
+	}
+
+	if options.Reproducible {
+		return fmt.Sprintf("Generated by yamlc v%s from %s", Version, typeName)
+	}
+	return fmt.Sprintf("Generated by yamlc v%s on %s from %s",
+		Version, timeNow().Format(time.RFC3339), typeName)
+}
+
+// commentBlock 将多行文本转为注释块，空行输出为单独的 "#"
+func commentBlock(lines []string) string {
+	var result strings.Builder
+	for _, line := range lines {
+		for _, subLine := range strings.Split(line, "\n") {
+			subLine = strings.TrimRight(subLine, " \t\r")
+			if subLine == "" {
+				result.WriteString("#\n")
+			} else {
+				result.WriteString("# " + subLine + "\n")
+			}
+		}
+	}
+	return result.String()
+}
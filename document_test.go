@@ -0,0 +1,105 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHeaderAndFooter(t *testing.T) {
+	user := createTestUser()
+
+	for _, style := range []CommentStyle{StyleTop, StyleMinimal} {
+		data, err := Gen(user, WithStyle(style),
+			WithHeader("Copyright 2024 binrc", "", "DO NOT EDIT"),
+			WithFooter("end of config"))
+		if err != nil {
+			t.Fatalf("Gen failed: %v", err)
+		}
+		yamlStr := string(data)
+		if !strings.HasPrefix(yamlStr, "# Copyright 2024 binrc\n#\n# DO NOT EDIT\n\n") {
+			t.Errorf("style %s: header missing:\n%s", GetStyleString(int(style)), yamlStr)
+		}
+		if !strings.HasSuffix(yamlStr, "\n\n# end of config\n") {
+			t.Errorf("style %s: footer missing:\n%s", GetStyleString(int(style)), yamlStr)
+		}
+	}
+}
+
+func TestGeneratedBanner(t *testing.T) {
+	originalNow := timeNow
+	defer func() { timeNow = originalNow }()
+	timeNow = func() time.Time {
+		return time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	}
+
+	user := createTestUser()
+	data, err := Gen(user, WithGeneratedBanner(), WithHeader("DO NOT EDIT"))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	expected := "# Generated by yamlc v" + Version + " on 2024-05-01T08:00:00Z from yamlc.User\n# DO NOT EDIT\n\n"
+	if !strings.HasPrefix(string(data), expected) {
+		t.Errorf("banner missing, got:\n%s", data)
+	}
+
+	first, err := Gen(user, WithGeneratedBanner(), WithReproducible())
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	timeNow = time.Now
+	second, err := Gen(user, WithGeneratedBanner(), WithReproducible())
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if string(first) != string(second) {
+		t.Error("reproducible output should not depend on time")
+	}
+	if !strings.HasPrefix(string(first), "# Generated by yamlc v"+Version+" from yamlc.User\n\n") {
+		t.Errorf("reproducible banner malformed:\n%s", first)
+	}
+}
+
+func TestDocumentMarkers(t *testing.T) {
+	user := createTestUser()
+
+	data, err := Gen(user, WithYAMLDirective("1.2"), WithDocumentEnd(), WithHeader("DO NOT EDIT"))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	yamlStr := string(data)
+	if !strings.HasPrefix(yamlStr, "%YAML 1.2\n---\n# DO NOT EDIT\n") {
+		t.Errorf("expected directive, start marker and header:\n%s", yamlStr)
+	}
+	if !strings.HasSuffix(yamlStr, "\n...\n") {
+		t.Errorf("expected end marker:\n%s", yamlStr)
+	}
+
+	data, err = Gen(user, WithStyle(StyleMinimal), WithDocumentStart())
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "---\nname: ") {
+		t.Errorf("expected start marker:\n%s", data)
+	}
+}
+
+func TestGenAllWithDocumentMarkers(t *testing.T) {
+	docs := []interface{}{manifest{Kind: "A", Name: "a"}, manifest{Kind: "B", Name: "b"}}
+
+	data, err := GenAll(docs, WithDocumentStart())
+	if err != nil {
+		t.Fatalf("GenAll failed: %v", err)
+	}
+	if strings.Count(string(data), "---\n") != 2 || !strings.HasPrefix(string(data), "---\n") {
+		t.Errorf("start markers should not be duplicated:\n%s", data)
+	}
+
+	data, err = GenAll(docs, WithYAMLDirective("1.2"))
+	if err != nil {
+		t.Fatalf("GenAll failed: %v", err)
+	}
+	if !strings.Contains(string(data), "\n...\n%YAML 1.2\n---\n") {
+		t.Errorf("directive of the second document needs an explicit end marker:\n%s", data)
+	}
+}
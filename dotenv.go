@@ -0,0 +1,237 @@
+package yamlc
+
+import (
+	"encoding"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// dotenvSpecialChars 值中出现时需要加引号的字符
+const dotenvSpecialChars = " \t\r\n#\"'\\$`"
+
+// GenDotenv 使用默认配置生成 .env 文件内容
+func GenDotenv(v interface{}, opts ...Option) ([]byte, error) {
+	return Default().GenDotenv(v, opts...)
+}
+
+// GenDotenv 将结构体展开为 .env 格式的 KEY=value 行，每行上方以 "# " 输出字段注释，用于同时提供YAML和 .env 的部署方式
+// 变量名取 env 标签（yamlc标签中的 env= 或独立的 env 标签），没有时由字段路径转换为大写下划线形式（"database.maxConns" 对应 DATABASE_MAX_CONNS）；
+// 带 env 标签的嵌套结构体，其子字段以该名称为前缀。列表输出为逗号分隔的值，映射等其他复杂类型输出为单行YAML。
+// 字段过滤、敏感字段屏蔽、值转换等选项与 Gen 相同，注释风格不起作用
+func (c *Config) GenDotenv(v interface{}, opts ...Option) ([]byte, error) {
+	if v == nil {
+		return nil, ErrNilInput
+	}
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return nil, fmt.Errorf("%w: got nil %T", ErrNilInput, v)
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("dotenv output requires a struct, got %s", val.Type())
+	}
+
+	options := c.newOptions(opts...)
+	w := &dotenvWriter{keys: make(map[string]string)}
+	for _, line := range options.Header {
+		w.comment(line)
+	}
+	if len(options.Header) > 0 {
+		w.blank = true
+	}
+	if err := w.writeStruct(val, "", "", options); err != nil {
+		return nil, err
+	}
+	if len(options.Footer) > 0 {
+		w.blank = true
+		for _, line := range options.Footer {
+			w.comment(line)
+		}
+	}
+	return []byte(w.b.String()), nil
+}
+
+// dotenvWriter 生成 .env 内容时的状态
+type dotenvWriter struct {
+	b     strings.Builder
+	keys  map[string]string // 已输出的变量名及其字段路径，用于发现重名
+	blank bool              // 下一项之前是否需要空行
+}
+
+// writeStruct 输出结构体的字段，prefix 为上层结构体对应的变量名前缀
+func (w *dotenvWriter) writeStruct(val reflect.Value, fieldPath, prefix string, options *Options) error {
+	fields := collectFieldInfo(val, val.Type(), fieldPath, options)
+	if err := transformFields(fields, options); err != nil {
+		return err
+	}
+	if err := encryptFields(fields, options); err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		key := getEnvName(field)
+		if key == "" {
+			key = dotenvKey(field.Name)
+			if prefix != "" {
+				key = prefix + "_" + key
+			}
+		}
+
+		if nested, ok := dotenvStruct(field.Field); ok && !field.secret {
+			w.blank = w.b.Len() > 0
+			w.comments(commentWithExample(field))
+			if err := w.writeStruct(nested, field.FieldPath, key, options); err != nil {
+				return err
+			}
+			w.blank = true
+			continue
+		}
+
+		if previous, exists := w.keys[key]; exists {
+			return fmt.Errorf("duplicate environment variable %s for %s and %s", key, previous, field.FieldPath)
+		}
+		w.keys[key] = field.FieldPath
+
+		value, err := dotenvValue(field.Field)
+		if err != nil {
+			return fmt.Errorf("failed to format %s: %w", field.FieldPath, err)
+		}
+		w.comments(commentWithExample(field))
+		w.b.WriteString(key)
+		w.b.WriteString("=")
+		w.b.WriteString(quoteDotenv(value))
+		w.b.WriteString("\n")
+	}
+	return nil
+}
+
+// comments 输出多行注释，每行一个 "# "
+func (w *dotenvWriter) comments(comment string) {
+	for _, line := range strings.Split(comment, "\n") {
+		if line = sanitizeComment(line); line != "" {
+			w.comment(line)
+		}
+	}
+}
+
+// comment 输出一行注释，需要时先输出空行
+func (w *dotenvWriter) comment(line string) {
+	if w.blank {
+		w.b.WriteString("\n")
+		w.blank = false
+	}
+	w.b.WriteString("# ")
+	w.b.WriteString(line)
+	w.b.WriteString("\n")
+}
+
+// dotenvStruct 判断字段是否为需要展开的嵌套结构体（不含 time.Time 这类以文本表示的类型）
+func dotenvStruct(val reflect.Value) (reflect.Value, bool) {
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return reflect.Value{}, false
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct || val.Type().Implements(textMarshalerType) || reflect.PtrTo(val.Type()).Implements(textMarshalerType) {
+		return reflect.Value{}, false
+	}
+	return val, true
+}
+
+// dotenvValue 将值格式化为 .env 中的文本
+func dotenvValue(val reflect.Value) (string, error) {
+	for val.IsValid() && (val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface) {
+		if val.IsNil() {
+			return "", nil
+		}
+		val = val.Elem()
+	}
+	if !val.IsValid() {
+		return "", nil
+	}
+
+	if val.Type() == durationType {
+		return time.Duration(val.Int()).String(), nil
+	}
+	if val.CanInterface() {
+		if marshaler, ok := val.Interface().(encoding.TextMarshaler); ok {
+			text, err := marshaler.MarshalText()
+			return string(text), err
+		}
+	}
+
+	switch val.Kind() {
+	case reflect.String:
+		return val.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(val.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(val.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(val.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(val.Float(), 'g', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(val.Float(), 'g', -1, 64), nil
+	case reflect.Slice, reflect.Array:
+		if isByteSlice(val) {
+			return base64.StdEncoding.EncodeToString(val.Bytes()), nil
+		}
+		items := make([]string, 0, val.Len())
+		for i := 0; i < val.Len(); i++ {
+			if _, nested := dotenvStruct(val.Index(i)); nested || isComplexType(val.Index(i)) {
+				return diffValueString(val.Interface()), nil
+			}
+			item, err := dotenvValue(val.Index(i))
+			if err != nil {
+				return "", err
+			}
+			items = append(items, item)
+		}
+		return strings.Join(items, ","), nil
+	}
+	if !val.CanInterface() {
+		return "", fmt.Errorf("unsupported value of type %s", val.Type())
+	}
+	return diffValueString(val.Interface()), nil
+}
+
+// quoteDotenv 值中含空白、引号、"#"、"$" 等字符时加双引号并转义
+func quoteDotenv(value string) string {
+	if !strings.ContainsAny(value, dotenvSpecialChars) {
+		return value
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`", "\n", `\n`, "\r", `\r`)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// dotenvKey 将字段名或路径转换为大写下划线形式，如 "database.maxConns" 转换为 "DATABASE_MAX_CONNS"
+func dotenvKey(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteByte('_')
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return strings.TrimSuffix(b.String(), "_")
+}
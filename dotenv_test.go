@@ -0,0 +1,107 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type dotenvDatabase struct {
+	Host     string `yaml:"host"     yamlc:"comment=数据库地址"`
+	MaxConns int    `yaml:"maxConns" yamlc:"comment=最大连接数"`
+}
+
+type dotenvCache struct {
+	Addr string `yaml:"addr" yamlc:"comment=缓存地址"`
+}
+
+type dotenvConfig struct {
+	Name     string            `yaml:"name"     yamlc:"comment=服务名称"`
+	Port     int               `yaml:"port"     yamlc:"comment=监听端口,env=APP_PORT"`
+	Timeout  time.Duration     `yaml:"timeout"  yamlc:"comment=请求超时"`
+	Motd     string            `yaml:"motd"     yamlc:"comment=欢迎语"`
+	Password string            `yaml:"password" yamlc:"comment=密码,secret"`
+	Tags     []string          `yaml:"tags"     yamlc:"comment=标签"`
+	Labels   map[string]string `yaml:"labels"   yamlc:"comment=标签映射"`
+	Database dotenvDatabase    `yaml:"database" yamlc:"comment=数据库配置"`
+	Cache    dotenvCache       `yaml:"cache"    yamlc:"comment=缓存配置,env=REDIS"`
+}
+
+func TestGenDotenv(t *testing.T) {
+	cfg := &dotenvConfig{
+		Name:     "demo",
+		Port:     8080,
+		Timeout:  30 * time.Second,
+		Motd:     "hello $USER\n# welcome",
+		Password: "hunter2",
+		Tags:     []string{"web", "api"},
+		Labels:   map[string]string{"env": "prod"},
+		Database: dotenvDatabase{Host: "localhost", MaxConns: 10},
+		Cache:    dotenvCache{Addr: "127.0.0.1:6379"},
+	}
+
+	data, err := GenDotenv(cfg, WithHeader("应用环境变量"))
+	if err != nil {
+		t.Fatalf("GenDotenv failed: %v", err)
+	}
+	expected := `# 应用环境变量
+
+# 服务名称
+NAME=demo
+# 监听端口
+APP_PORT=8080
+# 请求超时
+TIMEOUT=30s
+# 欢迎语
+MOTD="hello \$USER\n# welcome"
+# 密码 (secret)
+PASSWORD=*****
+# 标签
+TAGS=web,api
+# 标签映射
+LABELS="{env: prod}"
+
+# 数据库配置
+# 数据库地址
+DATABASE_HOST=localhost
+# 最大连接数
+DATABASE_MAX_CONNS=10
+
+# 缓存配置
+# 缓存地址
+REDIS_ADDR=127.0.0.1:6379
+`
+	if string(data) != expected {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", data, expected)
+	}
+
+	data, err = GenDotenv(cfg, WithExclude("database.*", "cache", "labels"))
+	if err != nil {
+		t.Fatalf("GenDotenv failed: %v", err)
+	}
+	if strings.Contains(string(data), "DATABASE_HOST") || strings.Contains(string(data), "REDIS_ADDR") {
+		t.Errorf("excluded fields present:\n%s", data)
+	}
+
+	if _, err := GenDotenv([]string{"a"}); err == nil {
+		t.Error("expected error for non-struct value")
+	}
+	if _, err := GenDotenv(nil); err == nil {
+		t.Error("expected error for nil value")
+	}
+}
+
+func TestDotenvKey(t *testing.T) {
+	tests := map[string]string{
+		"name":              "NAME",
+		"maxConns":          "MAX_CONNS",
+		"database.maxConns": "DATABASE_MAX_CONNS",
+		"api-key":           "API_KEY",
+		"HTTPServer":        "HTTP_SERVER",
+	}
+	for name, want := range tests {
+		if got := dotenvKey(name); got != want {
+			t.Errorf("dotenvKey(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package yamlc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestDynamicValues(t *testing.T) {
+	type Item struct {
+		Name string      `yaml:"name"`
+		Tags []string    `yaml:"tags"`
+		Any  interface{} `yaml:"any"`
+	}
+	type Config struct {
+		Extra interface{}            `yaml:"extra" comment:"扩展"`
+		Meta  map[string]interface{} `yaml:"meta" comment:"元数据"`
+		List  []interface{}          `yaml:"list"`
+		Items []Item                 `yaml:"items"`
+	}
+	cfg := Config{
+		Extra: map[string]interface{}{
+			"a": map[string]interface{}{"b": []interface{}{1, map[string]interface{}{"c": "d", "e": []interface{}{"f"}}}},
+			"z": nil,
+		},
+		Meta: map[string]interface{}{
+			"owner":  "me",
+			"nested": map[string]interface{}{"k": 1.5, "l": []interface{}{}},
+			"matrix": []interface{}{[]interface{}{1, 2}, "s"},
+		},
+		List:  []interface{}{"a", map[string]interface{}{"k": "v", "n": map[string]interface{}{"x": 1}}, []interface{}{1}},
+		Items: []Item{{Name: "n", Tags: []string{"x", "y"}, Any: map[string]interface{}{"k": []interface{}{1}}}},
+	}
+	comments := map[string]string{
+		"extra.a.b":     "B注释",
+		"extra.a.b.1.c": "C注释",
+		"meta.nested.k": "K注释",
+		"list.1.k":      "LK注释",
+		"items.0.any.k": "动态K",
+	}
+
+	var want interface{}
+	plain, err := yaml.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if err := yaml.Unmarshal(plain, &want); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	for _, style := range []CommentStyle{StyleTop, StyleInline, StyleSmart, StyleCompact, StyleVerbose, StyleMinimal} {
+		data, err := Gen(cfg, WithStyle(style), WithComment(comments))
+		if err != nil {
+			t.Fatalf("style %s: Gen failed: %v", GetStyleString(int(style)), err)
+		}
+		var got interface{}
+		if err := yaml.Unmarshal(data, &got); err != nil {
+			t.Fatalf("style %s: Unmarshal failed: %v\n%s", GetStyleString(int(style)), err, data)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("style %s: dynamic values did not round-trip:\n%s", GetStyleString(int(style)), data)
+		}
+		if style == StyleMinimal {
+			continue
+		}
+		for path, comment := range comments {
+			if !strings.Contains(string(data), "# "+comment) {
+				t.Errorf("style %s: missing comment for %q:\n%s", GetStyleString(int(style)), path, data)
+			}
+		}
+	}
+}
+
+func TestDynamicValuesIndent(t *testing.T) {
+	type Config struct {
+		Extra interface{} `yaml:"extra"`
+	}
+	cfg := Config{Extra: map[string]interface{}{"a": map[string]interface{}{"b": []interface{}{1, 2}}}}
+
+	data, err := Gen(cfg, WithStyle(StyleInline), WithComment(map[string]string{"extra.a.b": "B"}))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if !strings.Contains(string(data), "    b:") || !strings.Contains(string(data), "\n      - 1\n      - 2\n") {
+		t.Errorf("list under dynamic map not indented below its key:\n%s", data)
+	}
+}
+
+func TestDynamicCommentPlacement(t *testing.T) {
+	type Item struct {
+		Name string      `yaml:"name"`
+		Any  interface{} `yaml:"any"`
+	}
+	type Config struct {
+		Items []Item `yaml:"items"`
+	}
+	cfg := Config{Items: []Item{{Name: "n", Any: map[string]interface{}{"k": 1}}}}
+
+	data, err := Gen(cfg, WithStyle(StyleTop), WithComment(map[string]string{"items.0.any.k": "动态K"}))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	// 动态映射中键的注释紧挨在该键之前，而不是提到列表元素之前
+	if !strings.Contains(string(data), "    any:\n      # 动态K\n      k: 1\n") {
+		t.Errorf("nested comment not kept next to its key:\n%s", data)
+	}
+}
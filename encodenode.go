@@ -0,0 +1,326 @@
+package yamlc
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	yamlMarshalerType = reflect.TypeOf((*yaml.Marshaler)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+	intType           = reflect.TypeOf(0)
+)
+
+// walkTypes 按类型缓存是否需要逐层编码
+var walkTypes sync.Map // map[reflect.Type]bool
+
+// encodeNode 将值编码为yaml节点树，用于最小风格，返回节点和被省略元素的说明（由调用方写在键的行尾、元素上方或文档末尾）
+// 可能含有通道、函数等 yaml.v3 无法编码的值的结构体、映射和列表逐层编码，按 WithUnsupportedKinds 跳过、输出空值或返回错误；
+// 设置 WithMaxDepth 或 WithMaxItems 时逐层编码并在遍历时截断，超出限制的部分不会被编码（自引用的值也能终止）。
+// 其余子树直接交给 yaml.v3 编码，字段名、omitempty、inline、flow 标签和映射键顺序与 yaml.v3 一致
+func encodeNode(val reflect.Value, fieldPath string, depth int, options *Options) (*yaml.Node, string, error) {
+	limited := options.MaxDepth > 0 || options.MaxItems > 0
+	if !val.IsValid() || isDelegatedType(val.Type()) || (!limited && !needsWalk(val.Type())) {
+		node, err := encodeLeaf(val)
+		if err != nil || !limited || (val.IsValid() && isByteSliceType(val.Type())) {
+			return node, "", err
+		}
+		return node, limitNode(node, val, fieldPath, depth, options), nil
+	}
+	if val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			node, err := encodeLeaf(val)
+			return node, "", err
+		}
+		return encodeNode(val.Elem(), fieldPath, depth, options)
+	}
+	if isUnsupportedKind(val) {
+		if _, err := generateUnsupported(val, fieldPath, options); err != nil {
+			return nil, "", err
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, "", nil
+	}
+
+	// 超过最大层级的非空容器以空值代替，结构体只检查是否有输出的字段，不编码字段的值
+	truncate := options.MaxDepth > 0 && depth >= options.MaxDepth
+	var node *yaml.Node
+	var more string
+	var err error
+	switch val.Kind() {
+	case reflect.Struct:
+		node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		err = encodeStructFields(node, val, fieldPath, depth, truncate, options)
+	case reflect.Map:
+		node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		if truncate && val.Len() > 0 {
+			break
+		}
+		more, err = encodeMapEntries(node, val, fieldPath, depth, true, options)
+	case reflect.Slice, reflect.Array:
+		node = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if truncate && val.Len() > 0 {
+			break
+		}
+		more, err = encodeItems(node, val, fieldPath, depth, options)
+	default:
+		node, err = encodeLeaf(val)
+		return node, "", err
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if truncate && (len(node.Content) > 0 || val.Kind() != reflect.Struct && val.Len() > 0) {
+		warn(options, WarningTruncated, fieldPath, "truncated at depth %d", options.MaxDepth)
+		return truncatedNode(options), "", nil
+	}
+	return node, more, nil
+}
+
+// encodeLeaf 由 yaml.v3 编码不需要逐层处理的值
+func encodeLeaf(val reflect.Value) (*yaml.Node, error) {
+	var v interface{}
+	if val.IsValid() {
+		v = val.Interface()
+	}
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return nil, err
+	}
+	return &node, nil
+}
+
+// truncatedNode 超过最大层级的容器的替代节点
+func truncatedNode(options *Options) *yaml.Node {
+	return &yaml.Node{
+		Kind:        yaml.ScalarNode,
+		Tag:         "!!null",
+		Value:       "null",
+		LineComment: fmt.Sprintf("# ... truncated at depth %d", options.MaxDepth),
+	}
+}
+
+// encodeItems 编码列表元素，最多 MaxItems 个
+func encodeItems(node *yaml.Node, val reflect.Value, fieldPath string, depth int, options *Options) (string, error) {
+	n := val.Len()
+	for i := 0; i < n; i++ {
+		if options.MaxItems > 0 && len(node.Content) == options.MaxItems {
+			warn(options, WarningTruncated, fieldPath, "%d of %d items omitted", n-i, n)
+			return fmt.Sprintf("# ... %d more items", n-i), nil
+		}
+		item := val.Index(i)
+		itemPath := buildFieldPath(fieldPath, strconv.Itoa(i))
+		if skipUnsupported(item, itemPath, options) {
+			continue
+		}
+		child, more, err := encodeNode(item, itemPath, depth+1, options)
+		if err != nil {
+			return "", err
+		}
+		if more != "" {
+			child.HeadComment = more
+		}
+		node.Content = append(node.Content, child)
+	}
+	return "", nil
+}
+
+// encodeStructFields 按 yaml.v3 的规则将结构体字段追加到映射节点，inline 字段展开到同一层
+// shallow 为 true 时只追加键，用于判断超过最大层级的结构体是否为空
+func encodeStructFields(node *yaml.Node, val reflect.Value, fieldPath string, depth int, shallow bool, options *Options) error {
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		fieldType := typ.Field(i)
+		name, flags, _ := strings.Cut(fieldType.Tag.Get("yaml"), ",")
+		// 与 yaml.v3 一致，未导出的嵌入类型只能以 inline 方式展开
+		if name == "-" || (!fieldType.IsExported() && !(fieldType.Anonymous && hasYAMLFlag(flags, "inline"))) {
+			continue
+		}
+		field := val.Field(i)
+		if hasYAMLFlag(flags, "inline") {
+			for field.Kind() == reflect.Ptr && !field.IsNil() {
+				field = field.Elem()
+			}
+			var err error
+			switch field.Kind() {
+			case reflect.Struct:
+				err = encodeStructFields(node, field, fieldPath, depth, shallow, options)
+			case reflect.Map:
+				if shallow && field.Len() > 0 {
+					node.Content = append(node.Content, &yaml.Node{}, &yaml.Node{})
+					continue
+				}
+				_, err = encodeMapEntries(node, field, fieldPath, depth, false, options)
+			}
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(fieldType.Name)
+		}
+		if hasYAMLFlag(flags, "omitempty") && isZeroValue(field) {
+			continue
+		}
+
+		childPath := buildFieldPath(fieldPath, name)
+		if isFieldFiltered(childPath, options) || skipUnsupported(field, childPath, options) {
+			continue
+		}
+		key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name}
+		if shallow {
+			node.Content = append(node.Content, key, &yaml.Node{})
+			continue
+		}
+		child, more, err := encodeNode(field, childPath, depth+1, options)
+		if err != nil {
+			return err
+		}
+		key.LineComment = more
+		// 行尾的省略说明只能跟在块风格容器的键之后
+		if hasYAMLFlag(flags, "flow") && more == "" && (child.Kind == yaml.MappingNode || child.Kind == yaml.SequenceNode) {
+			child.Style |= yaml.FlowStyle
+		}
+		node.Content = append(node.Content, key, child)
+	}
+	return nil
+}
+
+// encodeMapEntries 将映射项追加到映射节点，键的编码和顺序由 yaml.v3 对以元素下标为值的同键映射编码得到
+// limited 为 true 时最多追加 MaxItems 项，inline 展开的映射与结构体字段一样不受限制
+func encodeMapEntries(node *yaml.Node, val reflect.Value, fieldPath string, depth int, limited bool, options *Options) (string, error) {
+	keys := val.MapKeys()
+	index := reflect.MakeMapWithSize(reflect.MapOf(val.Type().Key(), intType), len(keys))
+	for i, key := range keys {
+		index.SetMapIndex(key, reflect.ValueOf(i))
+	}
+	ordered, err := encodeLeaf(index)
+	if err != nil {
+		return "", err
+	}
+
+	n := len(ordered.Content) / 2
+	emitted := 0
+	for i := 0; i < n; i++ {
+		if limited && options.MaxItems > 0 && emitted == options.MaxItems {
+			warn(options, WarningTruncated, fieldPath, "%d of %d items omitted", n-i, n)
+			return fmt.Sprintf("# ... %d more items", n-i), nil
+		}
+		key := ordered.Content[2*i]
+		k, err := strconv.Atoi(ordered.Content[2*i+1].Value)
+		if err != nil {
+			return "", err
+		}
+		value := val.MapIndex(keys[k])
+		childPath := buildFieldPath(fieldPath, key.Value)
+		if isFieldFiltered(childPath, options) || skipUnsupported(value, childPath, options) {
+			continue
+		}
+		child, more, err := encodeNode(value, childPath, depth+1, options)
+		if err != nil {
+			return "", err
+		}
+		key.LineComment = more
+		node.Content = append(node.Content, key, child)
+		emitted++
+	}
+	return "", nil
+}
+
+// hasYAMLFlag 判断 yaml 标签的选项部分是否包含指定选项
+func hasYAMLFlag(flags string, flag string) bool {
+	for flags != "" {
+		var current string
+		current, flags, _ = strings.Cut(flags, ",")
+		if current == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// isZeroValue 按 yaml.v3 的 omitempty 规则判断值是否为空
+func isZeroValue(val reflect.Value) bool {
+	kind := val.Kind()
+	if zeroer, ok := val.Interface().(yaml.IsZeroer); ok {
+		if (kind == reflect.Ptr || kind == reflect.Interface) && val.IsNil() {
+			return true
+		}
+		return zeroer.IsZero()
+	}
+	switch kind {
+	case reflect.String:
+		return val.Len() == 0
+	case reflect.Interface, reflect.Ptr:
+		return val.IsNil()
+	case reflect.Slice, reflect.Map:
+		return val.Len() == 0
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return val.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return val.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return val.Float() == 0
+	case reflect.Bool:
+		return !val.Bool()
+	case reflect.Struct:
+		typ := val.Type()
+		for i := 0; i < val.NumField(); i++ {
+			if typ.Field(i).IsExported() && !isZeroValue(val.Field(i)) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// isDelegatedType 判断类型是否由 yaml.v3 按其自身规则编码（Node、时间、实现了编码接口的类型和字节切片）
+func isDelegatedType(typ reflect.Type) bool {
+	return typ == yamlNodeType || typ == timeType || typ == durationType ||
+		typ.Implements(yamlMarshalerType) || typ.Implements(textMarshalerType) || isByteSliceType(typ)
+}
+
+// isByteSliceType 判断类型是否为字节切片
+func isByteSliceType(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8
+}
+
+// needsWalk 判断类型的值是否可能含有 yaml.v3 无法编码的值（接口类型的值在运行时才能确定）
+func needsWalk(typ reflect.Type) bool {
+	if cached, ok := walkTypes.Load(typ); ok {
+		return cached.(bool)
+	}
+	result := typeNeedsWalk(typ, make(map[reflect.Type]bool))
+	walkTypes.Store(typ, result)
+	return result
+}
+
+// typeNeedsWalk 递归检查类型，visiting 用于跳过递归类型中正在检查的类型
+func typeNeedsWalk(typ reflect.Type, visiting map[reflect.Type]bool) bool {
+	if visiting[typ] || isDelegatedType(typ) {
+		return false
+	}
+	switch typ.Kind() {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Complex64, reflect.Complex128, reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return typeNeedsWalk(typ.Elem(), visiting)
+	case reflect.Map:
+		return typeNeedsWalk(typ.Key(), visiting) || typeNeedsWalk(typ.Elem(), visiting)
+	case reflect.Struct:
+		visiting[typ] = true
+		for i := 0; i < typ.NumField(); i++ {
+			if (typ.Field(i).IsExported() || typ.Field(i).Anonymous) && typeNeedsWalk(typ.Field(i).Type, visiting) {
+				return true
+			}
+		}
+	}
+	return false
+}
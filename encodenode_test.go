@@ -0,0 +1,54 @@
+package yamlc
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type encodeNodeBase struct {
+	ID    int         `yaml:"id"`
+	Extra interface{} `yaml:"extra,omitempty"`
+}
+
+type encodeNodeConfig struct {
+	encodeNodeBase `yaml:",inline"`
+	Name           string                 `yaml:"name,omitempty"`
+	Tags           []interface{}          `yaml:"tags,flow"`
+	Values         map[string]interface{} `yaml:"values"`
+	Ports          map[int]interface{}    `yaml:"ports"`
+	Timeout        time.Duration          `yaml:"timeout"`
+	Untagged       interface{}
+	Skipped        interface{}       `yaml:"-"`
+	Nested         *encodeNodeConfig `yaml:"nested,omitempty"`
+}
+
+func TestEncodeNodeMatchesYAML(t *testing.T) {
+	cfg := encodeNodeConfig{
+		encodeNodeBase: encodeNodeBase{ID: 7},
+		Tags:           []interface{}{"a", 1, true},
+		Values:         map[string]interface{}{"b": "x", "a10": 1, "a2": []string{"y"}, "true": "yes"},
+		Ports:          map[int]interface{}{80: "http", 443: "https", 8080: nil},
+		Timeout:        time.Second,
+		Untagged:       "multi\nline",
+		Nested:         &encodeNodeConfig{Name: "child"},
+	}
+
+	expected, err := yaml.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, _, err := encodeNode(reflect.ValueOf(cfg), "", 0, &Options{})
+	if err != nil {
+		t.Fatalf("encodeNode failed: %v", err)
+	}
+	got, err := yaml.Marshal(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(expected) {
+		t.Errorf("encodeNode should match yaml.v3\nexpected:\n%s\ngot:\n%s", expected, got)
+	}
+}
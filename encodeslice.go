@@ -0,0 +1,168 @@
+package yamlc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EncodeSlice 将切片或数组逐个元素生成并写入到io.Writer，生成顶层列表
+// 每个元素生成后立即写出，内存占用只与单个元素相关，适合导出数十万条记录等超大列表
+// 注释对齐、空行策略和格式校验以元素为单位进行，头部注释和文档标记写在首个元素之前，尾部注释写在末尾
+// WithProfile、WithRequireComments 和 WithChecksumFooter 与 Gen 一致：环境取值在写出前应用，缺少注释时不写出任何内容，
+// 校验和随写出的内容增量计算并追加在末尾
+func EncodeSlice(w io.Writer, items any, opts ...Option) error {
+	if w == nil {
+		return fmt.Errorf("writer cannot be nil")
+	}
+	if items == nil {
+		return ErrNilInput
+	}
+
+	options := newOptions(opts...)
+	if options.Profile != "" {
+		profiled, err := applyProfile(items, options.Profile)
+		if err != nil {
+			return err
+		}
+		items = profiled
+	}
+
+	val := reflect.ValueOf(items)
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return fmt.Errorf("%w: got nil %T", ErrNilInput, items)
+		}
+		val = val.Elem()
+	}
+	if !isListValue(val) {
+		return fmt.Errorf("items must be a slice or array, got %s", val.Type())
+	}
+
+	if options.RequireComments {
+		if err := checkRequiredComments(items, options); err != nil {
+			return err
+		}
+	}
+
+	buf := bufio.NewWriter(w)
+	var out io.Writer = buf
+	var checksum *checksumWriter
+	if options.ChecksumFooter {
+		checksum = newChecksumWriter(buf)
+		out = checksum
+	}
+	if err := writeData(out, slicePrologue(items, options)); err != nil {
+		return err
+	}
+
+	if val.Len() == 0 {
+		if err := writeData(out, []byte("[]\n")); err != nil {
+			return err
+		}
+	} else if err := encodeSliceItems(out, val, descend(options)); err != nil {
+		return err
+	}
+
+	if err := writeData(out, sliceEpilogue(options)); err != nil {
+		return err
+	}
+	if checksum != nil {
+		if err := writeData(buf, checksum.footer()); err != nil {
+			return err
+		}
+	}
+	if err := buf.Flush(); err != nil {
+		return fmt.Errorf("failed to write data: %w", err)
+	}
+	return nil
+}
+
+// encodeSliceItems 逐个生成列表元素并写出
+func encodeSliceItems(w io.Writer, val reflect.Value, options *Options) error {
+	minimal := options.Style == StyleMinimal && !options.scaffold
+
+	headOptions, tailOptions := sliceItemOptions(options)
+
+	limit := itemLimit(val.Len(), options)
+	for i := 0; i < limit; i++ {
+		var item string
+		var err error
+		if minimal {
+			item, err = encodeMinimalItem(val.Index(i), i, options)
+		} else {
+			var generated strings.Builder
+			err = writeSliceItem(&generated, val, i, limit, "", 0, headOptions, tailOptions)
+			item = hoistElementComments(generated.String(), "")
+		}
+		if err != nil {
+			return fmt.Errorf("item %d: %w", i, err)
+		}
+		if item == "" {
+			continue
+		}
+
+		item = finishSliceItem(item, minimal, options)
+		if err := ValidateYAML([]byte(item)); err != nil {
+			return fmt.Errorf("item %d: generated YAML validation failed: %w", i, err)
+		}
+		if err := writeData(w, []byte(item)); err != nil {
+			return err
+		}
+	}
+	return writeData(w, []byte(moreItemsComment(val.Len(), "", "", options)))
+}
+
+// encodeMinimalItem 以最小风格生成单个列表元素
+func encodeMinimalItem(item reflect.Value, index int, options *Options) (string, error) {
+	if skipUnsupported(item, strconv.Itoa(index), options) {
+		return "", nil
+	}
+	single := reflect.MakeSlice(reflect.SliceOf(item.Type()), 1, 1)
+	single.Index(0).Set(item)
+	return generateMinimalStyleField(single.Interface(), options)
+}
+
+// finishSliceItem 对单个元素执行与 Gen 相同的后处理，行内注释按元素范围对齐
+func finishSliceItem(item string, minimal bool, options *Options) string {
+	if options.ValueAlignment {
+		item = alignValues(item)
+	}
+	if !minimal {
+		if options.diffFriendly {
+			item = alignTrailingComments(item, 0)
+		} else if options.CommentColumn > 0 {
+			item = alignTrailingComments(item, options.CommentColumn)
+		} else if usesStyle(options, StyleInline, StyleSmart) {
+			item = alignTrailingComments(item, documentCommentColumn(item))
+		}
+		if options.NullStyle == NullEmpty {
+			item = trimTrailingSpaces(item)
+		}
+	}
+	return applyBlankLines(item, options.BlankLines)
+}
+
+// slicePrologue 生成写在首个元素之前的指令、开始标记、横幅和头部注释
+func slicePrologue(items any, options *Options) []byte {
+	head := *options
+	head.Footer = nil
+	head.DocumentEnd = false
+	return markDocument(decorateDocument(nil, items, &head), &head)
+}
+
+// sliceEpilogue 生成写在末尾的尾部注释和结束标记
+func sliceEpilogue(options *Options) []byte {
+	var tail strings.Builder
+	if len(options.Footer) > 0 {
+		tail.WriteString("\n")
+		tail.WriteString(commentBlock(options.Footer))
+	}
+	if options.DocumentEnd {
+		tail.WriteString(documentEndMarker)
+	}
+	return []byte(tail.String())
+}
@@ -0,0 +1,147 @@
+package yamlc
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type inventoryItem struct {
+	SKU  string   `yaml:"sku" comment:"商品编号"`
+	Qty  int      `yaml:"qty" comment:"库存数量"`
+	Tags []string `yaml:"tags" comment:"标签"`
+}
+
+// countingWriter 记录写入次数
+type countingWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+func TestEncodeSlice(t *testing.T) {
+	items := []inventoryItem{
+		{SKU: "A-1", Qty: 3, Tags: []string{"new"}},
+		{SKU: "B-2", Qty: 0},
+		{SKU: "C-3", Qty: 7, Tags: []string{"sale", "bulk"}},
+	}
+
+	// 逐个元素生成的结果与一次性生成一致
+	for _, style := range GetAllStyle() {
+		for _, v := range []interface{}{items, &items, []string{"a", "b"}, [2]int{1, 2}} {
+			expected, err := Gen(v, WithStyle(style))
+			if err != nil {
+				t.Fatalf("style %s: Gen failed: %v", GetStyleString(int(style)), err)
+			}
+			var buf bytes.Buffer
+			if err := EncodeSlice(&buf, v, WithStyle(style)); err != nil {
+				t.Fatalf("style %s: EncodeSlice failed: %v", GetStyleString(int(style)), err)
+			}
+			if got := buf.String(); got != strings.TrimLeft(string(expected), "\n") {
+				t.Errorf("style %s: EncodeSlice should match Gen\nexpected:\n%s\ngot:\n%s", GetStyleString(int(style)), expected, got)
+			}
+		}
+	}
+}
+
+func TestEncodeSliceStreams(t *testing.T) {
+	items := make([]inventoryItem, 20000)
+	for i := range items {
+		items[i] = inventoryItem{SKU: fmt.Sprintf("SKU-%05d", i), Qty: i}
+	}
+
+	w := &countingWriter{}
+	if err := EncodeSlice(w, items); err != nil {
+		t.Fatalf("EncodeSlice failed: %v", err)
+	}
+	if w.writes < 2 {
+		t.Errorf("expected output to be written incrementally, got %d writes", w.writes)
+	}
+	decoded, err := Unmarshal[[]inventoryItem](w.Bytes())
+	if err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if len(decoded) != len(items) || decoded[len(items)-1].SKU != "SKU-19999" {
+		t.Errorf("unexpected decoded items: %d", len(decoded))
+	}
+}
+
+func TestEncodeSliceDocument(t *testing.T) {
+	items := []string{"a", "b", "c"}
+	var buf bytes.Buffer
+	err := EncodeSlice(&buf, items,
+		WithHeader("inventory export"),
+		WithFooter("end of export"),
+		WithDocumentStart(),
+		WithDocumentEnd(),
+		WithMaxItems(2),
+	)
+	if err != nil {
+		t.Fatalf("EncodeSlice failed: %v", err)
+	}
+	expected := "---\n# inventory export\n\n- a\n- b\n# ... 1 more items\n\n# end of export\n...\n"
+	if buf.String() != expected {
+		t.Errorf("expected:\n%q\ngot:\n%q", expected, buf.String())
+	}
+
+	buf.Reset()
+	if err := EncodeSlice(&buf, []string{}); err != nil || buf.String() != "[]\n" {
+		t.Errorf("expected empty list, got %q (%v)", buf.String(), err)
+	}
+
+	for _, v := range []interface{}{nil, "text", map[string]int{}, []byte("raw"), (*[]string)(nil)} {
+		if err := EncodeSlice(&buf, v); err == nil {
+			t.Errorf("expected error for %T", v)
+		}
+	}
+	if err := EncodeSlice(nil, items); err == nil {
+		t.Error("expected error for nil writer")
+	}
+}
+
+type profiledItem struct {
+	Name string `yaml:"name" comment:"名称"`
+	Port int    `yaml:"port" comment:"端口" defaults:"dev=8080,prod=80"`
+}
+
+func TestEncodeSliceDocumentOptions(t *testing.T) {
+	items := []profiledItem{{Name: "a"}, {Name: "b", Port: 1}}
+	for _, opts := range [][]Option{
+		{WithProfile("prod")},
+		{WithRequireComments()},
+		{WithChecksumFooter()},
+		{WithChecksumFooter(), WithProfile("dev"), WithFooter("end")},
+		{WithChecksumFooter(), WithStyle(StyleMinimal), WithBlankLines(BlankLinesBetweenTopLevel)},
+	} {
+		expected, err := Gen(items, opts...)
+		if err != nil {
+			t.Fatalf("Gen failed: %v", err)
+		}
+		var buf bytes.Buffer
+		if err := EncodeSlice(&buf, items, opts...); err != nil {
+			t.Fatalf("EncodeSlice failed: %v", err)
+		}
+		if got := buf.String(); got != strings.TrimLeft(string(expected), "\n") {
+			t.Errorf("EncodeSlice should match Gen\nexpected:\n%s\ngot:\n%s", expected, got)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeSlice(&buf, items, WithChecksumFooter()); err != nil || !VerifyChecksum(buf.Bytes()) {
+		t.Errorf("streamed checksum should verify (%v):\n%s", err, buf.String())
+	}
+
+	buf.Reset()
+	type undocumented struct {
+		Name string `yaml:"name"`
+	}
+	err := EncodeSlice(&buf, []undocumented{{Name: "a"}}, WithRequireComments())
+	if err == nil || !strings.Contains(err.Error(), "name") || buf.Len() != 0 {
+		t.Errorf("expected undocumented field error before any output, got %v: %q", err, buf.String())
+	}
+}
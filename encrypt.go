@@ -0,0 +1,210 @@
+package yamlc
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Encrypter 加密敏感字段的值，可对接 age、GPG、KMS 等，返回的密文按字符串输出
+// plaintext 为值的YAML标量文本，fieldPath 为字段的完整路径（如 "database.password"）
+type Encrypter interface {
+	Encrypt(fieldPath, plaintext string) (string, error)
+}
+
+// Decrypter 解密由 Encrypter 生成的密文，返回原值的YAML标量文本
+type Decrypter interface {
+	Decrypt(fieldPath, ciphertext string) (string, error)
+}
+
+// WithEncrypter 设置加密器：带 secret 标签的字段输出密文而不是占位符，生成的配置可以安全地提交到仓库
+// 只加密标量值；按名称识别的敏感字段（GenRedacted）仍输出占位符
+func WithEncrypter(e Encrypter) Option {
+	return func(o *Options) {
+		o.Encrypter = e
+	}
+}
+
+// WithDecrypter 设置解密器，Unmarshal 时将带 secret 标签的字段的密文解密后再解析
+func WithDecrypter(d Decrypter) Option {
+	return func(o *Options) {
+		o.Decrypter = d
+	}
+}
+
+// isEncryptedField 判断字段是否输出密文
+func isEncryptedField(field reflect.StructField, options *Options) bool {
+	return options.Encrypter != nil && hasTagFlag(field, "secret")
+}
+
+// encryptedComment 在注释末尾追加加密标注
+func encryptedComment(comment string) string {
+	if comment == "" {
+		return "(encrypted)"
+	}
+	return comment + " (encrypted)"
+}
+
+// encryptValue 加密标量值，空值保持为空值
+func encryptValue(val reflect.Value, fieldPath string, options *Options) (reflect.Value, error) {
+	var node yaml.Node
+	if err := node.Encode(valueInterface(val)); err != nil {
+		return reflect.Value{}, fmt.Errorf("failed to encrypt field %q: %w", fieldPath, err)
+	}
+	if node.Kind != yaml.ScalarNode {
+		return reflect.Value{}, fmt.Errorf("failed to encrypt field %q: only scalar values can be encrypted", fieldPath)
+	}
+	if node.Tag == "!!null" {
+		return val, nil
+	}
+	ciphertext, err := options.Encrypter.Encrypt(fieldPath, node.Value)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("failed to encrypt field %q: %w", fieldPath, err)
+	}
+	return reflect.ValueOf(ciphertext), nil
+}
+
+// encryptFields 将收集到的待加密字段替换为密文
+func encryptFields(fields []FieldInfo, options *Options) error {
+	for i := range fields {
+		field := &fields[i]
+		if !field.encrypted {
+			continue
+		}
+		value, err := encryptValue(field.Field, field.FieldPath, options)
+		if err != nil {
+			return err
+		}
+		field.Field = value
+		field.HasChildren = false
+	}
+	return nil
+}
+
+// encryptNode 在yaml节点树上加密敏感值，用于不经过字段渲染的最小风格
+// 输出变量引用的字段不含真实值，不加密
+func encryptNode(node *yaml.Node, val reflect.Value, fieldPath string, options *Options) error {
+	if options.Encrypter == nil {
+		return nil
+	}
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) > 0 {
+			return encryptNode(node.Content[0], val, fieldPath, options)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			childPath := buildFieldPath(fieldPath, key)
+			switch val.Kind() {
+			case reflect.Struct:
+				fieldType, field, ok := findYAMLField(val, key)
+				if !ok {
+					continue
+				}
+				if _, isRef := variableRef(fieldType, field, options); isRef {
+					continue
+				}
+				if !isEncryptedField(fieldType, options) {
+					if err := encryptNode(node.Content[i+1], field, childPath, options); err != nil {
+						return err
+					}
+					continue
+				}
+				value, err := encryptValue(field, childPath, options)
+				if err != nil {
+					return err
+				}
+				var encrypted yaml.Node
+				if err := encrypted.Encode(valueInterface(value)); err != nil {
+					return err
+				}
+				node.Content[i+1] = &encrypted
+			case reflect.Map:
+				if val.Type().Key().Kind() == reflect.String {
+					child := val.MapIndex(reflect.ValueOf(key).Convert(val.Type().Key()))
+					if err := encryptNode(node.Content[i+1], child, childPath, options); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	case yaml.SequenceNode:
+		if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+			return nil
+		}
+		for i, item := range node.Content {
+			if i < val.Len() {
+				if err := encryptNode(item, val.Index(i), buildFieldPath(fieldPath, strconv.Itoa(i)), options); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// decryptNode 按目标类型遍历节点树，将带 secret 标签的字段的密文替换为解密后的标量文本
+func decryptNode(node *yaml.Node, typ reflect.Type, fieldPath string, options *Options) error {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) > 0 {
+			return decryptNode(node.Content[0], typ, fieldPath, options)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			childPath := buildFieldPath(fieldPath, key)
+			value := node.Content[i+1]
+			switch typ.Kind() {
+			case reflect.Struct:
+				fieldType, _, ok := findYAMLField(reflect.New(typ).Elem(), key)
+				if !ok {
+					continue
+				}
+				if !hasTagFlag(fieldType, "secret") || value.Kind != yaml.ScalarNode || value.Tag == "!!null" {
+					if err := decryptNode(value, fieldType.Type, childPath, options); err != nil {
+						return err
+					}
+					continue
+				}
+				plaintext, err := options.Decrypter.Decrypt(childPath, value.Value)
+				if err != nil {
+					return fmt.Errorf("failed to decrypt field %q: %w", childPath, err)
+				}
+				// 清除原标签和引号风格，按字段类型重新解析明文
+				value.Value, value.Tag, value.Style = plaintext, "", 0
+				if elem := fieldType.Type; elem.Kind() == reflect.String || (elem.Kind() == reflect.Ptr && elem.Elem().Kind() == reflect.String) {
+					value.Tag = "!!str"
+				}
+			case reflect.Map:
+				if err := decryptNode(value, typ.Elem(), childPath, options); err != nil {
+					return err
+				}
+			}
+		}
+	case yaml.SequenceNode:
+		if typ.Kind() != reflect.Slice && typ.Kind() != reflect.Array {
+			return nil
+		}
+		for i, item := range node.Content {
+			if err := decryptNode(item, typ.Elem(), buildFieldPath(fieldPath, strconv.Itoa(i)), options); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package yamlc
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// base64Encrypter 测试用的可逆“加密”，密文形如 ENC[...]
+type base64Encrypter struct {
+	paths []string
+}
+
+func (e *base64Encrypter) Encrypt(fieldPath, plaintext string) (string, error) {
+	e.paths = append(e.paths, fieldPath)
+	return "ENC[" + base64.StdEncoding.EncodeToString([]byte(plaintext)) + "]", nil
+}
+
+func (e *base64Encrypter) Decrypt(fieldPath, ciphertext string) (string, error) {
+	if !strings.HasPrefix(ciphertext, "ENC[") || !strings.HasSuffix(ciphertext, "]") {
+		return "", errors.New("not encrypted")
+	}
+	data, err := base64.StdEncoding.DecodeString(ciphertext[4 : len(ciphertext)-1])
+	return string(data), err
+}
+
+type encryptedDatabase struct {
+	Host     string `yaml:"host"`
+	Password string `yaml:"password" yamlc:"comment=密码,secret"`
+	Pin      int    `yaml:"pin" yamlc:"secret"`
+}
+
+type encryptedConfig struct {
+	Database encryptedDatabase   `yaml:"database"`
+	Replicas []encryptedDatabase `yaml:"replicas"`
+}
+
+func TestWithEncrypter(t *testing.T) {
+	cfg := encryptedConfig{
+		Database: encryptedDatabase{Host: "localhost", Password: "s3cret: value", Pin: 1234},
+		Replicas: []encryptedDatabase{{Host: "replica", Password: "true", Pin: 7}},
+	}
+	e := &base64Encrypter{}
+
+	for _, style := range GetAllStyle() {
+		data, err := Marshal(cfg, WithStyle(style), WithEncrypter(e))
+		if err != nil {
+			t.Fatalf("style %s: Marshal failed: %v", GetStyleString(int(style)), err)
+		}
+		for _, plaintext := range []string{"s3cret", "1234", DefaultSecretPlaceholder} {
+			if strings.Contains(string(data), plaintext) {
+				t.Errorf("style %s: output contains %q:\n%s", GetStyleString(int(style)), plaintext, data)
+			}
+		}
+
+		decoded, err := Unmarshal[encryptedConfig](data, WithDecrypter(e))
+		if err != nil {
+			t.Fatalf("style %s: Unmarshal failed: %v\n%s", GetStyleString(int(style)), err, data)
+		}
+		if !reflect.DeepEqual(decoded, cfg) {
+			t.Errorf("style %s: round-trip mismatch: %+v\n%s", GetStyleString(int(style)), decoded, data)
+		}
+	}
+}
+
+func TestEncrypterComment(t *testing.T) {
+	e := &base64Encrypter{}
+	cfg := encryptedDatabase{Host: "localhost", Password: "pw", Pin: 1}
+
+	data, err := Gen(cfg, WithStyle(StyleTop), WithEncrypter(e), WithBlankLines(BlankLinesNone))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	expected := "host: localhost\n# 密码 (encrypted)\npassword: ENC[cHc=]\n# (encrypted)\npin: ENC[MQ==]\n"
+	if string(data) != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", data, expected)
+	}
+	if !reflect.DeepEqual(e.paths, []string{"password", "pin"}) {
+		t.Errorf("unexpected encrypted paths: %v", e.paths)
+	}
+}
+
+type failingEncrypter struct{}
+
+func (failingEncrypter) Encrypt(fieldPath, plaintext string) (string, error) {
+	return "", errors.New("key unavailable")
+}
+
+func TestEncrypterError(t *testing.T) {
+	cfg := encryptedDatabase{Password: "pw"}
+	for _, style := range []CommentStyle{StyleTop, StyleMinimal} {
+		_, err := Gen(cfg, WithStyle(style), WithEncrypter(failingEncrypter{}))
+		if err == nil || !strings.Contains(err.Error(), `"password"`) {
+			t.Errorf("style %s: expected encryption error, got %v", GetStyleString(int(style)), err)
+		}
+	}
+}
+
+func TestDecrypterError(t *testing.T) {
+	_, err := Unmarshal[encryptedDatabase]([]byte("password: plain\n"), WithDecrypter(&base64Encrypter{}))
+	if err == nil || !strings.Contains(err.Error(), "not encrypted") {
+		t.Errorf("expected decryption error, got %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package yamlc
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNilInput 输入值为nil（或nil指针）
+	ErrNilInput = errors.New("input value cannot be nil")
+	// ErrNilOptions 校验的选项为nil
+	ErrNilOptions = errors.New("options cannot be nil")
+	// ErrInvalidStyle 注释风格既不是内置风格，也没有注册
+	ErrInvalidStyle = errors.New("invalid comment style")
+)
+
+// ErrInvalidComment 注释映射中的路径或注释内容无效，Path 为对应的字段路径（路径本身无效时为空）
+// 可用 errors.As 取得路径；errors.Is(err, &ErrInvalidComment{}) 匹配任意路径，指定 Path 时只匹配该路径
+type ErrInvalidComment struct {
+	Path string
+	Err  error
+}
+
+// Error 返回错误信息
+func (e *ErrInvalidComment) Error() string {
+	reason := "invalid value"
+	if e.Err != nil {
+		reason = e.Err.Error()
+	}
+	if e.Path == "" {
+		return "invalid comment: " + reason
+	}
+	return fmt.Sprintf("invalid comment for field %q: %s", e.Path, reason)
+}
+
+// Unwrap 返回具体原因
+func (e *ErrInvalidComment) Unwrap() error {
+	return e.Err
+}
+
+// Is 支持 errors.Is 按路径匹配
+func (e *ErrInvalidComment) Is(target error) bool {
+	t, ok := target.(*ErrInvalidComment)
+	return ok && (t.Path == "" || t.Path == e.Path)
+}
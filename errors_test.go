@@ -0,0 +1,60 @@
+package yamlc
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrNilInput(t *testing.T) {
+	type Config struct {
+		Name string `yaml:"name"`
+	}
+	var nilConfig *Config
+
+	for _, err := range []error{
+		func() error { _, err := Gen(nil); return err }(),
+		func() error { _, err := Gen(nilConfig, WithStyle(StyleTop)); return err }(),
+		func() error { _, err := Gen(nilConfig, WithStyle(StyleMinimal)); return err }(),
+		func() error { _, err := GenWithValidation(nilConfig); return err }(),
+		func() error { _, err := GenDotenv(nilConfig); return err }(),
+		func() error { _, err := GenExample(nil); return err }(),
+	} {
+		if !errors.Is(err, ErrNilInput) {
+			t.Errorf("expected ErrNilInput, got %v", err)
+		}
+	}
+}
+
+func TestValidateOptionsErrors(t *testing.T) {
+	if err := ValidateOptions(nil); !errors.Is(err, ErrNilOptions) {
+		t.Errorf("expected ErrNilOptions, got %v", err)
+	}
+
+	err := ValidateOptions(&Options{Style: CommentStyle(99)})
+	if !errors.Is(err, ErrInvalidStyle) || err.Error() != "invalid comment style: 99" {
+		t.Errorf("expected ErrInvalidStyle, got %v", err)
+	}
+	if _, err := Compile[struct{}](WithStyle(CommentStyle(99))); !errors.Is(err, ErrInvalidStyle) {
+		t.Errorf("expected wrapped ErrInvalidStyle from Compile, got %v", err)
+	}
+	if _, err := Gen(struct{}{}, WithStyle(CommentStyle(99))); !errors.Is(err, ErrInvalidStyle) {
+		t.Errorf("expected ErrInvalidStyle from Gen, got %v", err)
+	}
+
+	err = ValidateOptions(newOptions(WithComment(map[string]string{"server.port": "bad\x00comment"})))
+	var invalid *ErrInvalidComment
+	if !errors.As(err, &invalid) || invalid.Path != "server.port" {
+		t.Fatalf("expected ErrInvalidComment for server.port, got %v", err)
+	}
+	if !errors.Is(err, &ErrInvalidComment{}) || !errors.Is(err, &ErrInvalidComment{Path: "server.port"}) {
+		t.Errorf("errors.Is should match any path and the same path: %v", err)
+	}
+	if errors.Is(err, &ErrInvalidComment{Path: "server.host"}) {
+		t.Errorf("errors.Is should not match another path: %v", err)
+	}
+
+	err = ValidateOptions(newOptions(WithComment(map[string]string{"": "comment"})))
+	if !errors.As(err, &invalid) || invalid.Path != "" {
+		t.Errorf("expected ErrInvalidComment for empty path, got %v", err)
+	}
+}
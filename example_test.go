@@ -0,0 +1,82 @@
+package yamlc
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+type exampleDatabase struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	User     string `yaml:"user"`
+	MaxConns int    `yaml:"maxConns"`
+}
+
+type exampleConfig struct {
+	Name        string            `yaml:"name" yamlc:"example=billing-api"`
+	AdminEmail  string            `yaml:"adminEmail"`
+	BaseURL     string            `yaml:"baseURL"`
+	Description string            `yaml:"description"`
+	LogLevel    string            `yaml:"logLevel" yamlc:"enum=debug|info|warn"`
+	Workers     int               `yaml:"workers" yamlc:"default=8"`
+	KeyFile     string            `yaml:"keyFile"`
+	Ratio       float64           `yaml:"ratio"`
+	Debug       bool              `yaml:"debug"`
+	Database    exampleDatabase   `yaml:"database"`
+	Cache       *exampleDatabase  `yaml:"cache"`
+	Hosts       []string          `yaml:"hosts"`
+	Labels      map[string]string `yaml:"labels"`
+}
+
+func TestGenExample(t *testing.T) {
+	cfg := exampleConfig{Database: exampleDatabase{Port: 5432}}
+
+	data, err := GenExample(cfg, WithStyle(StyleMinimal))
+	if err != nil {
+		t.Fatalf("GenExample failed: %v", err)
+	}
+	var decoded exampleConfig
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("invalid YAML: %v\n%s", err, data)
+	}
+
+	expected := exampleConfig{
+		Name:        "billing-api",
+		AdminEmail:  "user@example.com",
+		BaseURL:     "https://example.com",
+		Description: "example",
+		LogLevel:    "debug",
+		Workers:     8,
+		KeyFile:     "/etc/app/config.yaml",
+		Ratio:       0.5,
+		Database:    exampleDatabase{Host: "example.com", Port: 5432, User: "admin", MaxConns: 10},
+		Cache:       &exampleDatabase{Host: "example.com", Port: 8080, User: "admin", MaxConns: 10},
+		Hosts:       []string{"example.com"},
+		Labels:      map[string]string{},
+	}
+	if !reflect.DeepEqual(decoded, expected) {
+		t.Errorf("unexpected example:\n%s", data)
+	}
+
+	if cfg.Database.Host != "" || cfg.Cache != nil || cfg.Hosts != nil {
+		t.Errorf("input was modified: %+v", cfg)
+	}
+}
+
+func TestGenExampleSelfReference(t *testing.T) {
+	type Node struct {
+		Name     string  `yaml:"name"`
+		Next     *Node   `yaml:"next"`
+		Children []*Node `yaml:"children"`
+	}
+
+	data, err := GenExample(&Node{}, WithStyle(StyleTop), WithBlankLines(BlankLinesNone))
+	if err != nil {
+		t.Fatalf("GenExample failed: %v", err)
+	}
+	if string(data) != "name: example\nnext: null\nchildren: []\n" {
+		t.Errorf("unexpected output:\n%s", data)
+	}
+}
@@ -0,0 +1,74 @@
+package yamlc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ExtractComments 解析YAML文档，返回 路径→注释 的映射，便于把手写的文档迁移为注释清单或结构体标签
+// 路径写法与 WithComment、AddComments 相同（列表元素使用下标），字段上方和行尾的注释都会提取，
+// 多行注释以换行连接，helm-docs的 "-- " 和 "@param 路径" 前缀会被去掉。多文档时只读取第一个文档
+func ExtractComments(data []byte) (map[string]string, error) {
+	var doc yaml.Node
+	if err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("YAML parsing error: %w", err)
+	}
+
+	comments := make(map[string]string)
+	for _, node := range doc.Content {
+		extractNodeComments(node, "", comments)
+	}
+	return comments, nil
+}
+
+// extractNodeComments 递归收集映射键和列表元素上的注释
+func extractNodeComments(node *yaml.Node, fieldPath string, comments map[string]string) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			keyPath := buildFieldPath(fieldPath, key.Value)
+			lineComment := key.LineComment
+			if value.Kind == yaml.ScalarNode || value.Kind == yaml.AliasNode {
+				lineComment = joinCommentText(lineComment, value.LineComment)
+			}
+			if comment := joinCommentText(key.HeadComment, lineComment); comment != "" {
+				comments[keyPath] = comment
+			}
+			extractNodeComments(value, keyPath, comments)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			itemPath := buildFieldPath(fieldPath, strconv.Itoa(i))
+			lineComment := ""
+			if item.Kind == yaml.ScalarNode || item.Kind == yaml.AliasNode {
+				lineComment = item.LineComment
+			}
+			if comment := joinCommentText(item.HeadComment, lineComment); comment != "" {
+				comments[itemPath] = comment
+			}
+			extractNodeComments(item, itemPath, comments)
+		}
+	}
+}
+
+// joinCommentText 将多段YAML注释去掉 "#" 前缀后以换行连接，跳过空段
+func joinCommentText(parts ...string) string {
+	var lines []string
+	for _, part := range parts {
+		for _, line := range strings.Split(part, "\n") {
+			line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "#"))
+			line = trimHelmDocsPrefix(line)
+			if line != "" {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,60 @@
+package yamlc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractComments(t *testing.T) {
+	input := `# 文档注释
+
+# 名称
+name: demo
+port: 8080 # 端口
+# -- 服务配置
+# 第二行
+server: # 行尾
+  host: localhost
+servers:
+  # 第一台
+  - a
+  - b # 第二台
+  - # 主机
+    host: c
+`
+	comments, err := ExtractComments([]byte(input))
+	if err != nil {
+		t.Fatalf("ExtractComments failed: %v", err)
+	}
+	expected := map[string]string{
+		"name":           "名称",
+		"port":           "端口",
+		"server":         "服务配置\n第二行\n行尾",
+		"servers.0":      "第一台",
+		"servers.1":      "第二台",
+		"servers.2.host": "主机",
+	}
+	if !reflect.DeepEqual(comments, expected) {
+		t.Errorf("expected %v, got %v", expected, comments)
+	}
+
+	// 与 AddComments 往返
+	data, err := AddComments([]byte("a: 1\nb:\n  c: x\nl:\n  - k: v\n"), map[string]string{"a": "甲", "b.c": "丙", "l.0.k": "键"}, StyleTop)
+	if err != nil {
+		t.Fatalf("AddComments failed: %v", err)
+	}
+	comments, err = ExtractComments(data)
+	if err != nil {
+		t.Fatalf("ExtractComments failed: %v", err)
+	}
+	if expected := map[string]string{"a": "甲", "b.c": "丙", "l.0.k": "键"}; !reflect.DeepEqual(comments, expected) {
+		t.Errorf("round trip: expected %v, got %v", expected, comments)
+	}
+
+	if comments, err := ExtractComments(nil); err != nil || len(comments) != 0 {
+		t.Errorf("expected no comments for empty input, got %v, %v", comments, err)
+	}
+	if _, err := ExtractComments([]byte("a: [1\n")); err == nil {
+		t.Error("expected error for invalid YAML")
+	}
+}
@@ -0,0 +1,87 @@
+package yamlc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type fieldInfoDatabase struct {
+	Host  string            `yaml:"host" yamlc:"required,env=DB_HOST"`
+	Mode  string            `yaml:"mode" yamlc:"default=rw,enum=rw|ro"`
+	Extra map[string]string `yaml:"extra"`
+}
+
+type fieldInfoConfig struct {
+	Name     string              `yaml:"name"`
+	Database fieldInfoDatabase   `yaml:"database"`
+	Replicas []fieldInfoDatabase `yaml:"replicas"`
+}
+
+func TestFieldInfoMetadata(t *testing.T) {
+	cfg := fieldInfoConfig{
+		Name:     "app",
+		Database: fieldInfoDatabase{Host: "db", Mode: "rw", Extra: map[string]string{"sslmode": "off"}},
+		Replicas: []fieldInfoDatabase{{Host: "replica", Mode: "ro"}},
+	}
+
+	type summary struct {
+		required bool
+		def      string
+		enum     []string
+		env      string
+		depth    int
+		parent   string
+	}
+	expected := map[string]summary{
+		"name":                   {},
+		"database":               {required: true},
+		"database.host":          {required: true, env: "DB_HOST", depth: 1, parent: "database"},
+		"database.mode":          {def: "rw", enum: []string{"rw", "ro"}, depth: 1, parent: "database"},
+		"database.extra":         {depth: 1, parent: "database"},
+		"database.extra.sslmode": {depth: 2, parent: "database.extra"},
+		"replicas":               {},
+		"replicas.0.host":        {required: true, env: "DB_HOST", depth: 1, parent: "replicas.0"},
+		"replicas.0.mode":        {def: "rw", enum: []string{"rw", "ro"}, depth: 1, parent: "replicas.0"},
+	}
+
+	for _, style := range []CommentStyle{StyleTop, StyleInline, StyleAnnotated} {
+		got := make(map[string]summary)
+		hook := WithFieldHook(func(result *strings.Builder, field FieldInfo) {
+			got[field.FieldPath] = summary{field.IsRequired, field.Default, field.Enum, field.EnvVar, field.Depth, field.Parent}
+		}, nil)
+		if _, err := Gen(cfg, WithStyle(style), hook); err != nil {
+			t.Fatalf("style %v: Gen failed: %v", style, err)
+		}
+		for path, want := range expected {
+			if !reflect.DeepEqual(got[path], want) {
+				t.Errorf("style %v: field %s = %+v, want %+v", style, path, got[path], want)
+			}
+		}
+	}
+}
+
+func TestFieldInfoMetadataMinimal(t *testing.T) {
+	cfg := fieldInfoConfig{Database: fieldInfoDatabase{Host: "db", Extra: map[string]string{"sslmode": "off"}}}
+
+	got := make(map[string]FieldInfo)
+	transformer := WithValueTransformer(func(field FieldInfo, v interface{}) (interface{}, error) {
+		got[field.FieldPath] = field
+		return v, nil
+	})
+	if _, err := Gen(cfg, WithStyle(StyleMinimal), transformer); err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+
+	host := got["database.host"]
+	if !host.IsRequired || host.EnvVar != "DB_HOST" || host.Depth != 1 || host.Parent != "database" {
+		t.Errorf("unexpected database.host info: %+v", host)
+	}
+	mode := got["database.mode"]
+	if mode.Default != "rw" || !reflect.DeepEqual(mode.Enum, []string{"rw", "ro"}) {
+		t.Errorf("unexpected database.mode info: %+v", mode)
+	}
+	if sslmode := got["database.extra.sslmode"]; sslmode.Depth != 2 || sslmode.Parent != "database.extra" {
+		t.Errorf("unexpected database.extra.sslmode info: %+v", sslmode)
+	}
+}
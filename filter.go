@@ -0,0 +1,132 @@
+package yamlc
+
+import (
+	"path"
+	"reflect"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WithInclude 只输出匹配通配符的字段（如 "database.*"、"logging.**"），匹配字段的上级和下级字段一并输出
+// 通配符规则与 WithComment 的路径相同，列表元素的下标段可以省略
+func WithInclude(globs ...string) Option {
+	return func(o *Options) {
+		o.Include = append(o.Include, globs...)
+	}
+}
+
+// WithExclude 不输出匹配通配符的字段及其下级字段，优先于 WithInclude
+func WithExclude(globs ...string) Option {
+	return func(o *Options) {
+		o.Exclude = append(o.Exclude, globs...)
+	}
+}
+
+// isFieldFiltered 判断字段路径是否被包含/排除规则过滤掉
+func isFieldFiltered(fieldPath string, options *Options) bool {
+	for _, glob := range options.Exclude {
+		if matchFilterGlob(glob, fieldPath) {
+			return true
+		}
+	}
+	if len(options.Include) == 0 {
+		return false
+	}
+	for _, glob := range options.Include {
+		if matchFilterGlob(glob, fieldPath) || matchFilterPrefix(glob, fieldPath) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchFilterGlob 判断路径或其某个上级路径是否匹配通配符，同时尝试去掉列表下标的路径
+func matchFilterGlob(glob, fieldPath string) bool {
+	for _, candidate := range []string{fieldPath, stripIndexSegments(fieldPath)} {
+		segments := splitPath(candidate)
+		for i := len(segments); i > 0; i-- {
+			if matchSegments(splitPath(glob), segments[:i]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchFilterPrefix 判断路径是否可能是匹配通配符的字段的上级
+func matchFilterPrefix(glob, fieldPath string) bool {
+	for _, candidate := range []string{fieldPath, stripIndexSegments(fieldPath)} {
+		if matchSegmentsPrefix(splitPath(glob), splitPath(candidate)) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegmentsPrefix 判断路径段能否作为通配符匹配结果的前缀
+func matchSegmentsPrefix(patterns, segments []string) bool {
+	for len(segments) > 0 {
+		if len(patterns) == 0 {
+			return false
+		}
+		if patterns[0] == "**" {
+			return true
+		}
+		if ok, err := path.Match(patterns[0], segments[0]); err != nil || !ok {
+			return false
+		}
+		patterns = patterns[1:]
+		segments = segments[1:]
+	}
+	return len(patterns) > 0
+}
+
+// filterNode 在yaml节点树上应用包含/排除规则，用于不经过字段渲染的最小风格
+func filterNode(node *yaml.Node, val reflect.Value, fieldPath string, options *Options) {
+	if len(options.Include) == 0 && len(options.Exclude) == 0 {
+		return
+	}
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			break
+		}
+		val = val.Elem()
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) > 0 {
+			filterNode(node.Content[0], val, fieldPath, options)
+		}
+	case yaml.MappingNode:
+		content := node.Content[:0]
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			childPath := buildFieldPath(fieldPath, key.Value)
+			if isFieldFiltered(childPath, options) {
+				continue
+			}
+			var child reflect.Value
+			switch val.Kind() {
+			case reflect.Struct:
+				_, child, _ = findYAMLField(val, key.Value)
+			case reflect.Map:
+				if val.Type().Key().Kind() == reflect.String {
+					child = val.MapIndex(reflect.ValueOf(key.Value).Convert(val.Type().Key()))
+				}
+			}
+			filterNode(value, child, childPath, options)
+			content = append(content, key, value)
+		}
+		node.Content = content
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			var child reflect.Value
+			if (val.Kind() == reflect.Slice || val.Kind() == reflect.Array) && i < val.Len() {
+				child = val.Index(i)
+			}
+			filterNode(item, child, buildFieldPath(fieldPath, strconv.Itoa(i)), options)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package yamlc
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+type filterConfig struct {
+	Name     string `yaml:"name"`
+	Database struct {
+		Host     string `yaml:"host"`
+		Password string `yaml:"password"`
+	} `yaml:"database"`
+	Logging struct {
+		Level string `yaml:"level"`
+	} `yaml:"logging"`
+	Servers []struct {
+		Addr  string `yaml:"addr"`
+		Debug bool   `yaml:"debug"`
+	} `yaml:"servers"`
+}
+
+func TestFieldFilter(t *testing.T) {
+	var cfg filterConfig
+	cfg.Name = "app"
+	cfg.Database.Host = "db"
+	cfg.Database.Password = "secret"
+	cfg.Logging.Level = "info"
+	cfg.Servers = append(cfg.Servers, struct {
+		Addr  string `yaml:"addr"`
+		Debug bool   `yaml:"debug"`
+	}{Addr: ":80", Debug: true})
+
+	testCases := []struct {
+		name     string
+		opts     []Option
+		expected map[string]interface{}
+	}{
+		{
+			"include",
+			[]Option{WithInclude("database.*", "logging.*")},
+			map[string]interface{}{
+				"database": map[string]interface{}{"host": "db", "password": "secret"},
+				"logging":  map[string]interface{}{"level": "info"},
+			},
+		},
+		{
+			"include and exclude",
+			[]Option{WithInclude("database", "servers.addr"), WithExclude("**.password")},
+			map[string]interface{}{
+				"database": map[string]interface{}{"host": "db"},
+				"servers":  []interface{}{map[string]interface{}{"addr": ":80"}},
+			},
+		},
+		{
+			"exclude",
+			[]Option{WithExclude("database", "servers.*.debug")},
+			map[string]interface{}{
+				"name":    "app",
+				"logging": map[string]interface{}{"level": "info"},
+				"servers": []interface{}{map[string]interface{}{"addr": ":80"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, style := range GetAllStyle() {
+			data, err := Gen(cfg, append(tc.opts, WithStyle(style))...)
+			if err != nil {
+				t.Fatalf("%s/%s: Gen failed: %v", tc.name, GetStyleString(int(style)), err)
+			}
+			var decoded map[string]interface{}
+			if err := yaml.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("%s/%s: Unmarshal failed: %v\n%s", tc.name, GetStyleString(int(style)), err, data)
+			}
+			if !reflect.DeepEqual(decoded, tc.expected) {
+				t.Errorf("%s/%s: got %v\n%s", tc.name, GetStyleString(int(style)), decoded, data)
+			}
+		}
+	}
+}
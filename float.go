@@ -0,0 +1,101 @@
+package yamlc
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WithSpecialFloats 允许将 NaN 和 ±Inf 输出为YAML规范中的 .nan、.inf、-.inf
+// 默认（严格模式）遇到这些值时返回错误
+func WithSpecialFloats(allow bool) Option {
+	return func(o *Options) {
+		o.SpecialFloats = allow
+	}
+}
+
+// formatSpecialFloat 返回 NaN 和 ±Inf 在YAML中的表示，普通数值返回false
+func formatSpecialFloat(f float64) (string, bool) {
+	switch {
+	case math.IsNaN(f):
+		return ".nan", true
+	case math.IsInf(f, 1):
+		return ".inf", true
+	case math.IsInf(f, -1):
+		return "-.inf", true
+	}
+	return "", false
+}
+
+// WithFloatFormat 设置浮点数的格式化动词，如 "%.2f"、"%e"、"%g"
+// 默认 float32 使用 "%.7g"，float64 使用 "%.15g"；字段可通过 precision= 标签指定固定小数位
+func WithFloatFormat(format string) Option {
+	return func(o *Options) {
+		o.FloatFormat = format
+	}
+}
+
+// getPrecisionFormat 获取字段通过 precision= 标签指定的定点格式
+func getPrecisionFormat(field reflect.StructField) (string, bool) {
+	value, ok := getTagValue(field, "precision")
+	if !ok {
+		return "", false
+	}
+	precision, err := strconv.Atoi(value)
+	if err != nil || precision < 0 {
+		return "", false
+	}
+	return fmt.Sprintf("%%.%df", precision), true
+}
+
+// formatFloat 按选项格式化浮点数，格式化结果必须仍是合法的数字
+func formatFloat(f float64, kind reflect.Kind, options *Options) (string, error) {
+	format := options.FloatFormat
+	if format == "" {
+		if kind == reflect.Float32 {
+			return fmt.Sprintf("%.7g", float32(f)), nil
+		}
+		return fmt.Sprintf("%.15g", f), nil
+	}
+
+	var result string
+	if kind == reflect.Float32 {
+		result = fmt.Sprintf(format, float32(f))
+	} else {
+		result = fmt.Sprintf(format, f)
+	}
+	if _, err := strconv.ParseFloat(result, 64); err != nil {
+		return "", fmt.Errorf("float format %q produced non-numeric value %q", format, result)
+	}
+	return result, nil
+}
+
+// formatFloatNode 在yaml节点上输出浮点数：NaN 和 ±Inf 按 SpecialFloats 输出或返回错误，
+// 设置了浮点数格式时按 formatFloat 格式化，未设置时保留 yaml.v3 的最短表示
+func formatFloatNode(node *yaml.Node, val reflect.Value, options *Options) error {
+	// yaml.v3 按文本推断标签，整数值的浮点数（如 12）编码为 !!int
+	if (node.Tag != "!!float" && node.Tag != "!!int") || (val.Kind() != reflect.Float32 && val.Kind() != reflect.Float64) {
+		return nil
+	}
+	floatVal := val.Float()
+	if special, ok := formatSpecialFloat(floatVal); ok {
+		if !options.SpecialFloats {
+			return fmt.Errorf("invalid float value: %f", floatVal)
+		}
+		node.Value = special
+		return nil
+	}
+	if options.FloatFormat == "" {
+		return nil
+	}
+	value, err := formatFloat(floatVal, val.Kind(), options)
+	if err != nil {
+		return err
+	}
+	// 格式化后可能在整数和小数形式之间变化，清空标签由 yaml.v3 按新文本推断
+	node.Value, node.Tag = value, ""
+	return nil
+}
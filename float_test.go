@@ -0,0 +1,93 @@
+package yamlc
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSpecialFloats(t *testing.T) {
+	type Metrics struct {
+		Ratio float64 `yaml:"ratio"`
+		Max   float64 `yaml:"max"`
+		Min   float32 `yaml:"min"`
+	}
+	metrics := Metrics{Ratio: math.NaN(), Max: math.Inf(1), Min: float32(math.Inf(-1))}
+
+	for _, style := range []CommentStyle{StyleTop, StyleMinimal} {
+		if _, err := Gen(metrics, WithStyle(style)); err == nil {
+			t.Errorf("style %s: expected error for NaN without WithSpecialFloats", GetStyleString(int(style)))
+		}
+		if _, err := Gen(map[string]interface{}{"ratio": math.Inf(1)}, WithStyle(style)); err == nil {
+			t.Errorf("style %s: expected error for Inf without WithSpecialFloats", GetStyleString(int(style)))
+		}
+	}
+
+	var data []byte
+	for _, style := range []CommentStyle{StyleTop, StyleMinimal} {
+		var err error
+		data, err = Gen(metrics, WithStyle(style), WithSpecialFloats(true))
+		if err != nil {
+			t.Fatalf("style %s: Gen failed: %v", GetStyleString(int(style)), err)
+		}
+		for _, expected := range []string{"ratio: .nan\n", "max: .inf\n", "min: -.inf\n"} {
+			if !strings.Contains(string(data), expected) {
+				t.Errorf("style %s: expected %q in:\n%s", GetStyleString(int(style)), expected, data)
+			}
+		}
+	}
+
+	var decoded Metrics
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !math.IsNaN(decoded.Ratio) || !math.IsInf(decoded.Max, 1) || !math.IsInf(float64(decoded.Min), -1) {
+		t.Errorf("special floats did not round-trip: %+v", decoded)
+	}
+}
+
+func TestFloatFormat(t *testing.T) {
+	type Invoice struct {
+		Rate   float64   `yaml:"rate"`
+		Amount float64   `yaml:"amount" yamlc:"precision=2"`
+		Prices []float64 `yaml:"prices" yamlc:"precision=1"`
+		Count  float32   `yaml:"count"`
+	}
+	invoice := Invoice{Rate: 0.5, Amount: 12, Prices: []float64{1, 2.25}, Count: 1e6}
+
+	for _, style := range []CommentStyle{StyleTop, StyleMinimal} {
+		data, err := Gen(invoice, WithStyle(style))
+		if err != nil {
+			t.Fatalf("style %s: Gen failed: %v", GetStyleString(int(style)), err)
+		}
+		for _, expected := range []string{"rate: 0.5\n", "amount: 12.00\n", "- 1.0\n", "- 2.2\n"} {
+			if !strings.Contains(string(data), expected) {
+				t.Errorf("style %s: expected %q in:\n%s", GetStyleString(int(style)), expected, data)
+			}
+		}
+
+		data, err = Gen(invoice, WithStyle(style), WithFloatFormat("%.2f"))
+		if err != nil {
+			t.Fatalf("style %s: Gen failed: %v", GetStyleString(int(style)), err)
+		}
+		for _, expected := range []string{"rate: 0.50\n", "amount: 12.00\n", "- 1.0\n", "count: 1000000.00\n"} {
+			if !strings.Contains(string(data), expected) {
+				t.Errorf("style %s: expected %q in:\n%s", GetStyleString(int(style)), expected, data)
+			}
+		}
+
+		if _, err := Gen(invoice, WithStyle(style), WithFloatFormat("%v%%")); err == nil {
+			t.Errorf("style %s: expected error for non-numeric float format", GetStyleString(int(style)))
+		}
+	}
+
+	data, err := Gen(invoice, WithStyle(StyleTop))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if !strings.Contains(string(data), "count: 1000000\n") {
+		t.Errorf("expected default float32 precision in:\n%s", data)
+	}
+}
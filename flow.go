@@ -0,0 +1,147 @@
+package yamlc
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WithFlowThreshold 元素个数少于 n 且没有元素注释的简单切片以流式风格输出，如 tags: [dev, go]
+// n<=0 表示不使用流式风格
+func WithFlowThreshold(n int) Option {
+	return func(o *Options) {
+		o.FlowThreshold = n
+	}
+}
+
+// isFlowSlice 判断切片是否以流式风格输出：元素个数低于阈值且未被截断、元素均为单行简单值且没有按下标指定的注释
+func isFlowSlice(val reflect.Value, fieldPath string, options *Options) bool {
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return false
+	}
+	if options.FlowThreshold <= 0 || val.Len() == 0 || val.Len() >= options.FlowThreshold {
+		return false
+	}
+	// 截断的列表需要逐项输出，才能附加省略说明
+	if itemLimit(val.Len(), options) < val.Len() {
+		return false
+	}
+	for i := 0; i < val.Len(); i++ {
+		item := val.Index(i)
+		if isComplexType(item) {
+			return false
+		}
+		for item.Kind() == reflect.Ptr || item.Kind() == reflect.Interface {
+			if item.IsNil() {
+				break
+			}
+			item = item.Elem()
+		}
+		if item.Kind() == reflect.String && strings.Contains(item.String(), "\n") {
+			return false
+		}
+		if _, ok := lookupIndexedComment(buildFieldPath(fieldPath, strconv.Itoa(i)), options); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// generateFlowSlice 生成流式风格的切片，与空切片一样以空格开头
+func generateFlowSlice(val reflect.Value, fieldPath string, indent int, options *Options) (string, error) {
+	items := make([]string, 0, val.Len())
+	for i := 0; i < val.Len(); i++ {
+		item := val.Index(i)
+		if skipUnsupported(item, buildFieldPath(fieldPath, strconv.Itoa(i)), options) {
+			continue
+		}
+		for (item.Kind() == reflect.Ptr || item.Kind() == reflect.Interface) && !item.IsNil() {
+			item = item.Elem()
+		}
+
+		if item.Kind() == reflect.String {
+			str := item.String()
+			if err := validateStringContent(str); err != nil {
+				return "", fmt.Errorf("invalid string content: %w", err)
+			}
+			items = append(items, quoteFlowString(str, options))
+			continue
+		}
+
+		itemStr, err := generateValue(item, buildFieldPath(fieldPath, strconv.Itoa(i)), indent+1, options)
+		if err != nil {
+			return "", err
+		}
+		itemStr = strings.TrimSpace(itemStr)
+		if itemStr == "" {
+			// 流式上下文中空值不能省略
+			itemStr = "null"
+		}
+		items = append(items, itemStr)
+	}
+	return fmt.Sprintf(" [%s]\n", strings.Join(items, ", ")), nil
+}
+
+// quoteFlowString 按引号风格输出流式序列中的字符串元素：只有在块上下文中可以原样输出、
+// 且放在流式序列中能原样读回时才不加引号（流式上下文中逗号、括号等也是指示符）
+func quoteFlowString(str string, options *Options) string {
+	if options.QuoteStyle != QuoteAlways && !needsQuotingFor(str, options) && (isSimplePlain(str) || isFlowPlain(str)) {
+		return str
+	}
+	return quote(str, options.QuoteStyle)
+}
+
+// isFlowPlain 判断字符串作为流式序列的普通标量元素时能否原样读回
+func isFlowPlain(str string) bool {
+	var items []interface{}
+	if err := yaml.Unmarshal([]byte("["+str+"]"), &items); err != nil || len(items) != 1 {
+		return false
+	}
+	value, ok := items[0].(string)
+	return ok && value == str
+}
+
+// hasBlockItems 判断切片是否在后续行逐项输出（非空、未被截断且不是流式风格或字节切片）
+func hasBlockItems(val reflect.Value, fieldPath string, options *Options) bool {
+	return (val.Kind() == reflect.Slice || val.Kind() == reflect.Array) &&
+		val.Len() > 0 && !isByteSlice(val) && !exceedsDepth(val, options) && !isFlowSlice(val, fieldPath, options)
+}
+
+// flowNode 在yaml节点树上将满足条件的简单序列设为流式风格，用于不经过字段渲染的最小风格
+// 带省略说明（写在键的行尾、元素上方或文档末尾）的序列保持块风格，yaml.v3 不能在流式序列之后写行尾注释
+func flowNode(node *yaml.Node, options *Options) {
+	if options.FlowThreshold <= 0 {
+		return
+	}
+	flowNodeTree(node, node.FootComment != "", options)
+}
+
+// flowNodeTree 递归设置流式风格，truncated 表示节点带有省略说明
+func flowNodeTree(node *yaml.Node, truncated bool, options *Options) {
+	if !truncated && node.Kind == yaml.SequenceNode && len(node.Content) > 0 && len(node.Content) < options.FlowThreshold {
+		flow := true
+		for _, item := range node.Content {
+			if item.Kind != yaml.ScalarNode || strings.Contains(item.Value, "\n") {
+				flow = false
+				break
+			}
+		}
+		if flow {
+			node.Style |= yaml.FlowStyle
+			return
+		}
+	}
+	for i, child := range node.Content {
+		switch node.Kind {
+		case yaml.MappingNode:
+			flowNodeTree(child, i%2 == 1 && node.Content[i-1].LineComment != "", options)
+		case yaml.SequenceNode:
+			flowNodeTree(child, child.HeadComment != "", options)
+		default:
+			flowNodeTree(child, false, options)
+		}
+	}
+}
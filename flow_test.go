@@ -0,0 +1,99 @@
+package yamlc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+type flowServer struct {
+	Name  string `yaml:"name" comment:"名称"`
+	Ports []int  `yaml:"ports" comment:"端口"`
+}
+
+type flowConfig struct {
+	Tags    []string     `yaml:"tags" comment:"标签"`
+	Hosts   []string     `yaml:"hosts" comment:"主机"`
+	Servers []flowServer `yaml:"servers" comment:"服务"`
+}
+
+func TestWithFlowThreshold(t *testing.T) {
+	cfg := flowConfig{
+		Tags:    []string{"dev", "go, rust", "true"},
+		Hosts:   []string{"a", "b", "c", "d"},
+		Servers: []flowServer{{Name: "web", Ports: []int{80, 443}}},
+	}
+
+	data, err := Gen(cfg, WithFlowThreshold(4))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	yamlStr := string(data)
+	for _, expected := range []string{
+		"tags: [dev, 'go, rust', \"true\"]\n",
+		"hosts:\n  - a\n",
+		"    ports: [80, 443]\n",
+	} {
+		if !strings.Contains(yamlStr, expected) {
+			t.Errorf("expected %q in:\n%s", expected, yamlStr)
+		}
+	}
+
+	// 元素带注释时保持块风格
+	data, err = Gen(cfg, WithFlowThreshold(4), WithComment(map[string]string{"tags.1": "多语言"}))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if !strings.Contains(string(data), "tags:\n  - dev\n") {
+		t.Errorf("slice with element comments should not use flow style:\n%s", data)
+	}
+
+	for _, style := range GetAllStyle() {
+		data, err := Gen(cfg, WithStyle(style), WithFlowThreshold(4))
+		if err != nil {
+			t.Errorf("style %s failed: %v", GetStyleString(int(style)), err)
+			continue
+		}
+		if !strings.Contains(string(data), "[80, 443]") {
+			t.Errorf("style %s should render ports in flow style:\n%s", GetStyleString(int(style)), data)
+		}
+		var decoded flowConfig
+		if err := yaml.Unmarshal(data, &decoded); err != nil {
+			t.Errorf("style %s produced invalid yaml: %v\n%s", GetStyleString(int(style)), err, data)
+			continue
+		}
+		if !reflect.DeepEqual(decoded, cfg) {
+			t.Errorf("style %s did not round-trip:\n%s", GetStyleString(int(style)), data)
+		}
+	}
+}
+
+func TestFlowSliceIndicators(t *testing.T) {
+	type Config struct {
+		Tags []string `yaml:"tags"`
+	}
+
+	for _, item := range []string{
+		"x?", "?x", "? x", "x:", "a: b", "a:b", "#x", "a #b", "&a", "*a", "!a", "|a", ">a",
+		"'a", "a'b", "\"a", "%a", "@a", "`a", "-a", "- a", "-", " a", "a ", "a,b", "[a", "a]", "{a", "a}",
+		"http://example.com", "true", "1", "~",
+	} {
+		cfg := Config{Tags: []string{item, "z"}}
+		for _, style := range []CommentStyle{StyleTop, StyleMinimal} {
+			data, err := Gen(cfg, WithStyle(style), WithFlowThreshold(3))
+			if err != nil {
+				t.Errorf("item %q, style %s: Gen failed: %v", item, GetStyleString(int(style)), err)
+				continue
+			}
+			if !strings.HasPrefix(string(data), "tags: [") {
+				t.Errorf("item %q, style %s: expected flow style:\n%s", item, GetStyleString(int(style)), data)
+			}
+			var decoded Config
+			if err := yaml.Unmarshal(data, &decoded); err != nil || !reflect.DeepEqual(decoded, cfg) {
+				t.Errorf("item %q, style %s: did not round-trip (%v):\n%s", item, GetStyleString(int(style)), err, data)
+			}
+		}
+	}
+}
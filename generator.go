@@ -0,0 +1,115 @@
+package yamlc
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// Generator 预先编译的生成器，选项只构建和校验一次，类型的字段生成计划在编译时准备好
+// 可在多个goroutine中并发使用
+type Generator[T any] struct {
+	options *Options
+	// mu 保护编译时设置的输出目标（WithSourceMap、WithWarnings），每次调用结束后在锁内合并
+	mu sync.Mutex
+}
+
+// Compile 为类型 T 编译生成器：校验选项并预先解析 T 及其嵌套结构体的标签，
+// 适合反复生成同一类型配置的场景
+func Compile[T any](opts ...Option) (*Generator[T], error) {
+	options := newOptions(opts...)
+	if err := ValidateOptions(options); err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Complex64, reflect.Complex128:
+		return nil, fmt.Errorf("unsupported type %s", typ)
+	}
+	prepareTypePlans(typ, map[reflect.Type]bool{})
+
+	return &Generator[T]{options: options}, nil
+}
+
+// Generate 生成YAML内容，opts 只作用于本次调用，如用 WithSourceMap、WithWarnings 为本次调用指定单独的输出目标
+func (g *Generator[T]) Generate(v T, opts ...Option) ([]byte, error) {
+	// 每次调用使用选项的副本，编译时设置的输出目标由所有调用共用，先写入本次调用自己的映射和列表
+	options := *g.options
+	var sourceMap map[string]LineRange
+	var warnings []Warning
+	if g.options.SourceMap != nil {
+		sourceMap = make(map[string]LineRange)
+		options.SourceMap = sourceMap
+	}
+	if g.options.Warnings != nil {
+		options.Warnings = &warnings
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	startWarnings(&options)
+	if len(opts) > 0 {
+		if err := ValidateOptions(&options); err != nil {
+			return nil, fmt.Errorf("invalid options: %w", err)
+		}
+	}
+
+	data, err := generate(v, &options)
+	g.merge(sourceMap, warnings)
+	return data, err
+}
+
+// merge 将一次调用的源码映射和警告合并到编译时设置的输出目标
+func (g *Generator[T]) merge(sourceMap map[string]LineRange, warnings []Warning) {
+	if len(sourceMap) == 0 && len(warnings) == 0 {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for path, r := range sourceMap {
+		g.options.SourceMap[path] = r
+	}
+	if len(warnings) > 0 {
+		*g.options.Warnings = append(*g.options.Warnings, warnings...)
+	}
+}
+
+// Write 生成YAML内容并写入到io.Writer，opts 与 Generate 相同，只作用于本次调用
+func (g *Generator[T]) Write(w io.Writer, v T, opts ...Option) error {
+	if w == nil {
+		return fmt.Errorf("writer cannot be nil")
+	}
+	data, err := g.Generate(v, opts...)
+	if err != nil {
+		return err
+	}
+	return writeData(w, data)
+}
+
+// prepareTypePlans 递归为类型中出现的所有结构体准备字段生成计划，visited 用于避免自引用类型无限递归
+func prepareTypePlans(typ reflect.Type, visited map[reflect.Type]bool) {
+	for {
+		switch typ.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			typ = typ.Elem()
+			continue
+		case reflect.Map:
+			prepareTypePlans(typ.Key(), visited)
+			typ = typ.Elem()
+			continue
+		}
+		break
+	}
+	if typ.Kind() != reflect.Struct || visited[typ] {
+		return
+	}
+	visited[typ] = true
+	for _, plan := range getTypePlan(typ) {
+		prepareTypePlans(plan.fieldType.Type, visited)
+	}
+}
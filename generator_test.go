@@ -0,0 +1,106 @@
+package yamlc
+
+import (
+	"bytes"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type compiledNode struct {
+	Name     string                  `yaml:"name" comment:"名称"`
+	Children []*compiledNode         `yaml:"children" comment:"子节点"`
+	Meta     map[string]compiledMeta `yaml:"meta"`
+}
+
+type compiledMeta struct {
+	Owner string `yaml:"owner"`
+}
+
+func TestCompile(t *testing.T) {
+	gen, err := Compile[compiledNode](WithStyle(StyleInline))
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	for _, typ := range []reflect.Type{reflect.TypeOf(compiledNode{}), reflect.TypeOf(compiledMeta{})} {
+		if _, ok := typePlans.Load(typ); !ok {
+			t.Errorf("plan for %s should be prepared at compile time", typ)
+		}
+	}
+
+	node := compiledNode{Name: "root", Children: []*compiledNode{{Name: "leaf"}}}
+	expected, err := Gen(node, WithStyle(StyleInline))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data, err := gen.Generate(node)
+			if err != nil || !bytes.Equal(data, expected) {
+				t.Errorf("Generate should match Gen: %v\n%s", err, data)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var buf bytes.Buffer
+	if err := gen.Write(&buf, node); err != nil || !bytes.Equal(buf.Bytes(), expected) {
+		t.Errorf("Write should match Gen: %v", err)
+	}
+
+	if _, err := Compile[chan int](); err == nil {
+		t.Error("expected error for unsupported type")
+	}
+	if _, err := Compile[compiledNode](WithStyle(CommentStyle(-1))); err == nil {
+		t.Error("expected error for invalid options")
+	}
+}
+
+func TestGeneratorConcurrentSourceMap(t *testing.T) {
+	shared := make(map[string]LineRange)
+	var warnings []Warning
+	gen, err := Compile[map[string]compiledMeta](WithSourceMap(shared), WithWarnings(&warnings))
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+
+	value := map[string]compiledMeta{"a: b": {Owner: "x"}, "c": {Owner: "y"}}
+	_, expected, err := GenSourceMap(value)
+	if err != nil {
+		t.Fatalf("GenSourceMap failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// 编译时设置的映射和每次调用单独的映射同时使用
+			perCall := make(map[string]LineRange)
+			if _, err := gen.Generate(value, WithSourceMap(perCall)); err != nil {
+				t.Errorf("Generate failed: %v", err)
+			}
+			if !reflect.DeepEqual(perCall, expected) {
+				t.Errorf("unexpected per-call source map: %v", perCall)
+			}
+			if _, err := gen.Generate(value); err != nil {
+				t.Errorf("Generate failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if !reflect.DeepEqual(shared, expected) {
+		t.Errorf("unexpected shared source map: %v", shared)
+	}
+	if len(warnings) != 16 || warnings[0].Kind != WarningKeyQuoted {
+		t.Errorf("expected one quoted-key warning per call, got %v", warnings)
+	}
+	if _, err := gen.Generate(value, WithStyle(CommentStyle(-1))); err == nil {
+		t.Error("expected error for invalid per-call options")
+	}
+}
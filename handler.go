@@ -0,0 +1,41 @@
+package yamlc
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Handler 使用默认配置创建输出当前配置的HTTP处理器
+func Handler(v func() interface{}, opts ...Option) http.Handler {
+	return Default().Handler(v, opts...)
+}
+
+// Handler 创建以带注释的YAML输出当前配置的HTTP处理器，适合挂载到 /debug/config 这类调试端点
+// 每次请求调用 v 获取当前值，输出方式与 GenRedacted 相同：带secret标签和名称看起来敏感的字段都会被屏蔽。只接受GET和HEAD请求
+func (c *Config) Handler(v func() interface{}, opts ...Option) http.Handler {
+	opts = append(append([]Option(nil), opts...), func(o *Options) {
+		o.redactAll = true
+	})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if v == nil {
+			http.Error(w, "config source cannot be nil", http.StatusInternalServerError)
+			return
+		}
+		data, err := c.Gen(v(), opts...)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to generate config: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		if r.Method == http.MethodHead {
+			return
+		}
+		w.Write(data)
+	})
+}
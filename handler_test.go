@@ -0,0 +1,84 @@
+package yamlc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	cfg := newSecretConfig()
+	handler := Handler(func() interface{} { return cfg }, WithStyle(StyleTop))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/config", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/yaml") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	body := rec.Body.String()
+	for _, leaked := range []string{"p@ssw0rd", "tok-123", "hunter2"} {
+		if strings.Contains(body, leaked) {
+			t.Errorf("secret %q leaked:\n%s", leaked, body)
+		}
+	}
+	if !strings.Contains(body, "# 用户名") || !strings.Contains(body, "user: admin") {
+		t.Errorf("expected commented config:\n%s", body)
+	}
+
+	// 每次请求读取当前值
+	cfg.User = "root"
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/config", nil))
+	if !strings.Contains(rec.Body.String(), "user: root") {
+		t.Errorf("expected updated value:\n%s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/debug/config", nil))
+	if rec.Code != http.StatusOK || rec.Body.Len() != 0 {
+		t.Errorf("unexpected HEAD response: %d %q", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/config", nil))
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") == "" {
+		t.Errorf("expected 405 with Allow header, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	Handler(func() interface{} { return nil }).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500 for nil value, got %d", rec.Code)
+	}
+}
+
+func TestHandlerInlineSecret(t *testing.T) {
+	type credentials struct {
+		User     string `yaml:"user"`
+		Password string `yaml:"password" yamlc:"secret"`
+		Token    string `yaml:"token"`
+	}
+	type Config struct {
+		credentials `yaml:",inline"`
+		Host        string `yaml:"host"`
+	}
+	cfg := Config{credentials: credentials{User: "admin", Password: "p@ssw0rd", Token: "tok-123"}, Host: "h"}
+
+	for _, style := range []CommentStyle{StyleTop, StyleMinimal} {
+		rec := httptest.NewRecorder()
+		Handler(func() interface{} { return cfg }, WithStyle(style)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/config", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("style %s: unexpected status %d: %s", GetStyleString(int(style)), rec.Code, rec.Body.String())
+		}
+		body := rec.Body.String()
+		for _, leaked := range []string{"p@ssw0rd", "tok-123"} {
+			if strings.Contains(body, leaked) {
+				t.Errorf("style %s: secret %q leaked:\n%s", GetStyleString(int(style)), leaked, body)
+			}
+		}
+	}
+}
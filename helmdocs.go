@@ -0,0 +1,39 @@
+package yamlc
+
+import "strings"
+
+// WithHelmDocs 以helm-docs格式输出注释，生成的values.yaml可直接用于Helm chart文档工具
+// 等同于 WithStyle(StyleHelmDocs)
+func WithHelmDocs() Option {
+	return WithStyle(StyleHelmDocs)
+}
+
+// generateHelmDocsStyleField 生成helm-docs风格字段：注释写在字段上方，形如 "# -- @param image.tag 说明"，
+// 示例值写在后续的注释行中，helm-docs会将其并入描述
+func generateHelmDocsStyleField(result *strings.Builder, field FieldInfo, indentStr string, options *Options) error {
+	if field.Comment != "" {
+		writeStrings(result, indentStr, "# ", helmDocsComment(field.FieldPath, field.Comment), "\n")
+	}
+	writeExampleLine(result, field, indentStr)
+	writeStrings(result, indentStr, field.Name, ":")
+
+	return generateFieldValue(result, field, indentStr, options)
+}
+
+// helmDocsComment helm-docs格式的注释文本（不含 "# "）
+func helmDocsComment(fieldPath, comment string) string {
+	return "-- @param " + fieldPath + " " + comment
+}
+
+// trimHelmDocsPrefix 去掉helm-docs注释的 "-- " 和 "@param 路径" 前缀，只保留说明
+func trimHelmDocsPrefix(line string) string {
+	if !strings.HasPrefix(line, "-- ") {
+		return line
+	}
+	line = strings.TrimPrefix(line, "-- ")
+	if strings.HasPrefix(line, "@param ") {
+		_, comment, _ := strings.Cut(strings.TrimPrefix(line, "@param "), " ")
+		return strings.TrimSpace(comment)
+	}
+	return line
+}
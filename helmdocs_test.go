@@ -0,0 +1,39 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHelmDocsStyle(t *testing.T) {
+	type Image struct {
+		Repository string `yaml:"repository" comment:"镜像仓库"`
+		Tag        string `yaml:"tag" comment:"镜像标签" yamlc:"example=1.2.3"`
+	}
+	type Values struct {
+		ReplicaCount int   `yaml:"replicaCount" comment:"副本数"`
+		Image        Image `yaml:"image" comment:"镜像配置"`
+	}
+
+	data, err := Gen(Values{ReplicaCount: 1, Image: Image{Repository: "nginx", Tag: "latest"}}, WithHelmDocs())
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	expected := "# -- @param replicaCount 副本数\nreplicaCount: 1\n# -- @param image 镜像配置\nimage:\n" +
+		"  # -- @param image.repository 镜像仓库\n  repository: nginx\n  # -- @param image.tag 镜像标签\n  # e.g. tag: 1.2.3\n  tag: latest\n"
+	if !strings.HasPrefix(string(data), expected) {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", data, expected)
+	}
+	if GetStyleFromString("helm-docs") != StyleHelmDocs {
+		t.Error("helm-docs style name should resolve")
+	}
+
+	// 读取注释时去掉 "-- @param 路径" 前缀
+	comments, err := ExtractComments(data)
+	if err != nil {
+		t.Fatalf("ExtractComments failed: %v", err)
+	}
+	if comments["image.tag"] != "镜像标签\ne.g. tag: 1.2.3" || comments["replicaCount"] != "副本数" {
+		t.Errorf("unexpected extracted comments: %v", comments)
+	}
+}
@@ -0,0 +1,29 @@
+package yamlc
+
+import "strings"
+
+// FieldHook 字段生成钩子，可向当前层级的输出中写入自定义内容（分隔行、附加注释等）
+type FieldHook func(result *strings.Builder, field FieldInfo)
+
+// WithFieldHook 设置字段生成前后调用的钩子，参数为nil时不调用
+// 钩子写入的内容需要自行处理缩进；最小风格不经过字段渲染，不调用钩子
+func WithFieldHook(before, after FieldHook) Option {
+	return func(o *Options) {
+		o.BeforeField = before
+		o.AfterField = after
+	}
+}
+
+// runBeforeField 在字段输出之前调用钩子
+func runBeforeField(result *strings.Builder, field FieldInfo, options *Options) {
+	if options.BeforeField != nil {
+		options.BeforeField(result, field)
+	}
+}
+
+// runAfterField 在字段输出之后调用钩子
+func runAfterField(result *strings.Builder, field FieldInfo, options *Options) {
+	if options.AfterField != nil {
+		options.AfterField(result, field)
+	}
+}
@@ -0,0 +1,54 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithFieldHook(t *testing.T) {
+	type Server struct {
+		Host string `yaml:"host" comment:"主机"`
+		Port int    `yaml:"port" comment:"端口"`
+	}
+
+	var before, after []string
+	hook := WithFieldHook(
+		func(result *strings.Builder, field FieldInfo) {
+			before = append(before, field.FieldPath)
+			if field.FieldPath == "port" {
+				result.WriteString("# ---\n")
+			}
+		},
+		func(result *strings.Builder, field FieldInfo) {
+			after = append(after, field.FieldPath)
+		},
+	)
+
+	data, err := Gen(Server{Host: "localhost", Port: 80}, WithStyle(StyleTop), hook)
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if !strings.Contains(string(data), "host: localhost\n# ---\n# 端口\nport: 80") {
+		t.Errorf("before hook output should precede the field:\n%s", data)
+	}
+	if strings.Join(before, ",") != "host,port" || strings.Join(after, ",") != "host,port" {
+		t.Errorf("unexpected hook calls: before=%v after=%v", before, after)
+	}
+
+	for _, style := range GetAllStyle() {
+		before, after = nil, nil
+		if _, err := Gen(Server{Host: "localhost", Port: 80}, WithStyle(style), hook); err != nil {
+			t.Errorf("style %s failed: %v", GetStyleString(int(style)), err)
+			continue
+		}
+		if style == StyleMinimal {
+			if len(before) != 0 {
+				t.Errorf("minimal style should not call hooks: %v", before)
+			}
+			continue
+		}
+		if len(before) != 2 || len(after) != 2 {
+			t.Errorf("style %s: expected hooks for both fields, got before=%v after=%v", GetStyleString(int(style)), before, after)
+		}
+	}
+}
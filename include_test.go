@@ -0,0 +1,106 @@
+package yamlc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type includeDatabase struct {
+	Host string `yaml:"host" yamlc:"comment=主机"`
+	Port int    `yaml:"port"`
+}
+
+type includeConfig struct {
+	Name     string          `yaml:"name"`
+	Database includeDatabase `yaml:"database" yamlc:"comment=数据库,include=db.yaml"`
+}
+
+func TestIncludeTag(t *testing.T) {
+	cfg := includeConfig{Name: "app", Database: includeDatabase{Host: "localhost", Port: 5432}}
+
+	testCases := []struct {
+		style    CommentStyle
+		opts     []Option
+		expected string
+	}{
+		{StyleTop, nil, "name: app\n# 数据库\ndatabase: !include db.yaml\n"},
+		{StyleMinimal, nil, "name: app\ndatabase: !include db.yaml\n"},
+		{StyleTop, []Option{WithIncludeStyle(IncludeRef)}, "name: app\n# 数据库\ndatabase:\n  $ref: db.yaml\n"},
+		{StyleMinimal, []Option{WithIncludeStyle(IncludeRef)}, "name: app\ndatabase:\n  $ref: db.yaml\n"},
+	}
+	for _, tc := range testCases {
+		opts := append([]Option{WithStyle(tc.style), WithIndent(2), WithBlankLines(BlankLinesNone)}, tc.opts...)
+		data, err := Gen(cfg, opts...)
+		if err != nil {
+			t.Fatalf("Gen failed: %v", err)
+		}
+		if string(data) != tc.expected {
+			t.Errorf("style %s: unexpected output:\n%s\nexpected:\n%s", GetStyleString(int(tc.style)), data, tc.expected)
+		}
+	}
+}
+
+func TestWithIncludeFiles(t *testing.T) {
+	type Config struct {
+		Name     string          `yaml:"name"`
+		Database includeDatabase `yaml:"database"`
+	}
+	cfg := Config{Name: "app", Database: includeDatabase{Host: "localhost", Port: 5432}}
+
+	for _, style := range []CommentStyle{StyleTop, StyleMinimal} {
+		data, err := Gen(cfg, WithStyle(style), WithIncludeFiles(map[string]string{"database": "conf/db.yaml"}), WithBlankLines(BlankLinesNone))
+		if err != nil {
+			t.Fatalf("Gen failed: %v", err)
+		}
+		if string(data) != "name: app\ndatabase: !include conf/db.yaml\n" {
+			t.Errorf("style %s: unexpected output:\n%s", GetStyleString(int(style)), data)
+		}
+	}
+}
+
+func TestWriteFileWithIncludes(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.yaml")
+	cfg := includeConfig{Name: "app", Database: includeDatabase{Host: "localhost", Port: 5432}}
+
+	if err := WriteFileWithIncludes(filename, cfg, WithStyle(StyleTop), WithIndent(2), WithBlankLines(BlankLinesNone)); err != nil {
+		t.Fatalf("WriteFileWithIncludes failed: %v", err)
+	}
+
+	main, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("failed to read main file: %v", err)
+	}
+	if !strings.Contains(string(main), "database: !include db.yaml\n") {
+		t.Errorf("main file missing include:\n%s", main)
+	}
+
+	included, err := os.ReadFile(filepath.Join(dir, "db.yaml"))
+	if err != nil {
+		t.Fatalf("failed to read included file: %v", err)
+	}
+	if string(included) != "# 主机\nhost: localhost\nport: 5432\n" {
+		t.Errorf("unexpected included file:\n%s", included)
+	}
+}
+
+func TestWriteFileWithIncludesCommentsByFullPath(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.yaml")
+	cfg := includeConfig{Name: "app", Database: includeDatabase{Host: "localhost", Port: 5432}}
+
+	err := WriteFileWithIncludes(filename, cfg, WithStyle(StyleTop), WithBlankLines(BlankLinesNone),
+		WithComment(map[string]string{"database.port": "端口"}))
+	if err != nil {
+		t.Fatalf("WriteFileWithIncludes failed: %v", err)
+	}
+	included, err := os.ReadFile(filepath.Join(dir, "db.yaml"))
+	if err != nil {
+		t.Fatalf("failed to read included file: %v", err)
+	}
+	if !strings.Contains(string(included), "# 端口\nport: 5432\n") {
+		t.Errorf("comment for full path not applied:\n%s", included)
+	}
+}
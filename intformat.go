@@ -0,0 +1,61 @@
+package yamlc
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// intBases format= 标签支持的整数进制及YAML前缀
+var intBases = map[string]struct {
+	base   int
+	prefix string
+}{
+	"hex":    {16, "0x"},
+	"octal":  {8, "0o"},
+	"binary": {2, "0b"},
+}
+
+// getValueFormat 获取字段通过 format= 标签指定的输出格式（整数进制或字节切片编码），未设置或无法识别时返回空
+func getValueFormat(field reflect.StructField) string {
+	format, ok := getTagValue(field, "format")
+	if !ok {
+		return ""
+	}
+	if _, known := intBases[format]; known {
+		return format
+	}
+	if bytesFormats[format] {
+		return format
+	}
+	return ""
+}
+
+// formatIntBase 按进制格式化整数的绝对值和符号，如 0xFF、0o644、-0b101
+func formatIntBase(abs uint64, negative bool, format string) string {
+	b := intBases[format]
+	digits := b.prefix + strings.ToUpper(strconv.FormatUint(abs, b.base))
+	if negative {
+		return "-" + digits
+	}
+	return digits
+}
+
+// formatIntNode 将整数标量节点按进制格式化；已被屏蔽、加密或替换为其他类型的节点保持不变
+func formatIntNode(node *yaml.Node, val reflect.Value, format string) {
+	if _, ok := intBases[format]; !ok || node.Tag != "!!int" {
+		return
+	}
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n := val.Int(); n < 0 {
+			node.Value = formatIntBase(uint64(-n), true, format)
+		} else {
+			node.Value = formatIntBase(uint64(n), false, format)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		node.Value = formatIntBase(val.Uint(), false, format)
+	}
+}
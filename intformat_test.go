@@ -0,0 +1,57 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestIntFormat(t *testing.T) {
+	type File struct {
+		Mode   uint32 `yaml:"mode" yamlc:"format=octal"`
+		Flags  uint8  `yaml:"flags" yamlc:"format=hex"`
+		Mask   int    `yaml:"mask" yamlc:"format=binary"`
+		Offset int64  `yaml:"offset" yamlc:"format=hex"`
+		Size   int    `yaml:"size" yamlc:"format=roman"`
+	}
+	file := File{Mode: 0644, Flags: 0xFF, Mask: 5, Offset: -0x1A, Size: 10}
+
+	for _, style := range []CommentStyle{StyleTop, StyleMinimal} {
+		data, err := Gen(file, WithStyle(style))
+		if err != nil {
+			t.Fatalf("style %s: Gen failed: %v", GetStyleString(int(style)), err)
+		}
+		for _, expected := range []string{"mode: 0o644\n", "flags: 0xFF\n", "mask: 0b101\n", "offset: -0x1A\n", "size: 10\n"} {
+			if !strings.Contains(string(data), expected) {
+				t.Errorf("style %s: expected %q in:\n%s", GetStyleString(int(style)), expected, data)
+			}
+		}
+
+		var decoded File
+		if err := yaml.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("style %s: Unmarshal failed: %v", GetStyleString(int(style)), err)
+		}
+		if decoded != file {
+			t.Errorf("style %s: formatted integers did not round-trip: %+v", GetStyleString(int(style)), decoded)
+		}
+	}
+}
+
+func TestIntFormatMinimalNested(t *testing.T) {
+	type Entry struct {
+		Mode  *uint32 `yaml:"mode" yamlc:"format=octal"`
+		Token int     `yaml:"token" yamlc:"secret,format=hex"`
+	}
+	type Config struct {
+		Entries []Entry `yaml:"entries"`
+	}
+	mode := uint32(0755)
+	data, err := Gen(Config{Entries: []Entry{{Mode: &mode, Token: 255}}}, WithStyle(StyleMinimal))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if !strings.Contains(string(data), "mode: 0o755\n") || strings.Contains(string(data), "0xFF") {
+		t.Errorf("unexpected minimal output:\n%s", data)
+	}
+}
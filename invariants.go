@@ -0,0 +1,107 @@
+package yamlc
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CheckInvariants 检查 v 的生成结果满足基本不变式：能够生成、能被yaml.v3解析、
+// 解码回 v 的类型后与 v 本身经yaml.v3编码再解码的结果一致（无损往返），且各层级的缩进一致
+// 适合在用户的模糊测试中对任意值调用；屏蔽敏感值、过滤字段、值转换、MaxDepth/MaxItems 等有意改变内容的选项会被报告为往返不一致
+func CheckInvariants(v interface{}, opts ...Option) error {
+	return Default().CheckInvariants(v, opts...)
+}
+
+// CheckInvariants 按该配置检查生成结果的不变式，见包级函数 CheckInvariants
+func (c *Config) CheckInvariants(v interface{}, opts ...Option) error {
+	options := c.newOptions(opts...)
+	data, err := generate(v, options)
+	if err != nil {
+		return fmt.Errorf("generate: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parse: %w\n%s", err, data)
+	}
+	if len(doc.Content) > 0 {
+		step := 0
+		if err := checkNodeIndent(doc.Content[0], &step); err != nil {
+			return fmt.Errorf("indentation: %w\n%s", err, data)
+		}
+	}
+
+	typ := reflect.TypeOf(v)
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	reference, err := yaml.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("yaml.v3 cannot encode %s: %w", typ, err)
+	}
+	want, err := reencode(reference, typ, options)
+	if err != nil {
+		return fmt.Errorf("yaml.v3 cannot round-trip %s: %w", typ, err)
+	}
+	got, err := reencode(data, typ, options)
+	if err != nil {
+		return fmt.Errorf("round trip: %w\n%s", err, data)
+	}
+	if !bytes.Equal(got, want) {
+		return fmt.Errorf("round trip changed the value:\n%s", unifiedDiff(want, got, "expected", "generated"))
+	}
+	return nil
+}
+
+// reencode 将YAML解码为 typ 类型后重新编码，使两份内容可以按文本比较（NaN等值也能比较）
+func reencode(data []byte, typ reflect.Type, options *Options) ([]byte, error) {
+	value := reflect.New(typ)
+	if err := decodeYAML(data, value.Interface(), options); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(value.Interface())
+}
+
+// checkNodeIndent 检查缩进是否一致：整个文档中嵌套映射比所属的键多缩进同样的列数（由第一个嵌套映射确定），
+// 嵌套列表的 "-" 不早于所属的键、也不超过该列数（允许与键对齐的 indentless sequence）。流式集合不检查
+func checkNodeIndent(node *yaml.Node, step *int) error {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if isBlockCollection(value) && value.Line > key.Line {
+				offset := value.Column - key.Column
+				if value.Kind == yaml.MappingNode {
+					if *step == 0 && offset > 0 {
+						*step = offset
+					}
+					if offset != *step {
+						return fmt.Errorf("line %d: mapping under key %q is indented by %d columns, expected %d",
+							value.Line, key.Value, offset, *step)
+					}
+				} else if offset < 0 || (*step > 0 && offset > *step) {
+					return fmt.Errorf("line %d: sequence under key %q is indented by %d columns", value.Line, key.Value, offset)
+				}
+			}
+			if err := checkNodeIndent(value, step); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			if err := checkNodeIndent(item, step); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isBlockCollection 判断节点是否为非空的块风格映射或列表
+func isBlockCollection(node *yaml.Node) bool {
+	return (node.Kind == yaml.MappingNode || node.Kind == yaml.SequenceNode) &&
+		node.Style&yaml.FlowStyle == 0 && len(node.Content) > 0
+}
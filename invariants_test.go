@@ -0,0 +1,89 @@
+package yamlc
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+type invariantItem struct {
+	Name  string            `yaml:"name"  yamlc:"comment=名称"`
+	Attrs map[string]string `yaml:"attrs" yamlc:"comment=属性"`
+}
+
+type invariantConfig struct {
+	Title  string          `yaml:"title"   yamlc:"comment=标题"`
+	Text   string          `yaml:"text"    yamlc:"comment=多行文本"`
+	Ratio  float64         `yaml:"ratio"   yamlc:"comment=比例"`
+	Ptr    *int            `yaml:"ptr"     yamlc:"comment=指针"`
+	Items  []invariantItem `yaml:"items"   yamlc:"comment=条目"`
+	Secret string          `yaml:"secret"  yamlc:"comment=密钥,secret"`
+}
+
+func TestCheckInvariants(t *testing.T) {
+	n := 3
+	cfg := &invariantConfig{
+		Title: "key: value # not a comment",
+		Text:  "line1\nline2\n",
+		Ratio: 0.1,
+		Ptr:   &n,
+		Items: []invariantItem{
+			{Name: "a", Attrs: map[string]string{"k": "v", "a b": "c: d", "yes": "1"}},
+			{Name: ""},
+		},
+	}
+
+	for _, style := range GetAllStyle() {
+		if style == StyleCommentedDefaults {
+			// 取默认值的字段被整行注释，有意不可往返
+			continue
+		}
+		t.Run(GetStyleString(int(style)), func(t *testing.T) {
+			if err := CheckInvariants(cfg, WithStyle(style), WithExclude("secret")); err != nil {
+				t.Error(err)
+			}
+			if err := CheckInvariants(cfg, WithStyle(style), WithIndent(4), WithExclude("secret")); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	if err := CheckInvariants(&invariantConfig{Ratio: math.Inf(1)}, WithSpecialFloats(true), WithExclude("secret")); err != nil {
+		t.Errorf("special floats: %v", err)
+	}
+
+	// 敏感值被屏蔽，往返不一致
+	err := CheckInvariants(&invariantConfig{Secret: "hunter2"})
+	if err == nil || !strings.Contains(err.Error(), "round trip") {
+		t.Errorf("expected round trip error, got %v", err)
+	}
+
+	if err := CheckInvariants(nil); err == nil {
+		t.Error("expected error for nil value")
+	}
+}
+
+func TestCheckNodeIndent(t *testing.T) {
+	tests := []struct {
+		data  string
+		valid bool
+	}{
+		{"a:\n  b: 1\n  c:\n    - x\n", true},
+		{"a:\n- x\n", true},
+		{"a:\n  b: 1\nc:\n    d: 1\n", false},
+		{"a:\n  b:\n      - x\n", false},
+		{"a:\n  - b:\n      c: 1\n", true},
+		{"x:\n  y: 1\na:\n  - b:\n     c: 1\n", false},
+	}
+	for _, tt := range tests {
+		docs, err := decodeDocumentNodes([]byte(tt.data))
+		if err != nil {
+			t.Fatalf("decode %q: %v", tt.data, err)
+		}
+		step := 0
+		err = checkNodeIndent(docs[0].Content[0], &step)
+		if (err == nil) != tt.valid {
+			t.Errorf("checkNodeIndent(%q) = %v, want valid=%v", tt.data, err, tt.valid)
+		}
+	}
+}
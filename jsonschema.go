@@ -0,0 +1,195 @@
+package yamlc
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// jsonSchemaDraft 生成的JSON Schema版本
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	durationType      = reflect.TypeOf(time.Duration(0))
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// jsonSchema JSON Schema中用到的关键字
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 interface{}            `json:"type,omitempty"`
+	Default              interface{}            `json:"default,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
+	Examples             []interface{}          `json:"examples,omitempty"`
+	Minimum              *int                   `json:"minimum,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties interface{}            `json:"additionalProperties,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	MaxItems             *int                   `json:"maxItems,omitempty"`
+	Defs                 map[string]*jsonSchema `json:"$defs,omitempty"`
+}
+
+// jsonSchemaBuilder 生成过程中的状态，自引用的结构体放入 $defs 并以 $ref 引用
+type jsonSchemaBuilder struct {
+	visiting  map[reflect.Type]bool
+	recursive map[reflect.Type]bool
+	defs      map[string]*jsonSchema
+}
+
+// GenJSONSchema 根据结构体及其标签生成JSON Schema，供编辑器为yamlc生成的配置提供补全和校验
+// 注释转为 description，default= 转为 default，enum= 转为 enum，example= 转为 examples，
+// 带 required 标记的字段列入 required
+func GenJSONSchema(v interface{}) ([]byte, error) {
+	if v == nil {
+		return nil, ErrNilInput
+	}
+	typ := reflect.TypeOf(v)
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Complex64, reflect.Complex128:
+		return nil, fmt.Errorf("unsupported type %s", typ)
+	}
+
+	builder := &jsonSchemaBuilder{
+		visiting:  make(map[reflect.Type]bool),
+		recursive: make(map[reflect.Type]bool),
+		defs:      make(map[string]*jsonSchema),
+	}
+	schema := builder.schemaFor(typ)
+	if schema.Ref != "" {
+		// 顶层类型自引用时，正文直接展开，$defs 中保留一份供内部引用
+		schema = builder.defs[typ.Name()]
+	}
+	root := *schema
+	root.Schema = jsonSchemaDraft
+	root.Title = typ.Name()
+	if len(builder.defs) > 0 {
+		root.Defs = builder.defs
+	}
+
+	data, err := json.MarshalIndent(&root, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON schema: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// schemaFor 生成类型对应的Schema
+func (b *jsonSchemaBuilder) schemaFor(typ reflect.Type) *jsonSchema {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	switch {
+	case typ == durationType:
+		// 时长可以写成 "30s"，也可能以纳秒整数输出
+		return &jsonSchema{Type: []string{"string", "integer"}}
+	case reflect.PtrTo(typ).Implements(textMarshalerType) || typ.Implements(textMarshalerType):
+		return &jsonSchema{Type: "string"}
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return &jsonSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &jsonSchema{Type: "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		minimum := 0
+		return &jsonSchema{Type: "integer", Minimum: &minimum}
+	case reflect.Float32, reflect.Float64:
+		return &jsonSchema{Type: "number"}
+	case reflect.String:
+		return &jsonSchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return &jsonSchema{Type: "string"}
+		}
+		schema := &jsonSchema{Type: "array", Items: b.schemaFor(typ.Elem())}
+		if typ.Kind() == reflect.Array {
+			maxItems := typ.Len()
+			schema.MaxItems = &maxItems
+		}
+		return schema
+	case reflect.Map:
+		return &jsonSchema{Type: "object", AdditionalProperties: b.schemaFor(typ.Elem())}
+	case reflect.Struct:
+		return b.structSchema(typ)
+	}
+	// interface{} 等任意值
+	return &jsonSchema{}
+}
+
+// structSchema 生成结构体的Schema；自引用的结构体放入 $defs，出现处以 $ref 引用
+func (b *jsonSchemaBuilder) structSchema(typ reflect.Type) *jsonSchema {
+	ref := &jsonSchema{Ref: "#/$defs/" + typ.Name()}
+	if _, ok := b.defs[typ.Name()]; ok {
+		return ref
+	}
+	if b.visiting[typ] {
+		b.recursive[typ] = true
+		return ref
+	}
+	b.visiting[typ] = true
+	defer delete(b.visiting, typ)
+
+	schema := &jsonSchema{
+		Type:                 "object",
+		Properties:           make(map[string]*jsonSchema),
+		AdditionalProperties: false,
+	}
+	for _, plan := range getTypePlan(typ) {
+		field := FieldInfo{FieldType: plan.fieldType}
+		property := b.schemaFor(plan.fieldType.Type)
+		property.Description = plan.tagComment
+		if property.Ref == "" {
+			if value, ok := getDefaultValue(field); ok {
+				property.Default = schemaValue(value, plan.fieldType.Type)
+			}
+			for _, value := range getAllowedValues(field) {
+				property.Enum = append(property.Enum, schemaValue(value, plan.fieldType.Type))
+			}
+			if plan.example != "" {
+				property.Examples = []interface{}{schemaValue(plan.example, plan.fieldType.Type)}
+			}
+		}
+		schema.Properties[plan.name] = property
+		if hasTagFlag(plan.fieldType, "required") {
+			schema.Required = append(schema.Required, plan.name)
+		}
+	}
+
+	if b.recursive[typ] {
+		b.defs[typ.Name()] = schema
+		return ref
+	}
+	return schema
+}
+
+// schemaValue 将标签中的文本按字段类型解析为JSON值，解析失败或时长类型时保留文本
+func schemaValue(text string, typ reflect.Type) interface{} {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		if typ == durationType {
+			return text
+		}
+		value := reflect.New(typ)
+		if err := yaml.Unmarshal([]byte(text), value.Interface()); err == nil {
+			return value.Elem().Interface()
+		}
+	}
+	return text
+}
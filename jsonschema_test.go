@@ -0,0 +1,79 @@
+package yamlc
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type schemaNode struct {
+	Name     string        `yaml:"name" comment:"节点名称" yamlc:"required"`
+	Children []*schemaNode `yaml:"children" comment:"子节点"`
+}
+
+type schemaServer struct {
+	Host    string            `yaml:"host" comment:"监听地址" yamlc:"default=0.0.0.0,required"`
+	Port    uint16            `yaml:"port" comment:"端口" yamlc:"default=8080,example=443"`
+	Mode    string            `yaml:"mode" comment:"运行模式" yamlc:"enum=debug|release"`
+	Timeout time.Duration     `yaml:"timeout" yamlc:"default=30s"`
+	Ratio   float64           `yaml:"ratio"`
+	Debug   bool              `yaml:"debug" yamlc:"default=false"`
+	Labels  map[string]string `yaml:"labels"`
+	Extra   interface{}       `yaml:"extra"`
+	Tree    schemaNode        `yaml:"tree" comment:"节点树"`
+	secret  string
+}
+
+func TestGenJSONSchema(t *testing.T) {
+	data, err := GenJSONSchema(&schemaServer{})
+	if err != nil {
+		t.Fatalf("GenJSONSchema failed: %v", err)
+	}
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, data)
+	}
+	if schema["$schema"] != jsonSchemaDraft || schema["title"] != "schemaServer" || schema["additionalProperties"] != false {
+		t.Errorf("unexpected root schema:\n%s", data)
+	}
+	if !reflect.DeepEqual(schema["required"], []interface{}{"host"}) {
+		t.Errorf("unexpected required list: %v", schema["required"])
+	}
+
+	properties := schema["properties"].(map[string]interface{})
+	expected := map[string]map[string]interface{}{
+		"host":    {"type": "string", "description": "监听地址", "default": "0.0.0.0"},
+		"port":    {"type": "integer", "description": "端口", "default": 8080.0, "examples": []interface{}{443.0}, "minimum": 0.0},
+		"mode":    {"type": "string", "description": "运行模式", "enum": []interface{}{"debug", "release"}},
+		"timeout": {"type": []interface{}{"string", "integer"}, "default": "30s"},
+		"ratio":   {"type": "number"},
+		"debug":   {"type": "boolean", "default": false},
+		"labels":  {"type": "object", "additionalProperties": map[string]interface{}{"type": "string"}},
+		"extra":   {},
+		"tree":    {"$ref": "#/$defs/schemaNode", "description": "节点树"},
+	}
+	if len(properties) != len(expected) {
+		t.Errorf("expected %d properties, got %d:\n%s", len(expected), len(properties), data)
+	}
+	for name, want := range expected {
+		if got := properties[name]; !reflect.DeepEqual(got, map[string]interface{}(want)) {
+			t.Errorf("property %s: expected %v, got %v", name, want, got)
+		}
+	}
+
+	// 自引用的结构体放入 $defs
+	node := schema["$defs"].(map[string]interface{})["schemaNode"].(map[string]interface{})
+	children := node["properties"].(map[string]interface{})["children"].(map[string]interface{})
+	if children["items"].(map[string]interface{})["$ref"] != "#/$defs/schemaNode" {
+		t.Errorf("expected recursive reference, got %v", children)
+	}
+
+	if _, err := GenJSONSchema(nil); err == nil {
+		t.Error("expected error for nil value")
+	}
+	if _, err := GenJSONSchema(make(chan int)); err == nil {
+		t.Error("expected error for unsupported type")
+	}
+}
@@ -0,0 +1,63 @@
+package yamlc
+
+import "sort"
+
+// kubernetesKeyRanks Kubernetes清单中顶级键的规范顺序，其余键位于 metadata 与 spec 之间并保持原有顺序
+var kubernetesKeyRanks = map[string]int{
+	"apiVersion": 0,
+	"kind":       1,
+	"metadata":   2,
+	"spec":       4,
+	"status":     5,
+}
+
+// kubernetesOtherRank 非规范键的排序位置
+const kubernetesOtherRank = 3
+
+// kubernetesComments 与上游API文档一致的默认注释，用于没有注释的规范键
+var kubernetesComments = map[string]string{
+	"apiVersion": "APIVersion defines the versioned schema of this representation of an object.",
+	"kind":       "Kind is a string value representing the REST resource this object represents.",
+	"metadata":   "Standard object's metadata.",
+	"spec":       "Spec defines the desired state of the object.",
+	"status":     "Status defines the observed state of the object.",
+}
+
+// WithKubernetes 按Kubernetes清单的习惯输出：apiVersion、kind、metadata、spec、status 按规范顺序排列，
+// 这些键没有注释时使用上游API文档风格的注释，适合从Go类型生成CRD示例
+func WithKubernetes() Option {
+	return func(o *Options) {
+		o.Kubernetes = true
+	}
+}
+
+// WithStripStatus 不输出顶层的 status 字段，生成可直接apply的清单
+func WithStripStatus() Option {
+	return WithExclude("status")
+}
+
+// sortKubernetesFields 按Kubernetes规范顺序稳定排序清单顶层（depth 为0）的字段，嵌套结构体中的同名字段保持原有顺序
+func sortKubernetesFields(fields []FieldInfo, depth int, options *Options) {
+	if !options.Kubernetes || depth != 0 {
+		return
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		return kubernetesKeyRank(fields[i].Name) < kubernetesKeyRank(fields[j].Name)
+	})
+}
+
+// kubernetesKeyRank 获取键的规范排序位置
+func kubernetesKeyRank(name string) int {
+	if rank, ok := kubernetesKeyRanks[name]; ok {
+		return rank
+	}
+	return kubernetesOtherRank
+}
+
+// kubernetesComment 获取清单顶层（depth 为0）规范键的默认注释
+func kubernetesComment(name string, depth int, options *Options) string {
+	if !options.Kubernetes || depth != 0 {
+		return ""
+	}
+	return kubernetesComments[name]
+}
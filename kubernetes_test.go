@@ -0,0 +1,104 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+)
+
+type k8sMetadata struct {
+	Name string `yaml:"name" comment:"名称"`
+}
+
+type k8sWidget struct {
+	Spec       map[string]int `yaml:"spec"`
+	Status     string         `yaml:"status"`
+	Metadata   k8sMetadata    `yaml:"metadata"`
+	Data       string         `yaml:"data" comment:"数据"`
+	Kind       string         `yaml:"kind"`
+	APIVersion string         `yaml:"apiVersion" comment:"API版本"`
+}
+
+func TestWithKubernetes(t *testing.T) {
+	widget := k8sWidget{
+		Spec:       map[string]int{"replicas": 2},
+		Status:     "Ready",
+		Metadata:   k8sMetadata{Name: "demo"},
+		Data:       "x",
+		Kind:       "Widget",
+		APIVersion: "example.com/v1",
+	}
+
+	for _, style := range []CommentStyle{StyleTop, StyleMinimal} {
+		data, err := Gen(widget, WithStyle(style), WithKubernetes())
+		if err != nil {
+			t.Fatalf("Gen failed: %v", err)
+		}
+		var keys []string
+		for _, line := range strings.Split(string(data), "\n") {
+			if line != "" && line[0] != ' ' && line[0] != '#' {
+				keys = append(keys, strings.SplitN(line, ":", 2)[0])
+			}
+		}
+		if got := strings.Join(keys, ","); got != "apiVersion,kind,metadata,data,spec,status" {
+			t.Errorf("style %s: unexpected key order %s", GetStyleString(int(style)), got)
+		}
+	}
+
+	data, err := Gen(widget, WithKubernetes(), WithStripStatus())
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	yamlStr := string(data)
+	if strings.Contains(yamlStr, "status") {
+		t.Errorf("status should be stripped:\n%s", yamlStr)
+	}
+	for _, expected := range []string{
+		"# API版本\napiVersion: example.com/v1\n",
+		"# " + kubernetesComments["kind"] + "\nkind: Widget\n",
+		"# " + kubernetesComments["spec"] + "\nspec:\n",
+	} {
+		if !strings.Contains(yamlStr, expected) {
+			t.Errorf("expected %q in:\n%s", expected, yamlStr)
+		}
+	}
+}
+
+type k8sTemplate struct {
+	Spec     string      `yaml:"spec"`
+	Kind     string      `yaml:"kind"`
+	Metadata k8sMetadata `yaml:"metadata"`
+}
+
+type k8sDeployment struct {
+	Spec       k8sTemplate `yaml:"spec"`
+	Kind       string      `yaml:"kind"`
+	APIVersion string      `yaml:"apiVersion"`
+}
+
+func TestWithKubernetesNested(t *testing.T) {
+	deployment := k8sDeployment{
+		Spec:       k8sTemplate{Spec: "inner", Kind: "Template", Metadata: k8sMetadata{Name: "tpl"}},
+		Kind:       "Deployment",
+		APIVersion: "apps/v1",
+	}
+
+	for _, style := range []CommentStyle{StyleTop, StyleMinimal} {
+		data, err := Gen(deployment, WithStyle(style), WithKubernetes())
+		if err != nil {
+			t.Fatalf("Gen failed: %v", err)
+		}
+		var keys []string
+		for _, line := range strings.Split(string(data), "\n") {
+			if trimmed := strings.TrimSpace(line); trimmed != "" && trimmed[0] != '#' {
+				keys = append(keys, strings.SplitN(trimmed, ":", 2)[0])
+			}
+		}
+		// 只有顶层按规范顺序排列，嵌套结构体中的同名键保持声明顺序
+		if got := strings.Join(keys, ","); got != "apiVersion,kind,spec,spec,kind,metadata,name" {
+			t.Errorf("style %s: unexpected key order %q", GetStyleString(int(style)), got)
+		}
+		if style == StyleTop && strings.Count(string(data), kubernetesComments["kind"]) != 1 {
+			t.Errorf("default comments should only be added at the top level:\n%s", data)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package yamlc
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WithMaxDepth 限制嵌套层级，超过 n 层的结构体、映射和列表以注释代替，n<=0 表示不限制
+// 被截断的字段读取时为空值，适合输出运行时状态等不需要完整还原的场景
+func WithMaxDepth(n int) Option {
+	return func(o *Options) {
+		o.MaxDepth = n
+	}
+}
+
+// WithMaxItems 限制每个列表和映射输出的元素个数，其余元素以注释 "# ... N more items" 代替，n<=0 表示不限制
+func WithMaxItems(n int) Option {
+	return func(o *Options) {
+		o.MaxItems = n
+	}
+}
+
+// exceedsDepth 判断值是否为超过最大层级的非空容器
+func exceedsDepth(val reflect.Value, options *Options) bool {
+	return options.MaxDepth > 0 && options.depth >= options.MaxDepth && isComplexType(val) && !isByteSlice(val)
+}
+
+// truncateDepth 超过最大层级的容器输出为带说明的空值，与空切片一样以空格开头、不带换行
+func truncateDepth(val reflect.Value, fieldPath string, options *Options) (string, bool) {
+	if !exceedsDepth(val, options) {
+		return "", false
+	}
+	warn(options, WarningTruncated, fieldPath, "truncated at depth %d", options.MaxDepth)
+	return fmt.Sprintf(" %s # ... truncated at depth %d", nullValue(options), options.MaxDepth), true
+}
+
+// descend 返回进入下一层容器时使用的选项
+func descend(options *Options) *Options {
+	if options.MaxDepth <= 0 {
+		return options
+	}
+	child := *options
+	child.depth++
+	return &child
+}
+
+// itemLimit 返回容器实际输出的元素个数
+func itemLimit(n int, options *Options) int {
+	if options.MaxItems > 0 && n > options.MaxItems {
+		return options.MaxItems
+	}
+	return n
+}
+
+// moreItemsComment 生成被省略元素的说明注释，没有省略时返回空
+func moreItemsComment(n int, indentStr string, fieldPath string, options *Options) string {
+	limit := itemLimit(n, options)
+	if limit == n {
+		return ""
+	}
+	warn(options, WarningTruncated, fieldPath, "%d of %d items omitted", n-limit, n)
+	return fmt.Sprintf("%s# ... %d more items\n", indentStr, n-limit)
+}
+
+// limitNode 在yaml节点树上应用层级和元素个数限制，用于 encodeNode 整体编码的叶子值（如自定义 MarshalYAML 的结果）
+// 元素个数只限制列表和映射，结构体的字段不受影响。yaml.v3 不输出块风格容器自身的行尾注释，
+// 因此省略说明由调用方写在映射键的行尾、列表元素的上方或文档末尾
+func limitNode(node *yaml.Node, val reflect.Value, fieldPath string, depth int, options *Options) string {
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			break
+		}
+		val = val.Elem()
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) > 0 {
+			node.FootComment = limitNode(node.Content[0], val, fieldPath, depth, options)
+		}
+		return ""
+	case yaml.MappingNode, yaml.SequenceNode:
+	default:
+		return ""
+	}
+
+	if options.MaxDepth > 0 && depth >= options.MaxDepth && len(node.Content) > 0 {
+		warn(options, WarningTruncated, fieldPath, "truncated at depth %d", options.MaxDepth)
+		*node = yaml.Node{
+			Kind:        yaml.ScalarNode,
+			Tag:         "!!null",
+			Value:       "null",
+			LineComment: fmt.Sprintf("# ... truncated at depth %d", options.MaxDepth),
+		}
+		return ""
+	}
+
+	var more string
+	if node.Kind == yaml.SequenceNode {
+		if n := len(node.Content); itemLimit(n, options) < n {
+			node.Content = node.Content[:itemLimit(n, options)]
+			// 行尾的省略说明只能跟在块风格容器的键之后
+			node.Style &^= yaml.FlowStyle
+			more = fmt.Sprintf("# ... %d more items", n-len(node.Content))
+			warn(options, WarningTruncated, fieldPath, "%d of %d items omitted", n-len(node.Content), n)
+		}
+		for i, item := range node.Content {
+			var child reflect.Value
+			if (val.Kind() == reflect.Slice || val.Kind() == reflect.Array) && i < val.Len() {
+				child = val.Index(i)
+			}
+			if comment := limitNode(item, child, buildFieldPath(fieldPath, strconv.Itoa(i)), depth+1, options); comment != "" {
+				item.HeadComment = comment
+			}
+		}
+		return more
+	}
+
+	if val.Kind() == reflect.Map {
+		if n := len(node.Content) / 2; itemLimit(n, options) < n {
+			node.Content = node.Content[:itemLimit(n, options)*2]
+			more = fmt.Sprintf("# ... %d more items", n-len(node.Content)/2)
+			warn(options, WarningTruncated, fieldPath, "%d of %d items omitted", n-len(node.Content)/2, n)
+		}
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var child reflect.Value
+		switch val.Kind() {
+		case reflect.Struct:
+			_, child, _ = findYAMLField(val, node.Content[i].Value)
+		case reflect.Map:
+			if val.Type().Key().Kind() == reflect.String {
+				child = val.MapIndex(reflect.ValueOf(node.Content[i].Value).Convert(val.Type().Key()))
+			}
+		}
+		if comment := limitNode(node.Content[i+1], child, buildFieldPath(fieldPath, node.Content[i].Value), depth+1, options); comment != "" {
+			node.Content[i].LineComment = comment
+		}
+	}
+	return more
+}
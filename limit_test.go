@@ -0,0 +1,123 @@
+package yamlc
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+type limitTree struct {
+	Name     string                 `yaml:"name" comment:"名称"`
+	Children []limitTree            `yaml:"children" comment:"子节点"`
+	Attrs    map[string]interface{} `yaml:"attrs"`
+}
+
+func TestMaxItems(t *testing.T) {
+	type State struct {
+		IDs    []int          `yaml:"ids" comment:"编号"`
+		Counts map[string]int `yaml:"counts"`
+	}
+	state := State{IDs: make([]int, 1000), Counts: map[string]int{}}
+	for i := range state.IDs {
+		state.IDs[i] = i
+	}
+	for i := 0; i < 5; i++ {
+		state.Counts[fmt.Sprintf("k%d", i)] = i
+	}
+
+	for _, style := range GetAllStyle() {
+		data, err := Gen(state, WithStyle(style), WithMaxItems(10), WithFlowThreshold(20))
+		if err != nil {
+			t.Fatalf("style %s failed: %v", GetStyleString(int(style)), err)
+		}
+		yamlStr := string(data)
+		if !strings.Contains(yamlStr, "# ... 990 more items") {
+			t.Errorf("style %s: expected truncation comment:\n%s", GetStyleString(int(style)), yamlStr)
+		}
+		var decoded State
+		if err := yaml.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("style %s: Unmarshal failed: %v\n%s", GetStyleString(int(style)), err, yamlStr)
+		}
+		if len(decoded.IDs) != 10 || decoded.IDs[9] != 9 || len(decoded.Counts) != 5 {
+			t.Errorf("style %s: unexpected truncated content %+v", GetStyleString(int(style)), decoded)
+		}
+	}
+
+	data, err := Gen(state, WithStyle(StyleTop), WithMaxItems(3))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if !strings.Contains(string(data), "  k2: 2\n  # ... 2 more items\n") {
+		t.Errorf("expected map truncation comment:\n%s", data)
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	tree := limitTree{
+		Name: "root",
+		Children: []limitTree{{
+			Name:     "a",
+			Children: []limitTree{{Name: "a1"}},
+			Attrs:    map[string]interface{}{"deep": map[string]int{"x": 1}},
+		}},
+	}
+
+	for _, style := range GetAllStyle() {
+		data, err := Gen(tree, WithStyle(style), WithMaxDepth(3))
+		if err != nil {
+			t.Fatalf("style %s failed: %v", GetStyleString(int(style)), err)
+		}
+		yamlStr := string(data)
+		if !strings.Contains(yamlStr, "# ... truncated at depth 3") {
+			t.Errorf("style %s: expected depth truncation comment:\n%s", GetStyleString(int(style)), yamlStr)
+		}
+		var decoded limitTree
+		if err := yaml.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("style %s: Unmarshal failed: %v\n%s", GetStyleString(int(style)), err, yamlStr)
+		}
+		if decoded.Name != "root" || len(decoded.Children) != 1 || decoded.Children[0].Name != "a" ||
+			decoded.Children[0].Children != nil || decoded.Children[0].Attrs != nil {
+			t.Errorf("style %s: unexpected truncated content %+v\n%s", GetStyleString(int(style)), decoded, yamlStr)
+		}
+	}
+}
+
+func TestMaxDepthSelfReference(t *testing.T) {
+	type Node struct {
+		Name string `yaml:"name"`
+		Next *Node  `yaml:"next"`
+	}
+	node := &Node{Name: "loop"}
+	node.Next = node
+
+	for _, style := range GetAllStyle() {
+		data, err := Gen(node, WithStyle(style), WithMaxDepth(3))
+		if err != nil {
+			t.Fatalf("style %s failed: %v", GetStyleString(int(style)), err)
+		}
+		if strings.Count(string(data), "# ... truncated at depth 3") != 1 {
+			t.Errorf("style %s: expected one depth truncation comment:\n%s", GetStyleString(int(style)), data)
+		}
+	}
+}
+
+func TestMaxDepthNoBlankLine(t *testing.T) {
+	type Config struct {
+		Host  string         `yaml:"host"`
+		Attrs map[string]int `yaml:"attrs"`
+		Port  int            `yaml:"port"`
+	}
+	cfg := Config{Host: "h", Attrs: map[string]int{"a": 1}, Port: 80}
+
+	for _, style := range []CommentStyle{StyleTop, StyleDoc, StyleSeparate, StyleSectioned} {
+		data, err := Gen(cfg, WithStyle(style), WithMaxDepth(1))
+		if err != nil {
+			t.Fatalf("style %s failed: %v", GetStyleString(int(style)), err)
+		}
+		if !strings.Contains(string(data), "attrs: null # ... truncated at depth 1\nport: 80\n") {
+			t.Errorf("style %s: unexpected blank line after truncated value:\n%s", GetStyleString(int(style)), data)
+		}
+	}
+}
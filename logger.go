@@ -0,0 +1,105 @@
+package yamlc
+
+// Logger 记录生成决策的日志接口，*slog.Logger 满足该接口
+// args 为交替的键和值，与 slog 的约定一致
+type Logger interface {
+	Debug(msg string, args ...any)
+}
+
+// WithLogger 记录每个字段使用的注释风格及其来源、注释来源和字符串的引号选择，
+// 用于排查输出为何是当前的样子
+func WithLogger(l Logger) Option {
+	return func(o *Options) {
+		o.Logger = l
+	}
+}
+
+// logField 记录字段的注释风格和注释来源，tagComment 为结构体标签中的注释
+func logField(info FieldInfo, tagComment string, options *Options) {
+	if options.Logger == nil {
+		return
+	}
+	style, styleSource := fieldStyleSource(info, options)
+	options.Logger.Debug("yamlc field",
+		"path", info.FieldPath,
+		"style", GetStyleString(int(style)),
+		"styleSource", styleSource,
+		"commentSource", commentSource(info, tagComment, options),
+		"secret", info.secret,
+	)
+}
+
+// fieldStyleSource 返回字段使用的注释风格以及该风格来自何处
+func fieldStyleSource(info FieldInfo, options *Options) (CommentStyle, string) {
+	if info.Style != nil {
+		for _, override := range options.StyleOverrides {
+			if matchPathGlob(override.Pattern, info.FieldPath) {
+				return *info.Style, "override"
+			}
+		}
+		return *info.Style, "tag"
+	}
+	if options.styleFixed {
+		return options.Style, "inherited"
+	}
+	// levelOptions 已将层级加1
+	if _, ok := options.StylePerDepth[options.level-1]; ok {
+		return options.Style, "depth"
+	}
+	return options.Style, "options"
+}
+
+// commentSource 返回字段注释的来源，优先级与 collectFieldInfo 一致
+func commentSource(info FieldInfo, tagComment string, options *Options) string {
+	if info.Comment == "" {
+		return "none"
+	}
+	source, optionComment := "", ""
+	if comment, ok := lookupComment(info.FieldPath, options); ok {
+		source, optionComment = "option", sanitizeComment(comment)
+	} else if comment, ok := getAudienceComment(info.FieldType, options); ok {
+		source, optionComment = "audience", sanitizeComment(comment)
+	}
+	merge := options.CommentMerge != CommentReplace
+	switch {
+	case source == "" && tagComment != "":
+		return "tag"
+	case optionComment != "" && tagComment != "" && merge:
+		return source + "+tag"
+	case optionComment != "":
+		return source
+	case source != "" && tagComment != "" && merge:
+		return "tag"
+	}
+	if kubernetesComment(info.Name, info.Depth, options) != "" {
+		return "kubernetes"
+	}
+	if info.secret {
+		return "secret"
+	}
+	return "none"
+}
+
+// logScalar 记录字符串值的表示方式，representation 为空时按引号规则判断
+func logScalar(str string, fieldPath string, representation string, options *Options) {
+	if options.Logger == nil {
+		return
+	}
+	if representation == "" {
+		representation = quoteDecision(str, options)
+	}
+	options.Logger.Debug("yamlc scalar", "path", fieldPath, "representation", representation)
+}
+
+// quoteDecision 说明字符串是否加引号以及原因
+func quoteDecision(str string, options *Options) string {
+	switch {
+	case options.QuoteStyle == QuoteAlways:
+		return "quoted (always)"
+	case needsQuoting(str):
+		return "quoted (special characters)"
+	case options.Compatibility == YAML11 && isYAML11Ambiguous(str):
+		return "quoted (YAML 1.1 ambiguous)"
+	}
+	return "plain"
+}
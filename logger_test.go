@@ -0,0 +1,69 @@
+package yamlc
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// recordLogger 以 "消息 键=值 ..." 的形式记录日志
+type recordLogger struct {
+	entries []string
+}
+
+func (l *recordLogger) Debug(msg string, args ...any) {
+	var entry strings.Builder
+	entry.WriteString(msg)
+	for i := 0; i+1 < len(args); i += 2 {
+		fmt.Fprintf(&entry, " %v=%v", args[i], args[i+1])
+	}
+	l.entries = append(l.entries, entry.String())
+}
+
+type loggerServer struct {
+	Host string `yaml:"host" comment:"主机"`
+	Mode string `yaml:"mode" yamlc:"style=inline"`
+}
+
+type loggerConfig struct {
+	Name   string       `yaml:"name" comment:"名称"`
+	Server loggerServer `yaml:"server"`
+	Notes  string       `yaml:"notes" yamlc:"literal"`
+}
+
+func TestWithLogger(t *testing.T) {
+	cfg := loggerConfig{
+		Name:   "demo",
+		Server: loggerServer{Host: "0.0.0.0", Mode: "yes"},
+		Notes:  "line1\nline2",
+	}
+	logger := &recordLogger{}
+	if _, err := Gen(cfg,
+		WithLogger(logger),
+		WithComment(map[string]string{"server.host": "监听地址"}),
+		WithStylePerDepth(map[int]CommentStyle{1: StyleCompact}),
+	); err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+
+	for _, expected := range []string{
+		"yamlc field path=name style=top styleSource=options commentSource=tag secret=false",
+		"yamlc field path=server style=top styleSource=options commentSource=none secret=false",
+		"yamlc field path=server.host style=compact styleSource=depth commentSource=option secret=false",
+		"yamlc field path=server.mode style=inline styleSource=tag commentSource=none secret=false",
+		"yamlc scalar path=name representation=plain",
+		"yamlc scalar path=server.mode representation=quoted (YAML 1.1 ambiguous)",
+		"yamlc scalar path=notes representation=block scalar",
+	} {
+		found := false
+		for _, entry := range logger.entries {
+			if entry == expected {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("expected log entry %q in:\n%s", expected, strings.Join(logger.entries, "\n"))
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package yamlc
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manager 将结构体、配置文件和生成选项绑定在一起，封装"生成带注释的默认配置、读取用户修改、安全写回"的常见流程
+// 各方法可并发调用；直接修改 Value 返回的结构体时，调用方需自行避免与 Load 同时进行
+type Manager struct {
+	mu       sync.RWMutex
+	path     string
+	value    reflect.Value // 指向结构体的指针
+	defaults []byte        // 创建时结构体的内容，文件中缺少的键取这里的值
+	config   *Config
+	hash     [sha256.Size]byte // 最近一次读取或写入时文件内容的摘要
+	known    bool              // hash 是否有效
+}
+
+// NewManager 创建配置管理器，v 为指向结构体的指针，其当前值作为默认值；opts 用于生成、写入和读取文件（如 WithDecrypter）
+func NewManager(path string, v interface{}, opts ...Option) (*Manager, error) {
+	if path == "" {
+		return nil, fmt.Errorf("filename cannot be empty")
+	}
+	value := reflect.ValueOf(v)
+	if v == nil || value.Kind() != reflect.Ptr || value.IsNil() {
+		return nil, fmt.Errorf("value must be a non-nil pointer, got %T", v)
+	}
+	defaults, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot defaults: %w", err)
+	}
+	return &Manager{path: path, value: value, defaults: defaults, config: NewConfig(opts...)}, nil
+}
+
+// Path 返回配置文件路径
+func (m *Manager) Path() string {
+	return m.path
+}
+
+// Value 返回绑定的结构体指针
+func (m *Manager) Value() interface{} {
+	return m.value.Interface()
+}
+
+// Save 将当前值原子地写入配置文件
+func (m *Manager) Save() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	perm := fs.FileMode(0644)
+	if info, err := os.Stat(m.path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	if err := m.config.WriteFileAtomic(m.path, m.value.Interface(), perm); err != nil {
+		return err
+	}
+	return m.remember()
+}
+
+// Load 读取配置文件并解码到绑定的结构体，文件中缺少的键取创建时的默认值；解码失败时结构体保持不变
+func (m *Manager) Load() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.load()
+}
+
+// Reload 配置文件自上次读取或写入后有变化时重新读取，返回是否重新读取了
+func (m *Manager) Reload() (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	changed, err := m.changed()
+	if err != nil || !changed {
+		return false, err
+	}
+	return true, m.load()
+}
+
+// Changed 判断配置文件自上次读取或写入后是否有变化（按内容比较，而不是修改时间）
+func (m *Manager) Changed() (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.changed()
+}
+
+// EnsureExists 配置文件不存在时写入默认配置，已存在时读取它，返回是否新建了文件
+func (m *Manager) EnsureExists() (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	created, err := m.config.WriteFileOnce(m.path, m.value.Interface())
+	if err != nil {
+		return false, err
+	}
+	if created {
+		return true, m.remember()
+	}
+	return false, m.load()
+}
+
+// Edited 判断配置文件生成后是否被用户修改过：文件末尾的校验和注释（见 WithChecksumFooter）与内容不一致，
+// 或没有校验和时返回 true；文件不存在时返回 false。可在 Save 覆盖文件之前调用
+func (m *Manager) Edited() (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	data, err := os.ReadFile(m.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read %q: %w", m.path, err)
+	}
+	return !VerifyChecksum(data), nil
+}
+
+// load 读取并解码配置文件，调用方持有写锁
+func (m *Manager) load() error {
+	data, err := os.ReadFile(m.path)
+	if err != nil {
+		return fmt.Errorf("failed to read %q: %w", m.path, err)
+	}
+	return m.decode(data)
+}
+
+// decode 将文件内容解码到绑定的结构体并记录摘要，设置 WithDecrypter 时先解密 secret 字段，调用方持有写锁
+func (m *Manager) decode(data []byte) error {
+	fresh := reflect.New(m.value.Elem().Type())
+	if err := decodeYAML(m.defaults, fresh.Interface(), &Options{}); err != nil {
+		return fmt.Errorf("failed to restore defaults: %w", err)
+	}
+	if err := decodeYAML(data, fresh.Interface(), m.config.newOptions()); err != nil {
+		return fmt.Errorf("failed to load %q: %w", m.path, err)
+	}
+	m.value.Elem().Set(fresh.Elem())
+	m.hash, m.known = sha256.Sum256(data), true
+	return nil
+}
+
+// remember 记录文件当前内容的摘要，调用方持有写锁
+func (m *Manager) remember() error {
+	data, err := os.ReadFile(m.path)
+	if err != nil {
+		return fmt.Errorf("failed to read %q: %w", m.path, err)
+	}
+	m.hash, m.known = sha256.Sum256(data), true
+	return nil
+}
+
+// changed 比较文件内容与记录的摘要，调用方持有锁
+func (m *Manager) changed() (bool, error) {
+	data, err := os.ReadFile(m.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return m.known, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read %q: %w", m.path, err)
+	}
+	return !m.known || sha256.Sum256(data) != m.hash, nil
+}
@@ -0,0 +1,111 @@
+package yamlc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type managedConfig struct {
+	Host    string `yaml:"host" comment:"主机"`
+	Port    int    `yaml:"port" comment:"端口"`
+	Workers int    `yaml:"workers" comment:"工作线程数"`
+}
+
+func TestManager(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "app.yaml")
+	cfg := &managedConfig{Host: "localhost", Port: 8080, Workers: 4}
+	manager, err := NewManager(filename, cfg, WithStyle(StyleInline))
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	created, err := manager.EnsureExists()
+	if err != nil || !created {
+		t.Fatalf("expected default config to be created: %v", err)
+	}
+	data, _ := os.ReadFile(filename)
+	if !strings.Contains(string(data), "# 端口") {
+		t.Errorf("expected commented config:\n%s", data)
+	}
+	if changed, err := manager.Changed(); err != nil || changed {
+		t.Errorf("expected no change after creation: %v", err)
+	}
+
+	// 用户修改文件，缺少的键保留默认值
+	if err := os.WriteFile(filename, []byte("port: 9090\n"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if changed, err := manager.Changed(); err != nil || !changed {
+		t.Errorf("expected change to be detected: %v", err)
+	}
+	reloaded, err := manager.Reload()
+	if err != nil || !reloaded {
+		t.Fatalf("expected reload: %v", err)
+	}
+	if cfg.Port != 9090 || cfg.Host != "localhost" || cfg.Workers != 4 {
+		t.Errorf("unexpected value after reload: %+v", cfg)
+	}
+	if reloaded, err := manager.Reload(); err != nil || reloaded {
+		t.Errorf("expected no reload without changes: %v", err)
+	}
+
+	// 解码失败时结构体不变
+	if err := os.WriteFile(filename, []byte("port: [\n"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := manager.Load(); err == nil {
+		t.Error("expected error for invalid file")
+	}
+	if cfg.Port != 9090 {
+		t.Errorf("value changed after failed load: %+v", cfg)
+	}
+
+	cfg.Workers = 8
+	if err := manager.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	created, err = manager.EnsureExists()
+	if err != nil || created {
+		t.Fatalf("expected existing config to be loaded: %v", err)
+	}
+	if cfg.Workers != 8 || manager.Value() != cfg || manager.Path() != filename {
+		t.Errorf("unexpected manager state: %+v", cfg)
+	}
+
+	if _, err := NewManager(filename, managedConfig{}); err == nil {
+		t.Error("expected error for non-pointer value")
+	}
+	if _, err := NewManager("", cfg); err == nil {
+		t.Error("expected error for empty path")
+	}
+}
+
+func TestManagerDecrypter(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "db.yaml")
+	e := &base64Encrypter{}
+	cfg := &encryptedDatabase{Host: "db", Password: "pw", Pin: 1234}
+	manager, err := NewManager(filename, cfg, WithStyle(StyleTop), WithEncrypter(e), WithDecrypter(e))
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	if err := manager.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	first, _ := os.ReadFile(filename)
+	if err := manager.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Password != "pw" || cfg.Pin != 1234 {
+		t.Errorf("expected decrypted values after Load: %+v", cfg)
+	}
+	if err := manager.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	second, _ := os.ReadFile(filename)
+	if string(first) != string(second) || strings.Contains(string(second), "ENC[RU5D") {
+		t.Errorf("expected Save/Load/Save to keep a single encryption layer:\n%s\n---\n%s", first, second)
+	}
+}
@@ -0,0 +1,98 @@
+package yamlc
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// mapKeyText 获取映射键的文本，用于字段路径和排序；实现 encoding.TextMarshaler 的键使用其文本
+func mapKeyText(key reflect.Value) string {
+	key = derefKey(key)
+	if key.CanInterface() {
+		if marshaler, ok := key.Interface().(encoding.TextMarshaler); ok {
+			if text, err := marshaler.MarshalText(); err == nil {
+				return string(text)
+			}
+		}
+	}
+	switch key.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(key.Float(), 'g', -1, key.Type().Bits())
+	}
+	return fmt.Sprintf("%v", key.Interface())
+}
+
+// formatMapKey 生成映射键在YAML中的写法：整数、浮点数和布尔键原样输出以保留类型，其余按字符串加引号
+func formatMapKey(key reflect.Value, options *Options) string {
+	text := mapKeyText(key)
+	if isScalarKey(derefKey(key)) {
+		return text
+	}
+	return quoteKey(text, options)
+}
+
+// isScalarKey 判断键是否为按原类型输出的数字或布尔值（实现 TextMarshaler 的类型除外）
+func isScalarKey(key reflect.Value) bool {
+	if key.CanInterface() {
+		if _, ok := key.Interface().(encoding.TextMarshaler); ok {
+			return false
+		}
+	}
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Bool:
+		return true
+	}
+	return false
+}
+
+// derefKey 取出 interface{} 键的实际值
+func derefKey(key reflect.Value) reflect.Value {
+	for key.Kind() == reflect.Interface && !key.IsNil() {
+		key = key.Elem()
+	}
+	return key
+}
+
+// sortMapKeys 对映射键排序：数字按数值、布尔值 false 在前，其余按文本排序；不同类型的键数字在前
+func sortMapKeys(keys []reflect.Value) {
+	sort.SliceStable(keys, func(i, j int) bool {
+		a, b := derefKey(keys[i]), derefKey(keys[j])
+		aNum, aIsNum := keyNumber(a)
+		bNum, bIsNum := keyNumber(b)
+		switch {
+		case aIsNum && bIsNum:
+			if aNum != bNum {
+				return aNum < bNum
+			}
+		case aIsNum != bIsNum:
+			return aIsNum
+		}
+		return mapKeyText(a) < mapKeyText(b)
+	})
+}
+
+// keyNumber 将数字和布尔键转换为可比较的数值
+func keyNumber(key reflect.Value) (float64, bool) {
+	if !isScalarKey(key) {
+		return 0, false
+	}
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(key.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(key.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return key.Float(), true
+	case reflect.Bool:
+		if key.Bool() {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,67 @@
+package yamlc
+
+import (
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestNonStringMapKeys(t *testing.T) {
+	type Tables struct {
+		Ports   map[int]string         `yaml:"ports"`
+		Flags   map[bool]string        `yaml:"flags"`
+		Weights map[float64]int        `yaml:"weights"`
+		Hosts   map[string]int         `yaml:"hosts"`
+		Mixed   map[interface{}]string `yaml:"mixed"`
+	}
+	tables := Tables{
+		Ports:   map[int]string{10: "ten", 2: "two", -1: "neg"},
+		Flags:   map[bool]string{true: "on", false: "off"},
+		Weights: map[float64]int{0.5: 1, 1.25: 2},
+		Hosts:   map[string]int{"8080": 1, "yes": 2},
+		Mixed:   map[interface{}]string{"b": "x", 3: "y"},
+	}
+
+	data, err := Gen(tables, WithStyle(StyleTop))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	yamlStr := string(data)
+	for _, expected := range []string{
+		"ports:\n  -1: neg\n  2: two\n  10: ten\n",
+		"flags:\n  false: 'off'\n  true: 'on'\n",
+		"weights:\n  0.5: 1\n  1.25: 2\n",
+		"hosts:\n  \"8080\": 1\n  'yes': 2\n",
+		"mixed:\n  3: 'y'\n  b: x\n",
+	} {
+		if !strings.Contains(yamlStr, expected) {
+			t.Errorf("expected %q in:\n%s", expected, yamlStr)
+		}
+	}
+
+	var decoded Tables
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v\n%s", err, yamlStr)
+	}
+	if !reflect.DeepEqual(decoded.Ports, tables.Ports) || !reflect.DeepEqual(decoded.Flags, tables.Flags) ||
+		!reflect.DeepEqual(decoded.Weights, tables.Weights) || !reflect.DeepEqual(decoded.Hosts, tables.Hosts) {
+		t.Errorf("map keys did not round-trip: %+v", decoded)
+	}
+}
+
+func TestTextMarshalerMapKeys(t *testing.T) {
+	routes := map[string]map[netip.Addr]string{
+		"routes": {netip.MustParseAddr("10.0.0.2"): "b", netip.MustParseAddr("10.0.0.1"): "a"},
+	}
+
+	data, err := Gen(routes, WithStyle(StyleTop))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if !strings.Contains(string(data), "routes:\n  10.0.0.1: a\n  10.0.0.2: b\n") {
+		t.Errorf("expected TextMarshaler keys in order:\n%s", data)
+	}
+}
@@ -0,0 +1,45 @@
+package yamlc
+
+import (
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Marshal 类型安全的 Gen，调用处在编译期检查类型
+func Marshal[T any](v T, opts ...Option) ([]byte, error) {
+	return Gen(v, opts...)
+}
+
+// Unmarshal 将YAML内容解析为类型 T 的值，注释会被忽略
+// 字节切片字段按生成时的编码（!!binary 或 format= 标签）还原；设置 WithDecrypter 时，带 secret 标签的字段先解密再解析
+func Unmarshal[T any](data []byte, opts ...Option) (T, error) {
+	var v T
+	err := decodeYAML(data, &v, Default().newOptions(opts...))
+	return v, err
+}
+
+// decodeYAML 将YAML内容解码到 out 指向的值：字节切片字段按生成时的编码还原，设置 Decrypter 时先解密 secret 字段
+func decodeYAML(data []byte, out interface{}, options *Options) error {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return fmt.Errorf("failed to unmarshal YAML: %w", err)
+	}
+	if node.Kind == 0 {
+		return nil
+	}
+	typ := reflect.TypeOf(out).Elem()
+	if options.Decrypter != nil {
+		if err := decryptNode(&node, typ, "", options); err != nil {
+			return err
+		}
+	}
+	if err := decodeBytesNode(&node, typ, "", ""); err != nil {
+		return err
+	}
+	if err := node.Decode(out); err != nil {
+		return fmt.Errorf("failed to unmarshal YAML: %w", err)
+	}
+	return nil
+}
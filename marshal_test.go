@@ -0,0 +1,33 @@
+package yamlc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMarshalUnmarshal(t *testing.T) {
+	type Config struct {
+		Name  string   `yaml:"name" comment:"名称"`
+		Ports []int    `yaml:"ports" comment:"端口"`
+		Tags  []string `yaml:"tags"`
+	}
+	cfg := Config{Name: "demo", Ports: []int{80, 443}, Tags: []string{"a"}}
+
+	for _, style := range GetAllStyle() {
+		data, err := Marshal(cfg, WithStyle(style))
+		if err != nil {
+			t.Fatalf("style %s: Marshal failed: %v", GetStyleString(int(style)), err)
+		}
+		decoded, err := Unmarshal[Config](data)
+		if err != nil {
+			t.Fatalf("style %s: Unmarshal failed: %v\n%s", GetStyleString(int(style)), err, data)
+		}
+		if !reflect.DeepEqual(decoded, cfg) {
+			t.Errorf("style %s: round-trip mismatch: %+v", GetStyleString(int(style)), decoded)
+		}
+	}
+
+	if _, err := Unmarshal[Config]([]byte("name: [")); err == nil {
+		t.Error("expected error for invalid YAML")
+	}
+}
@@ -0,0 +1,85 @@
+package yamlc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	// documentSeparator 文档开始标记，也是多文档之间的分隔符
+	documentSeparator = "---\n"
+	// documentEndMarker 文档结束标记
+	documentEndMarker = "...\n"
+)
+
+// GenAll 生成包含多个文档的YAML流，文档之间以 "---" 分隔，如Kubernetes清单集合
+// 每个文档按相同的选项单独生成注释
+func GenAll(docs []interface{}, opts ...Option) ([]byte, error) {
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no documents to generate")
+	}
+
+	parts := make([][]byte, 0, len(docs))
+	for i, doc := range docs {
+		data, err := Gen(doc, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+		parts = append(parts, data)
+	}
+	return joinDocuments(parts), nil
+}
+
+// joinDocuments 将多个已生成的文档以 "---" 连接为一个YAML流
+func joinDocuments(parts [][]byte) []byte {
+	var buf bytes.Buffer
+	for i, data := range parts {
+		data = bytes.TrimRight(data, "\n")
+		if i > 0 {
+			// 后续文档带指令时，前一个文档必须显式结束
+			if bytes.HasPrefix(data, []byte("%")) && !bytes.HasSuffix(buf.Bytes(), []byte(documentEndMarker)) {
+				buf.WriteString(documentEndMarker)
+			}
+			// 文档自带开始标记时不再重复输出分隔符
+			if !bytes.HasPrefix(data, []byte("%")) && !bytes.HasPrefix(data, []byte(documentSeparator)) {
+				buf.WriteString(documentSeparator)
+			}
+		}
+		buf.Write(data)
+		buf.WriteString("\n")
+	}
+	return buf.Bytes()
+}
+
+// WriteAll 将多个文档写入到io.Writer
+func WriteAll(w io.Writer, docs []interface{}, opts ...Option) error {
+	if w == nil {
+		return fmt.Errorf("writer cannot be nil")
+	}
+
+	data, err := GenAll(docs, opts...)
+	if err != nil {
+		return err
+	}
+	return writeData(w, data)
+}
+
+// WriteFileAll 将多个文档写入到文件：全部文档生成成功后才原子地替换文件，
+// 与 WriteFileAtomic 一样按 WithOverwritePolicy 处理已存在的文件，并支持 WithBackup
+func WriteFileAll(filename string, docs []interface{}, opts ...Option) error {
+	if filename == "" {
+		return fmt.Errorf("filename cannot be empty")
+	}
+
+	options := newOptions(opts...)
+	if write, err := shouldWrite(filename, os.ReadFile, options); !write || err != nil {
+		return err
+	}
+	data, err := GenAll(docs, opts...)
+	if err != nil {
+		return err
+	}
+	return writeDataAtomic(filename, data, 0644, options)
+}
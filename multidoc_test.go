@@ -0,0 +1,113 @@
+package yamlc
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+type manifest struct {
+	Kind string            `yaml:"kind" comment:"资源类型"`
+	Name string            `yaml:"name" comment:"资源名称"`
+	Data map[string]string `yaml:"data,omitempty"`
+}
+
+func TestGenAll(t *testing.T) {
+	docs := []interface{}{
+		manifest{Kind: "ConfigMap", Name: "app", Data: map[string]string{"mode": "prod"}},
+		&manifest{Kind: "Service", Name: "web"},
+	}
+
+	data, err := GenAll(docs, WithStyle(StyleTop))
+	if err != nil {
+		t.Fatalf("GenAll failed: %v", err)
+	}
+	yamlStr := string(data)
+	if strings.Count(yamlStr, "\n---\n") != 1 {
+		t.Errorf("expected one document separator:\n%s", yamlStr)
+	}
+	if strings.Count(yamlStr, "# 资源类型\n") != 2 {
+		t.Errorf("each document should carry its own comments:\n%s", yamlStr)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	var kinds []string
+	for {
+		var m manifest
+		if err := decoder.Decode(&m); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			t.Fatalf("decode failed: %v", err)
+		}
+		kinds = append(kinds, m.Kind)
+	}
+	if strings.Join(kinds, ",") != "ConfigMap,Service" {
+		t.Errorf("unexpected documents: %v", kinds)
+	}
+
+	if _, err := GenAll(nil); err == nil {
+		t.Error("GenAll should fail without documents")
+	}
+	if _, err := GenAll([]interface{}{docs[0], nil}); err == nil || !strings.Contains(err.Error(), "document 1") {
+		t.Errorf("expected error for nil document, got %v", err)
+	}
+}
+
+func TestWriteFileAll(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "bundle.yaml")
+	docs := []interface{}{manifest{Kind: "A", Name: "a"}, manifest{Kind: "B", Name: "b"}}
+
+	if err := WriteFileAll(filename, docs); err != nil {
+		t.Fatalf("WriteFileAll failed: %v", err)
+	}
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteAll(&buf, docs); err != nil {
+		t.Fatalf("WriteAll failed: %v", err)
+	}
+	if buf.String() != string(content) {
+		t.Errorf("WriteAll and WriteFileAll output differ:\n%s\n%s", buf.String(), content)
+	}
+}
+
+func TestWriteFileAllExisting(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "bundle.yaml")
+	original := []byte("kind: Old\n")
+	if err := os.WriteFile(filename, original, 0644); err != nil {
+		t.Fatal(err)
+	}
+	docs := []interface{}{manifest{Kind: "A", Name: "a"}, manifest{Kind: "B", Name: "b"}}
+
+	// 生成失败时不改动已有文件
+	if err := WriteFileAll(filename, []interface{}{manifest{Kind: "A"}, make(chan int)}); err == nil {
+		t.Fatal("expected generation error")
+	}
+	if content, _ := os.ReadFile(filename); !bytes.Equal(content, original) {
+		t.Errorf("existing file was modified on error:\n%s", content)
+	}
+
+	if err := WriteFileAll(filename, docs, WithOverwritePolicy(OverwriteNever)); !errors.Is(err, os.ErrExist) {
+		t.Errorf("expected os.ErrExist, got %v", err)
+	}
+
+	if err := WriteFileAll(filename, docs, WithBackup(".bak")); err != nil {
+		t.Fatalf("WriteFileAll failed: %v", err)
+	}
+	if backup, _ := os.ReadFile(filename + ".bak"); !bytes.Equal(backup, original) {
+		t.Errorf("expected backup of the original file, got:\n%s", backup)
+	}
+	if content, _ := os.ReadFile(filename); !bytes.Contains(content, []byte("name: b")) {
+		t.Errorf("unexpected content:\n%s", content)
+	}
+}
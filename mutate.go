@@ -0,0 +1,159 @@
+package yamlc
+
+import (
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SetPath 在YAML文档中设置路径上的值，未改动部分的注释和顺序保持不变，便于运维脚本修补配置
+// 路径以 "." 分隔，列表元素使用下标（如 "servers.0.port"）；不存在的映射键会被创建（追加在末尾），
+// 下标等于列表长度时追加元素。被替换的节点上原有的注释保留。多文档时只修改第一个文档
+func SetPath(data []byte, path string, value interface{}) ([]byte, error) {
+	var replacement yaml.Node
+	if err := replacement.Encode(value); err != nil {
+		return nil, fmt.Errorf("failed to encode value for %q: %w", path, err)
+	}
+	return mutateDocument(data, path, true, func(parent *yaml.Node, index int) error {
+		old := parent.Content[index]
+		replacement.HeadComment = old.HeadComment
+		replacement.LineComment = old.LineComment
+		replacement.FootComment = old.FootComment
+		parent.Content[index] = &replacement
+		return nil
+	})
+}
+
+// DeletePath 删除YAML文档中路径上的映射键或列表元素，连同其注释一起删除
+func DeletePath(data []byte, path string) ([]byte, error) {
+	return mutateDocument(data, path, false, func(parent *yaml.Node, index int) error {
+		if parent.Kind == yaml.MappingNode {
+			parent.Content = append(parent.Content[:index-1], parent.Content[index+1:]...)
+		} else {
+			parent.Content = append(parent.Content[:index], parent.Content[index+1:]...)
+		}
+		return nil
+	})
+}
+
+// RenameKey 将路径上的映射键改名为 newKey，值、注释和位置保持不变
+func RenameKey(data []byte, path string, newKey string) ([]byte, error) {
+	if newKey == "" {
+		return nil, fmt.Errorf("new key cannot be empty")
+	}
+	return mutateDocument(data, path, false, func(parent *yaml.Node, index int) error {
+		if parent.Kind != yaml.MappingNode {
+			return fmt.Errorf("path %q is not a mapping key", path)
+		}
+		if _, exists := mappingValueIndex(parent, newKey); exists {
+			return fmt.Errorf("key %q already exists", newKey)
+		}
+		parent.Content[index-1].Value = newKey
+		return nil
+	})
+}
+
+// mutateDocument 在第一个文档中定位路径，对目标节点执行修改后重新编码
+// 对于映射，index 指向值节点（键在 index-1）；对于列表，index 指向元素
+func mutateDocument(data []byte, path string, create bool, mutate func(parent *yaml.Node, index int) error) ([]byte, error) {
+	segments := splitPath(path)
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("path cannot be empty")
+	}
+
+	docs, err := decodeDocumentNodes(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		if !create {
+			return nil, fmt.Errorf("path %q not found", path)
+		}
+		docs = []*yaml.Node{{Kind: yaml.DocumentNode}}
+	}
+	doc := docs[0]
+	if len(doc.Content) == 0 || (doc.Content[0].Kind == yaml.ScalarNode && doc.Content[0].Tag == "!!null") {
+		if !create {
+			return nil, fmt.Errorf("path %q not found", path)
+		}
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+
+	parent, index, err := locateNode(doc.Content[0], segments, create)
+	if err != nil {
+		return nil, fmt.Errorf("path %q: %w", path, err)
+	}
+	if err := mutate(parent, index); err != nil {
+		return nil, err
+	}
+	return encodeDocumentNodes(docs, detectIndent(data))
+}
+
+// locateNode 逐段查找路径，返回目标所在的父节点和下标；create 为真时创建缺失的映射键和末尾元素
+func locateNode(node *yaml.Node, segments []string, create bool) (*yaml.Node, int, error) {
+	for i, segment := range segments {
+		for node.Kind == yaml.AliasNode && node.Alias != nil {
+			node = node.Alias
+		}
+		last := i == len(segments)-1
+
+		var index int
+		switch node.Kind {
+		case yaml.MappingNode:
+			var exists bool
+			index, exists = mappingValueIndex(node, segment)
+			if !exists {
+				if !create {
+					return nil, 0, fmt.Errorf("key %q not found", segment)
+				}
+				node.Content = append(node.Content,
+					&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: segment},
+					emptyContainer(last, segments, i))
+				index = len(node.Content) - 1
+			}
+		case yaml.SequenceNode:
+			n, err := strconv.Atoi(segment)
+			if err != nil || n < 0 {
+				return nil, 0, fmt.Errorf("invalid list index %q", segment)
+			}
+			switch {
+			case n < len(node.Content):
+			case n == len(node.Content) && create:
+				node.Content = append(node.Content, emptyContainer(last, segments, i))
+			default:
+				return nil, 0, fmt.Errorf("list index %d out of range", n)
+			}
+			index = n
+		default:
+			return nil, 0, fmt.Errorf("cannot descend into %s at %q", nodeKindName(node), segment)
+		}
+
+		if last {
+			return node, index, nil
+		}
+		node = node.Content[index]
+	}
+	return nil, 0, fmt.Errorf("path cannot be empty")
+}
+
+// mappingValueIndex 返回映射中键对应的值节点下标
+func mappingValueIndex(node *yaml.Node, key string) (int, bool) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
+
+// emptyContainer 为新建的路径段创建占位节点：下一段是下标时为列表，否则为映射；最后一段为空值
+func emptyContainer(last bool, segments []string, i int) *yaml.Node {
+	if last {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	}
+	if _, err := strconv.Atoi(segments[i+1]); err == nil {
+		return &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	}
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+}
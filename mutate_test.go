@@ -0,0 +1,124 @@
+package yamlc
+
+import (
+	"testing"
+)
+
+const mutateInput = `# 服务配置
+server:
+  host: localhost # 主机
+  # 端口
+  port: 8080
+servers:
+  - a
+  - b
+`
+
+func TestSetPath(t *testing.T) {
+	data, err := SetPath([]byte(mutateInput), "server.port", 9090)
+	if err != nil {
+		t.Fatalf("SetPath failed: %v", err)
+	}
+	expected := `# 服务配置
+server:
+  host: localhost # 主机
+  # 端口
+  port: 9090
+servers:
+  - a
+  - b
+`
+	if string(data) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, data)
+	}
+
+	// 创建缺失的映射键和列表元素
+	data, err = SetPath([]byte(mutateInput), "server.tls.enabled", true)
+	if err != nil {
+		t.Fatalf("SetPath failed: %v", err)
+	}
+	data, err = SetPath(data, "servers.2", "c")
+	if err != nil {
+		t.Fatalf("SetPath failed: %v", err)
+	}
+	data, err = SetPath(data, "limits", map[string]int{"cpu": 2})
+	if err != nil {
+		t.Fatalf("SetPath failed: %v", err)
+	}
+	expected = `# 服务配置
+server:
+  host: localhost # 主机
+  # 端口
+  port: 8080
+  tls:
+    enabled: true
+servers:
+  - a
+  - b
+  - c
+limits:
+  cpu: 2
+`
+	if string(data) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, data)
+	}
+
+	if data, err := SetPath(nil, "a.0.b", "x"); err != nil || string(data) != "a:\n  - b: x\n" {
+		t.Errorf("unexpected result %q: %v", data, err)
+	}
+	for _, path := range []string{"", "servers.5", "servers.x", "server.host.name"} {
+		if _, err := SetPath([]byte(mutateInput), path, 1); err == nil {
+			t.Errorf("expected error for path %q", path)
+		}
+	}
+}
+
+func TestDeletePath(t *testing.T) {
+	data, err := DeletePath([]byte(mutateInput), "server.port")
+	if err != nil {
+		t.Fatalf("DeletePath failed: %v", err)
+	}
+	data, err = DeletePath(data, "servers.0")
+	if err != nil {
+		t.Fatalf("DeletePath failed: %v", err)
+	}
+	expected := `# 服务配置
+server:
+  host: localhost # 主机
+servers:
+  - b
+`
+	if string(data) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, data)
+	}
+
+	if _, err := DeletePath([]byte(mutateInput), "server.missing"); err == nil {
+		t.Error("expected error for missing key")
+	}
+}
+
+func TestRenameKey(t *testing.T) {
+	data, err := RenameKey([]byte(mutateInput), "server.port", "listenPort")
+	if err != nil {
+		t.Fatalf("RenameKey failed: %v", err)
+	}
+	expected := `# 服务配置
+server:
+  host: localhost # 主机
+  # 端口
+  listenPort: 8080
+servers:
+  - a
+  - b
+`
+	if string(data) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, data)
+	}
+
+	if _, err := RenameKey([]byte(mutateInput), "server.port", "host"); err == nil {
+		t.Error("expected error for existing key")
+	}
+	if _, err := RenameKey([]byte(mutateInput), "servers.0", "x"); err == nil {
+		t.Error("expected error for list element")
+	}
+}
@@ -0,0 +1,75 @@
+package yamlc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestNestedContainerSlices(t *testing.T) {
+	type Config struct {
+		Maps    []map[string]string `yaml:"maps" comment:"映射列表"`
+		Matrix  [][]int             `yaml:"matrix" comment:"矩阵"`
+		Groups  [][]map[string]int  `yaml:"groups"`
+		Words   [][]string          `yaml:"words"`
+		Dynamic []interface{}       `yaml:"dynamic"`
+	}
+	cfg := Config{
+		Maps:    []map[string]string{{"a": "1", "b": "2"}, {"c": "3"}},
+		Matrix:  [][]int{{1, 2}, {3}, {}},
+		Groups:  [][]map[string]int{{{"x": 1, "y": 2}}},
+		Words:   [][]string{{"p"}},
+		Dynamic: []interface{}{[]interface{}{"a", []interface{}{"b"}}},
+	}
+
+	for _, indent := range []int{2, 4} {
+		for _, style := range []CommentStyle{StyleTop, StyleInline, StyleSmart, StyleCompact, StyleVerbose, StyleSpaced, StyleMinimal} {
+			data, err := Gen(cfg, WithStyle(style), WithIndent(indent), WithComment(map[string]string{"maps.0.a": "A注释", "matrix.0.1": "二"}))
+			if err != nil {
+				t.Fatalf("style %s indent %d: Gen failed: %v", GetStyleString(int(style)), indent, err)
+			}
+			var decoded Config
+			if err := yaml.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("style %s indent %d: Unmarshal failed: %v\n%s", GetStyleString(int(style)), indent, err, data)
+			}
+			if !reflect.DeepEqual(decoded, cfg) {
+				t.Errorf("style %s indent %d: nested slices did not round-trip:\n%s", GetStyleString(int(style)), indent, data)
+			}
+		}
+	}
+}
+
+func TestNestedScalarSliceIndent(t *testing.T) {
+	type Config struct {
+		Matrix [][]int `yaml:"matrix"`
+	}
+	cfg := Config{Matrix: [][]int{{1, 2}, {3}}}
+
+	data, err := Gen(cfg, WithStyle(StyleTop), WithIndent(4))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	expected := "matrix:\n    -   - 1\n        - 2\n    -   - 3\n"
+	if !strings.HasPrefix(string(data), expected) {
+		t.Errorf("unexpected output:\n%s\nexpected prefix:\n%s", data, expected)
+	}
+}
+
+func TestNestedSliceElementComment(t *testing.T) {
+	type Config struct {
+		Matrix [][]int `yaml:"matrix"`
+	}
+	cfg := Config{Matrix: [][]int{{1, 2}}}
+
+	data, err := Gen(cfg, WithStyle(StyleTop), WithComment(map[string]string{"matrix.0.1": "第二个"}))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	// 内层元素的注释留在该元素之前，而不是提到外层元素之前
+	expected := "  - - 1\n    # 第二个\n    - 2\n"
+	if !strings.Contains(string(data), expected) {
+		t.Errorf("unexpected output:\n%s\nexpected to contain:\n%s", data, expected)
+	}
+}
@@ -0,0 +1,184 @@
+package yamlc
+
+import (
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NullStyle 空值的表示方式
+type NullStyle int
+
+const (
+	// NullKeyword 输出 null（默认）
+	NullKeyword NullStyle = iota
+	// NullTilde 输出 ~
+	NullTilde
+	// NullEmpty 不输出值，如 "key:"
+	NullEmpty
+)
+
+// NilPointerPolicy 结构体中nil指针字段的处理方式
+type NilPointerPolicy int
+
+const (
+	// NilNull 输出为空值（默认）
+	NilNull NilPointerPolicy = iota
+	// NilOmit 省略该字段
+	NilOmit
+	// NilSkeleton 指向结构体的nil指针输出被注释掉的子字段骨架，便于用户取消注释后填写
+	NilSkeleton
+	// NilCommented 与 NilSkeleton 相同，但字段键本身也被注释掉，读取时该字段不存在
+	NilCommented
+)
+
+// Defaulter 由结构体类型实现，为nil指针字段的骨架填充默认值
+type Defaulter interface {
+	SetDefaults()
+}
+
+// WithNullStyle 设置空值的表示方式
+func WithNullStyle(style NullStyle) Option {
+	return func(o *Options) {
+		o.NullStyle = style
+	}
+}
+
+// WithNilPointerPolicy 设置结构体中nil指针字段的处理方式
+func WithNilPointerPolicy(policy NilPointerPolicy) Option {
+	return func(o *Options) {
+		o.NilPointers = policy
+	}
+}
+
+// WithOptionalSkeletons 将指向结构体的nil指针字段（如可选的TLS配置）整段注释输出，
+// 子字段取类型的默认值（实现 Defaulter 时）或零值，用户取消注释即可启用
+func WithOptionalSkeletons() Option {
+	return WithNilPointerPolicy(NilCommented)
+}
+
+// nullValue 按空值风格返回空值的文本
+func nullValue(options *Options) string {
+	switch options.NullStyle {
+	case NullTilde:
+		return "~"
+	case NullEmpty:
+		return ""
+	}
+	return "null"
+}
+
+// isNilPointer 判断值是否为nil指针
+func isNilPointer(val reflect.Value) bool {
+	return val.Kind() == reflect.Ptr && val.IsNil()
+}
+
+// isSkeletonField 判断字段是否按骨架输出：骨架策略下指向结构体的nil指针
+func isSkeletonField(val reflect.Value, options *Options) bool {
+	if options.NilPointers != NilSkeleton && options.NilPointers != NilCommented {
+		return false
+	}
+	return isNilPointer(val) && val.Type().Elem().Kind() == reflect.Struct
+}
+
+// fieldKey 生成字段键所在行的开头（"key:"），整段注释的字段键也被注释掉
+func fieldKey(indentStr string, field FieldInfo, options *Options) string {
+	if isCommentedField(field, options) {
+		return indentStr + "# " + field.Name + ":"
+	}
+	return indentStr + field.Name + ":"
+}
+
+// generateSkeleton 以结构体默认值生成子字段，再逐行注释掉；字段本身读取时仍为空值
+func generateSkeleton(typ reflect.Type, fieldPath string, indent int, options *Options) (string, error) {
+	skeletonOptions := *options
+	skeletonOptions.skeleton = false
+	value := reflect.New(typ)
+	if defaulter, ok := value.Interface().(Defaulter); ok {
+		defaulter.SetDefaults()
+	}
+	content, err := generateStruct(value.Elem(), fieldPath, indent, &skeletonOptions)
+	if err != nil {
+		return "", err
+	}
+	return commentOutLines(content), nil
+}
+
+// commentOutLines 将非注释行注释掉，保留原有缩进
+func commentOutLines(content string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		lines[i] = line[:len(line)-len(trimmed)] + "# " + trimmed
+	}
+	return strings.Join(lines, "\n")
+}
+
+// trimTrailingSpaces 去掉行尾空格，用于空值不输出文本时 "key: " 这样的行
+func trimTrailingSpaces(content string) string {
+	lines := strings.Split(content, "\n")
+	inBlock := blockContentLines(lines)
+	for i, line := range lines {
+		if !inBlock[i] {
+			lines[i] = strings.TrimRight(line, " ")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// nullNode 在yaml节点树上应用空值风格并省略nil指针字段，用于不经过字段渲染的最小风格
+func nullNode(node *yaml.Node, val reflect.Value, options *Options) {
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			break
+		}
+		val = val.Elem()
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) > 0 {
+			nullNode(node.Content[0], val, options)
+		}
+	case yaml.MappingNode:
+		content := node.Content[:0]
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			var child reflect.Value
+			switch val.Kind() {
+			case reflect.Struct:
+				if _, field, ok := findYAMLField(val, key.Value); ok {
+					// 整段注释的骨架字段在最小风格中同样不存在
+					if (options.NilPointers == NilOmit && isNilPointer(field)) ||
+						(options.NilPointers == NilCommented && isSkeletonField(field, options)) {
+						continue
+					}
+					child = field
+				}
+			case reflect.Map:
+				if val.Type().Key().Kind() == reflect.String {
+					child = val.MapIndex(reflect.ValueOf(key.Value).Convert(val.Type().Key()))
+				}
+			}
+			nullNode(value, child, options)
+			content = append(content, key, value)
+		}
+		node.Content = content
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			var child reflect.Value
+			if (val.Kind() == reflect.Slice || val.Kind() == reflect.Array) && i < val.Len() {
+				child = val.Index(i)
+			}
+			nullNode(item, child, options)
+		}
+	case yaml.ScalarNode:
+		if node.ShortTag() == "!!null" && options.NullStyle != NullKeyword {
+			node.Value = nullValue(options)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+type nullDatabase struct {
+	Host string `yaml:"host" comment:"主机"`
+	Port int    `yaml:"port" comment:"端口"`
+}
+
+type nullConfig struct {
+	Name     string        `yaml:"name" comment:"名称"`
+	Database *nullDatabase `yaml:"database" comment:"数据库"`
+	Timeout  *int          `yaml:"timeout" comment:"超时"`
+	Tags     []*string     `yaml:"tags" comment:"标签"`
+}
+
+func TestNullStyle(t *testing.T) {
+	cfg := nullConfig{Name: "demo", Tags: []*string{nil}}
+
+	testCases := []struct {
+		style    NullStyle
+		expected []string
+	}{
+		{NullKeyword, []string{"database: null\n", "timeout: null\n", "  - null\n"}},
+		{NullTilde, []string{"database: ~\n", "timeout: ~\n", "  - ~\n"}},
+		{NullEmpty, []string{"database:\n", "timeout:\n", "  -\n"}},
+	}
+
+	for _, tc := range testCases {
+		for _, style := range []CommentStyle{StyleTop, StyleInline, StyleMinimal} {
+			data, err := Gen(cfg, WithStyle(style), WithNullStyle(tc.style))
+			if err != nil {
+				t.Fatalf("Gen failed: %v", err)
+			}
+			yamlStr := string(data)
+			if style == StyleTop {
+				for _, expected := range tc.expected {
+					if !strings.Contains(yamlStr, expected) {
+						t.Errorf("null style %d: expected %q in:\n%s", tc.style, expected, yamlStr)
+					}
+				}
+			}
+
+			var decoded nullConfig
+			if err := yaml.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("Unmarshal failed: %v\n%s", err, yamlStr)
+			}
+			if decoded.Name != "demo" || decoded.Database != nil || decoded.Timeout != nil || len(decoded.Tags) != 1 || decoded.Tags[0] != nil {
+				t.Errorf("null style %d, comment style %s did not round-trip:\n%s", tc.style, GetStyleString(int(style)), yamlStr)
+			}
+		}
+	}
+}
+
+func TestNilPointerPolicy(t *testing.T) {
+	cfg := nullConfig{Name: "demo"}
+
+	for _, style := range []CommentStyle{StyleTop, StyleMinimal} {
+		data, err := Gen(cfg, WithStyle(style), WithNilPointerPolicy(NilOmit))
+		if err != nil {
+			t.Fatalf("Gen failed: %v", err)
+		}
+		if strings.Contains(string(data), "database") || strings.Contains(string(data), "timeout") {
+			t.Errorf("nil pointers should be omitted in style %s:\n%s", GetStyleString(int(style)), data)
+		}
+	}
+
+	for _, style := range GetAllStyle() {
+		data, err := Gen(cfg, WithStyle(style), WithNilPointerPolicy(NilSkeleton))
+		if err != nil {
+			t.Fatalf("style %s failed: %v", GetStyleString(int(style)), err)
+		}
+		yamlStr := string(data)
+		if style == StyleTop && !strings.Contains(yamlStr, "database:\n  # 主机\n  # host: \"\"\n  # 端口\n  # port: 0\n") {
+			t.Errorf("expected commented skeleton:\n%s", yamlStr)
+		}
+		if style != StyleMinimal && !strings.Contains(yamlStr, "# port: 0") {
+			t.Errorf("style %s: expected commented skeleton:\n%s", GetStyleString(int(style)), yamlStr)
+		}
+
+		var decoded nullConfig
+		if err := yaml.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unmarshal failed: %v\n%s", err, yamlStr)
+		}
+		if decoded.Database != nil || decoded.Timeout != nil {
+			t.Errorf("skeleton should decode as nil in style %s:\n%s", GetStyleString(int(style)), yamlStr)
+		}
+	}
+}
+
+type skeletonTLS struct {
+	Cert string `yaml:"cert" comment:"证书路径"`
+	Key  string `yaml:"key"`
+}
+
+func (t *skeletonTLS) SetDefaults() {
+	t.Cert = "/etc/ssl/server.crt"
+	t.Key = "/etc/ssl/server.key"
+}
+
+func TestOptionalSkeletons(t *testing.T) {
+	type Server struct {
+		Addr string       `yaml:"addr" comment:"监听地址"`
+		TLS  *skeletonTLS `yaml:"tls" comment:"TLS配置"`
+	}
+	server := Server{Addr: ":443"}
+
+	data, err := Gen(server, WithStyle(StyleTop), WithOptionalSkeletons())
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	expected := "# TLS配置\n# tls:\n  # 证书路径\n  # cert: /etc/ssl/server.crt\n  # key: /etc/ssl/server.key\n"
+	if !strings.Contains(string(data), expected) {
+		t.Errorf("expected %q in:\n%s", expected, data)
+	}
+
+	for _, style := range GetAllStyle() {
+		data, err := Gen(server, WithStyle(style), WithOptionalSkeletons())
+		if err != nil {
+			t.Fatalf("style %s failed: %v", GetStyleString(int(style)), err)
+		}
+		var decoded map[string]interface{}
+		if err := yaml.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unmarshal failed: %v\n%s", err, data)
+		}
+		if _, ok := decoded["tls"]; ok {
+			t.Errorf("style %s: tls should be commented out:\n%s", GetStyleString(int(style)), data)
+		}
+		if decoded["addr"] != ":443" {
+			t.Errorf("style %s: addr lost:\n%s", GetStyleString(int(style)), data)
+		}
+	}
+}
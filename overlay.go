@@ -0,0 +1,89 @@
+package yamlc
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// GenOverlay 使用默认配置生成覆盖文件
+func GenOverlay(base, modified interface{}, opts ...Option) ([]byte, error) {
+	return Default().GenOverlay(base, modified, opts...)
+}
+
+// GenOverlay 只输出 modified 中与 base 取值不同的字段（连同其上级键和注释），生成类似 kustomize 的覆盖文件，而不是完整文档
+// 映射逐键比较；列表整体比较，有差异时整个列表输出（覆盖时按整体替换）。base 中有而 modified 中没有的键无法用覆盖表示，不会输出。
+// 没有差异时返回 "{}"
+func (c *Config) GenOverlay(base, modified interface{}, opts ...Option) ([]byte, error) {
+	if base == nil || modified == nil {
+		return nil, ErrNilInput
+	}
+	treeOpts := append(append([]Option{}, opts...), WithStyle(StyleTop))
+	baseData, err := c.Gen(base, treeOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate base: %w", err)
+	}
+	modifiedData, err := c.Gen(modified, treeOpts...)
+	if err != nil {
+		return nil, err
+	}
+	baseTree, err := decodeTree(baseData)
+	if err != nil {
+		return nil, err
+	}
+	modifiedTree, err := decodeTree(modifiedData)
+	if err != nil {
+		return nil, err
+	}
+
+	if reflect.DeepEqual(baseTree, modifiedTree) {
+		return []byte("{}\n"), nil
+	}
+	// 顶层不是映射时无法只输出部分键，整体输出
+	_, baseIsMap := baseTree.(map[string]interface{})
+	_, modifiedIsMap := modifiedTree.(map[string]interface{})
+	if !baseIsMap || !modifiedIsMap {
+		return c.Gen(modified, opts...)
+	}
+
+	var paths []string
+	overlayPaths(baseTree, modifiedTree, "", &paths)
+	globs := make([]string, len(paths))
+	for i, fieldPath := range paths {
+		globs[i] = escapePathGlob(fieldPath)
+	}
+	return c.Gen(modified, append(append([]Option{}, opts...), WithInclude(globs...))...)
+}
+
+// overlayPaths 收集 modified 中与 base 不同的键路径，映射逐键递归，其他值整体比较
+func overlayPaths(base, modified interface{}, fieldPath string, paths *[]string) {
+	baseMap, baseIsMap := base.(map[string]interface{})
+	modifiedMap, modifiedIsMap := modified.(map[string]interface{})
+	if baseIsMap && modifiedIsMap {
+		for _, key := range unionKeys(nil, modifiedMap) {
+			keyPath := buildFieldPath(fieldPath, key)
+			baseValue, ok := baseMap[key]
+			if !ok {
+				*paths = append(*paths, keyPath)
+				continue
+			}
+			overlayPaths(baseValue, modifiedMap[key], keyPath, paths)
+		}
+		return
+	}
+	if !reflect.DeepEqual(base, modified) {
+		*paths = append(*paths, fieldPath)
+	}
+}
+
+// escapePathGlob 转义路径中的通配符字符，使其作为 WithInclude 的规则时只匹配该路径本身
+func escapePathGlob(fieldPath string) string {
+	var b strings.Builder
+	for _, r := range fieldPath {
+		if strings.ContainsRune(`*?[\`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,58 @@
+package yamlc
+
+import (
+	"testing"
+)
+
+func TestGenOverlay(t *testing.T) {
+	type Server struct {
+		Host string `yaml:"host" comment:"监听地址"`
+		Port int    `yaml:"port" comment:"端口"`
+	}
+	type Config struct {
+		Name    string            `yaml:"name" comment:"名称"`
+		Server  Server            `yaml:"server" comment:"服务"`
+		Tags    []string          `yaml:"tags" comment:"标签"`
+		Labels  map[string]string `yaml:"labels"`
+		Replica int               `yaml:"replica"`
+	}
+	base := Config{
+		Name:    "app",
+		Server:  Server{Host: "0.0.0.0", Port: 8080},
+		Tags:    []string{"a", "b"},
+		Labels:  map[string]string{"env": "dev", "team": "core"},
+		Replica: 1,
+	}
+	modified := base
+	modified.Server.Port = 80
+	modified.Tags = []string{"a", "c"}
+	modified.Labels = map[string]string{"env": "prod", "team": "core", "tier": "web"}
+
+	data, err := GenOverlay(base, modified, WithStyle(StyleTop), WithBlankLines(BlankLinesNone))
+	if err != nil {
+		t.Fatalf("GenOverlay failed: %v", err)
+	}
+	expected := "# 服务\nserver:\n  # 端口\n  port: 80\n" +
+		"# 标签\ntags:\n  - a\n  - c\n" +
+		"labels:\n  env: prod\n  tier: web\n"
+	if string(data) != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", data, expected)
+	}
+}
+
+func TestGenOverlayNoChanges(t *testing.T) {
+	type Config struct {
+		Name string `yaml:"name"`
+	}
+
+	data, err := GenOverlay(Config{Name: "a"}, &Config{Name: "a"})
+	if err != nil {
+		t.Fatalf("GenOverlay failed: %v", err)
+	}
+	if string(data) != "{}\n" {
+		t.Errorf("unexpected output: %q", data)
+	}
+	if _, err := GenOverlay(nil, Config{}); err == nil {
+		t.Error("expected error for nil base")
+	}
+}
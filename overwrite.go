@@ -0,0 +1,187 @@
+package yamlc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+)
+
+// OverwritePolicy 写入文件时目标文件已存在的处理策略
+type OverwritePolicy int
+
+const (
+	// OverwriteAlways 总是覆盖（默认）
+	OverwriteAlways OverwritePolicy = iota
+	// OverwriteNever 文件已存在时返回包装了 os.ErrExist 的错误
+	OverwriteNever
+	// OverwriteIfMissing 文件已存在时跳过写入，不报错
+	OverwriteIfMissing
+	// OverwriteIfUnmodified 写入时在文件末尾嵌入校验和注释；
+	// 文件已存在时，只有校验和与内容一致（用户未修改过）才覆盖，否则跳过
+	OverwriteIfUnmodified
+)
+
+// checksumPrefix 嵌入文件末尾的校验和注释前缀
+const checksumPrefix = "# yamlc-sha256: "
+
+// legacyChecksumPrefix 早期版本 OverwriteIfUnmodified 写入的校验和注释前缀，校验时仍然识别
+const legacyChecksumPrefix = "# yamlc-checksum: sha256:"
+
+// WithOverwritePolicy 设置 WriteFile、WriteFileAtomic 和 WriteFileOnce 遇到已存在文件时的策略
+func WithOverwritePolicy(policy OverwritePolicy) Option {
+	return func(o *Options) {
+		o.Overwrite = policy
+	}
+}
+
+// WriteFileOnce 使用默认配置写入文件，默认策略为 OverwriteIfMissing
+func WriteFileOnce(filename string, v interface{}, opts ...Option) (bool, error) {
+	return Default().WriteFileOnce(filename, v, opts...)
+}
+
+// WriteFileOnce 用于应用启动时放置带注释的默认配置：默认只在文件不存在时写入，
+// 可用 WithOverwritePolicy(OverwriteIfUnmodified) 在用户未修改过时更新。返回是否写入了文件
+func (c *Config) WriteFileOnce(filename string, v interface{}, opts ...Option) (bool, error) {
+	if filename == "" {
+		return false, fmt.Errorf("filename cannot be empty")
+	}
+	options := c.newOptions(append([]Option{WithOverwritePolicy(OverwriteIfMissing)}, opts...)...)
+	if write, err := shouldWrite(filename, os.ReadFile, options); !write || err != nil {
+		return false, err
+	}
+	if err := writeFileAtomic(filename, v, 0644, options); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// shouldWrite 按覆盖策略判断是否写入文件，readFile 用于读取已有文件
+func shouldWrite(filename string, readFile func(string) ([]byte, error), options *Options) (bool, error) {
+	if options.Overwrite == OverwriteAlways {
+		return true, nil
+	}
+	existing, err := readFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read %q: %w", filename, err)
+	}
+
+	switch options.Overwrite {
+	case OverwriteNever:
+		return false, fmt.Errorf("file %q already exists: %w", filename, os.ErrExist)
+	case OverwriteIfUnmodified:
+		return VerifyChecksum(existing), nil
+	}
+	return false, nil
+}
+
+// fileContent 写入文件的内容，OverwriteIfUnmodified 时追加校验和注释；内容已带 WithChecksumFooter 的校验和时不再追加
+func fileContent(data []byte, options *Options) []byte {
+	if options.Overwrite != OverwriteIfUnmodified || options.ChecksumFooter {
+		return data
+	}
+	return appendChecksum(data)
+}
+
+// appendChecksum 在内容末尾追加校验和注释，OverwriteIfUnmodified 和 WithChecksumFooter 共用同一格式
+func appendChecksum(data []byte) []byte {
+	var buf bytes.Buffer
+	buf.Write(data)
+	if len(data) > 0 && !bytes.HasSuffix(data, []byte("\n")) {
+		buf.WriteString("\n")
+	}
+	buf.WriteString(checksumPrefix + contentChecksum(data) + "\n")
+	return buf.Bytes()
+}
+
+// verifyChecksum 检查文件末尾的校验和注释是否与其余内容一致，没有校验和时视为已修改
+func verifyChecksum(data []byte) bool {
+	content := bytes.TrimRight(bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n")), " \t\n")
+	idx := bytes.LastIndexByte(content, '\n') + 1
+	line := content[idx:]
+	for _, prefix := range []string{checksumPrefix, legacyChecksumPrefix} {
+		if bytes.HasPrefix(line, []byte(prefix)) {
+			return string(line[len(prefix):]) == contentChecksum(content[:idx])
+		}
+	}
+	return false
+}
+
+// contentChecksum 计算规范化内容的 SHA-256：统一换行符，去掉行尾空白和末尾空行，编辑器的自动整理不会被视为修改
+func contentChecksum(data []byte) string {
+	lines := bytes.Split(bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n")), []byte("\n"))
+	var canonical bytes.Buffer
+	for _, line := range lines {
+		canonical.Write(bytes.TrimRight(line, " \t"))
+		canonical.WriteByte('\n')
+	}
+	sum := sha256.Sum256(append(bytes.TrimRight(canonical.Bytes(), "\n"), '\n'))
+	return hex.EncodeToString(sum[:])
+}
+
+// checksumWriter 在写出内容的同时按 contentChecksum 的规则增量计算校验和，用于流式输出
+type checksumWriter struct {
+	w       io.Writer
+	hash    hash.Hash
+	line    []byte
+	blank   int
+	written bool
+	last    byte
+}
+
+// newChecksumWriter 创建写入 w 并计算校验和的写入器
+func newChecksumWriter(w io.Writer) *checksumWriter {
+	return &checksumWriter{w: w, hash: sha256.New()}
+}
+
+func (c *checksumWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	for _, b := range p[:n] {
+		if b == '\n' {
+			c.endLine()
+		} else {
+			c.line = append(c.line, b)
+		}
+	}
+	if n > 0 {
+		c.last = p[n-1]
+	}
+	return n, err
+}
+
+// endLine 规范化当前行后计入校验和，空行推迟到下一个非空行之前计入，末尾空行不影响结果
+func (c *checksumWriter) endLine() {
+	line := bytes.TrimRight(bytes.TrimSuffix(c.line, []byte("\r")), " \t")
+	c.line = c.line[:0]
+	if len(line) == 0 {
+		c.blank++
+		return
+	}
+	for ; c.blank > 0; c.blank-- {
+		c.hash.Write([]byte("\n"))
+	}
+	c.hash.Write(line)
+	c.hash.Write([]byte("\n"))
+	c.written = true
+}
+
+// footer 返回与 appendChecksum 相同格式的校验和注释，内容不以换行结尾时先补换行
+func (c *checksumWriter) footer() []byte {
+	var prefix string
+	if c.last != 0 && c.last != '\n' {
+		prefix = "\n"
+		c.endLine()
+	}
+	if !c.written {
+		c.hash.Write([]byte("\n"))
+	}
+	return []byte(prefix + checksumPrefix + hex.EncodeToString(c.hash.Sum(nil)) + "\n")
+}
@@ -0,0 +1,67 @@
+package yamlc
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileOnce(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "server.yaml")
+	defaults := configServer{Host: "localhost", Port: 8080}
+
+	written, err := WriteFileOnce(filename, defaults)
+	if err != nil || !written {
+		t.Fatalf("expected first write to succeed: %v", err)
+	}
+	written, err = WriteFileOnce(filename, configServer{Host: "other", Port: 1})
+	if err != nil || written {
+		t.Fatalf("expected existing file to be kept: %v", err)
+	}
+	if data, _ := os.ReadFile(filename); !bytes.Contains(data, []byte("localhost")) {
+		t.Errorf("existing file was overwritten:\n%s", data)
+	}
+
+	if err := WriteFile(filename, defaults, WithOverwritePolicy(OverwriteNever)); !errors.Is(err, os.ErrExist) {
+		t.Errorf("expected os.ErrExist, got %v", err)
+	}
+}
+
+func TestOverwriteIfUnmodified(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "server.yaml")
+	policy := WithOverwritePolicy(OverwriteIfUnmodified)
+
+	if written, err := WriteFileOnce(filename, configServer{Host: "v1", Port: 1}, policy); err != nil || !written {
+		t.Fatalf("expected first write to succeed: %v", err)
+	}
+	data, _ := os.ReadFile(filename)
+	if !bytes.Contains(data, []byte(checksumPrefix)) || !verifyChecksum(data) {
+		t.Fatalf("expected valid checksum comment:\n%s", data)
+	}
+	if err := ValidateYAML(data); err != nil {
+		t.Errorf("invalid YAML: %v", err)
+	}
+
+	// 未修改的文件会被更新
+	if written, err := WriteFileOnce(filename, configServer{Host: "v2", Port: 2}, policy); err != nil || !written {
+		t.Fatalf("expected unmodified file to be updated: %v", err)
+	}
+
+	// 用户修改后不再覆盖
+	data, _ = os.ReadFile(filename)
+	modified := bytes.Replace(data, []byte("port: 2"), []byte("port: 9999"), 1)
+	if err := os.WriteFile(filename, modified, 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if written, err := WriteFileOnce(filename, configServer{Host: "v3", Port: 3}, policy); err != nil || written {
+		t.Fatalf("expected modified file to be kept: %v", err)
+	}
+	if err := WriteFileAtomic(filename, configServer{Host: "v3", Port: 3}, 0644, policy); err != nil {
+		t.Fatalf("WriteFileAtomic failed: %v", err)
+	}
+	if data, _ := os.ReadFile(filename); !bytes.Equal(data, modified) {
+		t.Errorf("modified file was overwritten:\n%s", data)
+	}
+}
@@ -0,0 +1,112 @@
+package yamlc
+
+import (
+	"path"
+	"strings"
+)
+
+// matchPathGlob 判断字段路径是否匹配通配符
+// 路径和通配符都按 "." 分段："*" 匹配恰好一段，"**" 匹配零或多段，
+// 其余分段按 path.Match 规则匹配（支持 "?"、"[a-z]" 及段内 "*"）
+func matchPathGlob(pattern, fieldPath string) bool {
+	if pattern == fieldPath {
+		return true
+	}
+	return matchSegments(splitPath(pattern), splitPath(fieldPath))
+}
+
+// matchSegments 逐段匹配
+func matchSegments(patterns, segments []string) bool {
+	for len(patterns) > 0 {
+		if patterns[0] == "**" {
+			// "**" 尝试吞掉0到全部剩余段
+			for i := 0; i <= len(segments); i++ {
+				if matchSegments(patterns[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, err := path.Match(patterns[0], segments[0]); err != nil || !ok {
+			return false
+		}
+		patterns = patterns[1:]
+		segments = segments[1:]
+	}
+	return len(segments) == 0
+}
+
+// splitPath 将字段路径拆分为段
+func splitPath(fieldPath string) []string {
+	if fieldPath == "" {
+		return nil
+	}
+	return strings.Split(fieldPath, ".")
+}
+
+// isPathPattern 判断路径是否包含通配符
+func isPathPattern(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+// patternSpecificity 通配符的具体程度：不含通配符的段越多越具体，"**" 最不具体
+func patternSpecificity(pattern string) int {
+	score := 0
+	for _, segment := range splitPath(pattern) {
+		switch {
+		case segment == "**":
+		case isPathPattern(segment):
+			score += 1
+		default:
+			score += 2
+		}
+	}
+	return score
+}
+
+// stripIndexSegments 去掉路径中的列表下标段，如 "workExperience.0.company" -> "workExperience.company"
+func stripIndexSegments(fieldPath string) string {
+	hasIndex := false
+	for rest, more := fieldPath, true; more && !hasIndex; {
+		var segment string
+		segment, rest, more = strings.Cut(rest, ".")
+		hasIndex = isIndexSegment(segment)
+	}
+	if !hasIndex {
+		return fieldPath
+	}
+	var result strings.Builder
+	kept := 0
+	for rest, more := fieldPath, true; more; {
+		var segment string
+		segment, rest, more = strings.Cut(rest, ".")
+		if isIndexSegment(segment) {
+			continue
+		}
+		if kept > 0 {
+			result.WriteByte('.')
+		}
+		result.WriteString(segment)
+		kept++
+	}
+	return result.String()
+}
+
+// isIndexSegment 判断路径段是否为列表下标（可带正负号的十进制整数）
+func isIndexSegment(segment string) bool {
+	if segment != "" && (segment[0] == '+' || segment[0] == '-') {
+		segment = segment[1:]
+	}
+	if segment == "" {
+		return false
+	}
+	for i := 0; i < len(segment); i++ {
+		if segment[i] < '0' || segment[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
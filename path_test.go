@@ -0,0 +1,29 @@
+package yamlc
+
+import "testing"
+
+func TestMatchPathGlob(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{"database", "database", true},
+		{"database.*", "database.host", true},
+		{"database.*", "database", false},
+		{"database.*", "database.pool.size", false},
+		{"database.**", "database.pool.size", true},
+		{"**.port", "servers.web.port", true},
+		{"**.port", "port", true},
+		{"servers.*.port", "servers.web.port", true},
+		{"servers.*.port", "servers.web.host", false},
+		{"db*", "dbHost", true},
+		{"tags.?", "tags.1", true},
+	}
+
+	for _, tc := range testCases {
+		if got := matchPathGlob(tc.pattern, tc.path); got != tc.expected {
+			t.Errorf("matchPathGlob(%q, %q) = %v, expected %v", tc.pattern, tc.path, got, tc.expected)
+		}
+	}
+}
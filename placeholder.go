@@ -0,0 +1,54 @@
+package yamlc
+
+import (
+	"reflect"
+)
+
+// DefaultPlaceholder 必填但为空的字符串字段默认的占位文本
+const DefaultPlaceholder = "<REQUIRED>"
+
+// WithPlaceholders 将带 required 标签、值为空的字符串字段输出为占位文本并在注释中提示，
+// 避免生成的配置中出现解析后静默得到 "" 的空值
+// 占位文本依次取 yamlc 标签中的 placeholder=、由 env= 标签得到的 ${环境变量}，都没有时为 DefaultPlaceholder
+func WithPlaceholders() Option {
+	return func(o *Options) {
+		o.Placeholders = true
+	}
+}
+
+// placeholderFor 判断字段是否需要输出占位文本，返回占位文本
+func placeholderFor(field reflect.StructField, val reflect.Value, options *Options) (string, bool) {
+	if !options.Placeholders || !hasTagFlag(field, "required") {
+		return "", false
+	}
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			break
+		}
+		val = val.Elem()
+	}
+	if val.Kind() == reflect.Ptr {
+		if val.Type().Elem().Kind() != reflect.String {
+			return "", false
+		}
+	} else if val.Kind() != reflect.String || val.Len() > 0 {
+		return "", false
+	}
+
+	if placeholder, ok := getTagValue(field, "placeholder"); ok && placeholder != "" {
+		return placeholder, true
+	}
+	if env := getEnvName(FieldInfo{FieldType: field}); env != "" {
+		return "${" + env + "}", true
+	}
+	return DefaultPlaceholder, true
+}
+
+// placeholderComment 在注释末尾追加需要替换占位文本的提示
+func placeholderComment(comment string) string {
+	const note = "(required: replace the placeholder)"
+	if comment == "" {
+		return note
+	}
+	return comment + " " + note
+}
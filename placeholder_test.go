@@ -0,0 +1,76 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestWithPlaceholders(t *testing.T) {
+	type Database struct {
+		Host     string  `yaml:"host" yamlc:"comment=主机,required"`
+		Password string  `yaml:"password" yamlc:"required" env:"DB_PASSWORD"`
+		Token    *string `yaml:"token" yamlc:"required,placeholder=<TOKEN>"`
+		Name     string  `yaml:"name" yamlc:"required"`
+		Option   string  `yaml:"option"`
+	}
+	type Config struct {
+		Database Database `yaml:"database"`
+	}
+	cfg := Config{Database: Database{Name: "app"}}
+
+	data, err := Gen(cfg, WithStyle(StyleTop), WithPlaceholders(), WithBlankLines(BlankLinesNone))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	expected := "database:\n" +
+		"  # 主机 (required: replace the placeholder)\n" +
+		"  host: <REQUIRED>\n" +
+		"  # (required: replace the placeholder)\n" +
+		"  password: ${DB_PASSWORD}\n" +
+		"  # (required: replace the placeholder)\n" +
+		"  token: <TOKEN>\n" +
+		"  name: app\n" +
+		"  option: \"\"\n"
+	if string(data) != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", data, expected)
+	}
+
+	var decoded Config
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded.Database.Host != DefaultPlaceholder || decoded.Database.Password != "${DB_PASSWORD}" {
+		t.Errorf("placeholders did not parse as strings: %+v", decoded.Database)
+	}
+}
+
+func TestWithPlaceholdersMinimal(t *testing.T) {
+	type Config struct {
+		Host string `yaml:"host" yamlc:"required"`
+		Port int    `yaml:"port" yamlc:"required"`
+	}
+
+	data, err := Gen(Config{}, WithStyle(StyleMinimal), WithPlaceholders())
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if string(data) != "host: <REQUIRED>\nport: 0\n" {
+		t.Errorf("unexpected output:\n%s", data)
+	}
+}
+
+func TestPlaceholdersDisabled(t *testing.T) {
+	type Config struct {
+		Host string `yaml:"host" yamlc:"required"`
+	}
+
+	data, err := Gen(Config{}, WithStyle(StyleTop))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if strings.Contains(string(data), DefaultPlaceholder) {
+		t.Errorf("placeholder written without WithPlaceholders:\n%s", data)
+	}
+}
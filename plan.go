@@ -0,0 +1,59 @@
+package yamlc
+
+import (
+	"reflect"
+	"sync"
+)
+
+// fieldPlan 结构体字段中与选项无关、可按类型缓存的信息
+type fieldPlan struct {
+	index      int
+	fieldType  reflect.StructField
+	name       string
+	tagComment string
+	example    string
+	section    string
+	// 以下信息供 FieldInfo 使用
+	required     bool
+	defaultValue string
+	enum         []string
+	envVar       string
+}
+
+// typePlans 按类型缓存的字段生成计划，避免每次生成都重新解析结构体标签
+var typePlans sync.Map // map[reflect.Type][]fieldPlan
+
+// getTypePlan 获取结构体类型的字段生成计划：导出且未被忽略的字段，按声明顺序
+func getTypePlan(typ reflect.Type) []fieldPlan {
+	if plan, ok := typePlans.Load(typ); ok {
+		return plan.([]fieldPlan)
+	}
+
+	plan := make([]fieldPlan, 0, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		fieldType := typ.Field(i)
+		if !fieldType.IsExported() {
+			continue
+		}
+		name := getFieldName(fieldType)
+		if name == "-" {
+			continue
+		}
+		info := FieldInfo{FieldType: fieldType}
+		setFieldTags(&info)
+		plan = append(plan, fieldPlan{
+			index:        i,
+			fieldType:    fieldType,
+			name:         name,
+			tagComment:   getTagComment(fieldType),
+			example:      getExample(fieldType),
+			section:      getSection(fieldType),
+			required:     info.IsRequired,
+			defaultValue: info.Default,
+			enum:         info.Enum,
+			envVar:       info.EnvVar,
+		})
+	}
+	actual, _ := typePlans.LoadOrStore(typ, plan)
+	return actual.([]fieldPlan)
+}
@@ -0,0 +1,37 @@
+package yamlc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTypePlanCache(t *testing.T) {
+	type Plan struct {
+		Host    string `yaml:"host" comment:"主机" yamlc:"example=db"`
+		Ignored string `yaml:"-"`
+		private string
+		Port    int `yaml:"port" yamlc:"section=Network"`
+	}
+
+	typ := reflect.TypeOf(Plan{})
+	plan := getTypePlan(typ)
+	if len(plan) != 2 || plan[0].name != "host" || plan[1].index != 3 {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+	if plan[0].tagComment != "主机" || plan[0].example != "db" || plan[1].section != "Network" {
+		t.Errorf("plan should hold tag metadata: %+v", plan)
+	}
+	if cached := getTypePlan(typ); &cached[0] != &plan[0] {
+		t.Error("plan should be cached per type")
+	}
+
+	// 缓存只保存标签信息，选项中的注释仍然生效
+	data, err := Gen(Plan{Host: "a", Port: 1}, WithComment(map[string]string{"host": "覆盖"}))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if !strings.Contains(string(data), "# 覆盖\n") || strings.Contains(string(data), "# 主机\n") {
+		t.Errorf("option comments should override cached tag comments:\n%s", data)
+	}
+}
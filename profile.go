@@ -0,0 +1,153 @@
+package yamlc
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WithProfile 按环境（如 "dev"、"prod"）生成配置：带 defaults 标签的字段取该环境的值，文档头部注明所用环境
+// 标签写法为 defaults:"dev=8080,prod=80"，值按YAML解析为字段类型；标签中没有该环境的字段保持原值
+func WithProfile(name string) Option {
+	return func(o *Options) {
+		o.Profile = name
+	}
+}
+
+// profileHeader 文档头部注明所用环境的注释
+func profileHeader(profile string) string {
+	return "Profile: " + profile
+}
+
+// profileValue 获取字段在指定环境下的值文本（defaults标签）
+func profileValue(field reflect.StructField, profile string) (string, bool) {
+	tag, ok := field.Tag.Lookup("defaults")
+	if !ok {
+		return "", false
+	}
+	for _, entry := range strings.Split(tag, ",") {
+		name, value, found := strings.Cut(entry, "=")
+		if found && strings.TrimSpace(name) == profile {
+			return strings.TrimSpace(value), true
+		}
+	}
+	return "", false
+}
+
+// applyProfile 返回按环境取值后的副本，原值不被修改；没有字段需要修改时返回原值
+func applyProfile(v interface{}, profile string) (interface{}, error) {
+	val, changed, err := profileCopy(reflect.ValueOf(v), "", profile)
+	if err != nil || !changed {
+		return v, err
+	}
+	return val.Interface(), nil
+}
+
+// profileCopy 递归处理结构体、指针、切片和映射，只复制需要修改的路径
+func profileCopy(val reflect.Value, fieldPath, profile string) (reflect.Value, bool, error) {
+	switch val.Kind() {
+	case reflect.Ptr:
+		if val.IsNil() {
+			return val, false, nil
+		}
+		elem, changed, err := profileCopy(val.Elem(), fieldPath, profile)
+		if err != nil || !changed {
+			return val, false, err
+		}
+		copied := reflect.New(elem.Type())
+		copied.Elem().Set(elem)
+		return copied, true, nil
+	case reflect.Struct:
+		var copied reflect.Value
+		typ := val.Type()
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			childPath := buildFieldPath(fieldPath, getFieldName(field))
+			var child reflect.Value
+			if text, ok := profileValue(field, profile); ok {
+				child = reflect.New(field.Type)
+				if err := yaml.Unmarshal([]byte(text), child.Interface()); err != nil {
+					return val, false, fmt.Errorf("invalid %s value %q for field %q: %w", profile, text, childPath, err)
+				}
+				child = child.Elem()
+			} else {
+				value, changed, err := profileCopy(val.Field(i), childPath, profile)
+				if err != nil {
+					return val, false, err
+				}
+				if !changed {
+					continue
+				}
+				child = value
+			}
+			if !copied.IsValid() {
+				copied = reflect.New(typ).Elem()
+				copied.Set(val)
+			}
+			copied.Field(i).Set(child)
+		}
+		if !copied.IsValid() {
+			return val, false, nil
+		}
+		return copied, true, nil
+	case reflect.Slice, reflect.Array:
+		var copied reflect.Value
+		for i := 0; i < val.Len(); i++ {
+			item, changed, err := profileCopy(val.Index(i), buildFieldPath(fieldPath, strconv.Itoa(i)), profile)
+			if err != nil {
+				return val, false, err
+			}
+			if !changed {
+				continue
+			}
+			if !copied.IsValid() {
+				if val.Kind() == reflect.Slice {
+					copied = reflect.MakeSlice(val.Type(), val.Len(), val.Len())
+				} else {
+					copied = reflect.New(val.Type()).Elem()
+				}
+				reflect.Copy(copied, val)
+			}
+			copied.Index(i).Set(item)
+		}
+		if !copied.IsValid() {
+			return val, false, nil
+		}
+		return copied, true, nil
+	case reflect.Map:
+		var copied reflect.Value
+		iter := val.MapRange()
+		for iter.Next() {
+			item, changed, err := profileCopy(iter.Value(), buildFieldPath(fieldPath, fmt.Sprint(iter.Key())), profile)
+			if err != nil {
+				return val, false, err
+			}
+			if !changed {
+				continue
+			}
+			if !copied.IsValid() {
+				copied = reflect.MakeMapWithSize(val.Type(), val.Len())
+				for all := val.MapRange(); all.Next(); {
+					copied.SetMapIndex(all.Key(), all.Value())
+				}
+			}
+			copied.SetMapIndex(iter.Key(), item)
+		}
+		if !copied.IsValid() {
+			return val, false, nil
+		}
+		return copied, true, nil
+	case reflect.Interface:
+		if val.IsNil() {
+			return val, false, nil
+		}
+		return profileCopy(val.Elem(), fieldPath, profile)
+	}
+	return val, false, nil
+}
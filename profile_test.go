@@ -0,0 +1,84 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithProfile(t *testing.T) {
+	type Server struct {
+		Host  string `yaml:"host" comment:"监听地址" defaults:"dev=localhost,prod=0.0.0.0"`
+		Port  int    `yaml:"port" comment:"端口" defaults:"dev=8080, prod=80"`
+		Level string `yaml:"level" defaults:"prod=warn"`
+	}
+	type Config struct {
+		Server  Server   `yaml:"server"`
+		Mirrors []Server `yaml:"mirrors"`
+		Debug   bool     `yaml:"debug" defaults:"dev=true"`
+	}
+	cfg := &Config{
+		Server:  Server{Host: "127.0.0.1", Port: 9000, Level: "debug"},
+		Mirrors: []Server{{Host: "m", Port: 1}},
+	}
+
+	data, err := Gen(cfg, WithStyle(StyleTop), WithProfile("prod"), WithBlankLines(BlankLinesNone))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	expected := "# Profile: prod\n\nserver:\n" +
+		"  # 监听地址\n  host: 0.0.0.0\n  # 端口\n  port: 80\n  level: warn\n" +
+		"mirrors:\n    # 监听地址\n    # 端口\n  - host: 0.0.0.0\n    port: 80\n    level: warn\n" +
+		"debug: false\n"
+	if string(data) != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", data, expected)
+	}
+	if cfg.Server.Port != 9000 || cfg.Mirrors[0].Host != "m" {
+		t.Errorf("WithProfile modified the input: %+v", cfg)
+	}
+
+	data, err = Gen(cfg, WithStyle(StyleTop), WithProfile("dev"))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	for _, want := range []string{"# Profile: dev\n", "host: localhost", "port: 8080", "level: debug", "debug: true"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("dev profile output missing %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestWithProfileInvalidValue(t *testing.T) {
+	type Config struct {
+		Port int `yaml:"port" defaults:"prod=eighty"`
+	}
+
+	_, err := Gen(Config{}, WithProfile("prod"))
+	if err == nil || !strings.Contains(err.Error(), `"port"`) {
+		t.Errorf("expected error naming the field, got %v", err)
+	}
+}
+
+func TestWithProfileMapValues(t *testing.T) {
+	type Server struct {
+		Port int `yaml:"port" defaults:"prod=80"`
+	}
+	type Config struct {
+		Servers map[string]Server      `yaml:"servers"`
+		Extra   map[string]interface{} `yaml:"extra"`
+	}
+	cfg := Config{
+		Servers: map[string]Server{"api": {Port: 8080}},
+		Extra:   map[string]interface{}{"admin": &Server{Port: 9000}, "name": "x"},
+	}
+
+	data, err := Gen(cfg, WithStyle(StyleTop), WithProfile("prod"))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if strings.Count(string(data), "port: 80\n") != 2 || !strings.Contains(string(data), "name: x") {
+		t.Errorf("expected profile values inside maps:\n%s", data)
+	}
+	if cfg.Servers["api"].Port != 8080 || cfg.Extra["admin"].(*Server).Port != 9000 {
+		t.Errorf("WithProfile modified the input: %+v", cfg)
+	}
+}
@@ -0,0 +1,35 @@
+package yamlc
+
+import (
+	"fmt"
+)
+
+// GetPath 读取YAML文档中路径上的值，路径写法与注释映射相同（如 "servers.0.port"），空路径返回整个文档
+// 值按YAML规则解码：映射为 map[string]interface{}，列表为 []interface{}，标量为对应的Go类型。多文档时只读取第一个文档
+func GetPath(data []byte, path string) (interface{}, error) {
+	docs, err := decodeDocumentNodes(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 || len(docs[0].Content) == 0 {
+		if path == "" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("path %q not found", path)
+	}
+
+	node := docs[0].Content[0]
+	if segments := splitPath(path); len(segments) > 0 {
+		parent, index, err := locateNode(node, segments, false)
+		if err != nil {
+			return nil, fmt.Errorf("path %q: %w", path, err)
+		}
+		node = parent.Content[index]
+	}
+
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to decode value at %q: %w", path, err)
+	}
+	return value, nil
+}
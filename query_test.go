@@ -0,0 +1,41 @@
+package yamlc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetPath(t *testing.T) {
+	data, err := Gen(layeredConfig{Servers: []layeredServer{{Host: "a", Port: 1}, {Host: "b", Port: 2}}, Name: "demo"})
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+
+	cases := map[string]interface{}{
+		"name":           "demo",
+		"servers.1.port": 2,
+		"servers.0":      map[string]interface{}{"host": "a", "port": 1},
+	}
+	for path, expected := range cases {
+		value, err := GetPath(data, path)
+		if err != nil {
+			t.Errorf("GetPath(%q) failed: %v", path, err)
+			continue
+		}
+		if !reflect.DeepEqual(value, expected) {
+			t.Errorf("GetPath(%q) = %#v, want %#v", path, value, expected)
+		}
+	}
+
+	if value, err := GetPath(data, ""); err != nil || len(value.(map[string]interface{})) != 2 {
+		t.Errorf("expected whole document, got %v, %v", value, err)
+	}
+	for _, path := range []string{"missing", "servers.2", "name.first"} {
+		if _, err := GetPath(data, path); err == nil {
+			t.Errorf("expected error for path %q", path)
+		}
+	}
+	if _, err := GetPath(nil, "name"); err == nil {
+		t.Error("expected error for empty document")
+	}
+}
@@ -0,0 +1,186 @@
+package yamlc
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// QuoteStyle 字符串的引号风格
+type QuoteStyle int
+
+const (
+	// QuoteAuto 仅在需要时加引号，由yaml.v3选择最简表示（通常为单引号，需要转义时为双引号）
+	QuoteAuto QuoteStyle = iota
+	// QuoteSingle 需要时使用单引号，无法用单引号表示的内容（换行、控制字符）回退为双引号
+	QuoteSingle
+	// QuoteDouble 需要时始终使用双引号
+	QuoteDouble
+	// QuoteAlways 所有字符串值都加双引号
+	QuoteAlways
+)
+
+// WithQuoteStyle 设置字符串的引号风格
+func WithQuoteStyle(style QuoteStyle) Option {
+	return func(o *Options) {
+		o.QuoteStyle = style
+	}
+}
+
+// Compatibility 输出面向的YAML版本，决定哪些字符串需要加引号
+type Compatibility int
+
+const (
+	// YAML11 兼容YAML 1.1解析器（默认）：yes/no/on/off 等布尔写法和六十进制数字形式的字符串加引号
+	YAML11 Compatibility = iota
+	// YAML12 按YAML 1.2核心模式加引号，输出更简洁
+	YAML12
+)
+
+// WithCompatibility 设置输出兼容的YAML版本
+func WithCompatibility(c Compatibility) Option {
+	return func(o *Options) {
+		o.Compatibility = c
+	}
+}
+
+// quoteString 按引号风格输出字符串值
+func quoteString(str string, options *Options) string {
+	if options.QuoteStyle != QuoteAlways && !needsQuotingFor(str, options) {
+		return str
+	}
+	return quote(str, options.QuoteStyle)
+}
+
+// quoteKey 按引号风格输出映射键，键只在需要时加引号
+func quoteKey(key string, options *Options) string {
+	if !needsQuotingFor(key, options) {
+		return key
+	}
+	return quote(key, options.QuoteStyle)
+}
+
+// needsQuoting 检查字符串能否作为普通标量原样输出，判断规则与yaml.v3（YAML 1.2）一致
+func needsQuoting(str string) bool {
+	return encodeScalar(str, 0) != str
+}
+
+// needsQuotingFor 按兼容版本检查字符串是否需要引号
+func needsQuotingFor(str string, options *Options) bool {
+	return needsQuoting(str) || (options.Compatibility == YAML11 && isYAML11Ambiguous(str))
+}
+
+// yaml11Bools YAML 1.1中会被解析为布尔值的写法（true/false 在1.2中同样是布尔值）
+var yaml11Bools = map[string]bool{
+	"y": true, "Y": true, "yes": true, "Yes": true, "YES": true,
+	"n": true, "N": true, "no": true, "No": true, "NO": true,
+	"on": true, "On": true, "ON": true, "off": true, "Off": true, "OFF": true,
+}
+
+// yaml11Sexagesimal YAML 1.1中的六十进制整数和浮点数，如 "1:20"、"190:20:30.15"
+var yaml11Sexagesimal = regexp.MustCompile(`^[-+]?[0-9][0-9_]*(:[0-5]?[0-9])+(\.[0-9_]*)?$`)
+
+// isYAML11Ambiguous 判断字符串在YAML 1.1解析器中是否会被解析为非字符串
+func isYAML11Ambiguous(str string) bool {
+	return yaml11Bools[str] || yaml11Sexagesimal.MatchString(str)
+}
+
+// quote 为字符串加引号，引号内的转义由yaml.v3完成
+func quote(str string, style QuoteStyle) string {
+	var quoted string
+	switch style {
+	case QuoteAuto:
+		quoted = encodeScalar(str, 0)
+		if quoted == str {
+			// 无需引号但调用方要求加引号（如流式中的逗号），使用单引号
+			quoted = encodeScalar(str, yaml.SingleQuotedStyle)
+		}
+	case QuoteSingle:
+		quoted = encodeScalar(str, yaml.SingleQuotedStyle)
+	default:
+		quoted = encodeScalar(str, yaml.DoubleQuotedStyle)
+	}
+
+	// 字段值必须写在一行内，跨行的表示（块标量、折行的单引号）改用双引号
+	// yaml.v3 把 NEL、LS、PS 也当作换行
+	if strings.ContainsAny(quoted, "\n\u0085\u2028\u2029") {
+		quoted = encodeScalar(str, yaml.DoubleQuotedStyle)
+	}
+	return quoted
+}
+
+// compatNode 在yaml节点树上为YAML 1.1中有歧义的字符串加引号，用于不经过字段渲染的最小风格
+func compatNode(node *yaml.Node, options *Options) {
+	if options.Compatibility != YAML11 {
+		return
+	}
+	if node.Kind == yaml.ScalarNode && node.Style == 0 && node.ShortTag() == "!!str" && isYAML11Ambiguous(node.Value) {
+		node.Style = yaml.DoubleQuotedStyle
+	}
+	for _, child := range node.Content {
+		compatNode(child, options)
+	}
+}
+
+// scalarBufferPool 编码标量时复用的缓冲区
+var scalarBufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// encodeScalar 使用yaml.v3按指定风格编码字符串标量
+// 配置中绝大多数字符串是简单的标识符，可以确定原样输出，跳过编码器以减少分配
+func encodeScalar(str string, style yaml.Style) string {
+	if style == 0 && isSimplePlain(str) {
+		return str
+	}
+
+	buf := scalarBufferPool.Get().(*bytes.Buffer)
+	defer func() {
+		buf.Reset()
+		scalarBufferPool.Put(buf)
+	}()
+	encoder := yaml.NewEncoder(buf)
+	node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: str, Style: style}
+	if err := encoder.Encode(node); err != nil {
+		// yaml.v3 无法编码（如非法UTF-8）时退回Go风格的双引号转义
+		return fmt.Sprintf("%q", str)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Sprintf("%q", str)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// simplePlainReserved 以字母开头却会被解析为非字符串（或被yaml.v3加引号）的写法，统一走编码器判断
+var simplePlainReserved = map[string]bool{
+	"true": true, "false": true, "null": true,
+	"yes": true, "no": true, "on": true, "off": true, "y": true, "n": true,
+}
+
+// isSimplePlain 判断字符串是否确定可以作为普通标量原样输出：
+// 以字母开头，只包含字母、数字和 _ - . / @ +，不是布尔、空值等保留写法；
+// 较短的字符串允许单词之间有单个空格（过长时编码器可能折行）
+func isSimplePlain(str string) bool {
+	if str == "" || simplePlainReserved[strings.ToLower(str)] {
+		return false
+	}
+	if strings.Contains(str, " ") && (len(str) > 40 || strings.Contains(str, "  ") || strings.HasSuffix(str, " ")) {
+		return false
+	}
+	for i, r := range str {
+		switch {
+		case unicode.IsLetter(r):
+		case i > 0 && (unicode.IsDigit(r) || strings.ContainsRune("_-./@+ ", r)):
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,156 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestQuote(t *testing.T) {
+	testCases := []struct {
+		str      string
+		style    QuoteStyle
+		expected string
+	}{
+		{"张三: 工程师", QuoteAuto, `'张三: 工程师'`},
+		{"go, rust", QuoteAuto, `'go, rust'`},
+		{"张三: 工程师", QuoteDouble, `"张三: 工程师"`},
+		{"it's: ok", QuoteSingle, `'it''s: ok'`},
+		{"tab\there", QuoteSingle, `"tab\there"`},
+		{"bell\a", QuoteDouble, `"bell\a"`},
+		{"line\u2028sep", QuoteSingle, `"line\Lsep"`},
+	}
+
+	for _, tc := range testCases {
+		if got := quote(tc.str, tc.style); got != tc.expected {
+			t.Errorf("quote(%q, %d) = %s, expected %s", tc.str, tc.style, got, tc.expected)
+		}
+	}
+}
+
+func TestWithQuoteStyle(t *testing.T) {
+	type Profile struct {
+		Name  string            `yaml:"name"`
+		Title string            `yaml:"title"`
+		Tags  map[string]string `yaml:"tags"`
+	}
+	profile := Profile{Name: "张三", Title: "高级工程师: 后端", Tags: map[string]string{"a:b": "yes"}}
+
+	testCases := []struct {
+		style    QuoteStyle
+		expected []string
+	}{
+		{QuoteAuto, []string{"name: 张三\n", `title: '高级工程师: 后端'`, "a:b: 'yes'\n"}},
+		{QuoteSingle, []string{"name: 张三\n", `title: '高级工程师: 后端'`}},
+		{QuoteDouble, []string{"name: 张三\n", `title: "高级工程师: 后端"`}},
+		{QuoteAlways, []string{`name: "张三"`, `title: "高级工程师: 后端"`, `a:b: "yes"`}},
+	}
+
+	for _, tc := range testCases {
+		data, err := Gen(profile, WithStyle(StyleTop), WithQuoteStyle(tc.style))
+		if err != nil {
+			t.Fatalf("Gen failed: %v", err)
+		}
+		yamlStr := string(data)
+		for _, expected := range tc.expected {
+			if !strings.Contains(yamlStr, expected) {
+				t.Errorf("style %d: expected %q in:\n%s", tc.style, expected, yamlStr)
+			}
+		}
+
+		var decoded Profile
+		if err := yaml.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unmarshal failed: %v", err)
+		}
+		if decoded.Name != profile.Name || decoded.Title != profile.Title || decoded.Tags["a:b"] != "yes" {
+			t.Errorf("style %d did not round-trip: %+v", tc.style, decoded)
+		}
+	}
+}
+
+func TestWithCompatibility(t *testing.T) {
+	type Switches struct {
+		Enabled string   `yaml:"enabled"`
+		Time    string   `yaml:"time"`
+		Version string   `yaml:"version"`
+		Answers []string `yaml:"answers"`
+	}
+	sw := Switches{Enabled: "on", Time: "1:20", Version: "1.20", Answers: []string{"y", "No", "maybe"}}
+
+	testCases := []struct {
+		compat   Compatibility
+		expected []string
+	}{
+		{YAML11, []string{"enabled: 'on'\n", "time: '1:20'\n", `version: "1.20"`, "- 'y'\n", "- 'No'\n", "- maybe\n"}},
+		{YAML12, []string{"enabled: on\n", "time: 1:20\n", `version: "1.20"`, "- y\n", "- No\n", "- maybe\n"}},
+	}
+
+	for _, tc := range testCases {
+		data, err := Gen(sw, WithStyle(StyleTop), WithCompatibility(tc.compat))
+		if err != nil {
+			t.Fatalf("Gen failed: %v", err)
+		}
+		yamlStr := string(data)
+		for _, expected := range tc.expected {
+			if !strings.Contains(yamlStr, expected) {
+				t.Errorf("compatibility %d: expected %q in:\n%s", tc.compat, expected, yamlStr)
+			}
+		}
+
+		var decoded Switches
+		if err := yaml.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unmarshal failed: %v", err)
+		}
+		if decoded.Enabled != sw.Enabled || decoded.Time != sw.Time || decoded.Answers[1] != "No" {
+			t.Errorf("compatibility %d did not round-trip: %+v", tc.compat, decoded)
+		}
+	}
+
+	data, err := Gen(sw, WithStyle(StyleMinimal))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if !strings.Contains(string(data), `time: "1:20"`) {
+		t.Errorf("minimal style should quote sexagesimal strings:\n%s", data)
+	}
+}
+
+func TestIsYAML11Ambiguous(t *testing.T) {
+	testCases := []struct {
+		str      string
+		expected bool
+	}{
+		{"yes", true},
+		{"OFF", true},
+		{"y", true},
+		{"1:20", true},
+		{"-190:20:30.15", true},
+		{"12:60", false},
+		{"yEs", false},
+		{"10:30 am", false},
+		{"hello", false},
+	}
+
+	for _, tc := range testCases {
+		if got := isYAML11Ambiguous(tc.str); got != tc.expected {
+			t.Errorf("isYAML11Ambiguous(%q) = %v, expected %v", tc.str, got, tc.expected)
+		}
+	}
+}
+
+func TestIsSimplePlainMatchesEncoder(t *testing.T) {
+	for _, str := range []string{
+		"demo", "db.prod.internal", "user@example.com", "a-b_c/d", "中关村大街1号", "Hello World",
+		"yes", "No", "null", "True", "y", "1abc", "-a", ".inf", "a: b", "a #b", "a  b", "a ",
+		"x+y", "a very long sentence that goes beyond the forty character limit for sure",
+	} {
+		if isSimplePlain(str) {
+			node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: str}
+			data, err := yaml.Marshal(node)
+			if err != nil || strings.TrimSuffix(string(data), "\n") != str {
+				t.Errorf("isSimplePlain(%q) is true but yaml.v3 encodes it as %q", str, data)
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package yamlc
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Raw 预先渲染好的YAML片段，生成时原样插入（按所在位置重新缩进），
+// 用于保留由其他系统维护的配置内容，片段中的注释和格式保持不变
+// 解码时保存对应节点的YAML文本，因此读取后再生成不会丢失内容
+type Raw string
+
+var (
+	rawType      = reflect.TypeOf(Raw(""))
+	yamlNodeType = reflect.TypeOf(yaml.Node{})
+)
+
+// MarshalYAML 将片段解析为节点，供 yaml.v3 编码（包括最小风格）使用
+func (r Raw) MarshalYAML() (interface{}, error) {
+	node, err := parseRaw(string(r))
+	if err != nil || node == nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// UnmarshalYAML 将节点重新编码为YAML文本保存
+func (r *Raw) UnmarshalYAML(node *yaml.Node) error {
+	data, err := yaml.Marshal(node)
+	if err != nil {
+		return fmt.Errorf("failed to encode raw YAML: %w", err)
+	}
+	*r = Raw(strings.TrimRight(string(data), "\n"))
+	return nil
+}
+
+// isRawType 判断类型是否原样插入输出：Raw 或 yaml.Node
+// yaml.Node 为文档节点时插入其根节点；最小风格由 yaml.v3 直接编码，不接受嵌套的文档节点，需传入根节点
+func isRawType(typ reflect.Type) bool {
+	return typ == rawType || typ == yamlNodeType
+}
+
+// parseRaw 解析片段，返回文档的根节点；空片段返回nil
+func parseRaw(text string) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(text), &doc); err != nil {
+		return nil, fmt.Errorf("invalid raw YAML: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	return doc.Content[0], nil
+}
+
+// rawNode 返回 Raw 或 yaml.Node 值对应的节点，片段无法解析或为空时返回nil
+func rawNode(val reflect.Value) *yaml.Node {
+	switch val.Type() {
+	case rawType:
+		node, _ := parseRaw(val.String())
+		return node
+	case yamlNodeType:
+		node := val.Interface().(yaml.Node)
+		if node.Kind == yaml.DocumentNode {
+			if len(node.Content) == 0 {
+				return nil
+			}
+			return node.Content[0]
+		}
+		if node.Kind == 0 {
+			return nil
+		}
+		return &node
+	}
+	return nil
+}
+
+// rawHasChildren 判断 Raw 或 yaml.Node 值是否为块风格的映射或列表，需要另起一行输出
+func rawHasChildren(val reflect.Value) bool {
+	node := rawNode(val)
+	return node != nil && isBlockCollection(node)
+}
+
+// generateRaw 将 Raw 或 yaml.Node 值按所在位置的缩进插入
+// 块风格的映射和列表逐行缩进到 indent 级别；标量和流式值紧跟在键之后，多行时后续行同样缩进
+func generateRaw(val reflect.Value, fieldPath string, indent int, options *Options) (string, error) {
+	var text string
+	if val.Type() == rawType {
+		text = val.String()
+		if _, err := parseRaw(text); err != nil {
+			return "", fmt.Errorf("field %q: %w", fieldPath, err)
+		}
+	} else {
+		node := rawNode(val)
+		if node == nil {
+			return nullValue(options), nil
+		}
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(getIndentWidth(options))
+		if err := encoder.Encode(node); err != nil {
+			return "", fmt.Errorf("field %q: failed to encode YAML node: %w", fieldPath, err)
+		}
+		if err := encoder.Close(); err != nil {
+			return "", fmt.Errorf("field %q: failed to encode YAML node: %w", fieldPath, err)
+		}
+		text = buf.String()
+	}
+
+	lines := dedentLines(strings.Split(strings.TrimRight(text, "\n"), "\n"))
+	if len(lines) == 1 && strings.TrimSpace(lines[0]) == "" {
+		return nullValue(options), nil
+	}
+	indentStr := getIndentStr(indent, options)
+	block := rawHasChildren(val)
+	var result strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			result.WriteString("\n")
+		}
+		if line != "" && (block || i > 0) {
+			result.WriteString(indentStr)
+		}
+		result.WriteString(line)
+	}
+	if block {
+		result.WriteString("\n")
+	}
+	return result.String(), nil
+}
+
+// dedentLines 去掉各非空行共同的前导空格
+func dedentLines(lines []string) []string {
+	common := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		width := len(line) - len(strings.TrimLeft(line, " "))
+		if common < 0 || width < common {
+			common = width
+		}
+	}
+	if common <= 0 {
+		return lines
+	}
+	for i, line := range lines {
+		if len(line) >= common {
+			lines[i] = line[common:]
+		} else {
+			lines[i] = ""
+		}
+	}
+	return lines
+}
@@ -0,0 +1,103 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestRawPassthrough(t *testing.T) {
+	type Config struct {
+		Name   string `yaml:"name" comment:"名称"`
+		Plugin Raw    `yaml:"plugin" comment:"插件配置"`
+		Mode   Raw    `yaml:"mode"`
+		Empty  Raw    `yaml:"empty"`
+	}
+	cfg := Config{
+		Name:   "demo",
+		Plugin: Raw("    enabled: true   # 由插件维护\n    rules:\n      - a\n\n    limits: {cpu: 2}\n"),
+		Mode:   "fast",
+	}
+
+	data, err := Gen(cfg, WithStyle(StyleTop))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	expected := "# 名称\nname: demo\n# 插件配置\nplugin:\n  enabled: true   # 由插件维护\n  rules:\n    - a\n\n  limits: {cpu: 2}\nmode: fast\nempty: null\n"
+	if !strings.HasPrefix(string(data), expected) {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", data, expected)
+	}
+
+	var decoded Config
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded.Mode != "fast" || !strings.Contains(string(decoded.Plugin), "limits: {cpu: 2}") {
+		t.Errorf("raw fields did not round-trip: %+v", decoded)
+	}
+}
+
+func TestRawStyles(t *testing.T) {
+	type Config struct {
+		Plugin Raw   `yaml:"plugin" comment:"插件配置"`
+		Items  []Raw `yaml:"items"`
+		Text   Raw   `yaml:"text"`
+	}
+	cfg := Config{
+		Plugin: Raw("a: 1\nb:\n  - x"),
+		Items:  []Raw{"k: v\nq: 2", "plain"},
+		Text:   Raw("|\n  line1\n  line2"),
+	}
+
+	for _, style := range []CommentStyle{StyleTop, StyleInline, StyleSmart, StyleCompact, StyleVerbose, StyleMinimal} {
+		for _, indent := range []int{2, 4} {
+			data, err := Gen(cfg, WithStyle(style), WithIndent(indent))
+			if err != nil {
+				t.Fatalf("style %s: Gen failed: %v", GetStyleString(int(style)), err)
+			}
+			var decoded struct {
+				Plugin map[string]interface{} `yaml:"plugin"`
+				Items  []interface{}          `yaml:"items"`
+				Text   string                 `yaml:"text"`
+			}
+			if err := yaml.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("style %s: Unmarshal failed: %v\n%s", GetStyleString(int(style)), err, data)
+			}
+			if decoded.Plugin["a"] != 1 || len(decoded.Items) != 2 || decoded.Items[1] != "plain" || strings.TrimRight(decoded.Text, "\n") != "line1\nline2" {
+				t.Errorf("style %s indent %d: raw values not preserved:\n%s", GetStyleString(int(style)), indent, data)
+			}
+		}
+	}
+}
+
+func TestYAMLNodePassthrough(t *testing.T) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte("# 外部维护\nx: 1\ny: [1, 2]\n"), &doc); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	type Config struct {
+		Node    yaml.Node  `yaml:"node" comment:"节点"`
+		Pointer *yaml.Node `yaml:"pointer"`
+	}
+	cfg := Config{Node: doc, Pointer: doc.Content[0]}
+
+	data, err := Gen(cfg, WithStyle(StyleTop))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	expected := "# 节点\nnode:\n  # 外部维护\n  x: 1\n  y: [1, 2]\npointer:\n  # 外部维护\n  x: 1\n  y: [1, 2]\n"
+	if !strings.HasPrefix(string(data), expected) {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", data, expected)
+	}
+}
+
+func TestRawInvalid(t *testing.T) {
+	type Config struct {
+		Plugin Raw `yaml:"plugin"`
+	}
+	_, err := Gen(Config{Plugin: Raw("a: [1, 2")}, WithStyle(StyleTop))
+	if err == nil || !strings.Contains(err.Error(), "invalid raw YAML") {
+		t.Errorf("expected invalid raw YAML error, got %v", err)
+	}
+}
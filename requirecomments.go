@@ -0,0 +1,73 @@
+package yamlc
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// WithRequireComments 要求每个输出的结构体字段都有注释（来自标签或选项），否则生成失败并列出缺少注释的字段路径
+// 列表元素中的字段按去掉下标的路径报告，适合在CI中保证每个配置项都有文档
+func WithRequireComments() Option {
+	return func(o *Options) {
+		o.RequireComments = true
+	}
+}
+
+// checkRequiredComments 检查值中所有输出的结构体字段是否都有注释
+func checkRequiredComments(v interface{}, options *Options) error {
+	var missing []string
+	seen := make(map[string]bool)
+	findUndocumented(reflect.ValueOf(v), "", options, func(fieldPath string) {
+		fieldPath = stripIndexSegments(fieldPath)
+		if !seen[fieldPath] {
+			seen[fieldPath] = true
+			missing = append(missing, fieldPath)
+		}
+	})
+	if len(missing) > 0 {
+		return fmt.Errorf("undocumented fields: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// findUndocumented 递归查找没有注释的结构体字段，跳过被过滤或对当前受众隐藏的字段
+func findUndocumented(val reflect.Value, fieldPath string, options *Options, report func(string)) {
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return
+		}
+		val = val.Elem()
+	}
+
+	switch val.Kind() {
+	case reflect.Struct:
+		for _, plan := range getTypePlan(val.Type()) {
+			currentFieldPath := buildFieldPath(fieldPath, plan.name)
+			if isHiddenField(plan.fieldType, options) || isFieldFiltered(currentFieldPath, options) {
+				continue
+			}
+			if resolveFieldComment(plan, currentFieldPath, options) == "" {
+				report(currentFieldPath)
+			}
+			findUndocumented(val.Field(plan.index), currentFieldPath, options, report)
+		}
+	case reflect.Map:
+		keys := val.MapKeys()
+		sortMapKeys(keys)
+		for _, key := range keys {
+			entryPath := buildFieldPath(fieldPath, mapKeyText(key))
+			if !isFieldFiltered(entryPath, options) {
+				findUndocumented(val.MapIndex(key), entryPath, options, report)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if isByteSlice(val) {
+			return
+		}
+		for i := 0; i < val.Len(); i++ {
+			findUndocumented(val.Index(i), buildFieldPath(fieldPath, strconv.Itoa(i)), options, report)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+)
+
+type requireBackend struct {
+	Host string `yaml:"host" comment:"主机"`
+	Port int    `yaml:"port"`
+}
+
+type requireConfig struct {
+	Name     string                    `yaml:"name" comment:"名称"`
+	Version  string                    `yaml:"version"`
+	Backends []requireBackend          `yaml:"backends" comment:"后端"`
+	Routes   map[string]requireBackend `yaml:"routes" comment:"路由"`
+	Internal string                    `yaml:"internal"`
+}
+
+func TestWithRequireComments(t *testing.T) {
+	cfg := requireConfig{
+		Name:     "demo",
+		Backends: []requireBackend{{Host: "a"}, {Host: "b"}},
+		Routes:   map[string]requireBackend{"api": {Host: "c"}},
+	}
+
+	for _, style := range []CommentStyle{StyleTop, StyleMinimal} {
+		_, err := Gen(cfg, WithStyle(style), WithRequireComments())
+		if err == nil {
+			t.Fatalf("style %s: expected error for undocumented fields", GetStyleString(int(style)))
+		}
+		expected := "undocumented fields: version, backends.port, routes.api.port, internal"
+		if err.Error() != expected {
+			t.Errorf("style %s: expected %q, got %q", GetStyleString(int(style)), expected, err.Error())
+		}
+	}
+
+	// 选项中的注释和被过滤的字段都满足要求
+	_, err := Gen(cfg,
+		WithRequireComments(),
+		WithComment(map[string]string{"version": "版本", "backends.port": "端口", "routes.*.port": "端口"}),
+		WithExclude("internal"),
+	)
+	if err != nil {
+		t.Errorf("expected documented config to pass: %v", err)
+	}
+
+	// 替换方式下空的选项注释去掉了标签注释，字段视为没有注释
+	_, err = Gen(requireBackend{}, WithRequireComments(), WithComment(map[string]string{"host": "", "port": "端口"}))
+	if err == nil || !strings.Contains(err.Error(), "host") {
+		t.Errorf("expected host to be reported, got %v", err)
+	}
+
+	if _, err := Gen(cfg); err != nil {
+		t.Errorf("comments should not be required by default: %v", err)
+	}
+}
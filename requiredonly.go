@@ -0,0 +1,18 @@
+package yamlc
+
+import (
+	"reflect"
+)
+
+// WithRequiredOnly 只输出带 required 标签的字段及其上级字段，生成最短的起步配置；完整模板仍可另行生成
+// 判断规则与 GenScaffold 相同：结构体字段中含有必填子字段时保留
+func WithRequiredOnly() Option {
+	return func(o *Options) {
+		o.RequiredOnly = true
+	}
+}
+
+// isOptionalField 判断字段是否因只输出必填字段而被省略
+func isOptionalField(field reflect.StructField, options *Options) bool {
+	return options.RequiredOnly && !isRequiredField(field)
+}
@@ -0,0 +1,52 @@
+package yamlc
+
+import (
+	"testing"
+)
+
+func TestWithRequiredOnly(t *testing.T) {
+	type Database struct {
+		DSN      string `yaml:"dsn" yamlc:"required" comment:"连接串"`
+		MaxConns int    `yaml:"maxConns" comment:"最大连接数"`
+	}
+	type Log struct {
+		Level string `yaml:"level"`
+	}
+	type Config struct {
+		Name     string   `yaml:"name" yamlc:"required" comment:"服务名"`
+		Port     int      `yaml:"port" comment:"端口"`
+		Database Database `yaml:"database" comment:"数据库"`
+		Log      Log      `yaml:"log"`
+	}
+	cfg := Config{Name: "api", Port: 8080, Database: Database{DSN: "postgres://localhost/app", MaxConns: 10}, Log: Log{Level: "info"}}
+
+	testCases := []struct {
+		style    CommentStyle
+		expected string
+	}{
+		{StyleTop, "# 服务名\nname: api\n# 数据库\ndatabase:\n  # 连接串\n  dsn: postgres://localhost/app\n"},
+		{StyleMinimal, "name: api\ndatabase:\n  dsn: postgres://localhost/app\n"},
+	}
+	for _, tc := range testCases {
+		data, err := Gen(cfg, WithStyle(tc.style), WithIndent(2), WithRequiredOnly(), WithBlankLines(BlankLinesNone))
+		if err != nil {
+			t.Fatalf("Gen failed: %v", err)
+		}
+		if string(data) != tc.expected {
+			t.Errorf("style %s: unexpected output:\n%s\nexpected:\n%s", GetStyleString(int(tc.style)), data, tc.expected)
+		}
+	}
+}
+
+func TestWithRequiredOnlyNoRequiredFields(t *testing.T) {
+	type Config struct {
+		Port int `yaml:"port"`
+	}
+	data, err := Gen(Config{Port: 80}, WithStyle(StyleTop), WithRequiredOnly())
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if string(data) != "{}\n" {
+		t.Errorf("unexpected output: %q", data)
+	}
+}
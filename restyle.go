@@ -0,0 +1,169 @@
+package yamlc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var interfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+
+// Restyle 读取任意YAML，保留键、值、顺序和注释，按指定的注释风格重新输出（如行尾注释与头顶注释互转）
+// 文档开头和末尾的注释分别作为 WithHeader、WithFooter 输出；opts 在其后应用，其中的 WithHeader 追加在原有的开头注释之后
+func Restyle(data []byte, style CommentStyle, opts ...Option) ([]byte, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	var parts [][]byte
+	for i := 0; ; i++ {
+		var doc yaml.Node
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("YAML parsing error: %w", err)
+		}
+		if len(doc.Content) == 0 {
+			continue
+		}
+
+		typeNames := make(map[reflect.Type]string)
+		value := restyleValue(doc.Content[0], typeNames)
+		if !value.IsValid() {
+			return nil, fmt.Errorf("document %d: cannot restyle an empty document", i)
+		}
+
+		docOpts := []Option{WithStyle(style), withTypeNames(typeNames)}
+		if header := commentLines(doc.HeadComment, doc.Content[0].HeadComment); len(header) > 0 {
+			docOpts = append(docOpts, WithHeader(header...))
+		}
+		if footer := commentLines(doc.Content[0].FootComment, doc.FootComment); len(footer) > 0 {
+			docOpts = append(docOpts, WithFooter(footer...))
+		}
+		part, err := Gen(value.Interface(), append(docOpts, opts...)...)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+		parts = append(parts, part)
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("no documents to restyle")
+	}
+	return joinDocuments(parts), nil
+}
+
+// withTypeNames 为动态生成的类型指定在注释中显示的类型名
+func withTypeNames(names map[reflect.Type]string) Option {
+	return func(o *Options) {
+		o.typeNames = names
+	}
+}
+
+// fieldTypeString 字段类型在注释中显示的名称
+func fieldTypeString(field FieldInfo, options *Options) string {
+	if name, ok := options.typeNames[field.FieldType.Type]; ok {
+		return name
+	}
+	return field.FieldType.Type.String()
+}
+
+// restyleValue 将节点转换为可生成的值：映射转为按原顺序排列、注释写入标签的动态结构体，
+// 列表转为 []interface{}，标量按YAML规则解码。空值返回无效的 reflect.Value
+func restyleValue(node *yaml.Node, typeNames map[reflect.Type]string) reflect.Value {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		return restyleMapping(node, typeNames)
+	case yaml.SequenceNode:
+		items := make([]interface{}, len(node.Content))
+		for i, item := range node.Content {
+			if value := restyleValue(item, typeNames); value.IsValid() {
+				items[i] = value.Interface()
+			}
+		}
+		return reflect.ValueOf(items)
+	case yaml.ScalarNode:
+		if node.ShortTag() == "!!timestamp" {
+			// 时间按原始文本保留，避免 time.Time 被当作结构体展开
+			return reflect.ValueOf(node.Value)
+		}
+		var value interface{}
+		if err := node.Decode(&value); err != nil || value == nil {
+			return reflect.Value{}
+		}
+		return reflect.ValueOf(value)
+	}
+	return reflect.Value{}
+}
+
+// restyleMapping 将映射节点转为动态结构体；键无法作为yaml标签名时退回为 map[string]interface{}
+func restyleMapping(node *yaml.Node, typeNames map[reflect.Type]string) reflect.Value {
+	var fields []reflect.StructField
+	var values []reflect.Value
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, valueNode := node.Content[i], node.Content[i+1]
+		if key.Value == "" || key.Value == "-" || strings.ContainsAny(key.Value, ",\"") {
+			fields = nil
+			break
+		}
+
+		value := restyleValue(valueNode, typeNames)
+		fieldType := interfaceType
+		if value.IsValid() {
+			fieldType = value.Type()
+		}
+
+		lineComment := key.LineComment
+		if valueNode.Kind == yaml.ScalarNode || valueNode.Kind == yaml.AliasNode {
+			lineComment = joinCommentText(lineComment, valueNode.LineComment)
+		}
+		tag := "yaml:" + strconv.Quote(key.Value)
+		if comment := joinCommentText(key.HeadComment, lineComment); comment != "" {
+			tag += " comment:" + strconv.Quote(comment)
+		}
+		fields = append(fields, reflect.StructField{
+			Name: "F" + strconv.Itoa(len(fields)),
+			Type: fieldType,
+			Tag:  reflect.StructTag(tag),
+		})
+		values = append(values, value)
+	}
+
+	if len(fields) == 0 {
+		m := make(map[string]interface{}, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			var value interface{}
+			if v := restyleValue(node.Content[i+1], typeNames); v.IsValid() {
+				value = v.Interface()
+			}
+			m[node.Content[i].Value] = value
+		}
+		return reflect.ValueOf(m)
+	}
+
+	typ := reflect.StructOf(fields)
+	typeNames[typ] = "map"
+	result := reflect.New(typ).Elem()
+	for i, value := range values {
+		if value.IsValid() {
+			result.Field(i).Set(value)
+		}
+	}
+	return result
+}
+
+// commentLines 将文档级注释拆为去掉 "#" 前缀的行
+func commentLines(comments ...string) []string {
+	text := joinCommentText(comments...)
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
@@ -0,0 +1,93 @@
+package yamlc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const restyleInput = `# 文档说明
+
+name: demo # 名称
+# 端口
+port: 8080
+server: # 服务
+  host: localhost # 主机
+  tls: true
+tags: [a, b]
+servers:
+  - host: a
+    weight: 1
+empty: {}
+nothing: ~
+`
+
+func TestRestyle(t *testing.T) {
+	data, err := Restyle([]byte(restyleInput), StyleTop)
+	if err != nil {
+		t.Fatalf("Restyle failed: %v", err)
+	}
+	yamlStr := string(data)
+	for _, expected := range []string{
+		"# 文档说明\n\n# 名称\nname: demo\n# 端口\nport: 8080\n# 服务\nserver:\n  # 主机\n  host: localhost\n  tls: true\n",
+		"servers:\n  - host: a\n    weight: 1\n",
+		"empty: {}\n",
+		"nothing: null\n",
+	} {
+		if !strings.Contains(yamlStr, expected) {
+			t.Errorf("expected %q in:\n%s", expected, yamlStr)
+		}
+	}
+
+	data, err = Restyle([]byte(restyleInput), StyleInline, WithHeader("新的说明"))
+	if err != nil {
+		t.Fatalf("Restyle failed: %v", err)
+	}
+	yamlStr = string(data)
+	for _, expected := range []string{"# 文档说明\n# 新的说明\n\n", "name: demo         # 名称\n", "port: 8080         # 端口\n", "  host: localhost  # 主机\n"} {
+		if !strings.Contains(yamlStr, expected) {
+			t.Errorf("expected %q in:\n%s", expected, yamlStr)
+		}
+	}
+
+	// 详细风格中动态类型显示为 map
+	data, err = Restyle([]byte(restyleInput), StyleVerbose)
+	if err != nil {
+		t.Fatalf("Restyle failed: %v", err)
+	}
+	if !strings.Contains(string(data), "# 服务 (map)\nserver:") {
+		t.Errorf("expected map type name:\n%s", data)
+	}
+
+	// 内容与注释在风格之间往返不变
+	for _, style := range []CommentStyle{StyleTop, StyleInline, StyleCompact} {
+		restyled, err := Restyle([]byte(restyleInput), style)
+		if err != nil {
+			t.Fatalf("Restyle %s failed: %v", GetStyleString(int(style)), err)
+		}
+		before, _ := decodeAllDocuments([]byte(restyleInput))
+		after, err := decodeAllDocuments(restyled)
+		if err != nil || !reflect.DeepEqual(before, after) {
+			t.Errorf("%s: content changed:\n%s", GetStyleString(int(style)), restyled)
+		}
+		expected, _ := ExtractComments([]byte(restyleInput))
+		if comments, _ := ExtractComments(restyled); comments["server.host"] != expected["server.host"] || comments["port"] != expected["port"] {
+			t.Errorf("%s: comments changed: %v", GetStyleString(int(style)), comments)
+		}
+	}
+
+	data, err = Restyle([]byte("a: 1 # 甲\n---\nb: 2 # 乙\n"), StyleTop)
+	if err != nil {
+		t.Fatalf("Restyle failed: %v", err)
+	}
+	if expected := "# 甲\na: 1\n---\n# 乙\nb: 2\n"; string(data) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, data)
+	}
+
+	if _, err := Restyle([]byte("a: [1\n"), StyleTop); err == nil {
+		t.Error("expected error for invalid YAML")
+	}
+	if _, err := Restyle(nil, StyleTop); err == nil {
+		t.Error("expected error for empty input")
+	}
+}
@@ -0,0 +1,76 @@
+package yamlc
+
+import (
+	"reflect"
+	"strings"
+)
+
+// GenScaffold 生成配置模板：除 required 标签标记的必填字段外，所有字段整段注释输出，
+// 用户按需取消注释即可。包含必填子字段的结构体字段本身保留
+func GenScaffold(v interface{}, opts ...Option) ([]byte, error) {
+	opts = append(opts[:len(opts):len(opts)], func(o *Options) {
+		o.scaffold = true
+	})
+	return Gen(v, opts...)
+}
+
+// isCommentedField 判断字段是否整段注释输出：可选骨架字段、取默认值的字段，或脚手架中的非必填字段
+func isCommentedField(field FieldInfo, options *Options) bool {
+	if field.atDefault {
+		return true
+	}
+	if options.NilPointers == NilCommented && isSkeletonField(field.Field, options) {
+		return true
+	}
+	return options.scaffold && !isRequiredField(field.FieldType)
+}
+
+// isRequiredField 判断字段是否必填：设置了 required 标签，或是包含必填子字段的结构体
+func isRequiredField(field reflect.StructField) bool {
+	return hasTagFlag(field, "required") || hasRequiredField(field.Type, map[reflect.Type]bool{})
+}
+
+// hasRequiredField 判断结构体类型中是否有必填字段，visited 用于避免自引用类型无限递归
+func hasRequiredField(typ reflect.Type, visited map[reflect.Type]bool) bool {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || visited[typ] {
+		return false
+	}
+	visited[typ] = true
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() || getFieldName(field) == "-" {
+			continue
+		}
+		if hasTagFlag(field, "required") || hasRequiredField(field.Type, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// isStructType 判断类型（解引用指针后）是否为结构体
+func isStructType(typ reflect.Type) bool {
+	if typ == nil {
+		return false
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ.Kind() == reflect.Struct
+}
+
+// commentOutValue 注释掉整段注释字段的值：首行跟在已注释的键之后，只处理后续行
+func commentOutValue(value string, field FieldInfo, options *Options) string {
+	if !isCommentedField(field, options) {
+		return value
+	}
+	idx := strings.Index(value, "\n")
+	if idx < 0 {
+		return value
+	}
+	return value[:idx] + commentOutLines(value[idx:])
+}
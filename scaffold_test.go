@@ -0,0 +1,78 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+type scaffoldDatabase struct {
+	DSN      string `yaml:"dsn" yamlc:"required" comment:"连接串"`
+	MaxConns int    `yaml:"maxConns" comment:"最大连接数"`
+}
+
+type scaffoldConfig struct {
+	Name     string            `yaml:"name" yamlc:"required" comment:"服务名"`
+	Port     int               `yaml:"port" comment:"端口"`
+	Database scaffoldDatabase  `yaml:"database" comment:"数据库"`
+	Labels   map[string]string `yaml:"labels" comment:"标签"`
+	Hosts    []string          `yaml:"hosts" comment:"主机列表"`
+}
+
+func TestGenScaffold(t *testing.T) {
+	cfg := scaffoldConfig{
+		Name:     "api",
+		Port:     8080,
+		Database: scaffoldDatabase{DSN: "postgres://localhost/app", MaxConns: 10},
+		Labels:   map[string]string{"team": "core"},
+		Hosts:    []string{"a", "b"},
+	}
+
+	data, err := GenScaffold(cfg, WithStyle(StyleTop))
+	if err != nil {
+		t.Fatalf("GenScaffold failed: %v", err)
+	}
+	yamlStr := string(data)
+	for _, expected := range []string{
+		"name: api\n",
+		"# 端口\n# port: 8080\n",
+		"database:\n  # 连接串\n  dsn: postgres://localhost/app\n  # 最大连接数\n  # maxConns: 10\n",
+		"# labels:\n  # team: core\n",
+		"# hosts:\n  # - a\n  # - b\n",
+	} {
+		if !strings.Contains(yamlStr, expected) {
+			t.Errorf("expected %q in:\n%s", expected, yamlStr)
+		}
+	}
+
+	for _, style := range GetAllStyle() {
+		data, err := GenScaffold(cfg, WithStyle(style))
+		if err != nil {
+			t.Fatalf("style %s failed: %v", GetStyleString(int(style)), err)
+		}
+		var decoded map[string]interface{}
+		if err := yaml.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unmarshal failed: %v\n%s", err, data)
+		}
+		expected := map[string]interface{}{
+			"name":     "api",
+			"database": map[string]interface{}{"dsn": "postgres://localhost/app"},
+		}
+		if !yamlEqual(decoded, expected) {
+			t.Errorf("style %s: scaffold should only keep required fields, got %v:\n%s", GetStyleString(int(style)), decoded, data)
+		}
+	}
+}
+
+func yamlEqual(a, b interface{}) bool {
+	left, err := yaml.Marshal(a)
+	if err != nil {
+		return false
+	}
+	right, err := yaml.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(left) == string(right)
+}
@@ -0,0 +1,184 @@
+package yamlc
+
+import (
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultSecretPlaceholder 敏感字段默认的替换文本
+const DefaultSecretPlaceholder = "*****"
+
+// sensitiveNameParts 全局脱敏时按字段名识别敏感字段的关键字
+var sensitiveNameParts = []string{
+	"password", "passwd", "secret", "token", "apikey", "api_key",
+	"credential", "privatekey", "private_key",
+}
+
+// WithSecretPlaceholder 设置敏感字段的替换文本
+func WithSecretPlaceholder(placeholder string) Option {
+	return func(o *Options) {
+		o.SecretPlaceholder = placeholder
+	}
+}
+
+// GenRedacted 生成脱敏后的YAML内容
+// 除了带secret标签的字段外，名称看起来敏感的字段（password、token等）也会被屏蔽，适合用于日志输出
+func GenRedacted(v interface{}, opts ...Option) ([]byte, error) {
+	opts = append(opts, func(o *Options) {
+		o.redactAll = true
+	})
+	return Gen(v, opts...)
+}
+
+// isSecretField 判断字段是否需要屏蔽
+func isSecretField(field reflect.StructField, fieldName string, options *Options) bool {
+	if options.revealSecrets {
+		return false
+	}
+	if hasTagFlag(field, "secret") {
+		return true
+	}
+	return options.redactAll && isSensitiveName(fieldName)
+}
+
+// isSensitiveName 判断键名是否看起来是敏感信息
+func isSensitiveName(name string) bool {
+	lower := strings.ToLower(name)
+	for _, part := range sensitiveNameParts {
+		if strings.Contains(lower, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// secretPlaceholder 获取敏感字段的替换文本
+func secretPlaceholder(options *Options) string {
+	if options.SecretPlaceholder != "" {
+		return options.SecretPlaceholder
+	}
+	return DefaultSecretPlaceholder
+}
+
+// secretComment 在注释末尾追加敏感标注
+func secretComment(comment string) string {
+	if comment == "" {
+		return "(secret)"
+	}
+	return comment + " (secret)"
+}
+
+// redactNode 在yaml节点树上屏蔽敏感值，用于不经过字段渲染的最小风格
+func redactNode(node *yaml.Node, val reflect.Value, options *Options) {
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return
+		}
+		val = val.Elem()
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) > 0 {
+			redactNode(node.Content[0], val, options)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			switch val.Kind() {
+			case reflect.Struct:
+				fieldType, field, ok := findYAMLField(val, key)
+				if !ok {
+					// inline 映射中的键
+					if options.redactAll && isSensitiveName(key) {
+						node.Content[i+1] = secretNode(options)
+					}
+					continue
+				}
+				if isSecretField(fieldType, key, options) {
+					// 加密的字段由 encryptNode 处理
+					if !isEncryptedField(fieldType, options) {
+						node.Content[i+1] = secretNode(options)
+					}
+					continue
+				}
+				redactNode(node.Content[i+1], field, options)
+			case reflect.Map:
+				if options.redactAll && isSensitiveName(key) {
+					node.Content[i+1] = secretNode(options)
+					continue
+				}
+				if val.Type().Key().Kind() == reflect.String {
+					redactNode(node.Content[i+1], val.MapIndex(reflect.ValueOf(key).Convert(val.Type().Key())), options)
+				}
+			}
+		}
+	case yaml.SequenceNode:
+		if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+			return
+		}
+		for i, item := range node.Content {
+			if i < val.Len() {
+				redactNode(item, val.Index(i), options)
+			}
+		}
+	}
+}
+
+// findYAMLField 按yaml.v3的命名规则查找键对应的结构体字段，键属于 inline 嵌入的结构体时返回其中的字段
+func findYAMLField(val reflect.Value, key string) (reflect.StructField, reflect.Value, bool) {
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		fieldType := typ.Field(i)
+		name, flags := strings.ToLower(fieldType.Name), ""
+		if yamlTag := fieldType.Tag.Get("yaml"); yamlTag != "" {
+			tagName, tagFlags, _ := strings.Cut(yamlTag, ",")
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+			flags = tagFlags
+		}
+		if hasYAMLFlag(flags, "inline") {
+			if inline, ok := inlineStruct(val.Field(i)); ok {
+				if fieldType, field, ok := findYAMLField(inline, key); ok {
+					return fieldType, field, true
+				}
+			}
+			continue
+		}
+		if !fieldType.IsExported() {
+			continue
+		}
+		if name == key {
+			return fieldType, val.Field(i), true
+		}
+	}
+	return reflect.StructField{}, reflect.Value{}, false
+}
+
+// inlineStruct 返回 inline 字段展开的结构体，nil 指针按零值处理以便按类型查找
+func inlineStruct(field reflect.Value) (reflect.Value, bool) {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			field = reflect.New(field.Type().Elem()).Elem()
+		} else {
+			field = field.Elem()
+		}
+	}
+	return field, field.Kind() == reflect.Struct
+}
+
+// secretNode 生成替换敏感值的标量节点
+func secretNode(options *Options) *yaml.Node {
+	return &yaml.Node{
+		Kind:  yaml.ScalarNode,
+		Tag:   "!!str",
+		Value: secretPlaceholder(options),
+		Style: yaml.DoubleQuotedStyle,
+	}
+}
@@ -0,0 +1,106 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+)
+
+type secretConfig struct {
+	User     string            `yaml:"user"     yamlc:"comment=用户名"`
+	Password string            `yaml:"password" yamlc:"comment=密码,secret"`
+	APIToken string            `yaml:"apiToken" yamlc:"comment=访问令牌"`
+	Extra    map[string]string `yaml:"extra"    yamlc:"comment=扩展配置"`
+}
+
+func newSecretConfig() *secretConfig {
+	return &secretConfig{
+		User:     "admin",
+		Password: "p@ssw0rd",
+		APIToken: "tok-123",
+		Extra:    map[string]string{"db_password": "hunter2"},
+	}
+}
+
+func TestSecretTag(t *testing.T) {
+	for _, style := range GetAllStyle() {
+		t.Run(GetStyleString(int(style)), func(t *testing.T) {
+			data, err := Gen(newSecretConfig(), WithStyle(style))
+			if err != nil {
+				t.Fatalf("Gen failed: %v", err)
+			}
+			yamlStr := string(data)
+			if strings.Contains(yamlStr, "p@ssw0rd") {
+				t.Errorf("secret value leaked:\n%s", yamlStr)
+			}
+			if !strings.Contains(yamlStr, DefaultSecretPlaceholder) {
+				t.Errorf("placeholder missing:\n%s", yamlStr)
+			}
+			if style != StyleMinimal && !strings.Contains(yamlStr, "密码 (secret)") {
+				t.Errorf("secret note missing:\n%s", yamlStr)
+			}
+			// 未标记的字段在 Gen 中保持原值
+			if !strings.Contains(yamlStr, "tok-123") {
+				t.Errorf("untagged field should not be masked by Gen:\n%s", yamlStr)
+			}
+		})
+	}
+}
+
+func TestSecretPlaceholder(t *testing.T) {
+	data, err := Gen(newSecretConfig(), WithSecretPlaceholder("<hidden>"))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if !strings.Contains(string(data), "password: <hidden>\n") {
+		t.Errorf("custom placeholder not applied:\n%s", data)
+	}
+}
+
+func TestGenRedacted(t *testing.T) {
+	for _, style := range []CommentStyle{StyleTop, StyleMinimal} {
+		data, err := GenRedacted(newSecretConfig(), WithStyle(style))
+		if err != nil {
+			t.Fatalf("GenRedacted failed: %v", err)
+		}
+		yamlStr := string(data)
+		for _, leaked := range []string{"p@ssw0rd", "tok-123", "hunter2"} {
+			if strings.Contains(yamlStr, leaked) {
+				t.Errorf("style %s: %q leaked:\n%s", GetStyleString(int(style)), leaked, yamlStr)
+			}
+		}
+		if !strings.Contains(yamlStr, "admin") {
+			t.Errorf("non-sensitive field should be kept:\n%s", yamlStr)
+		}
+	}
+}
+
+func TestSecretInline(t *testing.T) {
+	type credentials struct {
+		User     string `yaml:"user"`
+		Password string `yaml:"password" yamlc:"secret"`
+	}
+	type Config struct {
+		credentials `yaml:",inline"`
+		Host        string            `yaml:"host"`
+		Extra       map[string]string `yaml:",inline"`
+	}
+	cfg := Config{credentials: credentials{User: "admin", Password: "p@ssw0rd"}, Host: "h", Extra: map[string]string{"api_token": "tok-123"}}
+
+	data, err := Gen(cfg, WithStyle(StyleMinimal))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if strings.Contains(string(data), "p@ssw0rd") || !strings.Contains(string(data), "user: admin") {
+		t.Errorf("inline secret leaked:\n%s", data)
+	}
+
+	data, err = GenRedacted(cfg, WithStyle(StyleMinimal))
+	if err != nil {
+		t.Fatalf("GenRedacted failed: %v", err)
+	}
+	for _, leaked := range []string{"p@ssw0rd", "tok-123"} {
+		if strings.Contains(string(data), leaked) {
+			t.Errorf("%q leaked:\n%s", leaked, data)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package yamlc
+
+// SliceCommentMode 结构体列表中各元素字段注释的输出方式
+type SliceCommentMode int
+
+const (
+	// SliceCommentsFirstOnly 只在首个元素上输出独占一行的字段注释，后续元素只保留行尾注释和按下标指定的注释（默认）
+	SliceCommentsFirstOnly SliceCommentMode = iota
+	// SliceCommentsEvery 每个元素都重复输出字段注释
+	SliceCommentsEvery
+	// SliceCommentsNone 所有元素都不输出通用的字段注释，只保留按下标指定的注释（如 "servers.1.host"）
+	SliceCommentsNone
+)
+
+// WithSliceCommentMode 设置结构体列表中各元素字段注释的输出方式
+func WithSliceCommentMode(mode SliceCommentMode) Option {
+	return func(o *Options) {
+		o.SliceComments = mode
+	}
+}
+
+// sliceItemOptions 返回首个列表元素和后续元素使用的选项
+func sliceItemOptions(options *Options) (head, tail *Options) {
+	switch options.SliceComments {
+	case SliceCommentsEvery:
+		return options, options
+	case SliceCommentsNone:
+		indexed := *options
+		indexed.indexedOnly = true
+		return &indexed, &indexed
+	}
+	// 非首个元素不重复输出独占一行的通用注释
+	indexed := *options
+	indexed.indexedOnly = true
+	return options, &indexed
+}
@@ -0,0 +1,54 @@
+package yamlc
+
+import (
+	"testing"
+)
+
+func TestWithSliceCommentMode(t *testing.T) {
+	type Server struct {
+		Host string `yaml:"host" comment:"主机"`
+		Port int    `yaml:"port" comment:"端口"`
+	}
+	type Config struct {
+		Servers []Server `yaml:"servers"`
+	}
+	cfg := Config{Servers: []Server{{"a", 1}, {"b", 2}}}
+	comments := map[string]string{"servers.1.port": "第二个端口"}
+
+	testCases := []struct {
+		mode     SliceCommentMode
+		style    CommentStyle
+		expected string
+	}{
+		{SliceCommentsFirstOnly, StyleTop, "servers:\n    # 主机\n    # 端口\n  - host: a\n    port: 1\n    # 第二个端口\n  - host: b\n    port: 2\n"},
+		{SliceCommentsEvery, StyleTop, "servers:\n    # 主机\n    # 端口\n  - host: a\n    port: 1\n    # 主机\n    # 第二个端口\n  - host: b\n    port: 2\n"},
+		{SliceCommentsNone, StyleTop, "servers:\n  - host: a\n    port: 1\n    # 第二个端口\n  - host: b\n    port: 2\n"},
+		{SliceCommentsFirstOnly, StyleInline, "servers: \n  - host: a  # 主机\n    port: 1  # 端口\n  - host: b  # 主机\n    port: 2  # 第二个端口\n"},
+		{SliceCommentsNone, StyleInline, "servers: \n  - host: a\n    port: 1\n  - host: b\n    port: 2  # 第二个端口\n"},
+	}
+	for _, tc := range testCases {
+		data, err := Gen(cfg, WithStyle(tc.style), WithSliceCommentMode(tc.mode), WithComment(comments), WithBlankLines(BlankLinesNone))
+		if err != nil {
+			t.Fatalf("Gen failed: %v", err)
+		}
+		if string(data) != tc.expected {
+			t.Errorf("mode %d style %s: unexpected output:\n%s\nexpected:\n%s", tc.mode, GetStyleString(int(tc.style)), data, tc.expected)
+		}
+	}
+}
+
+func TestWithSliceCommentModeEncoder(t *testing.T) {
+	type Server struct {
+		Host string `yaml:"host" comment:"主机"`
+	}
+	servers := []Server{{"a"}, {"b"}}
+
+	data, err := Gen(servers, WithStyle(StyleTop), WithSliceCommentMode(SliceCommentsEvery))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	expected := "  # 主机\n- host: a\n  # 主机\n- host: b\n"
+	if string(data) != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", data, expected)
+	}
+}
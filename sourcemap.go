@@ -0,0 +1,122 @@
+package yamlc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LineRange 字段在生成的文档中所占的行，从1开始，Start 为键所在的行，End 为值的最后一行（均包含）
+// 字段上方的注释不计入范围
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// String 返回 "起始行-结束行" 形式的文本，只占一行时只返回行号
+func (r LineRange) String() string {
+	if r.Start == r.End {
+		return strconv.Itoa(r.Start)
+	}
+	return fmt.Sprintf("%d-%d", r.Start, r.End)
+}
+
+// WithSourceMap 生成时将每个字段路径（如 "servers.0.port"）在输出中的行范围写入 sourceMap，
+// 可用于把校验错误定位到行、实现行内编辑器或精确的diff；sourceMap 需非nil，多文档输出时行号相对于各自的文档
+// 并发的生成需各自使用单独的映射；Generator 编译时设置的映射由生成器在每次调用结束后加锁合并
+func WithSourceMap(sourceMap map[string]LineRange) Option {
+	return func(o *Options) {
+		o.SourceMap = sourceMap
+	}
+}
+
+// GenSourceMap 生成YAML内容，并返回字段路径到行范围的映射
+func GenSourceMap(v interface{}, opts ...Option) ([]byte, map[string]LineRange, error) {
+	sourceMap := make(map[string]LineRange)
+	data, err := Gen(v, append(opts, WithSourceMap(sourceMap))...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, sourceMap, nil
+}
+
+// recordSourceMap 解析生成的内容并记录各字段的行范围，offset 为 markDocument 在内容之前添加的行数
+func recordSourceMap(content []byte, offset int, options *Options) error {
+	if options.SourceMap == nil {
+		return nil
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return fmt.Errorf("failed to build source map: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+
+	m := &sourceMapper{lines: strings.Split(string(content), "\n"), ranges: make(map[string]LineRange)}
+	m.walk(doc.Content[0], "", len(m.lines)+1)
+
+	for path, r := range m.ranges {
+		options.SourceMap[path] = LineRange{Start: r.Start + offset, End: r.End + offset}
+	}
+	return nil
+}
+
+// sourceMapper 计算行范围时的状态
+type sourceMapper struct {
+	lines  []string
+	ranges map[string]LineRange
+}
+
+// walk 记录节点下各项的行范围，limit 为该节点之后下一项开始的行
+func (m *sourceMapper) walk(node *yaml.Node, path string, limit int) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			next := limit
+			if i+2 < len(node.Content) {
+				next = node.Content[i+2].Line
+			}
+			m.record(buildFieldPath(path, key.Value), key, value, next)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			next := limit
+			if i+1 < len(node.Content) {
+				next = node.Content[i+1].Line
+			}
+			m.record(buildFieldPath(path, strconv.Itoa(i)), item, item, next)
+		}
+	}
+}
+
+// record 记录一项的行范围：从 start 所在行到下一项之前最后一个属于该项的行
+func (m *sourceMapper) record(path string, start, value *yaml.Node, next int) {
+	end := next - 1
+	// 去掉末尾的空行和属于下一项的注释行（缩进不超过该项的注释）
+	for end > start.Line {
+		line := m.lines[end-1]
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed != "" && !(strings.HasPrefix(trimmed, "#") && len(line)-len(trimmed) < start.Column) {
+			break
+		}
+		end--
+	}
+	m.ranges[path] = LineRange{Start: start.Line, End: end}
+	m.walk(value, path, next)
+}
+
+// documentPrefixLines markDocument 在内容之前添加的行数
+func documentPrefixLines(options *Options) int {
+	lines := 0
+	if options.YAMLVersion != "" {
+		lines++
+	}
+	if options.YAMLVersion != "" || options.DocumentStart {
+		lines++
+	}
+	return lines
+}
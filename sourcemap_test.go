@@ -0,0 +1,80 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+)
+
+type sourceMapServer struct {
+	Host string `yaml:"host" yamlc:"comment=主机"`
+	Port int    `yaml:"port" yamlc:"comment=端口"`
+}
+
+type sourceMapConfig struct {
+	Name    string            `yaml:"name"    yamlc:"comment=服务名称"`
+	Motd    string            `yaml:"motd"    yamlc:"comment=欢迎语"`
+	Servers []sourceMapServer `yaml:"servers" yamlc:"comment=服务器列表"`
+	Tags    []string          `yaml:"tags"    yamlc:"comment=标签"`
+	Debug   bool              `yaml:"debug"   yamlc:"comment=调试模式"`
+}
+
+func TestGenSourceMap(t *testing.T) {
+	cfg := &sourceMapConfig{
+		Name:    "demo",
+		Motd:    "line1\nline2\nline3",
+		Servers: []sourceMapServer{{Host: "a", Port: 1}, {Host: "b", Port: 2}},
+		Tags:    []string{"x", "z"},
+		Debug:   true,
+	}
+
+	for _, style := range []CommentStyle{StyleTop, StyleInline, StyleMinimal, StyleSpaced} {
+		t.Run(GetStyleString(int(style)), func(t *testing.T) {
+			data, sourceMap, err := GenSourceMap(cfg, WithStyle(style), WithDocumentStart())
+			if err != nil {
+				t.Fatalf("GenSourceMap failed: %v", err)
+			}
+			lines := strings.Split(string(data), "\n")
+			line := func(n int) string { return strings.TrimSpace(lines[n-1]) }
+
+			for path, key := range map[string]string{
+				"name":           "name: demo",
+				"motd":           "motd: |",
+				"servers":        "servers:",
+				"servers.1.port": "port: 2",
+				"tags.1":         "- z",
+				"debug":          "debug: true",
+			} {
+				r, ok := sourceMap[path]
+				if !ok {
+					t.Errorf("path %q missing from source map", path)
+					continue
+				}
+				if start := line(r.Start); !strings.HasPrefix(start, key) && !strings.HasPrefix(strings.TrimPrefix(start, "- "), key) {
+					t.Errorf("%s starts at line %d %q, want %q\n%s", path, r.Start, line(r.Start), key, data)
+				}
+			}
+
+			if r := sourceMap["motd"]; !strings.HasPrefix(line(r.End), "line3") {
+				t.Errorf("motd ends at line %d %q\n%s", r.End, line(r.End), data)
+			}
+			if r := sourceMap["servers"]; !strings.HasPrefix(line(r.End), "port: 2") {
+				t.Errorf("servers ends at line %d %q\n%s", r.End, line(r.End), data)
+			}
+			if r := sourceMap["servers.0"]; !strings.Contains(line(r.Start), "host: a") || !strings.HasPrefix(line(r.End), "port: 1") {
+				t.Errorf("servers.0 has range %s\n%s", r, data)
+			}
+			if r := sourceMap["name"]; r.Start != r.End {
+				t.Errorf("name spans %s", r)
+			}
+		})
+	}
+}
+
+func TestLineRangeString(t *testing.T) {
+	if got := (LineRange{Start: 3, End: 3}).String(); got != "3" {
+		t.Errorf("got %q", got)
+	}
+	if got := (LineRange{Start: 3, End: 5}).String(); got != "3-5" {
+		t.Errorf("got %q", got)
+	}
+}
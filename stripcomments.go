@@ -0,0 +1,153 @@
+package yamlc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// StripComments 删除YAML中的所有注释，其余内容（缩进、引号、顺序、空行）保持原样，用于生成只供机器读取的副本
+// 只含注释的行整行删除，行尾注释连同前面的空白一起删除，文档开头注释块之后的空行也一并删除；块标量和引号字符串中的 "#" 不受影响
+func StripComments(data []byte) ([]byte, error) {
+	before, err := decodeAllDocuments(data)
+	if err != nil {
+		return nil, err
+	}
+
+	stripped := stripCommentText(string(data))
+
+	// 删除注释不应改变文档内容，否则说明扫描有误，宁可报错也不输出错误的结果
+	after, err := decodeAllDocuments([]byte(stripped))
+	if err != nil || !reflect.DeepEqual(before, after) {
+		return nil, fmt.Errorf("failed to strip comments without changing document content")
+	}
+	return []byte(stripped), nil
+}
+
+// decodeAllDocuments 解码所有文档，用于比较内容
+func decodeAllDocuments(data []byte) ([]interface{}, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	var docs []interface{}
+	for {
+		var doc interface{}
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				return docs, nil
+			}
+			return nil, fmt.Errorf("YAML parsing error: %w", err)
+		}
+		docs = append(docs, doc)
+	}
+}
+
+// commentScanner 逐行扫描时跨行保留的状态
+type commentScanner struct {
+	quote       byte // 未闭合的引号字符串（多行引号字符串）
+	blockIndent int  // 块标量所属行的缩进，-1 表示不在块标量中
+}
+
+// stripCommentText 逐行删除注释
+func stripCommentText(text string) string {
+	scanner := &commentScanner{blockIndent: -1}
+	lines := strings.SplitAfter(text, "\n")
+	var b strings.Builder
+	b.Grow(len(text))
+	// top 表示还在文档开头（没有内容），removedHeader 表示已删除了文档开头的注释块，其后的空行一并删除
+	top, removedHeader := true, false
+	for _, line := range lines {
+		content := strings.TrimRight(line, "\r\n")
+		newline := line[len(content):]
+
+		trimmed := strings.TrimLeft(content, " \t")
+		indent := len(content) - len(trimmed)
+		if scanner.blockIndent >= 0 {
+			if trimmed == "" || indent > scanner.blockIndent {
+				b.WriteString(line)
+				continue
+			}
+			scanner.blockIndent = -1
+		}
+
+		if scanner.quote == 0 && strings.HasPrefix(trimmed, "#") {
+			// 整行注释
+			removedHeader = removedHeader || top
+			continue
+		}
+		if top && trimmed == "" {
+			if !removedHeader {
+				b.WriteString(line)
+			}
+			continue
+		}
+		if scanner.quote == 0 {
+			top, removedHeader = indent == 0 && strings.HasPrefix(trimmed, "---"), false
+		}
+
+		kept := scanner.stripLine(content)
+		if kept != content {
+			kept = strings.TrimRight(kept, " \t")
+		}
+		if scanner.quote == 0 && isBlockScalarHeader(kept) {
+			scanner.blockIndent = indent
+		}
+		b.WriteString(kept)
+		b.WriteString(newline)
+	}
+	return b.String()
+}
+
+// stripLine 删除一行中引号以外的 " #" 注释，并更新跨行的引号状态
+func (s *commentScanner) stripLine(line string) string {
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch s.quote {
+		case '"':
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				s.quote = 0
+			}
+			continue
+		case '\'':
+			if c == '\'' {
+				if i+1 < len(line) && line[i+1] == '\'' {
+					// '' 是单引号字符串中的转义
+					i++
+				} else {
+					s.quote = 0
+				}
+			}
+			continue
+		}
+
+		switch c {
+		case '#':
+			if i == 0 || line[i-1] == ' ' || line[i-1] == '\t' {
+				return line[:i]
+			}
+		case '"', '\'':
+			if startsQuotedScalar(line[:i]) {
+				s.quote = c
+			}
+		}
+	}
+	return line
+}
+
+// startsQuotedScalar 判断引号前的内容是否意味着引号处开始一个引号字符串（而不是普通字符串中的引号）
+func startsQuotedScalar(prefix string) bool {
+	prefix = strings.TrimRight(prefix, " \t")
+	if prefix == "" {
+		return true
+	}
+	switch prefix[len(prefix)-1] {
+	case ':', '-', '[', '{', ',', '?':
+		return true
+	}
+	return false
+}
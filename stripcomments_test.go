@@ -0,0 +1,79 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStripComments(t *testing.T) {
+	input := `# 文档注释
+name: "demo # not a comment" # 名称
+url: http://example.com/#anchor
+tags: [a, b]   # 标签
+
+# 服务配置
+server:
+    host: 'it''s # here'
+    # 端口
+    port: 8080
+script: |
+  echo hi # kept
+  # kept too
+
+plain: it's a value # trailing
+multi: "first line
+  # still quoted"
+---
+# 第二个文档
+
+- a # 元素
+`
+	expected := `name: "demo # not a comment"
+url: http://example.com/#anchor
+tags: [a, b]
+
+server:
+    host: 'it''s # here'
+    port: 8080
+script: |
+  echo hi # kept
+  # kept too
+
+plain: it's a value
+multi: "first line
+  # still quoted"
+---
+- a
+`
+	data, err := StripComments([]byte(input))
+	if err != nil {
+		t.Fatalf("StripComments failed: %v", err)
+	}
+	if string(data) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, data)
+	}
+
+	// 生成的带注释YAML去掉注释后内容不变
+	generated, err := Gen(configServer{Host: "localhost", Port: 8080}, WithStyle(StyleInline))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if data, err := StripComments(generated); err != nil || !strings.HasPrefix(string(data), "host: localhost\nport: 8080\n") {
+		t.Errorf("unexpected result %q: %v", data, err)
+	}
+
+	// 文档开头的注释块连同其后的空行一起删除，没有注释时空行保持原样
+	for input, expected := range map[string]string{
+		"# header\n# more\n\n\nname: demo\n":  "name: demo\n",
+		"\nname: demo\n":                      "\nname: demo\n",
+		"name: demo\n# trailing\n\nport: 1\n": "name: demo\n\nport: 1\n",
+	} {
+		if data, err := StripComments([]byte(input)); err != nil || string(data) != expected {
+			t.Errorf("StripComments(%q) = %q, %v; expected %q", input, data, err, expected)
+		}
+	}
+
+	if _, err := StripComments([]byte("a: [1\n")); err == nil {
+		t.Error("expected error for invalid YAML")
+	}
+}
@@ -0,0 +1,143 @@
+package yamlc
+
+import (
+	"strings"
+	"sync"
+)
+
+// Style 注释风格插件，负责将同一层级的字段渲染为YAML
+// 内置风格同样以 Style 的形式注册，可通过 RegisterStyle 以相同名称替换
+type Style interface {
+	RenderStruct(fields []FieldInfo, ctx RenderContext) (string, error)
+}
+
+// StyleFunc 将普通函数适配为 Style
+type StyleFunc func(fields []FieldInfo, ctx RenderContext) (string, error)
+
+// RenderStruct 调用函数本身
+func (f StyleFunc) RenderStruct(fields []FieldInfo, ctx RenderContext) (string, error) {
+	return f(fields, ctx)
+}
+
+// RenderContext 渲染一个层级所需的上下文
+type RenderContext struct {
+	// Indent 当前缩进层级
+	Indent int
+	// Options 生成选项
+	Options *Options
+}
+
+// IndentString 当前层级的缩进字符串
+func (ctx RenderContext) IndentString() string {
+	return getIndentStr(ctx.Indent, ctx.Options)
+}
+
+// Key 字段的键（含缩进和冒号），整段注释的字段带 "# " 前缀
+func (ctx RenderContext) Key(field FieldInfo) string {
+	return fieldKey(ctx.IndentString(), field, ctx.Options)
+}
+
+// Value 按字段选项渲染紧跟在键之后的值：单行值以空格开头，子节点和列表从下一行开始并按下一层级缩进，均以单个换行结尾
+func (ctx RenderContext) Value(field FieldInfo) (string, error) {
+	value, err := generateValue(field.Field, field.FieldPath, ctx.Indent+1, optionsForField(field, ctx.Options))
+	if err != nil {
+		return "", err
+	}
+	value = strings.TrimRight(commentOutValue(value, field, ctx.Options), "\n")
+	switch {
+	case field.HasChildren && !strings.HasPrefix(value, "\n"):
+		value = "\n" + value
+	case !strings.HasPrefix(value, "\n"):
+		value = " " + strings.TrimLeft(value, " ")
+	}
+	return value + "\n", nil
+}
+
+// customStyleBase 自定义风格的起始取值，避免与后续新增的内置风格冲突
+const customStyleBase CommentStyle = 100
+
+type registeredStyle struct {
+	name  string
+	style Style
+}
+
+var (
+	styleMu         sync.RWMutex
+	styleRegistry   = make(map[CommentStyle]registeredStyle)
+	styleNames      = make(map[string]CommentStyle)
+	nextCustomStyle = customStyleBase
+)
+
+// writerStyle 内置风格的实现，直接写入上一层级的缓冲区，不经过中间字符串
+type writerStyle func(result *strings.Builder, fields []FieldInfo, indent int, options *Options) error
+
+// RenderStruct 将字段写入新的缓冲区并返回其内容
+func (f writerStyle) RenderStruct(fields []FieldInfo, ctx RenderContext) (string, error) {
+	var result strings.Builder
+	if err := f(&result, fields, ctx.Indent, ctx.Options); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+func init() {
+	renderers := map[CommentStyle]writerStyle{
+		StyleDoc:       generateStructDoc,
+		StyleSeparate:  generateStructSeparate,
+		StyleSectioned: generateStructSectioned,
+	}
+	for _, style := range GetAllStyle() {
+		renderer, ok := renderers[style]
+		if !ok {
+			renderer = generateStructDefault
+		}
+		registerStyle(style, GetStyleString(int(style)), renderer)
+	}
+}
+
+// RegisterStyle 注册自定义风格并返回其取值，可用于 WithStyle、style= 标签和 GetStyleFromString
+// 名称不区分大小写；与已注册的名称相同时替换其实现并沿用原取值
+func RegisterStyle(name string, s Style) CommentStyle {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		panic("yamlc: RegisterStyle name is empty")
+	}
+	if s == nil {
+		panic("yamlc: RegisterStyle style is nil")
+	}
+
+	styleMu.Lock()
+	defer styleMu.Unlock()
+	style, ok := styleNames[name]
+	if !ok {
+		style = nextCustomStyle
+		nextCustomStyle++
+	}
+	styleRegistry[style] = registeredStyle{name: name, style: s}
+	styleNames[name] = style
+	return style
+}
+
+// registerStyle 以指定取值注册风格，用于内置风格
+func registerStyle(style CommentStyle, name string, s Style) {
+	styleMu.Lock()
+	defer styleMu.Unlock()
+	styleRegistry[style] = registeredStyle{name: name, style: s}
+	styleNames[name] = style
+}
+
+// lookupStyle 获取风格的实现
+func lookupStyle(style CommentStyle) (registeredStyle, bool) {
+	styleMu.RLock()
+	defer styleMu.RUnlock()
+	registered, ok := styleRegistry[style]
+	return registered, ok
+}
+
+// lookupStyleName 按名称查找已注册的风格
+func lookupStyleName(name string) (CommentStyle, bool) {
+	styleMu.RLock()
+	defer styleMu.RUnlock()
+	style, ok := styleNames[strings.ToLower(strings.TrimSpace(name))]
+	return style, ok
+}
@@ -0,0 +1,74 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// houseStyle 测试用的自定义风格：注释以 "##" 写在字段上方
+func houseStyle(fields []FieldInfo, ctx RenderContext) (string, error) {
+	var result strings.Builder
+	for _, field := range fields {
+		if field.Comment != "" {
+			result.WriteString(ctx.IndentString() + "## " + field.Comment + "\n")
+		}
+		value, err := ctx.Value(field)
+		if err != nil {
+			return "", err
+		}
+		result.WriteString(ctx.Key(field) + value)
+	}
+	return result.String(), nil
+}
+
+func TestRegisterStyle(t *testing.T) {
+	type Database struct {
+		Host string `yaml:"host" comment:"主机"`
+	}
+	type Config struct {
+		Name     string   `yaml:"name" comment:"名称"`
+		Database Database `yaml:"database" comment:"数据库"`
+	}
+	cfg := Config{Name: "demo", Database: Database{Host: "localhost"}}
+
+	style := RegisterStyle("House", StyleFunc(houseStyle))
+	if style < customStyleBase {
+		t.Fatalf("custom style should not collide with built-in values: %d", style)
+	}
+	if again := RegisterStyle("house", StyleFunc(houseStyle)); again != style {
+		t.Errorf("re-registering should keep the value: %d != %d", again, style)
+	}
+	if GetStyleFromString("house") != style || GetStyleString(int(style)) != "house" {
+		t.Errorf("style name lookup failed")
+	}
+	if err := ValidateOptions(&Options{Style: style}); err != nil {
+		t.Errorf("registered style should be valid: %v", err)
+	}
+	if err := ValidateOptions(&Options{Style: CommentStyle(99)}); err == nil {
+		t.Error("unregistered style should be invalid")
+	}
+
+	data, err := Gen(cfg, WithStyle(style))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	expected := "## 名称\nname: demo\n## 数据库\ndatabase:\n  ## 主机\n  host: localhost\n"
+	if strings.TrimRight(string(data), "\n")+"\n" != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", data, expected)
+	}
+	var decoded Config
+	if err := yaml.Unmarshal(data, &decoded); err != nil || decoded != cfg {
+		t.Errorf("custom style did not round-trip: %v", err)
+	}
+
+	// 字段覆盖同样可以使用自定义风格
+	data, err = Gen(cfg, WithStyle(StyleTop), WithFieldStyleOverride("database", style))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if !strings.Contains(string(data), "  ## 主机\n  host: localhost") {
+		t.Errorf("override should render the subtree with the custom style:\n%s", data)
+	}
+}
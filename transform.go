@@ -0,0 +1,144 @@
+package yamlc
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValueTransformer 在输出标量值之前对其进行转换，返回值替代原值输出
+// 可用于掩码、单位换算、规范化或按环境替换，而无需修改源结构体
+type ValueTransformer func(field FieldInfo, v interface{}) (interface{}, error)
+
+// WithValueTransformer 设置标量值转换函数，作用于结构体字段和映射项的标量值（含字节切片）
+// 敏感字段已被替换为占位符，不会调用转换函数
+func WithValueTransformer(transformer ValueTransformer) Option {
+	return func(o *Options) {
+		o.ValueTransformer = transformer
+	}
+}
+
+// isScalarValue 判断值是否按标量输出
+func isScalarValue(val reflect.Value) bool {
+	return !isComplexType(val) || isByteSlice(val)
+}
+
+// transformFields 对收集到的字段中的标量值调用转换函数，转换结果为复杂类型时按子节点输出
+func transformFields(fields []FieldInfo, options *Options) error {
+	if options.ValueTransformer == nil {
+		return nil
+	}
+	for i := range fields {
+		field := &fields[i]
+		if field.secret || field.substituted || isSkeletonField(field.Field, options) || !isScalarValue(field.Field) {
+			continue
+		}
+		value, err := transformValue(*field, field.Field, options)
+		if err != nil {
+			return err
+		}
+		field.Field = value
+		field.HasChildren = hasChildren(value) && !exceedsDepth(value, options)
+	}
+	return nil
+}
+
+// transformValue 调用转换函数，nil结果按空值输出
+func transformValue(field FieldInfo, val reflect.Value, options *Options) (reflect.Value, error) {
+	var v interface{}
+	if val.IsValid() && val.CanInterface() {
+		v = val.Interface()
+	}
+	result, err := options.ValueTransformer(field, v)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("transform value at %s: %w", field.FieldPath, err)
+	}
+	return reflect.ValueOf(result), nil
+}
+
+// transformNode 在yaml节点树上转换标量值，用于不经过字段渲染的最小风格，level 为当前结构体或映射的嵌套层级
+func transformNode(node *yaml.Node, val reflect.Value, fieldPath string, level int, options *Options) error {
+	if options.ValueTransformer == nil {
+		return nil
+	}
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) > 0 {
+			return transformNode(node.Content[0], val, fieldPath, level, options)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			field := FieldInfo{Name: key, FieldPath: buildFieldPath(fieldPath, key), Depth: level, Parent: fieldPath}
+			switch val.Kind() {
+			case reflect.Struct:
+				fieldType, child, ok := findYAMLField(val, key)
+				if !ok || isSecretField(fieldType, key, options) {
+					continue
+				}
+				field.FieldType, field.Field = fieldType, child
+				setFieldTags(&field)
+			case reflect.Map:
+				if val.Type().Key().Kind() != reflect.String || (options.redactAll && isSensitiveName(key)) {
+					continue
+				}
+				child := val.MapIndex(reflect.ValueOf(key).Convert(val.Type().Key()))
+				if child.Kind() == reflect.Interface && !child.IsNil() {
+					child = child.Elem()
+				}
+				field.FieldType, field.Field = reflect.StructField{Name: key, Type: child.Type()}, child
+			default:
+				continue
+			}
+
+			if !isScalarValue(field.Field) {
+				if err := transformNode(node.Content[i+1], field.Field, field.FieldPath, level+1, options); err != nil {
+					return err
+				}
+				continue
+			}
+			value, err := transformValue(field, field.Field, options)
+			if err != nil {
+				return err
+			}
+			var replaced yaml.Node
+			if err := replaced.Encode(valueInterface(value)); err != nil {
+				return err
+			}
+			if err := formatNode(&replaced, value, getValueFormat(field.FieldType), options); err != nil {
+				return err
+			}
+			node.Content[i+1] = &replaced
+		}
+	case yaml.SequenceNode:
+		if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+			return nil
+		}
+		for i, item := range node.Content {
+			if i >= val.Len() {
+				break
+			}
+			if err := transformNode(item, val.Index(i), buildFieldPath(fieldPath, strconv.Itoa(i)), level, options); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// valueInterface 获取值的接口表示，无效值返回nil
+func valueInterface(val reflect.Value) interface{} {
+	if !val.IsValid() {
+		return nil
+	}
+	return val.Interface()
+}
@@ -0,0 +1,79 @@
+package yamlc
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type transformConfig struct {
+	Host     string            `yaml:"host" comment:"主机"`
+	Timeout  time.Duration     `yaml:"timeout" comment:"超时"`
+	Password string            `yaml:"password" yamlc:"secret" comment:"密码"`
+	Labels   map[string]string `yaml:"labels" comment:"标签"`
+	Ports    []int             `yaml:"ports" comment:"端口"`
+}
+
+func TestWithValueTransformer(t *testing.T) {
+	cfg := transformConfig{
+		Host:     "db.internal",
+		Timeout:  30 * time.Second,
+		Password: "hunter2",
+		Labels:   map[string]string{"env": "prod"},
+		Ports:    []int{5432},
+	}
+
+	var seen []string
+	transformer := func(field FieldInfo, v interface{}) (interface{}, error) {
+		seen = append(seen, field.FieldPath)
+		switch val := v.(type) {
+		case time.Duration:
+			return val.String(), nil
+		case string:
+			if field.FieldPath == "host" {
+				return "${DB_HOST}", nil
+			}
+			return strings.ToUpper(val), nil
+		}
+		return v, nil
+	}
+
+	for _, style := range GetAllStyle() {
+		seen = nil
+		data, err := Gen(cfg, WithStyle(style), WithValueTransformer(transformer))
+		if err != nil {
+			t.Fatalf("style %s failed: %v", GetStyleString(int(style)), err)
+		}
+		var decoded map[string]interface{}
+		if err := yaml.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("style %s produced invalid yaml: %v\n%s", GetStyleString(int(style)), err, data)
+		}
+		if decoded["host"] != "${DB_HOST}" || decoded["timeout"] != "30s" {
+			t.Errorf("style %s: scalars should be transformed:\n%s", GetStyleString(int(style)), data)
+		}
+		if decoded["password"] != DefaultSecretPlaceholder {
+			t.Errorf("style %s: secret should stay masked:\n%s", GetStyleString(int(style)), data)
+		}
+		if labels, _ := decoded["labels"].(map[string]interface{}); labels["env"] != "PROD" {
+			t.Errorf("style %s: map entries should be transformed:\n%s", GetStyleString(int(style)), data)
+		}
+		for _, path := range seen {
+			if path == "password" || path == "labels" || path == "ports" {
+				t.Errorf("style %s: transformer should not see %s", GetStyleString(int(style)), path)
+			}
+		}
+	}
+
+	failing := func(field FieldInfo, v interface{}) (interface{}, error) {
+		return nil, errors.New("boom")
+	}
+	if _, err := Gen(cfg, WithValueTransformer(failing)); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("expected transformer error, got %v", err)
+	}
+	if _, err := Gen(cfg, WithStyle(StyleMinimal), WithValueTransformer(failing)); err == nil {
+		t.Error("expected transformer error in minimal style")
+	}
+}
@@ -0,0 +1,59 @@
+package yamlc
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// UnsupportedPolicy 无法表示为YAML的类型（通道、函数、unsafe.Pointer、复数）的处理方式
+type UnsupportedPolicy int
+
+const (
+	// UnsupportedError 返回错误（默认）
+	UnsupportedError UnsupportedPolicy = iota
+	// UnsupportedSkip 跳过该字段、映射项或列表元素
+	UnsupportedSkip
+	// UnsupportedNull 输出为空值
+	UnsupportedNull
+)
+
+// WithUnsupportedKinds 设置无法表示为YAML的类型的处理方式
+// 最小风格由yaml.v3直接编码，遇到这些类型时总是返回错误
+func WithUnsupportedKinds(policy UnsupportedPolicy) Option {
+	return func(o *Options) {
+		o.UnsupportedKinds = policy
+	}
+}
+
+// isUnsupportedKind 判断值（解引用后）是否为无法表示为YAML的类型
+func isUnsupportedKind(val reflect.Value) bool {
+	for (val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface) && !val.IsNil() {
+		val = val.Elem()
+	}
+	switch val.Kind() {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Complex64, reflect.Complex128:
+		return true
+	}
+	return false
+}
+
+// skipUnsupported 判断字段、映射项或列表元素是否因类型不受支持而跳过
+func skipUnsupported(val reflect.Value, fieldPath string, options *Options) bool {
+	if options.UnsupportedKinds != UnsupportedSkip || !isUnsupportedKind(val) {
+		return false
+	}
+	warn(options, WarningUnsupportedSkipped, fieldPath, "skipped unsupported kind %s", val.Kind())
+	return true
+}
+
+// generateUnsupported 按策略生成不受支持类型的值；跳过策略下未能在上层跳过的值输出为空值
+func generateUnsupported(val reflect.Value, fieldPath string, options *Options) (string, error) {
+	if options.UnsupportedKinds == UnsupportedError {
+		if fieldPath == "" {
+			return "", fmt.Errorf("unsupported kind %s", val.Kind())
+		}
+		return "", fmt.Errorf("unsupported kind %s at %s", val.Kind(), fieldPath)
+	}
+	warn(options, WarningUnsupportedSkipped, fieldPath, "unsupported kind %s written as null", val.Kind())
+	return nullValue(options), nil
+}
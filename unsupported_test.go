@@ -0,0 +1,76 @@
+package yamlc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnsupportedKinds(t *testing.T) {
+	type Runtime struct {
+		Name     string                 `yaml:"name"`
+		Events   chan int               `yaml:"events"`
+		Handler  func()                 `yaml:"handler"`
+		Signal   complex128             `yaml:"signal"`
+		Extra    map[string]interface{} `yaml:"extra"`
+		Handlers []interface{}          `yaml:"handlers"`
+	}
+	runtime := Runtime{
+		Name:     "worker",
+		Events:   make(chan int),
+		Handler:  func() {},
+		Extra:    map[string]interface{}{"cb": func() {}, "size": 1},
+		Handlers: []interface{}{func() {}, "named"},
+	}
+
+	_, err := Gen(runtime, WithStyle(StyleTop))
+	if err == nil || !strings.Contains(err.Error(), "unsupported kind chan at events") {
+		t.Errorf("expected unsupported kind error, got %v", err)
+	}
+
+	data, err := Gen(runtime, WithStyle(StyleTop), WithUnsupportedKinds(UnsupportedSkip))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	expected := "name: worker\nextra:\n  size: 1\nhandlers:\n  - named\n"
+	if !strings.HasPrefix(string(data), expected) {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, data)
+	}
+
+	data, err = Gen(runtime, WithStyle(StyleTop), WithUnsupportedKinds(UnsupportedNull))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	for _, expected := range []string{"events: null\n", "handler: null\n", "signal: null\n", "cb: null\n", "  - null\n"} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("expected %q in:\n%s", expected, data)
+		}
+	}
+
+	// 最小风格在编码前按同样的策略处理，而不是由 yaml.v3 报错
+	_, err = Gen(runtime, WithStyle(StyleMinimal))
+	if err == nil || !strings.Contains(err.Error(), "unsupported kind chan at events") {
+		t.Errorf("StyleMinimal: expected unsupported kind error, got %v", err)
+	}
+
+	data, err = Gen(runtime, WithStyle(StyleMinimal), WithUnsupportedKinds(UnsupportedSkip))
+	if err != nil {
+		t.Fatalf("StyleMinimal: Gen failed: %v", err)
+	}
+	decoded, err := Unmarshal[map[string]interface{}](data)
+	if err != nil || len(decoded) != 3 || decoded["name"] != "worker" ||
+		!reflect.DeepEqual(decoded["extra"], map[string]interface{}{"size": 1}) ||
+		!reflect.DeepEqual(decoded["handlers"], []interface{}{"named"}) {
+		t.Errorf("StyleMinimal: unexpected skipped output (%v):\n%s", err, data)
+	}
+
+	data, err = Gen(runtime, WithStyle(StyleMinimal), WithUnsupportedKinds(UnsupportedNull))
+	if err != nil {
+		t.Fatalf("StyleMinimal: Gen failed: %v", err)
+	}
+	for _, expected := range []string{"events: null\n", "handler: null\n", "signal: null\n", "cb: null\n", "- null\n"} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("StyleMinimal: expected %q in:\n%s", expected, data)
+		}
+	}
+}
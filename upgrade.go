@@ -0,0 +1,317 @@
+package yamlc
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Upgrade 将旧版本的配置文件内容迁移到 v 的结构体版本，返回按新版本生成的内容；v 的字段值作为新增键的默认值，v 本身不被修改
+// 迁移规则来自新版本结构体的标签：
+//   - renamedfrom=旧路径：旧路径的值移到该字段（路径从根开始，列表和映射元素以 * 表示，如 servers.*.host）
+//   - removed=版本,replacement=新路径：该字段的值移到新路径
+//   - removed=版本：该字段的值被丢弃
+//
+// 新版本中不存在的键同样被丢弃，所有丢弃的键及原因以注释形式列在文档末尾
+func Upgrade(data []byte, v interface{}, opts ...Option) ([]byte, error) {
+	if v == nil {
+		return nil, ErrNilInput
+	}
+	typ, err := migrationStructType(v)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := decodeTree(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var dropped []string
+	fields := collectMigrationFields(typ, "", map[reflect.Type]bool{})
+	for _, field := range fields {
+		if from, ok := getTagValue(field.field, "renamedfrom"); ok && from != "" {
+			if err := moveTreePaths(tree, from, field.path, &dropped); err != nil {
+				return nil, fmt.Errorf("field %q: %w", field.path, err)
+			}
+		}
+	}
+	for _, field := range fields {
+		version, replacement, ok := getRemoved(field.field)
+		if !ok {
+			continue
+		}
+		if replacement != "" {
+			if err := moveTreePaths(tree, field.path, replacement, &dropped); err != nil {
+				return nil, fmt.Errorf("field %q: %w", field.path, err)
+			}
+			continue
+		}
+		reason := "removed"
+		if version != "" {
+			reason += " in " + version
+		}
+		for _, path := range expandTreePath(tree, splitTreePath(field.path), nil) {
+			deleteTreePath(tree, path.segments)
+			dropped = append(dropped, strings.Join(path.segments, ".")+": "+reason)
+		}
+	}
+	dropUnknownKeys(tree, typ, "", &dropped)
+
+	// 以 v 的值为默认值，用迁移后的内容覆盖
+	defaults, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode defaults: %w", err)
+	}
+	merged, err := decodeTree(defaults)
+	if err != nil {
+		return nil, err
+	}
+	merged = mergeTrees(merged, tree)
+	content, err := yaml.Marshal(merged)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode upgraded config: %w", err)
+	}
+	upgraded := reflect.New(typ)
+	if err := decodeYAML(content, upgraded.Interface(), &Options{}); err != nil {
+		return nil, fmt.Errorf("failed to decode upgraded config: %w", err)
+	}
+
+	if len(dropped) > 0 {
+		lines := []string{"Removed by upgrade:"}
+		for _, note := range dropped {
+			lines = append(lines, "  "+note)
+		}
+		opts = append(opts[:len(opts):len(opts)], WithFooter(lines...))
+	}
+	return Gen(upgraded.Interface(), opts...)
+}
+
+// treePath 值树中的一个具体路径，captures 为路径规则中 * 匹配到的键或下标
+type treePath struct {
+	segments []string
+	captures []string
+}
+
+// splitTreePath 拆分点号分隔的路径
+func splitTreePath(fieldPath string) []string {
+	if fieldPath == "" {
+		return nil
+	}
+	return strings.Split(fieldPath, ".")
+}
+
+// moveTreePaths 将匹配 from 规则的值移到 to 规则对应的路径，两者中的 * 按顺序对应；目标已有值时保留目标值，旧值记录到 dropped
+func moveTreePaths(tree interface{}, from, to string, dropped *[]string) error {
+	fromSegments, toSegments := splitTreePath(from), splitTreePath(to)
+	if countWildcards(fromSegments) != countWildcards(toSegments) {
+		return fmt.Errorf("paths %q and %q must have the same number of *", from, to)
+	}
+	for _, path := range expandTreePath(tree, fromSegments, nil) {
+		value, _ := getTreePath(tree, path.segments)
+		deleteTreePath(tree, path.segments)
+
+		target := make([]string, len(toSegments))
+		capture := 0
+		for i, segment := range toSegments {
+			if segment == "*" {
+				segment = path.captures[capture]
+				capture++
+			}
+			target[i] = segment
+		}
+		if _, exists := getTreePath(tree, target); exists {
+			*dropped = append(*dropped, strings.Join(path.segments, ".")+": superseded by "+strings.Join(target, "."))
+			continue
+		}
+		setTreePath(tree, target, value)
+	}
+	return nil
+}
+
+// countWildcards 统计路径中 * 的个数
+func countWildcards(segments []string) int {
+	count := 0
+	for _, segment := range segments {
+		if segment == "*" {
+			count++
+		}
+	}
+	return count
+}
+
+// expandTreePath 将路径规则展开为值树中实际存在的路径
+func expandTreePath(node interface{}, segments []string, prefix *treePath) []treePath {
+	if prefix == nil {
+		prefix = &treePath{}
+	}
+	if len(segments) == 0 {
+		return []treePath{{
+			segments: append([]string(nil), prefix.segments...),
+			captures: append([]string(nil), prefix.captures...),
+		}}
+	}
+
+	segment := segments[0]
+	var result []treePath
+	visit := func(key string, child interface{}, wildcard bool) {
+		next := &treePath{segments: append(prefix.segments[:len(prefix.segments):len(prefix.segments)], key), captures: prefix.captures}
+		if wildcard {
+			next.captures = append(prefix.captures[:len(prefix.captures):len(prefix.captures)], key)
+		}
+		result = append(result, expandTreePath(child, segments[1:], next)...)
+	}
+	switch node := node.(type) {
+	case map[string]interface{}:
+		if segment == "*" {
+			for _, key := range unionKeys(nil, node) {
+				visit(key, node[key], true)
+			}
+		} else if child, ok := node[segment]; ok {
+			visit(segment, child, false)
+		}
+	case []interface{}:
+		if segment == "*" {
+			for i, child := range node {
+				visit(strconv.Itoa(i), child, true)
+			}
+		} else if i, err := strconv.Atoi(segment); err == nil && i >= 0 && i < len(node) {
+			visit(segment, node[i], false)
+		}
+	}
+	return result
+}
+
+// getTreePath 获取值树中路径对应的值
+func getTreePath(node interface{}, segments []string) (interface{}, bool) {
+	for _, segment := range segments {
+		switch current := node.(type) {
+		case map[string]interface{}:
+			child, ok := current[segment]
+			if !ok {
+				return nil, false
+			}
+			node = child
+		case []interface{}:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(current) {
+				return nil, false
+			}
+			node = current[i]
+		default:
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+// setTreePath 设置值树中路径对应的值，缺少的上级映射自动创建；上级不是映射或列表时不设置
+func setTreePath(node interface{}, segments []string, value interface{}) {
+	for i, segment := range segments {
+		last := i == len(segments)-1
+		switch current := node.(type) {
+		case map[string]interface{}:
+			if last {
+				current[segment] = value
+				return
+			}
+			child := current[segment]
+			switch child.(type) {
+			case map[string]interface{}, []interface{}:
+			default:
+				child = map[string]interface{}{}
+				current[segment] = child
+			}
+			node = child
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(current) {
+				return
+			}
+			if last {
+				current[index] = value
+				return
+			}
+			node = current[index]
+		default:
+			return
+		}
+	}
+}
+
+// deleteTreePath 删除值树中路径对应的键；列表元素不删除，避免改变其他元素的下标
+func deleteTreePath(node interface{}, segments []string) {
+	if len(segments) == 0 {
+		return
+	}
+	parent, ok := getTreePath(node, segments[:len(segments)-1])
+	if !ok {
+		return
+	}
+	if m, ok := parent.(map[string]interface{}); ok {
+		delete(m, segments[len(segments)-1])
+	}
+}
+
+// dropUnknownKeys 按结构体类型删除值树中新版本不存在的键，记录到 dropped
+func dropUnknownKeys(node interface{}, typ reflect.Type, fieldPath string, dropped *[]string) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if isRawType(typ) || typ.Implements(textMarshalerType) || reflect.PtrTo(typ).Implements(textMarshalerType) {
+		return
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return
+		}
+		known := make(map[string]reflect.Type)
+		for _, plan := range getTypePlan(typ) {
+			known[plan.name] = plan.fieldType.Type
+		}
+		for _, key := range unionKeys(nil, m) {
+			childPath := buildFieldPath(fieldPath, key)
+			fieldType, ok := known[key]
+			if !ok {
+				delete(m, key)
+				*dropped = append(*dropped, childPath+": unknown key")
+				continue
+			}
+			dropUnknownKeys(m[key], fieldType, childPath, dropped)
+		}
+	case reflect.Map:
+		if m, ok := node.(map[string]interface{}); ok {
+			for _, key := range unionKeys(nil, m) {
+				dropUnknownKeys(m[key], typ.Elem(), buildFieldPath(fieldPath, key), dropped)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if list, ok := node.([]interface{}); ok {
+			for i, item := range list {
+				dropUnknownKeys(item, typ.Elem(), buildFieldPath(fieldPath, strconv.Itoa(i)), dropped)
+			}
+		}
+	}
+}
+
+// mergeTrees 将 override 合并到 base：映射逐键合并，其他值整体替换
+func mergeTrees(base, override interface{}) interface{} {
+	baseMap, baseIsMap := base.(map[string]interface{})
+	overrideMap, overrideIsMap := override.(map[string]interface{})
+	if !baseIsMap || !overrideIsMap {
+		return override
+	}
+	for key, value := range overrideMap {
+		if existing, ok := baseMap[key]; ok {
+			baseMap[key] = mergeTrees(existing, value)
+		} else {
+			baseMap[key] = value
+		}
+	}
+	return baseMap
+}
@@ -0,0 +1,89 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+type upgradeServer struct {
+	Address string `yaml:"address" yamlc:"renamedfrom=servers.*.host"`
+	Port    int    `yaml:"port"`
+}
+
+type upgradeDatabase struct {
+	Host string `yaml:"host" yamlc:"renamedfrom=dbHost"`
+	Pool int    `yaml:"pool"`
+}
+
+type upgradeConfig struct {
+	Name     string          `yaml:"name"`
+	Servers  []upgradeServer `yaml:"servers"`
+	Database upgradeDatabase `yaml:"database"`
+	LogLevel string          `yaml:"logLevel"`
+	Verbose  bool            `yaml:"verbose" yamlc:"removed=3.0,replacement=logLevel"`
+	Debug    bool            `yaml:"debug" yamlc:"removed=3.0"`
+}
+
+func TestUpgrade(t *testing.T) {
+	old := []byte(`name: api
+dbHost: db.local
+servers:
+  - host: a.local
+    port: 80
+  - host: b.local
+    port: 81
+verbose: info
+debug: true
+legacy: 1
+`)
+	defaults := upgradeConfig{Database: upgradeDatabase{Pool: 10}, LogLevel: "warn"}
+
+	data, err := Upgrade(old, defaults, WithStyle(StyleTop), WithIndent(2), WithBlankLines(BlankLinesNone))
+	if err != nil {
+		t.Fatalf("Upgrade failed: %v", err)
+	}
+
+	var upgraded upgradeConfig
+	if err := yaml.Unmarshal(data, &upgraded); err != nil {
+		t.Fatalf("invalid YAML: %v\n%s", err, data)
+	}
+	if upgraded.Name != "api" || upgraded.Database.Host != "db.local" || upgraded.Database.Pool != 10 {
+		t.Errorf("unexpected values: %+v\n%s", upgraded, data)
+	}
+	if len(upgraded.Servers) != 2 || upgraded.Servers[0].Address != "a.local" || upgraded.Servers[1].Address != "b.local" || upgraded.Servers[1].Port != 81 {
+		t.Errorf("servers not migrated: %+v\n%s", upgraded.Servers, data)
+	}
+	if upgraded.LogLevel != "info" || upgraded.Debug {
+		t.Errorf("removed keys not handled: %+v\n%s", upgraded, data)
+	}
+	if !strings.HasSuffix(string(data), "# Removed by upgrade:\n#   debug: removed in 3.0\n#   legacy: unknown key\n") {
+		t.Errorf("missing removal notes:\n%s", data)
+	}
+	if defaults.Name != "" || defaults.Database.Host != "" {
+		t.Errorf("defaults were modified: %+v", defaults)
+	}
+}
+
+func TestUpgradeKeepsNewValue(t *testing.T) {
+	type Config struct {
+		Host string `yaml:"host" yamlc:"renamedfrom=address"`
+	}
+	data, err := Upgrade([]byte("address: old\nhost: new\n"), Config{}, WithBlankLines(BlankLinesNone))
+	if err != nil {
+		t.Fatalf("Upgrade failed: %v", err)
+	}
+	if string(data) != "host: new\n\n# Removed by upgrade:\n#   address: superseded by host\n" {
+		t.Errorf("unexpected output:\n%s", data)
+	}
+}
+
+func TestUpgradeInvalidRename(t *testing.T) {
+	type Config struct {
+		Host string `yaml:"host" yamlc:"renamedfrom=servers.*.host"`
+	}
+	if _, err := Upgrade([]byte("servers: []\n"), Config{}); err == nil || !strings.Contains(err.Error(), "same number of *") {
+		t.Errorf("expected wildcard mismatch error, got %v", err)
+	}
+}
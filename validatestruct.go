@@ -0,0 +1,181 @@
+package yamlc
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	yamlUnmarshalerType = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// ValidateAgainstStruct 按结构体的形状校验YAML内容：未知的键、缺少的必填字段（yamlc:"required"）
+// 和类型不匹配都会报告，每个问题带有字段路径和行号。v 为结构体（或其指针），只用于提供类型
+func ValidateAgainstStruct(data []byte, v interface{}) error {
+	if v == nil {
+		return ErrNilInput
+	}
+	typ := reflect.TypeOf(v)
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	var doc yaml.Node
+	if err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("YAML parsing error: %w", err)
+	}
+
+	root := &yaml.Node{Kind: yaml.MappingNode, Line: 1}
+	if len(doc.Content) > 0 {
+		root = doc.Content[0]
+	}
+
+	var problems []string
+	checkNode(root, typ, "", func(node *yaml.Node, fieldPath, format string, args ...interface{}) {
+		if fieldPath == "" {
+			fieldPath = "<root>"
+		}
+		problems = append(problems, fmt.Sprintf("line %d: %s: %s", node.Line, fieldPath, fmt.Sprintf(format, args...)))
+	})
+	if len(problems) > 0 {
+		return fmt.Errorf("schema validation failed:\n  %s", strings.Join(problems, "\n  "))
+	}
+
+	// 最后按 KnownFields 完整解码一次，覆盖逐节点检查之外的情况（如自定义解码）
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(reflect.New(typ).Interface()); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("schema validation failed: %w", err)
+	}
+	return nil
+}
+
+// schemaReporter 记录一个校验问题
+type schemaReporter func(node *yaml.Node, fieldPath, format string, args ...interface{})
+
+// checkNode 按类型递归校验节点
+func checkNode(node *yaml.Node, typ reflect.Type, fieldPath string, report schemaReporter) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+	if isCustomDecoded(typ) {
+		checkDecode(node, typ, fieldPath, report)
+		return
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		if !expectNodeKind(node, yaml.MappingNode, "mapping", fieldPath, report) {
+			return
+		}
+		checkStructNode(node, typ, fieldPath, report)
+	case reflect.Map:
+		if !expectNodeKind(node, yaml.MappingNode, "mapping", fieldPath, report) {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyPath := buildFieldPath(fieldPath, node.Content[i].Value)
+			checkDecode(node.Content[i], typ.Key(), keyPath, report)
+			checkNode(node.Content[i+1], typ.Elem(), keyPath, report)
+		}
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			checkDecode(node, typ, fieldPath, report)
+			return
+		}
+		if !expectNodeKind(node, yaml.SequenceNode, "sequence", fieldPath, report) {
+			return
+		}
+		if typ.Kind() == reflect.Array && len(node.Content) > typ.Len() {
+			report(node, fieldPath, "expected at most %d items, got %d", typ.Len(), len(node.Content))
+		}
+		for i, item := range node.Content {
+			checkNode(item, typ.Elem(), buildFieldPath(fieldPath, strconv.Itoa(i)), report)
+		}
+	case reflect.Interface:
+	default:
+		checkDecode(node, typ, fieldPath, report)
+	}
+}
+
+// checkStructNode 校验映射节点中的键是否都是结构体字段，以及必填字段是否存在
+func checkStructNode(node *yaml.Node, typ reflect.Type, fieldPath string, report schemaReporter) {
+	plans := getTypePlan(typ)
+	seen := make(map[string]bool, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		seen[key] = true
+		keyPath := buildFieldPath(fieldPath, key)
+
+		found := false
+		for _, plan := range plans {
+			if plan.name == key {
+				found = true
+				checkNode(node.Content[i+1], plan.fieldType.Type, keyPath, report)
+				break
+			}
+		}
+		if !found {
+			report(node.Content[i], keyPath, "unknown field %q", key)
+		}
+	}
+	for _, plan := range plans {
+		if !seen[plan.name] && hasTagFlag(plan.fieldType, "required") {
+			report(node, buildFieldPath(fieldPath, plan.name), "missing required field")
+		}
+	}
+}
+
+// expectNodeKind 检查节点类型，不匹配时报告并返回false
+func expectNodeKind(node *yaml.Node, kind yaml.Kind, name, fieldPath string, report schemaReporter) bool {
+	if node.Kind == kind {
+		return true
+	}
+	report(node, fieldPath, "expected %s, got %s", name, nodeKindName(node))
+	return false
+}
+
+// checkDecode 将节点解码为类型的新值，失败时报告类型不匹配
+func checkDecode(node *yaml.Node, typ reflect.Type, fieldPath string, report schemaReporter) {
+	if err := node.Decode(reflect.New(typ).Interface()); err != nil {
+		if node.Kind == yaml.ScalarNode {
+			report(node, fieldPath, "cannot use %q as %s", node.Value, typ)
+		} else {
+			report(node, fieldPath, "cannot use %s as %s", nodeKindName(node), typ)
+		}
+	}
+}
+
+// isCustomDecoded 判断类型是否自定义了解码方式
+func isCustomDecoded(typ reflect.Type) bool {
+	ptr := reflect.PtrTo(typ)
+	return ptr.Implements(yamlUnmarshalerType) || ptr.Implements(textUnmarshalerType)
+}
+
+// nodeKindName 返回节点类型的名称
+func nodeKindName(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "mapping"
+	case yaml.SequenceNode:
+		return "sequence"
+	case yaml.ScalarNode:
+		return "scalar " + strconv.Quote(node.Value)
+	}
+	return "node"
+}
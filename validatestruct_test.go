@@ -0,0 +1,74 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type schemaLimits struct {
+	Timeout time.Duration `yaml:"timeout"`
+	Retries int           `yaml:"retries"`
+}
+
+type schemaConfig struct {
+	Name   string            `yaml:"name" yamlc:"required"`
+	Port   int               `yaml:"port" yamlc:"required"`
+	Tags   []string          `yaml:"tags"`
+	Limits *schemaLimits     `yaml:"limits"`
+	Labels map[string]string `yaml:"labels"`
+}
+
+func TestValidateAgainstStruct(t *testing.T) {
+	valid := "name: demo\nport: 8080\ntags: [a, b]\nlimits:\n  timeout: 30s\n  retries: 3\nlabels:\n  app: web\n"
+	if err := ValidateAgainstStruct([]byte(valid), &schemaConfig{}); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+
+	// 生成的内容总能通过校验
+	data, err := Gen(schemaConfig{Name: "demo", Port: 80, Labels: map[string]string{"app": "web"}}, WithStyle(StyleInline))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if err := ValidateAgainstStruct(data, schemaConfig{}); err != nil {
+		t.Errorf("generated config should validate: %v\n%s", err, data)
+	}
+
+	invalid := strings.Join([]string{
+		"name: demo",
+		"tags: oops",
+		"limits:",
+		"  timeout: soon",
+		"  retries: 3",
+		"  extra: true",
+		"labels:",
+		"  app: [1]",
+		"unknown: 1",
+	}, "\n")
+	err = ValidateAgainstStruct([]byte(invalid), schemaConfig{})
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, expected := range []string{
+		"line 2: tags: expected sequence, got scalar \"oops\"",
+		"line 4: limits.timeout: cannot use \"soon\" as time.Duration",
+		"line 6: limits.extra: unknown field \"extra\"",
+		"line 8: labels.app: cannot use sequence as string",
+		"line 9: unknown: unknown field \"unknown\"",
+		"line 1: port: missing required field",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected %q in:\n%v", expected, err)
+		}
+	}
+
+	if err := ValidateAgainstStruct([]byte("name: ["), schemaConfig{}); err == nil {
+		t.Error("expected parse error")
+	}
+	if err := ValidateAgainstStruct([]byte(""), schemaConfig{}); err == nil || !strings.Contains(err.Error(), "name: missing required field") {
+		t.Errorf("expected missing required fields for empty document, got %v", err)
+	}
+	if err := ValidateAgainstStruct([]byte("a: 1"), nil); err == nil {
+		t.Error("expected error for nil value")
+	}
+}
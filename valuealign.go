@@ -0,0 +1,111 @@
+package yamlc
+
+import "strings"
+
+// WithValueAlignment 在同一映射内补齐键后的空格，使所有值从同一列开始，形成表格式的排版
+func WithValueAlignment() Option {
+	return func(o *Options) {
+		o.ValueAlignment = true
+	}
+}
+
+// keyValueLine 同一映射内待对齐的 "key: value" 行
+type keyValueLine struct {
+	index int
+	// keyEnd 键（含冒号）结束的位置
+	keyEnd int
+	// keyWidth 从键所在列到冒号的显示宽度
+	keyWidth int
+}
+
+// alignValues 将每个映射内单行值的起始列对齐，跳过块标量内容、整行注释和没有值的键
+func alignValues(content string) string {
+	lines := strings.Split(content, "\n")
+	inBlock := blockContentLines(lines)
+
+	// groups 按键所在列记录当前仍未结束的映射
+	groups := make(map[int][]keyValueLine)
+	flush := func(column int) {
+		alignGroup(lines, groups[column])
+		delete(groups, column)
+	}
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if inBlock[i] || trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		column, dash := keyColumn(line)
+		for groupColumn := range groups {
+			// 更深的映射已结束；列表项开始一个新的映射
+			if groupColumn > column || (dash && groupColumn == column) {
+				flush(groupColumn)
+			}
+		}
+
+		body := line
+		if parsed, ok := splitTrailingComment(line); ok {
+			body = parsed.content
+		}
+		keyEnd := mappingKeyEnd(body, column)
+		if keyEnd < 0 || strings.TrimSpace(body[keyEnd:]) == "" {
+			// 没有值的键（子节点在后续行）不参与对齐，但仍属于该映射
+			groups[column] = append(groups[column], keyValueLine{index: -1})
+			continue
+		}
+		groups[column] = append(groups[column], keyValueLine{
+			index:    i,
+			keyEnd:   keyEnd,
+			keyWidth: getDisplayWidth(body[column:keyEnd]),
+		})
+	}
+	for column := range groups {
+		flush(column)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// alignGroup 对齐一个映射中各行的值
+func alignGroup(lines []string, group []keyValueLine) {
+	width := 0
+	for _, kv := range group {
+		if kv.index >= 0 && kv.keyWidth > width {
+			width = kv.keyWidth
+		}
+	}
+	for _, kv := range group {
+		if kv.index < 0 {
+			continue
+		}
+		line := lines[kv.index]
+		value := strings.TrimLeft(line[kv.keyEnd:], " ")
+		lines[kv.index] = line[:kv.keyEnd] + strings.Repeat(" ", width-kv.keyWidth+1) + value
+	}
+}
+
+// mappingKeyEnd 返回从 column 开始的 "key:" 中冒号之后的位置，引号内的冒号不算；不是映射键时返回-1
+func mappingKeyEnd(line string, column int) int {
+	inSingle, inDouble := false, false
+	for i := column; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case inDouble:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inDouble = false
+			}
+		case inSingle:
+			if c == '\'' {
+				inSingle = false
+			}
+		case c == '"' && i == column:
+			inDouble = true
+		case c == '\'' && i == column:
+			inSingle = true
+		case c == ':' && (i+1 == len(line) || line[i+1] == ' '):
+			return i + 1
+		}
+	}
+	return -1
+}
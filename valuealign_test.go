@@ -0,0 +1,53 @@
+package yamlc
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestWithValueAlignment(t *testing.T) {
+	type Server struct {
+		Host    string `yaml:"host"`
+		Port    int    `yaml:"port"`
+		Timeout string `yaml:"timeout"`
+	}
+	type Config struct {
+		Name    string            `yaml:"name" comment:"名称"`
+		Version int               `yaml:"version" comment:"版本"`
+		Server  Server            `yaml:"server"`
+		Nodes   []Server          `yaml:"nodes"`
+		Labels  map[string]string `yaml:"labels"`
+	}
+	cfg := Config{
+		Name:    "demo",
+		Version: 2,
+		Server:  Server{Host: "a", Port: 80, Timeout: "1s"},
+		Nodes:   []Server{{Host: "b", Port: 1, Timeout: "2s"}},
+		Labels:  map[string]string{"env": "prod", "region": "cn"},
+	}
+
+	testCases := []struct {
+		style    CommentStyle
+		expected string
+	}{
+		{StyleMinimal, "name:    demo\nversion: 2\nserver:\n  host:    a\n  port:    80\n  timeout: 1s\nnodes:\n  - host:    b\n    port:    1\n    timeout: 2s\nlabels:\n  env:    prod\n  region: cn\n"},
+		{StyleInline, "name:    demo  # 名称\nversion: 2     # 版本\nserver: \n  host:    a\n  port:    80\n  timeout: 1s\nnodes: \n  - host:    b\n    port:    1\n    timeout: 2s\nlabels: \n  env:    prod\n  region: cn\n"},
+	}
+	for _, tc := range testCases {
+		data, err := Gen(cfg, WithStyle(tc.style), WithValueAlignment(), WithBlankLines(BlankLinesNone))
+		if err != nil {
+			t.Fatalf("Gen failed: %v", err)
+		}
+		if string(data) != tc.expected {
+			t.Errorf("style %s: unexpected output:\n%s\nexpected:\n%s", GetStyleString(int(tc.style)), data, tc.expected)
+		}
+		var decoded Config
+		if err := yaml.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unmarshal failed: %v", err)
+		}
+		if decoded.Server != cfg.Server || decoded.Labels["region"] != "cn" {
+			t.Errorf("aligned output did not round-trip: %+v", decoded)
+		}
+	}
+}
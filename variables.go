@@ -0,0 +1,92 @@
+package yamlc
+
+import (
+	"reflect"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WithVariableRefs 变量引用模式：带 var= 或 env= 标签的标量字段输出为 ${变量名} 而不是实际值，
+// 用运行时的同一个结构体生成可由 envsubst 或部署工具替换的配置模板
+// var= 优先于 env=；敏感字段同样输出为引用，不再使用占位符
+func WithVariableRefs() Option {
+	return func(o *Options) {
+		o.VariableRefs = true
+	}
+}
+
+// variableName 获取字段引用的变量名（yamlc标签中的 var=，其次为 env=）
+func variableName(field reflect.StructField) string {
+	if name, ok := getTagValue(field, "var"); ok && name != "" {
+		return sanitizeComment(name)
+	}
+	return getEnvName(FieldInfo{FieldType: field})
+}
+
+// variableRef 变量引用模式下返回字段输出的 ${变量名}，只作用于标量字段
+func variableRef(field reflect.StructField, val reflect.Value, options *Options) (string, bool) {
+	if !options.VariableRefs || !isScalarValue(val) {
+		return "", false
+	}
+	name := variableName(field)
+	if name == "" {
+		return "", false
+	}
+	return "${" + name + "}", true
+}
+
+// substituteNode 在yaml节点树上替换文件引用、变量引用和占位文本，用于不经过字段渲染的最小风格
+func substituteNode(node *yaml.Node, val reflect.Value, fieldPath string, options *Options) {
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return
+		}
+		val = val.Elem()
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) > 0 {
+			substituteNode(node.Content[0], val, fieldPath, options)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			switch val.Kind() {
+			case reflect.Struct:
+				fieldType, field, ok := findYAMLField(val, node.Content[i].Value)
+				if !ok {
+					continue
+				}
+				childPath := buildFieldPath(fieldPath, node.Content[i].Value)
+				if file, ok := includeFile(fieldType, childPath, options); ok {
+					node.Content[i+1] = includeNode(file, options)
+					continue
+				}
+				text, ok := variableRef(fieldType, field, options)
+				if !ok && !isSecretField(fieldType, node.Content[i].Value, options) {
+					text, ok = placeholderFor(fieldType, field, options)
+				}
+				if ok {
+					node.Content[i+1] = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: text}
+					continue
+				}
+				substituteNode(node.Content[i+1], field, childPath, options)
+			case reflect.Map:
+				if val.Type().Key().Kind() == reflect.String {
+					key := node.Content[i].Value
+					substituteNode(node.Content[i+1], val.MapIndex(reflect.ValueOf(key).Convert(val.Type().Key())), buildFieldPath(fieldPath, key), options)
+				}
+			}
+		}
+	case yaml.SequenceNode:
+		if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+			return
+		}
+		for i, item := range node.Content {
+			if i < val.Len() {
+				substituteNode(item, val.Index(i), buildFieldPath(fieldPath, strconv.Itoa(i)), options)
+			}
+		}
+	}
+}
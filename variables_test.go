@@ -0,0 +1,54 @@
+package yamlc
+
+import (
+	"testing"
+)
+
+func TestWithVariableRefs(t *testing.T) {
+	type Database struct {
+		Host     string   `yaml:"host" yamlc:"comment=主机" env:"DB_HOST"`
+		Port     int      `yaml:"port" yamlc:"var=DB_PORT"`
+		Password string   `yaml:"password" yamlc:"secret" env:"DB_PASSWORD"`
+		Tags     []string `yaml:"tags" env:"DB_TAGS"`
+		Pool     int      `yaml:"pool"`
+	}
+	type Config struct {
+		Database Database `yaml:"database"`
+	}
+	cfg := Config{Database: Database{Host: "localhost", Port: 5432, Password: "secret", Tags: []string{"a"}, Pool: 10}}
+
+	testCases := []struct {
+		style    CommentStyle
+		expected string
+	}{
+		{StyleTop, "database:\n  # 主机\n  host: ${DB_HOST}\n  port: ${DB_PORT}\n  password: ${DB_PASSWORD}\n  tags:\n    - a\n  pool: 10\n"},
+		{StyleMinimal, "database:\n  host: ${DB_HOST}\n  port: ${DB_PORT}\n  password: ${DB_PASSWORD}\n  tags:\n    - a\n  pool: 10\n"},
+	}
+	for _, tc := range testCases {
+		data, err := Gen(cfg, WithStyle(tc.style), WithIndent(2), WithVariableRefs(), WithBlankLines(BlankLinesNone))
+		if err != nil {
+			t.Fatalf("Gen failed: %v", err)
+		}
+		if string(data) != tc.expected {
+			t.Errorf("style %s: unexpected output:\n%s\nexpected:\n%s", GetStyleString(int(tc.style)), data, tc.expected)
+		}
+	}
+}
+
+func TestVariableRefsSkipTransformer(t *testing.T) {
+	type Config struct {
+		Host string `yaml:"host" env:"HOST"`
+		Name string `yaml:"name"`
+	}
+	transformer := func(field FieldInfo, v interface{}) (interface{}, error) {
+		return "changed", nil
+	}
+
+	data, err := Gen(Config{Host: "h", Name: "n"}, WithStyle(StyleTop), WithVariableRefs(), WithValueTransformer(transformer), WithBlankLines(BlankLinesNone))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if string(data) != "host: ${HOST}\nname: changed\n" {
+		t.Errorf("unexpected output:\n%s", data)
+	}
+}
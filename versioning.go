@@ -0,0 +1,212 @@
+package yamlc
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// getSince 获取字段引入的版本（yamlc标签中的since=）
+func getSince(field reflect.StructField) string {
+	since, _ := getTagValue(field, "since")
+	return sanitizeComment(since)
+}
+
+// getRemoved 获取字段计划移除的版本和替代字段的路径（yamlc标签中的 removed= 和 replacement=）
+func getRemoved(field reflect.StructField) (version, replacement string, ok bool) {
+	version, ok = getTagValue(field, "removed")
+	if !ok {
+		return "", "", false
+	}
+	replacement, _ = getTagValue(field, "replacement")
+	return sanitizeComment(version), sanitizeComment(replacement), true
+}
+
+// removedNote 字段移除的说明，如 "2.0, use database.host"
+func removedNote(version, replacement string) string {
+	note := version
+	if replacement != "" {
+		if note != "" {
+			note += ", "
+		}
+		note += "use " + replacement
+	}
+	return note
+}
+
+// migrationField 迁移说明中比较的一个键
+type migrationField struct {
+	path  string
+	field reflect.StructField
+}
+
+// GenMigrationNotes 比较同一配置结构体的两个版本，生成以注释形式输出的变更说明，列出新增、改名和移除的键
+// oldType 和 newType 可以是结构体的值、指针或nil指针，只使用其类型。列表和映射元素内的键以 * 表示（如 servers.*.host）
+// 带 renamedfrom= 标签的字段视为由旧路径改名而来；新版本中仍保留但带 removed= 标签的字段视为已移除，同时带 replacement= 时视为改名；
+// 新增的键附带 since= 标注的版本
+// 整段新增或移除的结构体只列出其本身，不再列出子键
+func GenMigrationNotes(oldType, newType interface{}) ([]byte, error) {
+	oldStruct, err := migrationStructType(oldType)
+	if err != nil {
+		return nil, err
+	}
+	newStruct, err := migrationStructType(newType)
+	if err != nil {
+		return nil, err
+	}
+
+	oldFields := collectMigrationFields(oldStruct, "", map[reflect.Type]bool{})
+	newFields := collectMigrationFields(newStruct, "", map[reflect.Type]bool{})
+	oldPaths := make(map[string]bool, len(oldFields))
+	for _, field := range oldFields {
+		oldPaths[field.path] = true
+	}
+	newPaths := make(map[string]bool, len(newFields))
+	for _, field := range newFields {
+		newPaths[field.path] = true
+	}
+
+	var added, renamed, removed []string
+	replacements := make(map[string]bool)
+	renamedFrom := make(map[string]bool)
+	for _, field := range newFields {
+		if from, ok := getTagValue(field.field, "renamedfrom"); ok && from != "" && !newPaths[from] {
+			renamedFrom[from] = true
+			replacements[field.path] = true
+			renamed = append(renamed, migrationNote(from+" -> "+field.path, sinceNote(getSince(field.field)), ""))
+		}
+		if version, replacement, ok := getRemoved(field.field); ok {
+			if replacement != "" {
+				replacements[replacement] = true
+				renamed = append(renamed, migrationNote(field.path+" -> "+replacement, versionNote(version), ""))
+			} else {
+				removed = append(removed, migrationNote(field.path, versionNote(version), ""))
+			}
+		}
+	}
+	for _, field := range newFields {
+		if _, _, ok := getRemoved(field.field); ok || replacements[field.path] {
+			continue
+		}
+		if !oldPaths[field.path] && !hasAncestor(field.path, oldPaths, newPaths) {
+			added = append(added, migrationNote(field.path, sinceNote(getSince(field.field)), getTagComment(field.field)))
+		}
+	}
+	for _, field := range oldFields {
+		if !newPaths[field.path] && !renamedFrom[field.path] && !hasAncestor(field.path, newPaths, oldPaths) {
+			removed = append(removed, migrationNote(field.path, "", ""))
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("# Migration notes\n")
+	if len(added)+len(renamed)+len(removed) == 0 {
+		b.WriteString("#\n# No changes\n")
+		return []byte(b.String()), nil
+	}
+	for _, section := range []struct {
+		title string
+		notes []string
+	}{
+		{"Added", added},
+		{"Renamed", renamed},
+		{"Removed", removed},
+	} {
+		if len(section.notes) == 0 {
+			continue
+		}
+		b.WriteString("#\n# " + section.title + ":\n")
+		for _, note := range section.notes {
+			b.WriteString("#   - " + note + "\n")
+		}
+	}
+	return []byte(b.String()), nil
+}
+
+// migrationStructType 获取值对应的结构体类型
+func migrationStructType(v interface{}) (reflect.Type, error) {
+	if v == nil {
+		return nil, ErrNilInput
+	}
+	typ := reflect.TypeOf(v)
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("migration notes require struct types, got %s", typ)
+	}
+	return typ, nil
+}
+
+// collectMigrationFields 按声明顺序收集结构体类型中的全部键，列表、数组和映射的元素以 * 表示
+// visiting 用于避免自引用类型无限展开
+func collectMigrationFields(typ reflect.Type, fieldPath string, visiting map[reflect.Type]bool) []migrationField {
+	for {
+		switch typ.Kind() {
+		case reflect.Ptr:
+			typ = typ.Elem()
+			continue
+		case reflect.Slice, reflect.Array, reflect.Map:
+			if typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8 {
+				return nil
+			}
+			typ = typ.Elem()
+			fieldPath = buildFieldPath(fieldPath, "*")
+			continue
+		}
+		break
+	}
+	if typ.Kind() != reflect.Struct || isRawType(typ) || visiting[typ] ||
+		typ.Implements(textMarshalerType) || reflect.PtrTo(typ).Implements(textMarshalerType) {
+		return nil
+	}
+	visiting[typ] = true
+	defer delete(visiting, typ)
+
+	var fields []migrationField
+	for _, plan := range getTypePlan(typ) {
+		childPath := buildFieldPath(fieldPath, plan.name)
+		fields = append(fields, migrationField{path: childPath, field: plan.fieldType})
+		fields = append(fields, collectMigrationFields(plan.fieldType.Type, childPath, visiting)...)
+	}
+	return fields
+}
+
+// hasAncestor 判断路径的某个上级键只存在于 own 中而不在 other 中，即整段新增或移除，子键不必单独列出
+func hasAncestor(fieldPath string, other, own map[string]bool) bool {
+	for i := strings.LastIndex(fieldPath, "."); i > 0; i = strings.LastIndex(fieldPath[:i], ".") {
+		parent := fieldPath[:i]
+		if own[parent] && !other[parent] {
+			return true
+		}
+	}
+	return false
+}
+
+// versionNote 移除版本的说明
+func versionNote(version string) string {
+	if version == "" {
+		return ""
+	}
+	return "in " + version
+}
+
+// sinceNote 引入版本的说明
+func sinceNote(since string) string {
+	if since == "" {
+		return ""
+	}
+	return "since " + since
+}
+
+// migrationNote 生成一条变更说明，如 "database.pool (since 1.4): 连接池大小"
+func migrationNote(subject, version, comment string) string {
+	note := subject
+	if version != "" {
+		note += " (" + version + ")"
+	}
+	if comment != "" {
+		note += ": " + comment
+	}
+	return note
+}
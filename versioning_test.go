@@ -0,0 +1,106 @@
+package yamlc
+
+import (
+	"strings"
+	"testing"
+)
+
+type migrationServerV1 struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+}
+
+type migrationConfigV1 struct {
+	Name    string              `yaml:"name"`
+	DBHost  string              `yaml:"dbHost"`
+	Legacy  bool                `yaml:"legacy"`
+	Servers []migrationServerV1 `yaml:"servers"`
+	Cache   struct {
+		Size int `yaml:"size"`
+	} `yaml:"cache"`
+}
+
+type migrationDatabaseV2 struct {
+	Host string `yaml:"host"`
+	Pool int    `yaml:"pool"`
+}
+
+type migrationServerV2 struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+	TLS  bool   `yaml:"tls" yamlc:"since=2.0" comment:"启用TLS"`
+}
+
+type migrationConfigV2 struct {
+	Name     string              `yaml:"name"`
+	DBHost   string              `yaml:"dbHost" yamlc:"removed=3.0,replacement=database.host"`
+	Servers  []migrationServerV2 `yaml:"servers"`
+	Database migrationDatabaseV2 `yaml:"database" yamlc:"since=2.0"`
+	Debug    bool                `yaml:"debug" yamlc:"removed=3.0"`
+}
+
+func TestGenMigrationNotes(t *testing.T) {
+	data, err := GenMigrationNotes(migrationConfigV1{}, (*migrationConfigV2)(nil))
+	if err != nil {
+		t.Fatalf("GenMigrationNotes failed: %v", err)
+	}
+	expected := "# Migration notes\n" +
+		"#\n# Added:\n" +
+		"#   - servers.*.tls (since 2.0): 启用TLS\n" +
+		"#   - database (since 2.0)\n" +
+		"#\n# Renamed:\n" +
+		"#   - dbHost -> database.host (in 3.0)\n" +
+		"#\n# Removed:\n" +
+		"#   - debug (in 3.0)\n" +
+		"#   - legacy\n" +
+		"#   - cache\n"
+	if string(data) != expected {
+		t.Errorf("unexpected notes:\n%s\nexpected:\n%s", data, expected)
+	}
+}
+
+func TestGenMigrationNotesNoChanges(t *testing.T) {
+	data, err := GenMigrationNotes(migrationConfigV1{}, migrationConfigV1{})
+	if err != nil {
+		t.Fatalf("GenMigrationNotes failed: %v", err)
+	}
+	if string(data) != "# Migration notes\n#\n# No changes\n" {
+		t.Errorf("unexpected notes:\n%s", data)
+	}
+
+	if _, err := GenMigrationNotes(migrationConfigV1{}, 1); err == nil {
+		t.Error("expected error for non-struct type")
+	}
+}
+
+func TestVersionAnnotations(t *testing.T) {
+	data, err := Gen(migrationConfigV2{}, WithStyle(StyleAnnotated))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	for _, expected := range []string{
+		"#   removed: 3.0, use database.host\ndbHost:",
+		"#   since: 2.0\ndatabase:",
+		"#   removed: 3.0\ndebug:",
+	} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("expected %q in:\n%s", expected, data)
+		}
+	}
+}
+
+func TestGenMigrationNotesRenamedFrom(t *testing.T) {
+	type V1 struct {
+		Addr string `yaml:"addr"`
+	}
+	type V2 struct {
+		Address string `yaml:"address" yamlc:"renamedfrom=addr,since=2.0"`
+	}
+	data, err := GenMigrationNotes(V1{}, V2{})
+	if err != nil {
+		t.Fatalf("GenMigrationNotes failed: %v", err)
+	}
+	if string(data) != "# Migration notes\n#\n# Renamed:\n#   - addr -> address (since 2.0)\n" {
+		t.Errorf("unexpected notes:\n%s", data)
+	}
+}
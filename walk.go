@@ -0,0 +1,95 @@
+package yamlc
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Walk 按默认配置遍历 Gen 会输出的字段树，不生成YAML内容
+func Walk(v interface{}, fn func(FieldInfo) error, opts ...Option) error {
+	return Default().Walk(v, fn, opts...)
+}
+
+// Walk 按该配置遍历 Gen 会输出的字段树，对每个字段和映射项按输出顺序（先上级后子级）调用 fn，不生成YAML内容
+// 字段的筛选、排序、注释和占位替换与 Gen 一致，可据此生成文档、命令行参数或其他格式；列表元素本身不调用 fn，
+// 其中的字段以下标作为路径（如 servers.0.host）。值转换和加密不会执行；fn 返回错误时停止遍历并返回该错误
+func (c *Config) Walk(v interface{}, fn func(FieldInfo) error, opts ...Option) error {
+	if v == nil {
+		return ErrNilInput
+	}
+	if fn == nil {
+		return fmt.Errorf("walk function cannot be nil")
+	}
+	options := c.newOptions(opts...)
+	if options.Profile != "" {
+		profiled, err := applyProfile(v, options.Profile)
+		if err != nil {
+			return err
+		}
+		v = profiled
+	}
+
+	val := reflect.ValueOf(v)
+	if val.Kind() == reflect.Ptr && val.IsNil() {
+		return fmt.Errorf("%w: got nil %T", ErrNilInput, v)
+	}
+	return walkValue(val, options.rootPath, options, fn)
+}
+
+// walkValue 遍历值中的字段，与 generateValue 的递归方式一致
+func walkValue(val reflect.Value, fieldPath string, options *Options, fn func(FieldInfo) error) error {
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+	if exceedsDepth(val, options) || isRawType(val.Type()) {
+		return nil
+	}
+
+	var fields []FieldInfo
+	switch val.Kind() {
+	case reflect.Struct:
+		options = levelOptions(descend(options))
+		fields = collectFieldInfo(val, val.Type(), fieldPath, options)
+	case reflect.Map:
+		options = levelOptions(descend(options))
+		fields = collectMapEntries(val, fieldPath, options)
+	case reflect.Slice, reflect.Array:
+		if isByteSlice(val) {
+			return nil
+		}
+		options = descend(options)
+		for i := 0; i < itemLimit(val.Len(), options); i++ {
+			if err := walkValue(val.Index(i), buildFieldPath(fieldPath, strconv.Itoa(i)), options, fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return nil
+	}
+
+	for _, field := range fields {
+		if err := fn(field); err != nil {
+			return err
+		}
+		if !field.HasChildren {
+			continue
+		}
+		child := field.Field
+		if isSkeletonField(child, options) {
+			// 骨架字段按结构体默认值展开子字段
+			child = reflect.New(child.Type().Elem())
+			if defaulter, ok := child.Interface().(Defaulter); ok {
+				defaulter.SetDefaults()
+			}
+		}
+		if err := walkValue(child, field.FieldPath, options, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package yamlc
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type walkServer struct {
+	Host string `yaml:"host" comment:"主机"`
+	Port int    `yaml:"port" yamlc:"default=8080"`
+}
+
+type walkConfig struct {
+	Name     string            `yaml:"name" comment:"名称"`
+	Password string            `yaml:"password" yamlc:"secret"`
+	Servers  []walkServer      `yaml:"servers"`
+	Labels   map[string]string `yaml:"labels"`
+	Cache    *walkServer       `yaml:"cache"`
+	Internal string            `yaml:"internal" yamlc:"expose=internal"`
+}
+
+func TestWalk(t *testing.T) {
+	cfg := walkConfig{
+		Name:     "app",
+		Password: "hunter2",
+		Servers:  []walkServer{{Host: "a", Port: 1}, {Host: "b", Port: 2}},
+		Labels:   map[string]string{"team": "core"},
+	}
+
+	var paths []string
+	fields := make(map[string]FieldInfo)
+	err := Walk(&cfg, func(field FieldInfo) error {
+		paths = append(paths, field.FieldPath)
+		fields[field.FieldPath] = field
+		return nil
+	}, WithAudience(AudiencePublic))
+	if err != nil {
+		t.Fatalf("Walk failed: %v", err)
+	}
+
+	expected := "name,password,servers,servers.0.host,servers.0.port,servers.1.host,servers.1.port,labels,labels.team,cache"
+	if strings.Join(paths, ",") != expected {
+		t.Errorf("unexpected walk order:\n%s", strings.Join(paths, ","))
+	}
+	if fields["name"].Comment != "名称" {
+		t.Errorf("unexpected comment: %q", fields["name"].Comment)
+	}
+	if value := fields["password"].Field.Interface(); value == "hunter2" {
+		t.Errorf("secret value should be masked, got %v", value)
+	}
+	if port := fields["servers.1.port"]; port.Default != "8080" || port.Depth != 1 || port.Parent != "servers.1" {
+		t.Errorf("unexpected servers.1.port info: %+v", port)
+	}
+}
+
+func TestWalkOptions(t *testing.T) {
+	cfg := walkConfig{Servers: []walkServer{{Host: "a"}, {Host: "b"}}}
+
+	var paths []string
+	err := Walk(cfg, func(field FieldInfo) error {
+		paths = append(paths, field.FieldPath)
+		return nil
+	}, WithMaxItems(1), WithNilPointerPolicy(NilSkeleton), WithExclude("name", "password", "labels", "internal"))
+	if err != nil {
+		t.Fatalf("Walk failed: %v", err)
+	}
+	if strings.Join(paths, ",") != "servers,servers.0.host,servers.0.port,cache,cache.host,cache.port" {
+		t.Errorf("unexpected walk order:\n%s", strings.Join(paths, ","))
+	}
+}
+
+func TestWalkStop(t *testing.T) {
+	stop := errors.New("stop")
+	count := 0
+	err := Walk(walkConfig{}, func(field FieldInfo) error {
+		count++
+		if field.FieldPath == "password" {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) || count != 2 {
+		t.Errorf("expected walk to stop at password, got err=%v count=%d", err, count)
+	}
+
+	if err := Walk(nil, func(FieldInfo) error { return nil }); !errors.Is(err, ErrNilInput) {
+		t.Errorf("expected ErrNilInput, got %v", err)
+	}
+	if err := Walk((*walkConfig)(nil), func(FieldInfo) error { return nil }); !errors.Is(err, ErrNilInput) {
+		t.Errorf("expected ErrNilInput for nil pointer, got %v", err)
+	}
+	if err := Walk(walkConfig{}, nil); err == nil {
+		t.Error("expected error for nil walk function")
+	}
+}
@@ -0,0 +1,141 @@
+package yamlc
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WarningKind 非致命警告的类别
+type WarningKind int
+
+const (
+	// WarningKeyQuoted 映射键包含特殊字符，输出时加了引号
+	WarningKeyQuoted WarningKind = iota
+	// WarningUnsupportedSkipped 不受支持的类型被跳过或输出为空值
+	WarningUnsupportedSkipped
+	// WarningTruncated 内容因 MaxDepth 或 MaxItems 被截断
+	WarningTruncated
+	// WarningCommentFlattened 多行注释被合并为一行
+	WarningCommentFlattened
+)
+
+// String 返回警告类别的名称
+func (k WarningKind) String() string {
+	switch k {
+	case WarningKeyQuoted:
+		return "key-quoted"
+	case WarningUnsupportedSkipped:
+		return "unsupported-skipped"
+	case WarningTruncated:
+		return "truncated"
+	case WarningCommentFlattened:
+		return "comment-flattened"
+	}
+	return fmt.Sprintf("WarningKind(%d)", int(k))
+}
+
+// Warning 生成过程中不影响结果有效性、但用户可能需要知道的情况
+type Warning struct {
+	Kind WarningKind
+	// Path 相关字段的路径，顶层为空
+	Path    string
+	Message string
+}
+
+// String 返回 "路径: 说明" 形式的文本
+func (w Warning) String() string {
+	if w.Path == "" {
+		return w.Message
+	}
+	return w.Path + ": " + w.Message
+}
+
+// WithWarnings 将生成过程中的非致命警告追加到 warnings，一次生成中同一路径的同类警告只记录一次
+// 并发的生成需各自使用单独的列表；Generator 编译时设置的列表由生成器在每次调用结束后加锁合并
+func WithWarnings(warnings *[]Warning) Option {
+	return func(o *Options) {
+		o.Warnings = warnings
+	}
+}
+
+// GenReport 生成YAML内容，并返回生成过程中的非致命警告
+func GenReport(v interface{}, opts ...Option) ([]byte, []Warning, error) {
+	var warnings []Warning
+	data, err := Gen(v, append(opts, WithWarnings(&warnings))...)
+	return data, warnings, err
+}
+
+// startWarnings 为一次生成准备警告去重记录
+func startWarnings(options *Options) {
+	options.warned = nil
+	if options.Warnings != nil {
+		options.warned = make(map[string]struct{})
+	}
+}
+
+// warn 记录一条警告，未设置 WithWarnings 时忽略
+func warn(options *Options, kind WarningKind, path string, format string, args ...interface{}) {
+	if options.Warnings == nil {
+		return
+	}
+	key := kind.String() + ":" + path
+	if _, ok := options.warned[key]; ok {
+		return
+	}
+	if options.warned != nil {
+		options.warned[key] = struct{}{}
+	}
+	*options.Warnings = append(*options.Warnings, Warning{Kind: kind, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// warnNode 在yaml节点树上记录映射键加引号的警告，用于不经过字段渲染的最小风格；截断和不受支持类型的警告在编码时记录
+func warnNode(node *yaml.Node, val reflect.Value, fieldPath string, options *Options) {
+	if options.Warnings == nil {
+		return
+	}
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return
+		}
+		val = val.Elem()
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) > 0 {
+			warnNode(node.Content[0], val, fieldPath, options)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			childPath := buildFieldPath(fieldPath, key)
+			switch val.Kind() {
+			case reflect.Struct:
+				if _, field, ok := findYAMLField(val, key); ok {
+					warnNode(node.Content[i+1], field, childPath, options)
+				}
+			case reflect.Map:
+				if val.Type().Key().Kind() != reflect.String {
+					continue
+				}
+				mapKey := reflect.ValueOf(key).Convert(val.Type().Key())
+				if formatMapKey(mapKey, options) != key {
+					warn(options, WarningKeyQuoted, childPath, "key %q quoted because it contains special characters", key)
+				}
+				warnNode(node.Content[i+1], val.MapIndex(mapKey), childPath, options)
+			}
+		}
+	case yaml.SequenceNode:
+		if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+			return
+		}
+		for i, item := range node.Content {
+			if i < val.Len() {
+				warnNode(item, val.Index(i), buildFieldPath(fieldPath, strconv.Itoa(i)), options)
+			}
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package yamlc
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type warningConfig struct {
+	Name     string            `yaml:"name" comment:"名称"`
+	Labels   map[string]string `yaml:"labels" comment:"标签"`
+	Ports    []int             `yaml:"ports" comment:"端口"`
+	Callback func()            `yaml:"callback"`
+}
+
+func TestGenReport(t *testing.T) {
+	cfg := warningConfig{
+		Name:   "demo",
+		Labels: map[string]string{"app": "web", "a: b": "x"},
+		Ports:  []int{80, 443, 8080},
+	}
+
+	for _, style := range []CommentStyle{StyleTop, StyleMinimal} {
+		data, warnings, err := GenReport(cfg,
+			WithStyle(style),
+			WithUnsupportedKinds(UnsupportedSkip),
+			WithMaxItems(2),
+			WithComment(map[string]string{"name": "第一行\n第二行"}),
+		)
+		if err != nil {
+			t.Fatalf("style %s: GenReport failed: %v", GetStyleString(int(style)), err)
+		}
+		if err := ValidateYAML(data); err != nil {
+			t.Fatalf("style %s: invalid YAML: %v", GetStyleString(int(style)), err)
+		}
+
+		expected := []Warning{
+			{Kind: WarningUnsupportedSkipped, Path: "callback", Message: "skipped unsupported kind func"},
+			{Kind: WarningKeyQuoted, Path: "labels.a: b", Message: `key "a: b" quoted because it contains special characters`},
+			{Kind: WarningTruncated, Path: "ports", Message: "1 of 3 items omitted"},
+		}
+		// 最小风格不输出注释，没有合并注释的警告
+		if style != StyleMinimal {
+			expected = append(expected, Warning{Kind: WarningCommentFlattened, Path: "name", Message: "multi-line comment joined into a single line"})
+		}
+		for _, w := range expected {
+			if !containsWarning(warnings, w) {
+				t.Errorf("style %s: expected warning %+v in %+v", GetStyleString(int(style)), w, warnings)
+			}
+		}
+		if len(warnings) != len(expected) {
+			t.Errorf("style %s: expected %d warnings, got %+v", GetStyleString(int(style)), len(expected), warnings)
+		}
+	}
+}
+
+func TestWithWarnings(t *testing.T) {
+	type node struct {
+		Child *node `yaml:"child"`
+	}
+	cfg := node{Child: &node{Child: &node{Child: &node{}}}}
+
+	var warnings []Warning
+	for _, style := range []CommentStyle{StyleTop, StyleMinimal} {
+		warnings = nil
+		if _, err := Gen(cfg, WithStyle(style), WithMaxDepth(2), WithWarnings(&warnings)); err != nil {
+			t.Fatalf("Gen failed: %v", err)
+		}
+		expected := []Warning{{Kind: WarningTruncated, Path: "child.child", Message: "truncated at depth 2"}}
+		if !reflect.DeepEqual(warnings, expected) {
+			t.Errorf("style %s: expected %+v, got %+v", GetStyleString(int(style)), expected, warnings)
+		}
+	}
+
+	// 输出为空值的不受支持类型同样报告
+	warnings = nil
+	if _, err := Gen(warningConfig{}, WithUnsupportedKinds(UnsupportedNull), WithWarnings(&warnings)); err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if len(warnings) != 1 || warnings[0].String() != "callback: unsupported kind func written as null" {
+		t.Errorf("unexpected warnings: %+v", warnings)
+	}
+	if WarningTruncated.String() != "truncated" {
+		t.Errorf("unexpected kind name %q", WarningTruncated.String())
+	}
+}
+
+func TestWarningsPerCall(t *testing.T) {
+	cfg := warningConfig{Labels: map[string]string{"a: b": "x"}}
+
+	// 去重只在一次生成内进行，同一个列表在多次生成之间累积
+	var warnings []Warning
+	for i := 0; i < 2; i++ {
+		if _, err := Gen(cfg, WithUnsupportedKinds(UnsupportedSkip), WithWarnings(&warnings)); err != nil {
+			t.Fatalf("Gen failed: %v", err)
+		}
+	}
+	if len(warnings) != 4 {
+		t.Errorf("expected two warnings per call, got %+v", warnings)
+	}
+
+	// 并发的生成各自使用单独的列表，不需要全局锁
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, own, err := GenReport(cfg, WithUnsupportedKinds(UnsupportedSkip))
+			if err != nil || len(own) != 2 {
+				t.Errorf("unexpected warnings: %+v, %v", own, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func containsWarning(warnings []Warning, target Warning) bool {
+	for _, w := range warnings {
+		if w == target {
+			return true
+		}
+	}
+	return false
+}
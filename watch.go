@@ -0,0 +1,70 @@
+package yamlc
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watch 监听配置文件的修改，内容变化时重新读取到绑定的结构体并调用 onChange，直到 ctx 取消
+// onChange 收到重新读取后的结构体指针，以及读取失败或 ValidateAgainstStruct 校验不通过的错误；
+// 读取失败时结构体保持不变。监听的是文件所在目录，因此编辑器以重命名方式保存的文件也能被发现
+func (m *Manager) Watch(ctx context.Context, onChange func(newValue interface{}, err error)) error {
+	if onChange == nil {
+		return fmt.Errorf("callback cannot be nil")
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	target := filepath.Clean(m.path)
+	if err := watcher.Add(filepath.Dir(target)); err != nil {
+		return fmt.Errorf("failed to watch %q: %w", m.path, err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Clean(event.Name) != target || !event.Has(fsnotify.Write|fsnotify.Create) {
+				continue
+			}
+			if changed, err := m.reloadValidated(); changed {
+				onChange(m.Value(), err)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			onChange(m.Value(), fmt.Errorf("failed to watch %q: %w", m.path, err))
+		}
+	}
+}
+
+// reloadValidated 内容变化时重新读取并按结构体校验，返回内容是否变化
+func (m *Manager) reloadValidated() (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data, err := os.ReadFile(m.path)
+	if err != nil {
+		return true, fmt.Errorf("failed to read %q: %w", m.path, err)
+	}
+	if m.known && sha256.Sum256(data) == m.hash {
+		return false, nil
+	}
+	if err := m.decode(data); err != nil {
+		return true, err
+	}
+	return true, ValidateAgainstStruct(data, m.value.Interface())
+}
@@ -0,0 +1,80 @@
+package yamlc
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type watchEvent struct {
+	value managedConfig
+	err   error
+}
+
+func TestManagerWatch(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "app.yaml")
+	cfg := &managedConfig{Host: "localhost", Port: 8080, Workers: 4}
+	manager, err := NewManager(filename, cfg)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	if _, err := manager.EnsureExists(); err != nil {
+		t.Fatalf("EnsureExists failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan watchEvent, 16)
+	done := make(chan error, 1)
+	go func() {
+		done <- manager.Watch(ctx, func(newValue interface{}, err error) {
+			events <- watchEvent{value: *newValue.(*managedConfig), err: err}
+		})
+	}()
+
+	// 反复写入直到收到期望的通知；写入过程中可能读到截断后的空文件，忽略这类中间通知
+	next := func(content string, want func(watchEvent) bool) watchEvent {
+		t.Helper()
+		deadline := time.After(5 * time.Second)
+		for {
+			if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+				t.Fatalf("WriteFile failed: %v", err)
+			}
+			select {
+			case event := <-events:
+				if want(event) {
+					return event
+				}
+			case <-time.After(100 * time.Millisecond):
+			case <-deadline:
+				t.Fatalf("timed out waiting for change notification")
+			}
+		}
+	}
+
+	event := next("port: 9090\n", func(e watchEvent) bool { return e.value.Port == 9090 })
+	if event.err != nil || event.value.Workers != 4 {
+		t.Errorf("unexpected event: %+v", event)
+	}
+
+	event = next("port: 9091\nunknown: 1\n", func(e watchEvent) bool { return e.value.Port == 9091 })
+	if event.err == nil {
+		t.Errorf("expected validation error with reloaded value: %+v", event)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch did not return after cancel")
+	}
+
+	if err := manager.Watch(context.Background(), nil); err == nil {
+		t.Error("expected error for nil callback")
+	}
+}
@@ -0,0 +1,30 @@
+package yamlc
+
+import "testing"
+
+func TestGetDisplayWidth(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected int
+	}{
+		{"hello", 5},
+		{"张三", 4},
+		{"中关村大街1号", 13},
+		{"ｈｅｌｌｏ", 10},       // 全角字母
+		{"한국어", 6},          // 韩文音节
+		{"e\u0301", 1},      // e + 组合重音符
+		{"😀", 2},            // emoji
+		{"❤", 1},            // 文本默认宽度的符号
+		{"\u2764\ufe0f", 2}, // 加VS16后以emoji显示
+		{"👍🏽", 2},           // 肤色修饰符
+		{"\U0001f468\u200d\U0001f469\u200d\U0001f467", 2}, // ZWJ家庭序列
+		{"🇨🇳", 2},       // 国旗
+		{"a\u200bb", 2}, // 零宽空格
+	}
+
+	for _, tc := range testCases {
+		if got := getDisplayWidth(tc.input); got != tc.expected {
+			t.Errorf("getDisplayWidth(%q) = %d, expected %d", tc.input, got, tc.expected)
+		}
+	}
+}
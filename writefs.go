@@ -0,0 +1,104 @@
+package yamlc
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"reflect"
+)
+
+// WriteFileFS 支持写入整个文件的文件系统，如测试中使用的内存文件系统
+type WriteFileFS interface {
+	fs.FS
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+}
+
+// CreateFS 支持创建文件的文件系统
+type CreateFS interface {
+	fs.FS
+	Create(name string) (io.WriteCloser, error)
+}
+
+var (
+	stringType = reflect.TypeOf("")
+	errorType  = reflect.TypeOf((*error)(nil)).Elem()
+	writerType = reflect.TypeOf((*io.Writer)(nil)).Elem()
+)
+
+// WriteFS 使用默认配置将YAML写入文件系统
+func WriteFS(fsys fs.FS, name string, v interface{}, opts ...Option) error {
+	return Default().WriteFS(fsys, name, v, opts...)
+}
+
+// WriteFS 按该配置生成YAML并写入 fsys 中的 name，便于嵌入式或内存文件系统和测试不接触真实的磁盘
+// fsys 需要实现 WriteFileFS 或 CreateFS；其 Create 方法返回具体类型（如 afero.NewIOFS 包装的 afero.File）时也可使用。
+// 文件已存在时按 WithOverwritePolicy 的策略处理
+func (c *Config) WriteFS(fsys fs.FS, name string, v interface{}, opts ...Option) error {
+	if fsys == nil {
+		return fmt.Errorf("filesystem cannot be nil")
+	}
+	if !fs.ValidPath(name) {
+		return fmt.Errorf("invalid file name %q", name)
+	}
+
+	options := c.newOptions(opts...)
+	readFile := func(name string) ([]byte, error) { return fs.ReadFile(fsys, name) }
+	if write, err := shouldWrite(name, readFile, options); !write || err != nil {
+		return err
+	}
+	data, err := generate(v, options)
+	if err != nil {
+		return err
+	}
+	data = fileContent(data, options)
+
+	switch target := fsys.(type) {
+	case WriteFileFS:
+		if err := target.WriteFile(name, data, 0644); err != nil {
+			return fmt.Errorf("failed to write file %q: %w", name, err)
+		}
+		return nil
+	case CreateFS:
+		file, err := target.Create(name)
+		if err != nil {
+			return fmt.Errorf("failed to create file %q: %w", name, err)
+		}
+		return writeAndClose(file, data, name)
+	}
+
+	file, err := createReflect(fsys, name)
+	if err != nil {
+		return err
+	}
+	return writeAndClose(file, data, name)
+}
+
+// createReflect 调用签名为 Create(string) (T, error) 的方法，T 需实现 io.Writer
+func createReflect(fsys fs.FS, name string) (io.Writer, error) {
+	method := reflect.ValueOf(fsys).MethodByName("Create")
+	if !method.IsValid() {
+		return nil, fmt.Errorf("filesystem %T does not support writing", fsys)
+	}
+	typ := method.Type()
+	if typ.NumIn() != 1 || typ.In(0) != stringType || typ.NumOut() != 2 ||
+		!typ.Out(0).Implements(writerType) || typ.Out(1) != errorType {
+		return nil, fmt.Errorf("filesystem %T does not support writing", fsys)
+	}
+
+	results := method.Call([]reflect.Value{reflect.ValueOf(name)})
+	if err, _ := results[1].Interface().(error); err != nil {
+		return nil, fmt.Errorf("failed to create file %q: %w", name, err)
+	}
+	return results[0].Interface().(io.Writer), nil
+}
+
+// writeAndClose 写入数据后关闭文件（如果可关闭）
+func writeAndClose(w io.Writer, data []byte, name string) error {
+	err := writeData(w, data)
+	if closer, ok := w.(io.Closer); ok {
+		if closeErr := closer.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to close file %q: %w", name, closeErr)
+		}
+	}
+	return err
+}
@@ -0,0 +1,79 @@
+package yamlc
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+// memFS 支持 WriteFile 的内存文件系统
+type memFS struct {
+	fstest.MapFS
+}
+
+func (m memFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	m.MapFS[name] = &fstest.MapFile{Data: data, Mode: perm}
+	return nil
+}
+
+// memFile Create 返回的具体文件类型
+type memFile struct {
+	bytes.Buffer
+	name   string
+	fsys   fstest.MapFS
+	closed bool
+}
+
+func (f *memFile) Close() error {
+	f.fsys[f.name] = &fstest.MapFile{Data: f.Bytes()}
+	f.closed = true
+	return nil
+}
+
+// createFS Create 方法返回具体类型的文件系统（类似 afero.NewIOFS）
+type createFS struct {
+	fstest.MapFS
+	last *memFile
+}
+
+func (c *createFS) Create(name string) (*memFile, error) {
+	c.last = &memFile{name: name, fsys: c.MapFS}
+	return c.last, nil
+}
+
+func TestWriteFS(t *testing.T) {
+	server := configServer{Host: "localhost", Port: 8080}
+	expected, err := Gen(server)
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+
+	mem := memFS{fstest.MapFS{}}
+	if err := WriteFS(mem, "conf/server.yaml", server); err != nil {
+		t.Fatalf("WriteFS failed: %v", err)
+	}
+	if data, err := fs.ReadFile(mem, "conf/server.yaml"); err != nil || !bytes.Equal(data, expected) {
+		t.Errorf("unexpected content: %v\n%s", err, data)
+	}
+	if err := WriteFS(mem, "conf/server.yaml", server, WithOverwritePolicy(OverwriteNever)); !errors.Is(err, os.ErrExist) {
+		t.Errorf("expected os.ErrExist, got %v", err)
+	}
+
+	created := &createFS{MapFS: fstest.MapFS{}}
+	if err := WriteFS(created, "server.yaml", server); err != nil {
+		t.Fatalf("WriteFS failed: %v", err)
+	}
+	if !created.last.closed || !bytes.Equal(created.MapFS["server.yaml"].Data, expected) {
+		t.Errorf("expected file to be written and closed")
+	}
+
+	if err := WriteFS(fstest.MapFS{}, "server.yaml", server); err == nil {
+		t.Error("expected error for read-only filesystem")
+	}
+	if err := WriteFS(mem, "../server.yaml", server); err == nil {
+		t.Error("expected error for invalid path")
+	}
+}
@@ -4,8 +4,9 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"os"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -39,17 +40,22 @@ const (
 	StyleDoc
 	// StyleSeparate 分离风格：注释和值分离，所有注释在前面，值在后面
 	StyleSeparate
+	// StyleAnnotated 标注风格：每个字段上方输出多行元数据注释（类型、默认值、可选值、环境变量、是否必填）
+	StyleAnnotated
+	// StyleCommentedDefaults 注释默认值风格：取默认值的字段整行注释（如 "# workers: 4"），修改过的字段正常输出
+	StyleCommentedDefaults
+	// StyleHelmDocs helm-docs风格：注释以 "# -- " 开头写在字段上方，供Helm chart文档工具读取
+	StyleHelmDocs
 )
 
-// GlobalCommentStyle 全局注释风格设置
+// GlobalCommentStyle 没有设置风格的配置所使用的注释风格
+//
+// Deprecated: 直接修改该变量与并发生成存在数据竞争，请使用 SetGlobalStyle 或为每个场景创建各自的 Config
 var GlobalCommentStyle = StyleTop
 
-func init() {
-	GlobalCommentStyle = StyleTop
-}
-
+// GetStyle 获取默认配置的注释风格
 func GetStyle() CommentStyle {
-	return GlobalCommentStyle
+	return Default().Style()
 }
 
 func GetAllStyle() []CommentStyle {
@@ -65,6 +71,9 @@ func GetAllStyle() []CommentStyle {
 		StyleSectioned,
 		StyleDoc,
 		StyleSeparate,
+		StyleAnnotated,
+		StyleCommentedDefaults,
+		StyleHelmDocs,
 	}
 }
 
@@ -92,15 +101,164 @@ func GetStyleString(style int) string {
 		return "doc"
 	case 10:
 		return "separate"
+	case 11:
+		return "annotated"
+	case 12:
+		return "commented-defaults"
+	case 13:
+		return "helm-docs"
+	}
+	if registered, ok := lookupStyle(CommentStyle(style)); ok {
+		return registered.name
 	}
 	return "smart"
 }
 
+// DefaultIndent 默认每级缩进的空格数
+const DefaultIndent = 2
+
 type Option func(*Options)
 
 type Options struct {
-	Style    CommentStyle
+	Style CommentStyle
+	// Comments 直接设置的注释映射，优先级低于 CommentLayers，后面的映射优先
 	Comments []map[string]string
+	// CommentLayers 按优先级从高到低排列的注释层，由 WithComment 和 WithCommentsLayer 添加
+	CommentLayers []CommentLayer
+	// CommentMerge 选项中的注释与标签注释同时存在时的合并方式
+	CommentMerge CommentMergePolicy
+	// SecretPlaceholder 敏感字段的替换文本，默认为 DefaultSecretPlaceholder
+	SecretPlaceholder string
+	// Encrypter 敏感字段的加密器，设置后输出密文而不是占位符
+	Encrypter Encrypter
+	// Decrypter 敏感字段的解密器，Unmarshal 时使用
+	Decrypter Decrypter
+	// StyleOverrides 按字段路径通配符覆盖注释风格，后添加的优先
+	StyleOverrides []StyleOverride
+	// TypeComments 按Go类型名设置的结构体头部注释，优先于 TypeCommenter
+	TypeComments map[string]string
+	// Header 文档顶部的注释行
+	Header []string
+	// Footer 文档底部的注释行
+	Footer []string
+	// GeneratedBanner 是否在文档顶部输出 "Generated by yamlc" 横幅
+	GeneratedBanner bool
+	// Reproducible 可复现模式，横幅中不包含时间戳，便于稳定diff
+	Reproducible bool
+	// DocumentStart 是否在文档开头输出 "---"
+	DocumentStart bool
+	// DocumentEnd 是否在文档末尾输出 "..."
+	DocumentEnd bool
+	// ChecksumFooter 是否在文档末尾追加内容的校验和注释
+	ChecksumFooter bool
+	// YAMLVersion 非空时在文档开头输出 "%YAML <版本>" 指令
+	YAMLVersion string
+	// Indent 每级缩进的空格数，0表示使用 DefaultIndent
+	Indent int
+	// CommentColumn 行尾注释对齐的显示列，0表示按风格默认对齐
+	CommentColumn int
+	// FieldOrder 字段输出顺序
+	FieldOrder FieldOrder
+	// LongStringStyle 超长单行字符串的输出方式
+	LongStringStyle LongStringStyle
+	// FoldWidth 折叠块换行的目标宽度（含缩进），0表示使用 DefaultFoldWidth
+	FoldWidth int
+	// FlowThreshold 元素个数少于该值的简单切片使用流式风格，0表示不使用
+	FlowThreshold int
+	// QuoteStyle 字符串的引号风格
+	QuoteStyle QuoteStyle
+	// Compatibility 输出兼容的YAML版本
+	Compatibility Compatibility
+	// SpecialFloats 是否将 NaN/±Inf 输出为 .nan/.inf/-.inf，默认返回错误
+	SpecialFloats bool
+	// FloatFormat 浮点数的格式化动词，为空时使用默认精度
+	FloatFormat string
+	// NullStyle 空值的表示方式
+	NullStyle NullStyle
+	// NilPointers 结构体中nil指针字段的处理方式
+	NilPointers NilPointerPolicy
+	// Include 只输出匹配这些通配符的字段，为空时输出全部字段
+	Include []string
+	// Exclude 不输出匹配这些通配符的字段
+	Exclude []string
+	// Audience 输出面向的受众，为空时输出全部字段
+	Audience string
+	// RequiredOnly 只输出必填字段及其上级字段
+	RequiredOnly bool
+	// UnsupportedKinds 通道、函数等无法表示为YAML的类型的处理方式
+	UnsupportedKinds UnsupportedPolicy
+	// MaxDepth 最大嵌套层级，0表示不限制
+	MaxDepth int
+	// MaxItems 每个列表和映射最多输出的元素个数，0表示不限制
+	MaxItems int
+	// ValueTransformer 输出前对标量值进行转换，为nil时原样输出
+	ValueTransformer ValueTransformer
+	// BeforeField 字段输出之前调用的钩子
+	BeforeField FieldHook
+	// AfterField 字段输出之后调用的钩子
+	AfterField FieldHook
+	// StylePerDepth 按嵌套层级指定的注释风格
+	StylePerDepth map[int]CommentStyle
+	// Kubernetes 按Kubernetes清单的规范顺序输出键
+	Kubernetes bool
+	// BlankLines 字段之间空行的策略，默认由注释风格决定
+	BlankLines BlankLinePolicy
+	// ValueAlignment 是否将同一映射内的值对齐到同一列
+	ValueAlignment bool
+	// Warnings 非nil时追加生成过程中的非致命警告
+	Warnings *[]Warning
+	// warned 本次生成已记录的警告（类别和路径），用于去重，复制出的选项共用同一记录
+	warned map[string]struct{}
+	// Logger 记录生成决策的日志，为nil时不记录
+	Logger Logger
+	// RequireComments 要求每个输出的结构体字段都有注释
+	RequireComments bool
+	// Backup 非空时 WriteFileAtomic 覆盖文件前将原文件保存为 文件名+Backup
+	Backup string
+	// Overwrite 写入文件时目标文件已存在的处理策略
+	Overwrite OverwritePolicy
+	// Color Fprint 是否使用ANSI颜色，默认在终端中使用
+	Color ColorMode
+	// SourceMap 非nil时写入各字段路径在输出中的行范围
+	SourceMap map[string]LineRange
+	// SliceComments 结构体列表中各元素字段注释的输出方式
+	SliceComments SliceCommentMode
+	// Placeholders 是否将必填但为空的字符串字段输出为占位文本
+	Placeholders bool
+	// VariableRefs 是否将带 var=/env= 标签的标量字段输出为 ${变量名}
+	VariableRefs bool
+	// Profile 生成配置所用的环境，带 defaults 标签的字段取该环境的值
+	Profile string
+	// IncludeStyle 拆分到其他文件的字段的引用写法
+	IncludeStyle IncludeStyle
+	// IncludeFiles 按字段路径指定拆分到其他文件的字段
+	IncludeFiles map[string]string
+
+	redactAll bool
+	// revealSecrets 敏感字段输出原值，Diff 比较时使用
+	revealSecrets bool
+	// blockStyle 多行字符串的块标量风格，由字段的 literal/folded 标签指定
+	blockStyle BlockStyle
+	// indexedOnly 处于不输出通用注释的列表元素内，只保留按下标路径指定的注释
+	indexedOnly bool
+	// valueFormat 整数进制或字节切片编码，由字段的 format= 标签指定
+	valueFormat string
+	// skeleton 当前字段为骨架策略下的nil结构体指针
+	skeleton bool
+	// scaffold 脚手架模式，非必填字段整段注释输出
+	scaffold bool
+	// depth 当前所在的容器层级，用于 MaxDepth
+	depth int
+	// level 当前结构体或映射的嵌套层级，用于 StylePerDepth 和 FieldInfo.Depth
+	level int
+	// styleFixed 子树已由字段级覆盖指定风格，不再按层级切换
+	styleFixed bool
+	// diffFriendly 行尾注释与内容之间只保留一个空格，不做对齐
+	diffFriendly bool
+	// typeNames 动态生成的类型在注释中显示的名称，由 Restyle 设置
+	typeNames map[reflect.Type]string
+	// rootPath 生成的值在完整文档中的路径，写入拆分文件时用于按完整路径查找注释
+	rootPath string
 }
 
 func WithStyle(style CommentStyle) Option {
@@ -109,84 +267,211 @@ func WithStyle(style CommentStyle) Option {
 	}
 }
 
+// FieldOrder 字段输出顺序
+type FieldOrder int
+
+const (
+	// OrderDeclared 按结构体声明顺序（默认）
+	OrderDeclared FieldOrder = iota
+	// OrderAlphabetical 按字段名字母顺序
+	OrderAlphabetical
+	// OrderWeight 按 order=/weight= 标签的数值从小到大，未设置的视为0，相同数值保持声明顺序
+	OrderWeight
+)
+
+// WithFieldOrder 设置字段输出顺序
+func WithFieldOrder(order FieldOrder) Option {
+	return func(o *Options) {
+		o.FieldOrder = order
+	}
+}
+
+// WithIndent 设置每级缩进的空格数
+func WithIndent(n int) Option {
+	return func(o *Options) {
+		o.Indent = n
+	}
+}
+
+// WithComment 按字段路径设置注释，等同于优先级为0的注释层，后添加的优先
 func WithComment(comments map[string]string) Option {
+	return WithCommentsLayer(0, comments)
+}
+
+// StyleOverride 字段路径通配符与对应的注释风格
+type StyleOverride struct {
+	Pattern string
+	Style   CommentStyle
+}
+
+// WithFieldStyleOverride 为匹配路径通配符的字段（及其子树）指定注释风格
+// 通配符按 "." 分段匹配："*" 匹配一段，"**" 匹配任意多段，如 "database.*"
+func WithFieldStyleOverride(pathGlob string, style CommentStyle) Option {
 	return func(o *Options) {
-		o.Comments = append(o.Comments, comments)
+		o.StyleOverrides = append(o.StyleOverrides, StyleOverride{Pattern: pathGlob, Style: style})
 	}
 }
 
-// FieldInfo 字段信息结构
+// FieldInfo 字段信息结构，是传给 FieldHook、Style（StyleFunc）和 ValueTransformer 等扩展点的稳定类型
+// 已有字段的含义在后续版本中保持不变，新增信息只以新字段的形式加入；映射的键值对同样以 FieldInfo 表示，
+// 此时 FieldType 只有 Name 和 Type，标签相关的字段为空
 type FieldInfo struct {
-	Name        string
-	Comment     string
-	Field       reflect.Value
-	FieldType   reflect.StructField
+	// Name 输出的键名
+	Name string
+	// Comment 输出的注释，已包含敏感、加密、占位等标注
+	Comment string
+	// Field 输出的值，可能已被占位符、引用等替换
+	Field reflect.Value
+	// FieldType 结构体字段定义，包含全部标签
+	FieldType reflect.StructField
+	// HasChildren 值是否在后续行中展开输出子字段或元素
 	HasChildren bool
-	FieldPath   string
+	// FieldPath 字段路径，如 "database.host"，列表元素以下标表示
+	FieldPath string
+	// Example 示例值（yamlc标签中的example=）
+	Example string
+	// Style 字段级风格覆盖，作用于字段本身及其子树，为nil时沿用文档风格
+	Style *CommentStyle
+	// Section 分节标题，在字段上方输出分节横幅
+	Section string
+	// IsRequired 字段是否必填：设置了 required 标签，或是包含必填子字段的结构体
+	IsRequired bool
+	// Default 默认值说明（yamlc标签中的default=，或独立的default标签），未设置时为空
+	Default string
+	// Enum 可选值（yamlc标签中的enum=），未设置时为nil；与其他生成共享，不应修改
+	Enum []string
+	// EnvVar 对应的环境变量名（yamlc标签中的env=，或独立的env标签），未设置时为空
+	EnvVar string
+	// Depth 嵌套层级，0表示顶层字段，每进入一层结构体或映射加1（列表不计层级），与 WithStylePerDepth 的层级一致
+	Depth int
+	// Parent 上级字段的路径，顶层字段为空
+	Parent string
+
+	// secret 值已被替换为敏感占位符
+	secret bool
+	// encrypted 敏感字段保留原值，生成时替换为密文
+	encrypted bool
+	// substituted 值已被替换为文件引用、变量引用或必填字段的占位文本
+	substituted bool
+	// atDefault 注释默认值风格下字段取默认值，整行注释输出
+	atDefault bool
+}
+
+// newOptions 以默认配置构建选项
+func newOptions(opts ...Option) *Options {
+	return Default().newOptions(opts...)
+}
+
+// Gen 按默认配置生成YAML内容
+func Gen(v interface{}, opts ...Option) ([]byte, error) {
+	return Default().Gen(v, opts...)
 }
 
-// Gen 生成YAML内容
-func Gen(v interface{}, opts ...Option) ([]byte, error) {
-	options := &Options{
-		Style:    GlobalCommentStyle,
-		Comments: make([]map[string]string, 0),
+// generate 按已构建的选项生成YAML内容，选项在生成过程中只读，可在多次生成间复用
+// 所有风格都先检查输入和风格：输入为nil（或nil指针）时返回 ErrNilInput，风格无效时返回 ErrInvalidStyle
+func generate(v interface{}, options *Options) ([]byte, error) {
+	if v == nil {
+		return nil, ErrNilInput
 	}
-
-	for _, opt := range opts {
-		opt(options)
+	if val := reflect.ValueOf(v); val.Kind() == reflect.Ptr && val.IsNil() {
+		return nil, fmt.Errorf("%w: got nil %T", ErrNilInput, v)
 	}
-
-	if v == nil {
-		return nil, fmt.Errorf("input value cannot be nil")
+	if _, ok := lookupStyle(options.Style); !ok {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidStyle, options.Style)
+	}
+	if options.Profile != "" {
+		profiled, err := applyProfile(v, options.Profile)
+		if err != nil {
+			return nil, err
+		}
+		v = profiled
+	}
+	if options.RequireComments {
+		if err := checkRequiredComments(v, options); err != nil {
+			return nil, err
+		}
 	}
 
 	var result []byte
-	if options.Style == StyleMinimal {
-		yamlData, err := generateMinimalStyleField(v)
+	if options.Style == StyleMinimal && !options.scaffold {
+		yamlData, err := generateMinimalStyleField(v, options)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate YAML content: %w", err)
 		}
+		if options.ValueAlignment {
+			yamlData = alignValues(yamlData)
+		}
 		result = []byte(yamlData)
 	} else {
 
 		val := reflect.ValueOf(v)
 		if val.Kind() == reflect.Ptr {
-			if val.IsNil() {
-				return nil, fmt.Errorf("input pointer cannot be nil")
-			}
 			val = val.Elem()
 		}
 
 		var buf bytes.Buffer
 
-		content, err := generateValue(val, "", 0, options)
+		content, err := generateValue(val, options.rootPath, 0, options)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate YAML content: %w", err)
 		}
+		// 空结构体和映射按字段值生成时带有前导空格，顶层输出时去掉
+		if strings.TrimSpace(content) == "{}" {
+			content = "{}\n"
+		}
+
+		// 顶层列表与字段中的列表一样，把元素内的注释提到 "-" 之前
+		if isListValue(val) {
+			content = hoistElementComments(content, "")
+		}
 
+		// 先对齐值再对齐注释，注释列以对齐后的内容为准
+		if options.ValueAlignment {
+			content = alignValues(content)
+		}
+		if options.diffFriendly {
+			content = alignTrailingComments(content, 0)
+		} else if options.CommentColumn > 0 {
+			content = alignTrailingComments(content, options.CommentColumn)
+		} else if usesStyle(options, StyleInline, StyleSmart) {
+			// 行内注释在整个文档范围内对齐，避免嵌套层级的注释列参差不齐
+			content = alignTrailingComments(content, documentCommentColumn(content))
+		}
+		if options.NullStyle == NullEmpty {
+			content = trimTrailingSpaces(content)
+		}
 		buf.WriteString(content)
 
 		result = buf.Bytes()
 	}
+	result = []byte(applyBlankLines(string(result), options.BlankLines))
+
+	result = decorateDocument(result, v, options)
+
 	// 严格的YAML格式验证
 	if err := ValidateYAML(result); err != nil {
 		return nil, fmt.Errorf("generated YAML validation failed: %w", err)
 	}
 
+	if err := recordSourceMap(result, documentPrefixLines(options), options); err != nil {
+		return nil, err
+	}
+
+	// yaml.v3 只接受 %YAML 1.1 指令，因此在验证之后再添加指令和文档标记
+	result = markDocument(result, options)
+	if options.ChecksumFooter {
+		result = appendChecksum(result)
+	}
 	return result, nil
 }
 
-// Write 写入到io.Writer
+// Write 按默认配置写入到io.Writer
 func Write(w io.Writer, v interface{}, opts ...Option) error {
-	if w == nil {
-		return fmt.Errorf("writer cannot be nil")
-	}
-
-	data, err := Gen(v, opts...)
-	if err != nil {
-		return err
-	}
+	return Default().Write(w, v, opts...)
+}
 
+// writeData 将生成的内容完整写入io.Writer
+func writeData(w io.Writer, data []byte) error {
 	n, err := w.Write(data)
 	if err != nil {
 		return fmt.Errorf("failed to write data: %w", err)
@@ -198,19 +483,9 @@ func Write(w io.Writer, v interface{}, opts ...Option) error {
 	return nil
 }
 
-// WriteFile 写入到文件
+// WriteFile 按默认配置写入到文件
 func WriteFile(filename string, v interface{}, opts ...Option) error {
-	if filename == "" {
-		return fmt.Errorf("filename cannot be empty")
-	}
-
-	file, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("failed to create file %q: %w", filename, err)
-	}
-	defer file.Close()
-
-	return Write(file, v, opts...)
+	return Default().WriteFile(filename, v, opts...)
 }
 
 // ValidateYAML 使用yaml.v3进行严格的YAML格式验证
@@ -227,43 +502,47 @@ func ValidateYAML(data []byte) error {
 	return nil
 }
 
-// ValidateStructure 验证YAML结构的完整性
+// ValidateStructure 验证YAML结构的完整性（按2空格缩进）
 func ValidateStructure(data []byte) error {
+	return ValidateStructureIndent(data, DefaultIndent)
+}
+
+// ValidateStructureIndent 按指定的缩进宽度验证YAML结构的完整性
+func ValidateStructureIndent(data []byte, width int) error {
+	if width <= 0 {
+		return fmt.Errorf("invalid indent width: %d", width)
+	}
 	lines := strings.Split(string(data), "\n")
+	inBlock := blockContentLines(lines)
 	var indentStack []int
 
 	for i, line := range lines {
 		lineNum := i + 1
 		trimmed := strings.TrimSpace(line)
 
-		// 跳过空行和注释
-		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		// 跳过空行、注释和块标量内容
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || inBlock[i] {
 			continue
 		}
 
 		// 计算缩进
 		indent := len(line) - len(strings.TrimLeft(line, " "))
 
-		// 验证缩进是否为偶数（YAML标准）
-		if indent%2 != 0 {
-			return fmt.Errorf("invalid indentation at line %d: indentation must be even number of spaces", lineNum)
+		// 验证缩进是否为缩进宽度的整数倍
+		if indent%width != 0 {
+			return fmt.Errorf("invalid indentation at line %d: indentation must be a multiple of %d spaces", lineNum, width)
 		}
 
 		// 验证缩进层级的一致性
 		if len(indentStack) == 0 {
 			indentStack = append(indentStack, indent)
 		} else {
-			currentLevel := indent / 2
+			currentLevel := indent / width
 			if currentLevel > len(indentStack) {
 				return fmt.Errorf("invalid indentation jump at line %d: too many levels", lineNum)
 			}
-			// 调整堆栈
-			indentStack = indentStack[:currentLevel+1]
-			if currentLevel < len(indentStack) {
-				indentStack[currentLevel] = indent
-			} else {
-				indentStack = append(indentStack, indent)
-			}
+			// 调整堆栈：弹出更深的层级，再压入当前层级
+			indentStack = append(indentStack[:currentLevel], indent)
 		}
 
 		// 验证键值对格式
@@ -316,16 +595,23 @@ func Validate(v []byte) error {
 // generateValue 递归生成YAML值
 func generateValue(val reflect.Value, fieldPath string, indent int, options *Options) (string, error) {
 	if !val.IsValid() {
-		return "null", nil
+		return nullValue(options), nil
+	}
+	if truncated, ok := truncateDepth(val, fieldPath, options); ok {
+		return truncated, nil
+	}
+	if isRawType(val.Type()) {
+		return generateRaw(val, fieldPath, indent, options)
 	}
 
 	switch val.Kind() {
-	case reflect.Struct:
-		return generateStruct(val, fieldPath, indent, options)
-	case reflect.Map:
-		return generateMap(val, fieldPath, indent, options)
+	case reflect.Struct, reflect.Map:
+		return buildValue(val, fieldPath, indent, options)
 	case reflect.Slice, reflect.Array:
-		return generateSlice(val, fieldPath, indent, options)
+		if isByteSlice(val) {
+			return generateBytes(val, fieldPath, indent, options)
+		}
+		return buildValue(val, fieldPath, indent, options)
 	case reflect.String:
 		return generateString(val, fieldPath, indent, options)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
@@ -338,198 +624,503 @@ func generateValue(val reflect.Value, fieldPath string, indent int, options *Opt
 		return generateBool(val, fieldPath, indent, options)
 	case reflect.Ptr:
 		if val.IsNil() {
-			return "null", nil
+			if options.skeleton && val.Type().Elem().Kind() == reflect.Struct {
+				return generateSkeleton(val.Type().Elem(), fieldPath, indent, options)
+			}
+			return nullValue(options), nil
 		}
 		return generateValue(val.Elem(), fieldPath, indent, options)
 	case reflect.Interface:
 		if val.IsNil() {
-			return "null", nil
+			return nullValue(options), nil
 		}
 		return generateValue(val.Elem(), fieldPath, indent, options)
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Complex64, reflect.Complex128:
+		return generateUnsupported(val, fieldPath, options)
 	default:
 		if val.CanInterface() {
 			return fmt.Sprintf("%v", val.Interface()), nil
 		}
-		return "null", nil
+		return nullValue(options), nil
+	}
+}
+
+// buildValue 将结构体、映射或列表生成到新的缓冲区，供需要字符串结果的调用方使用
+func buildValue(val reflect.Value, fieldPath string, indent int, options *Options) (string, error) {
+	var result strings.Builder
+	if err := writeValue(&result, val, fieldPath, indent, options); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+// writeValue 递归生成YAML值并写入 result，结构体、映射和列表逐层写入同一缓冲区，不经过中间字符串
+func writeValue(result *strings.Builder, val reflect.Value, fieldPath string, indent int, options *Options) error {
+	if val.IsValid() && !exceedsDepth(val, options) && !isRawType(val.Type()) {
+		switch val.Kind() {
+		case reflect.Struct:
+			return writeStruct(result, val, fieldPath, indent, descend(options))
+		case reflect.Map:
+			return writeMap(result, val, fieldPath, indent, descend(options))
+		case reflect.Slice, reflect.Array:
+			if !isByteSlice(val) {
+				return writeSlice(result, val, fieldPath, indent, descend(options))
+			}
+		case reflect.Ptr, reflect.Interface:
+			if !val.IsNil() {
+				return writeValue(result, val.Elem(), fieldPath, indent, options)
+			}
+		}
+	}
+
+	value, err := generateValue(val, fieldPath, indent, options)
+	if err != nil {
+		return err
 	}
+	result.WriteString(value)
+	return nil
 }
 
 // generateStruct 生成结构体YAML
 func generateStruct(val reflect.Value, fieldPath string, indent int, options *Options) (string, error) {
+	var result strings.Builder
+	if err := writeStruct(&result, val, fieldPath, indent, options); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+// writeStruct 生成结构体YAML并写入 result
+func writeStruct(result *strings.Builder, val reflect.Value, fieldPath string, indent int, options *Options) error {
+	options = levelOptions(options)
 	typ := val.Type()
 	fields := collectFieldInfo(val, typ, fieldPath, options)
+	markDefaultFields(val, fields, options)
+	if err := transformFields(fields, options); err != nil {
+		return err
+	}
+	if err := encryptFields(fields, options); err != nil {
+		return err
+	}
 
 	if len(fields) == 0 {
-		return " {}\n", nil
+		result.WriteString(" {}\n")
+		return nil
 	}
 
-	var result string
-	var err error
-
-	switch options.Style {
-	case StyleDoc:
-		result, err = generateStructDoc(fields, indent, options)
-	case StyleSeparate:
-		result, err = generateStructSeparate(fields, indent, options)
-	case StyleSectioned:
-		result, err = generateStructSectioned(fields, indent, options)
-	default:
-		result, err = generateStructDefault(fields, indent, options)
+	if typeComment := getTypeComment(val, options); typeComment != "" && options.Style != StyleMinimal && !options.indexedOnly {
+		result.WriteString(formatCommentLines(typeComment, getIndentStr(indent, options)))
 	}
+	if err := renderFields(result, fields, indent, options); err != nil {
+		return err
+	}
+	result.WriteString("\n")
+	return nil
+}
 
+// renderFields 按注释风格渲染同一映射下的字段并写入 result，内置风格直接写入，自定义风格写入其返回的内容
+func renderFields(result *strings.Builder, fields []FieldInfo, indent int, options *Options) error {
+	registered, ok := lookupStyle(options.Style)
+	if !ok {
+		return generateStructDefault(result, fields, indent, options)
+	}
+	if render, ok := registered.style.(writerStyle); ok {
+		return render(result, fields, indent, options)
+	}
+	content, err := registered.style.RenderStruct(fields, RenderContext{Indent: indent, Options: options})
 	if err != nil {
-		return "", err
+		return err
 	}
+	result.WriteString(content)
+	return nil
+}
 
-	result = result + "\n"
+// TypeCommenter 由结构体类型实现，为整个映射节点提供头部注释
+type TypeCommenter interface {
+	TypeComment() string
+}
 
-	return result, nil
+// WithTypeComment 按Go类型名（如 "Address" 或 "config.Address"）设置结构体头部注释
+func WithTypeComment(comments map[string]string) Option {
+	return func(o *Options) {
+		if o.TypeComments == nil {
+			o.TypeComments = make(map[string]string)
+		}
+		for typeName, comment := range comments {
+			o.TypeComments[typeName] = comment
+		}
+	}
+}
+
+// getTypeComment 获取结构体的头部注释
+func getTypeComment(val reflect.Value, options *Options) string {
+	typ := val.Type()
+	if comment, ok := options.TypeComments[typ.String()]; ok {
+		return comment
+	}
+	if comment, ok := options.TypeComments[typ.Name()]; ok {
+		return comment
+	}
+
+	if val.CanInterface() {
+		if commenter, ok := val.Interface().(TypeCommenter); ok {
+			return commenter.TypeComment()
+		}
+	}
+	// 指针接收者实现的接口
+	if val.CanAddr() && val.Addr().CanInterface() {
+		if commenter, ok := val.Addr().Interface().(TypeCommenter); ok {
+			return commenter.TypeComment()
+		}
+	}
+	return ""
+}
+
+// formatCommentLines 将多行注释格式化为注释行，每行单独清理
+func formatCommentLines(comment string, indentStr string) string {
+	var result strings.Builder
+	for _, line := range strings.Split(comment, "\n") {
+		if line = sanitizeComment(line); line != "" {
+			result.WriteString(fmt.Sprintf("%s# %s\n", indentStr, line))
+		}
+	}
+	return result.String()
+}
+
+// applyIndexedOnly 非首个列表元素内，独占一行的通用注释已在首个元素上给出，
+// 只保留按下标路径指定的注释；行尾注释不占行，照常保留（SliceCommentsNone 时一并去掉）
+func applyIndexedOnly(info *FieldInfo, options *Options) {
+	if !options.indexedOnly {
+		return
+	}
+	info.Section = ""
+
+	style := options.Style
+	if info.Style != nil {
+		style = *info.Style
+	}
+	if isTrailingComment(style, info.HasChildren) && options.SliceComments != SliceCommentsNone {
+		return
+	}
+
+	info.Comment, info.Example = "", ""
+	if comment, ok := lookupIndexedComment(info.FieldPath, options); ok {
+		info.Comment = sanitizeComment(comment)
+	}
+}
+
+// isTrailingComment 判断该风格下字段注释是否写在行尾
+func isTrailingComment(style CommentStyle, hasChildren bool) bool {
+	switch style {
+	case StyleInline, StyleCompact:
+		return true
+	case StyleSmart:
+		return !hasChildren
+	}
+	return false
 }
 
 // collectFieldInfo 收集字段信息
 func collectFieldInfo(val reflect.Value, typ reflect.Type, fieldPath string, options *Options) []FieldInfo {
 	var fields []FieldInfo
 
-	for i := 0; i < val.NumField(); i++ {
-		field := val.Field(i)
-		fieldType := typ.Field(i)
+	for _, plan := range getTypePlan(typ) {
+		field := val.Field(plan.index)
+		fieldType := plan.fieldType
+		fieldName := plan.name
 
-		if !fieldType.IsExported() {
+		currentFieldPath := buildFieldPath(fieldPath, fieldName)
+		if (options.NilPointers == NilOmit && isNilPointer(field)) || isHiddenField(fieldType, options) {
 			continue
 		}
-
-		fieldName := getFieldName(fieldType)
-		if fieldName == "-" {
+		if skipUnsupported(field, currentFieldPath, options) {
 			continue
 		}
-
-		currentFieldPath := buildFieldPath(fieldPath, fieldName)
-		comment := getComment(fieldType, currentFieldPath, options)
-		hasChildren := hasChildren(field)
-
-		fields = append(fields, FieldInfo{
+		if isFieldFiltered(currentFieldPath, options) {
+			continue
+		}
+		comment := resolveFieldComment(plan, currentFieldPath, options)
+
+		// 拆分到其他文件的字段输出为引用；变量引用模式下输出 ${变量名}，不含真实值，敏感字段也无需屏蔽
+		file, isInclude := includeFile(fieldType, currentFieldPath, options)
+		ref, isRef := "", false
+		if !isInclude {
+			ref, isRef = variableRef(fieldType, field, options)
+		}
+		secret := !isInclude && !isRef && isSecretField(fieldType, fieldName, options)
+		placeholder, isPlaceholder := "", false
+		if !isInclude && !isRef && !secret {
+			placeholder, isPlaceholder = placeholderFor(fieldType, field, options)
+		}
+		switch {
+		case isInclude:
+			field = reflect.ValueOf(*includeNode(file, options))
+		case isRef:
+			field = reflect.ValueOf(ref)
+		case secret && isEncryptedField(fieldType, options):
+			// 加密的敏感字段：保留原值，由 encryptFields 替换为密文
+			comment = encryptedComment(comment)
+		case secret:
+			// 敏感字段：用占位符替换真实值，并在注释中标注
+			field = reflect.ValueOf(secretPlaceholder(options))
+			comment = secretComment(comment)
+		case isPlaceholder:
+			// 必填但为空的字符串字段：用占位文本代替空值，并在注释中提示替换
+			field = reflect.ValueOf(placeholder)
+			comment = placeholderComment(comment)
+		}
+		substituted := isInclude || isRef || isPlaceholder
+		// 骨架字段的子字段在后续行输出，超过最大层级的字段按标量输出
+		encrypted := secret && isEncryptedField(fieldType, options)
+		hasChildren := (hasChildren(field) || isSkeletonField(field, options)) && !exceedsDepth(field, options) && !encrypted
+
+		fieldStyle := getFieldStyle(fieldType, currentFieldPath, options)
+
+		info := FieldInfo{
 			Name:        fieldName,
 			Comment:     comment,
 			Field:       field,
 			FieldType:   fieldType,
 			HasChildren: hasChildren,
 			FieldPath:   currentFieldPath,
+			Example:     plan.example,
+			Style:       fieldStyle,
+			Section:     plan.section,
+			IsRequired:  plan.required,
+			Default:     plan.defaultValue,
+			Enum:        plan.enum,
+			EnvVar:      plan.envVar,
+			Depth:       options.level - 1,
+			Parent:      fieldPath,
+			secret:      secret,
+			encrypted:   encrypted,
+			substituted: substituted,
+		}
+		applyIndexedOnly(&info, options)
+		logField(info, plan.tagComment, options)
+		fields = append(fields, info)
+	}
+
+	sortFields(fields, options.FieldOrder)
+	sortKubernetesFields(fields, options.level-1, options)
+
+	return fields
+}
+
+// sortFields 按指定顺序对字段排序
+func sortFields(fields []FieldInfo, order FieldOrder) {
+	switch order {
+	case OrderAlphabetical:
+		sort.SliceStable(fields, func(i, j int) bool {
+			return fields[i].Name < fields[j].Name
+		})
+	case OrderWeight:
+		sort.SliceStable(fields, func(i, j int) bool {
+			return getFieldWeight(fields[i].FieldType) < getFieldWeight(fields[j].FieldType)
 		})
 	}
+}
 
-	return fields
+// orderNode 在yaml节点树上按字段顺序重排结构体对应的映射节点，depth 为映射的嵌套层级，顶层为0
+func orderNode(node *yaml.Node, val reflect.Value, depth int, options *Options) {
+	if options.FieldOrder == OrderDeclared && !options.Kubernetes {
+		return
+	}
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return
+		}
+		val = val.Elem()
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) > 0 {
+			orderNode(node.Content[0], val, depth, options)
+		}
+	case yaml.MappingNode:
+		type pair struct {
+			key, value *yaml.Node
+			field      FieldInfo
+		}
+		pairs := make([]pair, 0, len(node.Content)/2)
+		fields := make([]FieldInfo, 0, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			p := pair{key: node.Content[i], value: node.Content[i+1]}
+			p.field.Name = p.key.Value
+			switch val.Kind() {
+			case reflect.Struct:
+				if fieldType, field, ok := findYAMLField(val, p.key.Value); ok {
+					p.field.FieldType = fieldType
+					orderNode(p.value, field, depth+1, options)
+				}
+			case reflect.Map:
+				if val.Type().Key().Kind() == reflect.String {
+					orderNode(p.value, val.MapIndex(reflect.ValueOf(p.key.Value).Convert(val.Type().Key())), depth+1, options)
+				}
+			}
+			pairs = append(pairs, p)
+			fields = append(fields, p.field)
+		}
+		if val.Kind() != reflect.Struct {
+			return
+		}
+
+		// 借助字段排序规则得到新顺序
+		index := make(map[string]pair, len(pairs))
+		for _, p := range pairs {
+			index[p.key.Value] = p
+		}
+		sortFields(fields, options.FieldOrder)
+		sortKubernetesFields(fields, depth, options)
+		node.Content = node.Content[:0]
+		for _, field := range fields {
+			p := index[field.Name]
+			node.Content = append(node.Content, p.key, p.value)
+		}
+	case yaml.SequenceNode:
+		if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+			return
+		}
+		for i, item := range node.Content {
+			if i < val.Len() {
+				orderNode(item, val.Index(i), depth, options)
+			}
+		}
+	}
 }
 
-// generateStructDoc 生成文档风格的结构体
-func generateStructDoc(fields []FieldInfo, indent int, options *Options) (string, error) {
-	var result strings.Builder
-	indentStr := strings.Repeat("  ", indent)
+// getFieldWeight 获取字段的排序权重（yamlc标签中的order=或weight=）
+func getFieldWeight(field reflect.StructField) int {
+	for _, key := range []string{"order", "weight"} {
+		if value, ok := getTagValue(field, key); ok {
+			if weight, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+				return weight
+			}
+		}
+	}
+	return 0
+}
 
-	// 生成文档头部注释块
+// generateStructDoc 生成文档风格的结构体
+func generateStructDoc(result *strings.Builder, fields []FieldInfo, indent int, options *Options) error {
+	indentStr := getIndentStr(indent, options)
 
-	result.WriteString(fmt.Sprintf("%s############################################\n", indentStr))
+	// 生成文档头部注释块，没有任何注释时省略
+	var header strings.Builder
 	for _, field := range fields {
-		if field.Comment != "" {
-			typeStr := field.Field.Type().String()
-			result.WriteString(fmt.Sprintf("%s# %s(%s):%s\n", indentStr, field.Name, typeStr, field.Comment))
+		if comment := commentWithExample(field); comment != "" {
+			typeStr := fieldTypeString(field, options)
+			header.WriteString(fmt.Sprintf("%s# %s(%s):%s\n", indentStr, field.Name, typeStr, comment))
 		}
 		if field.HasChildren {
 			break
 		}
 	}
-	result.WriteString(fmt.Sprintf("%s###########################################\n\n", indentStr))
+	if header.Len() > 0 {
+		result.WriteString(fmt.Sprintf("%s############################################\n", indentStr))
+		result.WriteString(header.String())
+		result.WriteString(fmt.Sprintf("%s###########################################\n\n", indentStr))
+	}
 
 	// 生成字段
 	for i, field := range fields {
-
-		result.WriteString(fmt.Sprintf("%s%s: ", indentStr, field.Name))
+		runBeforeField(result, field, options)
+		result.WriteString(fieldKey(indentStr, field, options) + " ")
 
 		if field.HasChildren {
 			result.WriteString("\n")
-			fieldValue, err := generateValue(field.Field, field.FieldPath, indent+1, options)
+			fieldValue, err := generateValue(field.Field, field.FieldPath, indent+1, optionsForField(field, options))
 			if err != nil {
-				return "", err
+				return err
 			}
+			fieldValue = commentOutValue(fieldValue, field, options)
 			result.WriteString(fieldValue)
 		} else {
-			fieldValue, err := generateValue(field.Field, field.FieldPath, indent+1, options)
+			fieldValue, err := generateValue(field.Field, field.FieldPath, indent+1, optionsForField(field, options))
 			if err != nil {
-				return "", err
+				return err
 			}
-			result.WriteString(fieldValue)
+			fieldValue = commentOutValue(fieldValue, field, options)
+			result.WriteString(strings.TrimLeft(fieldValue, " "))
 		}
+		runAfterField(result, field, options)
 
 		if i < len(fields)-1 {
 			result.WriteString("\n")
 		}
 	}
 
-	return result.String(), nil
+	return nil
 }
 
 // generateStructSeparate 生成分离风格的结构体
-func generateStructSeparate(fields []FieldInfo, indent int, options *Options) (string, error) {
-	var result strings.Builder
+func generateStructSeparate(result *strings.Builder, fields []FieldInfo, indent int, options *Options) error {
 
 	// 如果是顶层，先生成所有注释
 	if indent == 0 {
 		result.WriteString("############################################\n")
-		generateAllComments(&result, fields, 0, "")
+		generateAllComments(result, fields, 0, "", options)
 		result.WriteString("###########################################\n\n")
 	}
 
 	// 生成字段值
-	indentStr := strings.Repeat("  ", indent)
+	indentStr := getIndentStr(indent, options)
 	for i, field := range fields {
-		result.WriteString(fmt.Sprintf("%s%s: ", indentStr, field.Name))
+		runBeforeField(result, field, options)
+		result.WriteString(fieldKey(indentStr, field, options) + " ")
 
 		if field.HasChildren {
 			result.WriteString("\n")
-			fieldValue, err := generateValue(field.Field, field.FieldPath, indent+1, options)
+			fieldValue, err := generateValue(field.Field, field.FieldPath, indent+1, optionsForField(field, options))
 			if err != nil {
-				return "", err
+				return err
 			}
+			fieldValue = commentOutValue(fieldValue, field, options)
 			result.WriteString(fieldValue)
 		} else {
-			fieldValue, err := generateValue(field.Field, field.FieldPath, indent+1, options)
+			fieldValue, err := generateValue(field.Field, field.FieldPath, indent+1, optionsForField(field, options))
 			if err != nil {
-				return "", err
+				return err
 			}
-			result.WriteString(fieldValue)
+			fieldValue = commentOutValue(fieldValue, field, options)
+			result.WriteString(strings.TrimLeft(fieldValue, " "))
 		}
+		runAfterField(result, field, options)
 
 		if i < len(fields)-1 {
 			result.WriteString("\n")
 		}
 	}
 
-	return result.String(), nil
+	return nil
 }
 
 // generateAllComments 递归生成所有注释
-func generateAllComments(result *strings.Builder, fields []FieldInfo, indent int, prefix string) {
+func generateAllComments(result *strings.Builder, fields []FieldInfo, indent int, prefix string, options *Options) {
 	// fmt.Println("generateAllComments", fields)
 	for _, field := range fields {
 		// if field.Comment != "" {
-		typeStr := field.Field.Type().String()
-		indentStr := strings.Repeat("  ", indent)
-		result.WriteString(fmt.Sprintf("# %s%s(%s):%s\n", indentStr, field.Name, typeStr, field.Comment))
+		typeStr := fieldTypeString(field, options)
+		indentStr := getIndentStr(indent, options)
+		result.WriteString(fmt.Sprintf("# %s%s(%s):%s\n", indentStr, field.Name, typeStr, commentWithExample(field)))
 		// }
 
 		// 如果有子结构，递归生成子注释
 		if field.HasChildren {
 			var subFields []FieldInfo
+			childOptions := descend(options)
 
 			switch field.Field.Kind() {
 			case reflect.Struct:
 				// 结构体类型，直接收集字段信息
-				subFields = collectFieldInfo(field.Field, field.Field.Type(), field.FieldPath, &Options{})
+				subFields = collectFieldInfo(field.Field, field.Field.Type(), field.FieldPath, childOptions)
 			case reflect.Ptr:
 				// 指针类型，解引用后收集字段信息
 				if !field.Field.IsNil() {
 					elem := field.Field.Elem()
 					if elem.Kind() == reflect.Struct {
-						subFields = collectFieldInfo(elem, elem.Type(), field.FieldPath, &Options{})
+						subFields = collectFieldInfo(elem, elem.Type(), field.FieldPath, childOptions)
 					}
 				}
 			case reflect.Slice, reflect.Array:
@@ -537,11 +1128,11 @@ func generateAllComments(result *strings.Builder, fields []FieldInfo, indent int
 				if field.Field.Len() > 0 {
 					firstItem := field.Field.Index(0)
 					if firstItem.Kind() == reflect.Struct {
-						subFields = collectFieldInfo(firstItem, firstItem.Type(), field.FieldPath+"[0]", &Options{})
+						subFields = collectFieldInfo(firstItem, firstItem.Type(), field.FieldPath+"[0]", childOptions)
 					} else if firstItem.Kind() == reflect.Ptr && !firstItem.IsNil() {
 						elem := firstItem.Elem()
 						if elem.Kind() == reflect.Struct {
-							subFields = collectFieldInfo(elem, elem.Type(), field.FieldPath+"[0]", &Options{})
+							subFields = collectFieldInfo(elem, elem.Type(), field.FieldPath+"[0]", childOptions)
 						}
 					}
 				}
@@ -552,11 +1143,11 @@ func generateAllComments(result *strings.Builder, fields []FieldInfo, indent int
 					if iter.Next() {
 						value := iter.Value()
 						if value.Kind() == reflect.Struct {
-							subFields = collectFieldInfo(value, value.Type(), field.FieldPath+"[key]", &Options{})
+							subFields = collectFieldInfo(value, value.Type(), field.FieldPath+"[key]", childOptions)
 						} else if value.Kind() == reflect.Ptr && !value.IsNil() {
 							elem := value.Elem()
 							if elem.Kind() == reflect.Struct {
-								subFields = collectFieldInfo(elem, elem.Type(), field.FieldPath+"[key]", &Options{})
+								subFields = collectFieldInfo(elem, elem.Type(), field.FieldPath+"[key]", childOptions)
 							}
 						}
 					}
@@ -564,16 +1155,15 @@ func generateAllComments(result *strings.Builder, fields []FieldInfo, indent int
 			}
 
 			if len(subFields) > 0 {
-				generateAllComments(result, subFields, indent+1, prefix+"    ")
+				generateAllComments(result, subFields, indent+1, prefix+"    ", childOptions)
 			}
 		}
 	}
 }
 
 // generateStructSectioned 生成分节风格的结构体
-func generateStructSectioned(fields []FieldInfo, indent int, options *Options) (string, error) {
-	var result strings.Builder
-	indentStr := strings.Repeat("  ", indent)
+func generateStructSectioned(result *strings.Builder, fields []FieldInfo, indent int, options *Options) error {
+	indentStr := getIndentStr(indent, options)
 
 	type FieldInfoArr struct {
 		Fields   []FieldInfo
@@ -586,6 +1176,7 @@ func generateStructSectioned(fields []FieldInfo, indent int, options *Options) (
 	for _, field := range fields {
 		if field.HasChildren {
 			fieldInfoArrs = append(fieldInfoArrs, FieldInfoArr{Fields: []FieldInfo{field}, isSimple: false})
+			old_hc_label = true
 		} else {
 			if old_hc_label {
 				fieldInfoArrs = append(fieldInfoArrs, FieldInfoArr{Fields: []FieldInfo{field}, isSimple: true})
@@ -596,24 +1187,36 @@ func generateStructSectioned(fields []FieldInfo, indent int, options *Options) (
 		}
 	}
 
-	for _, fieldInfoArr := range fieldInfoArrs {
+	for idx, fieldInfoArr := range fieldInfoArrs {
 
 		// 先处理简单字段，在第一个字段上方集中显示注释
 		if fieldInfoArr.isSimple {
+			// 跟在复杂字段后面的简单字段组，用空行隔开
+			if idx > 0 {
+				result.WriteString("\n")
+			}
 			for _, field := range fieldInfoArr.Fields {
-				if field.Comment != "" {
-					result.WriteString(fmt.Sprintf("%s# %s\n", indentStr, field.Comment))
+				if comment := commentWithExample(field); comment != "" {
+					result.WriteString(fmt.Sprintf("%s# %s\n", indentStr, comment))
 				}
 			}
 			result.WriteString("\n")
 
 			for i, field := range fieldInfoArr.Fields {
-				result.WriteString(fmt.Sprintf("%s%s: ", indentStr, field.Name))
-				fieldValue, err := generateValue(field.Field, field.FieldPath, indent+1, options)
+				runBeforeField(result, field, options)
+				result.WriteString(fieldKey(indentStr, field, options))
+				fieldValue, err := generateValue(field.Field, field.FieldPath, indent+1, optionsForField(field, options))
 				if err != nil {
-					return "", err
+					return err
+				}
+				fieldValue = commentOutValue(fieldValue, field, options)
+				if strings.HasPrefix(fieldValue, "\n") {
+					// 非空的简单切片，值从下一行开始
+					result.WriteString(strings.TrimRight(fieldValue, "\n"))
+				} else {
+					result.WriteString(" " + strings.TrimSpace(fieldValue))
 				}
-				result.WriteString(" " + strings.TrimSpace(fieldValue))
+				runAfterField(result, field, options)
 				if i < len(fieldInfoArr.Fields)-1 {
 					result.WriteString("\n")
 				}
@@ -628,20 +1231,23 @@ func generateStructSectioned(fields []FieldInfo, indent int, options *Options) (
 			// 再处理复杂字段
 			result.WriteString("\n")
 			for i, field := range fieldInfoArr.Fields {
-				if field.Comment != "" {
-					result.WriteString(fmt.Sprintf("%s# %s\n", indentStr, field.Comment))
+				runBeforeField(result, field, options)
+				if comment := commentWithExample(field); comment != "" {
+					result.WriteString(fmt.Sprintf("%s# %s\n", indentStr, comment))
 				}
-				result.WriteString(fmt.Sprintf("%s%s: ", indentStr, field.Name))
+				result.WriteString(fieldKey(indentStr, field, options) + " ")
 
-				fieldValue, err := generateValue(field.Field, field.FieldPath, indent+1, options)
+				fieldValue, err := generateValue(field.Field, field.FieldPath, indent+1, optionsForField(field, options))
 				if err != nil {
-					return "", err
+					return err
 				}
-				//如果fieldValue第一行是注释就换行
-				if strings.HasPrefix(strings.TrimSpace(fieldValue), "#") {
+				fieldValue = commentOutValue(fieldValue, field, options)
+				//如果fieldValue不是以换行开头就换行
+				if !strings.HasPrefix(fieldValue, "\n") {
 					result.WriteString("\n")
 				}
 				result.WriteString(fieldValue)
+				runAfterField(result, field, options)
 
 				if i < len(fieldInfoArr.Fields)-1 {
 					result.WriteString("\n")
@@ -650,18 +1256,25 @@ func generateStructSectioned(fields []FieldInfo, indent int, options *Options) (
 		}
 	}
 
-	return result.String(), nil
+	return nil
 }
 
 // generateStructDefault 生成默认风格的结构体
-func generateStructDefault(fields []FieldInfo, indent int, options *Options) (string, error) {
-	var result strings.Builder
+func generateStructDefault(result *strings.Builder, fields []FieldInfo, indent int, options *Options) error {
+	start := result.Len()
 	maxFieldNameLen := calculateMaxFieldNameLen(fields)
 
 	for i, field := range fields {
-		if err := generateFieldWithComment(&result, field, indent, options.Style, maxFieldNameLen, options); err != nil {
-			return "", err
+		if field.Section != "" {
+			writeSectionBanner(result, result.String()[start:], field.Section, getIndentStr(indent, options))
+		}
+
+		runBeforeField(result, field, options)
+		fieldOptions := optionsForField(field, options)
+		if err := generateFieldWithComment(result, field, indent, fieldOptions.Style, maxFieldNameLen, fieldOptions); err != nil {
+			return err
 		}
+		runAfterField(result, field, options)
 
 		// 添加字段间间隔
 		var nextField FieldInfo
@@ -673,7 +1286,63 @@ func generateStructDefault(fields []FieldInfo, indent int, options *Options) (st
 		}
 	}
 
-	return result.String(), nil
+	return nil
+}
+
+// getFieldStyle 获取字段级风格覆盖：选项中的路径覆盖优先于style=标签
+func getFieldStyle(field reflect.StructField, fieldPath string, options *Options) *CommentStyle {
+	for i := len(options.StyleOverrides) - 1; i >= 0; i-- {
+		override := options.StyleOverrides[i]
+		if matchPathGlob(override.Pattern, fieldPath) {
+			style := override.Style
+			return &style
+		}
+	}
+
+	if styleName, ok := getTagValue(field, "style"); ok {
+		if style, ok := parseStyle(styleName); ok {
+			return &style
+		}
+	}
+	return nil
+}
+
+// optionsForField 返回字段及其子树使用的选项，应用字段级风格覆盖、块标量风格、数字格式和nil指针骨架
+func optionsForField(field FieldInfo, options *Options) *Options {
+	// 首次修改时才复制选项，没有字段级设置的字段直接复用父级选项
+	var fieldOptions *Options
+	mutable := func() *Options {
+		if fieldOptions == nil {
+			copied := *options
+			fieldOptions = &copied
+		}
+		return fieldOptions
+	}
+	if field.Style != nil && (*field.Style != options.Style || (len(options.StylePerDepth) > 0 && !options.styleFixed)) {
+		mutable().Style = *field.Style
+		mutable().styleFixed = true
+	}
+	// 块标量风格只作用于字符串及字符串列表，不向结构体子树传递
+	if blockStyle, ok := getBlockStyle(field.FieldType); ok && !field.HasChildren && blockStyle != options.blockStyle {
+		mutable().blockStyle = blockStyle
+	}
+	if isSkeletonField(field.Field, options) {
+		mutable().skeleton = true
+	}
+	// 必填的非结构体字段整体保留，其中的映射项和列表元素不再按脚手架注释
+	if options.scaffold && !isStructType(field.FieldType.Type) && isRequiredField(field.FieldType) {
+		mutable().scaffold = false
+	}
+	if format := getValueFormat(field.FieldType); format != "" && !field.HasChildren && format != options.valueFormat {
+		mutable().valueFormat = format
+	}
+	if format, ok := getPrecisionFormat(field.FieldType); ok && !field.HasChildren && format != options.FloatFormat {
+		mutable().FloatFormat = format
+	}
+	if fieldOptions == nil {
+		return options
+	}
+	return fieldOptions
 }
 
 // calculateMaxFieldNameLen 计算最大字段名长度
@@ -703,8 +1372,8 @@ func shouldAddSpacing(style CommentStyle, currentIndex, totalFields int, current
 		// 分组风格：常规数据类型放在一起，遇到复杂数据类型时组间有空行分隔
 		// 如果当前字段是复杂类型，或者下一个字段是复杂类型，则添加空行
 		return currentField.HasChildren || nextField.HasChildren
-	case StyleSectioned:
-		// 分节风格：也需要间距
+	case StyleSectioned, StyleAnnotated:
+		// 分节风格和标注风格：也需要间距
 		return true
 	default:
 		return false
@@ -715,7 +1384,23 @@ func shouldAddSpacing(style CommentStyle, currentIndex, totalFields int, current
 func generateFieldWithComment(result *strings.Builder, field FieldInfo, indent int,
 	commentStyle CommentStyle, maxFieldNameLen int, options *Options) error {
 
-	indentStr := strings.Repeat("  ", indent)
+	indentStr := getIndentStr(indent, options)
+
+	// 整段注释的字段：先生成到临时缓冲区，再逐行注释掉
+	if isCommentedField(field, options) {
+		var commented strings.Builder
+		fieldOptions := *options
+		fieldOptions.scaffold = false
+		if fieldOptions.NilPointers == NilCommented {
+			fieldOptions.NilPointers = NilSkeleton
+		}
+		field.atDefault = false
+		if err := generateFieldWithComment(&commented, field, indent, commentStyle, maxFieldNameLen, &fieldOptions); err != nil {
+			return err
+		}
+		result.WriteString(commentOutLines(commented.String()))
+		return nil
+	}
 
 	// 智能风格的动态调整
 	if commentStyle == StyleSmart {
@@ -735,6 +1420,15 @@ func generateFieldWithComment(result *strings.Builder, field FieldInfo, indent i
 		return generateCompactStyleField(result, field, indentStr, options)
 	case StyleVerbose:
 		return generateVerboseStyleField(result, field, indentStr, options)
+	case StyleAnnotated:
+		return generateAnnotatedStyleField(result, field, indentStr, options)
+	case StyleHelmDocs:
+		return generateHelmDocsStyleField(result, field, indentStr, options)
+	case StyleMinimal:
+		// 作为字段级覆盖时，只输出字段和值
+		field.Comment = ""
+		field.Example = ""
+		return generateTopStyleField(result, field, indentStr, options)
 	case StyleSpaced, StyleGrouped:
 		return generateTopStyleField(result, field, indentStr, options)
 	default:
@@ -745,15 +1439,32 @@ func generateFieldWithComment(result *strings.Builder, field FieldInfo, indent i
 // generateTopStyleField 生成顶部风格字段
 func generateTopStyleField(result *strings.Builder, field FieldInfo, indentStr string, options *Options) error {
 	if field.Comment != "" {
-		result.WriteString(fmt.Sprintf("%s# %s\n", indentStr, field.Comment))
+		writeStrings(result, indentStr, "# ", field.Comment, "\n")
 	}
-	result.WriteString(fmt.Sprintf("%s%s: ", indentStr, field.Name))
+	writeExampleLine(result, field, indentStr)
+	writeStrings(result, indentStr, field.Name, ":")
 
 	return generateFieldValue(result, field, indentStr, options)
 }
 
+// writeStrings 依次写入多个片段，避免逐字段格式化产生的临时字符串
+func writeStrings(result *strings.Builder, parts ...string) {
+	for _, part := range parts {
+		result.WriteString(part)
+	}
+}
+
+// spaces 返回 n 个空格，用于注释对齐
+func spaces(n int) string {
+	if n <= len(indentSpaces) {
+		return indentSpaces[:n]
+	}
+	return strings.Repeat(" ", n)
+}
+
 // generateInlineStyleField 生成内联风格字段
 func generateInlineStyleField(result *strings.Builder, field FieldInfo, indentStr string, maxFieldNameLen int, options *Options) error {
+	field.Comment = commentWithExample(field)
 	maxFieldNameLen = maxFieldNameLen + 30
 	fieldNamePart := field.Name + ":"
 	currentFieldNameLen := getDisplayWidth(fieldNamePart)
@@ -768,9 +1479,7 @@ func generateInlineStyleField(result *strings.Builder, field FieldInfo, indentSt
 				if alignSpaces < 1 {
 					alignSpaces = 1
 				}
-				result.WriteString(fmt.Sprintf("%s%s %s%s# %s\n",
-					indentStr, fieldNamePart, emptyValue,
-					strings.Repeat(" ", alignSpaces), field.Comment))
+				writeStrings(result, indentStr, fieldNamePart, " ", emptyValue, spaces(alignSpaces), "# ", field.Comment, "\n")
 				return nil
 			}
 
@@ -779,31 +1488,28 @@ func generateInlineStyleField(result *strings.Builder, field FieldInfo, indentSt
 			if alignSpaces < 1 {
 				alignSpaces = 1
 			}
-			result.WriteString(fmt.Sprintf("%s%s%s# %s",
-				indentStr, fieldNamePart,
-				strings.Repeat(" ", alignSpaces), field.Comment))
+			writeStrings(result, indentStr, fieldNamePart, spaces(alignSpaces), "# ", field.Comment)
 		} else {
-			result.WriteString(fmt.Sprintf("%s%s ", indentStr, fieldNamePart))
+			writeStrings(result, indentStr, fieldNamePart, " ")
 		}
 		return generateFieldValue(result, field, indentStr, options)
 	}
 
 	indent := 0
 	if field.Field.Kind() == reflect.Slice || field.Field.Kind() == reflect.Array {
-		hasVisibleChildren := field.HasChildren || (field.Field.Kind() == reflect.Slice && field.Field.Len() > 0) ||
-			(field.Field.Kind() == reflect.Array && field.Field.Len() > 0)
+		hasVisibleChildren := field.HasChildren || hasBlockItems(field.Field, field.FieldPath, options)
 		if hasVisibleChildren {
 			// 复杂类型使用顶部注释
 			if field.Comment != "" {
 				fieldNameAndValueWidth := getDisplayWidth(indentStr + field.Name + ": ")
 				alignSpaces := maxFieldNameLen + getDisplayWidth(indentStr) - fieldNameAndValueWidth
-				result.WriteString(fmt.Sprintf("%s%s:%s# %s", indentStr, field.Name, strings.Repeat(" ", alignSpaces), field.Comment))
+				writeStrings(result, indentStr, field.Name, ":", spaces(alignSpaces), "# ", field.Comment)
 			} else {
-				result.WriteString(fmt.Sprintf("%s%s:", indentStr, field.Name))
+				writeStrings(result, indentStr, field.Name, ":")
 			}
-			indent = 1
+			indent = getIndentLevelFor(indentStr, options) + 1
 		} else {
-			result.WriteString(fmt.Sprintf("%s%s: ", indentStr, field.Name))
+			writeStrings(result, indentStr, field.Name, ": ")
 		}
 		// 生成字段值
 		fieldValue, err := generateValue(field.Field, field.FieldPath, indent, options)
@@ -811,39 +1517,43 @@ func generateInlineStyleField(result *strings.Builder, field FieldInfo, indentSt
 			return err
 		}
 		fieldValue = strings.TrimRight(fieldValue, "\n")
+		if !hasVisibleChildren {
+			// 空切片和流式切片紧跟在 "key: " 之后
+			fieldValue = strings.TrimLeft(fieldValue, " ")
+		}
 
 		if field.Comment != "" {
 			if hasVisibleChildren {
-				result.WriteString(fmt.Sprintf("%s\n", fieldValue))
+				writeStrings(result, fieldValue, "\n")
 			} else {
 				// 计算对齐空格 - 使用实际的字段名和值长度
-				fieldNameAndValueWidth := getDisplayWidth(indentStr + field.Name + ": " + fieldValue)
+				fieldNameAndValueWidth := getDisplayWidth(indentStr) + getDisplayWidth(field.Name) + 2 + getDisplayWidth(fieldValue)
 				alignSpaces := maxFieldNameLen + getDisplayWidth(indentStr) - fieldNameAndValueWidth + 2
 				if alignSpaces < 1 {
 					alignSpaces = 1
 				}
-				result.WriteString(fmt.Sprintf("%s%s# %s\n", fieldValue, strings.Repeat(" ", alignSpaces), field.Comment))
+				writeStrings(result, fieldValue, spaces(alignSpaces), "# ", field.Comment, "\n")
 			}
 		} else {
-			result.WriteString(fmt.Sprintf("%s\n", fieldValue))
+			writeStrings(result, fieldValue, "\n")
 		}
 		return nil
 	} else {
-		result.WriteString(fmt.Sprintf("%s%s: ", indentStr, field.Name))
+		writeStrings(result, indentStr, field.Name, ": ")
 	}
 
 	// 生成字段值
-	fieldValue, err := generateValue(field.Field, field.FieldPath, 0, options)
+	fieldValue, err := generateValue(field.Field, field.FieldPath, getIndentLevelFor(indentStr, options)+1, options)
 	if err != nil {
 		return err
 	}
-	fieldValue = strings.TrimRight(fieldValue, "\n")
+	// 块标量的注释写在指示符所在的首行
+	fieldValue, blockBody := splitBlockScalar(strings.TrimRight(fieldValue, "\n"))
 
 	// 计算注释对齐
 	if field.Comment != "" {
 		// 计算实际的字段行宽度
-		actualFieldLine := fmt.Sprintf("%s%s: %s", indentStr, field.Name, fieldValue)
-		fieldLineWidth := getDisplayWidth(actualFieldLine)
+		fieldLineWidth := getDisplayWidth(indentStr) + getDisplayWidth(field.Name) + 2 + getDisplayWidth(fieldValue)
 
 		// 计算对齐空格
 		targetWidth := getDisplayWidth(indentStr) + maxFieldNameLen
@@ -852,10 +1562,9 @@ func generateInlineStyleField(result *strings.Builder, field FieldInfo, indentSt
 			alignSpaces = 1
 		}
 
-		result.WriteString(fmt.Sprintf("%s%s# %s\n",
-			fieldValue, strings.Repeat(" ", alignSpaces), field.Comment))
+		writeStrings(result, fieldValue, spaces(alignSpaces), "# ", field.Comment, blockBody, "\n")
 	} else {
-		result.WriteString(fmt.Sprintf("%s\n", fieldValue))
+		writeStrings(result, fieldValue, blockBody, "\n")
 	}
 
 	return nil
@@ -888,59 +1597,87 @@ func getEmptyContainerValue(field reflect.Value) string {
 	}
 }
 
-// 更准确的显示宽度计算函数
+// getDisplayWidth 计算字符串在终端中的显示宽度
+// 按Unicode East Asian Width计算宽字符，并处理组合字符、emoji变体选择符、ZWJ序列和国旗
 func getDisplayWidth(s string) int {
 	width := 0
+	prevWidth := 0
+	joined := false
+	pendingFlag := false
 	for _, r := range s {
-		if isWideChar(r) {
+		switch {
+		case r == zeroWidthJoiner:
+			// ZWJ连接的后一个字符与前一个字符合并显示
+			joined = true
+			continue
+		case joined:
+			joined = false
+			continue
+		case r == emojiPresentation:
+			// 文本默认宽度的符号加上VS16后以emoji形式显示，占两列
+			if prevWidth == 1 {
+				width++
+				prevWidth = 2
+			}
+			continue
+		case isRegionalIndicator(r):
+			// 两个区域指示符组成一面国旗，占两列
+			if pendingFlag {
+				pendingFlag = false
+				continue
+			}
+			pendingFlag = true
 			width += 2
-		} else {
-			width += 1
+			prevWidth = 2
+			continue
+		}
+		pendingFlag = false
+
+		w := runeWidth(r)
+		width += w
+		if w > 0 {
+			prevWidth = w
 		}
 	}
 	return width
 }
 
-// isWideChar 判断是否为宽字符（简化版）
+// isWideChar 判断是否为宽字符（East Asian Wide/Fullwidth及emoji）
 func isWideChar(r rune) bool {
-	return (r >= 0x1100 && r <= 0x115F) ||
-		(r >= 0x2E80 && r <= 0x9FFF) ||
-		(r >= 0xAC00 && r <= 0xD7AF) ||
-		(r >= 0xF900 && r <= 0xFAFF) ||
-		(r >= 0xFE30 && r <= 0xFE4F) ||
-		(r >= 0xFF00 && r <= 0xFFEF)
+	return inRanges(r, wideRanges)
 }
 
 // generateCompactStyleField 生成紧凑风格字段
 func generateCompactStyleField(result *strings.Builder, field FieldInfo, indentStr string, options *Options) error {
+	field.Comment = commentWithExample(field)
+
 	// 处理复杂类型（有子字段的情况）
 	if field.HasChildren {
 		if field.Comment != "" {
 			// 看看子元素是否为空
-
 			switch field.Field.Kind() {
 			case reflect.Slice, reflect.Array:
 				if field.Field.Len() == 0 {
-					result.WriteString(fmt.Sprintf("%s%s: [] # %s\n", indentStr, field.Name, field.Comment))
+					writeStrings(result, indentStr, field.Name, ": [] # ", field.Comment, "\n")
+					return nil
 				}
-				return nil
 			case reflect.Map:
 				if field.Field.Len() == 0 {
-					result.WriteString(fmt.Sprintf("%s%s: {} # %s\n", indentStr, field.Name, field.Comment))
+					writeStrings(result, indentStr, field.Name, ": {} # ", field.Comment, "\n")
+					return nil
 				}
-				return nil
 			case reflect.Struct:
 				fields := collectFieldInfo(field.Field, field.Field.Type(), field.FieldPath, options)
 				if len(fields) == 0 {
-					result.WriteString(fmt.Sprintf("%s%s: {} # %s\n", indentStr, field.Name, field.Comment))
+					writeStrings(result, indentStr, field.Name, ": {} # ", field.Comment, "\n")
+					return nil
 				}
-				return nil
 			}
 
-			result.WriteString(fmt.Sprintf("%s%s:  # %s", indentStr, field.Name, field.Comment))
+			writeStrings(result, indentStr, field.Name, ":  # ", field.Comment)
 
 		} else {
-			result.WriteString(fmt.Sprintf("%s%s: ", indentStr, field.Name))
+			writeStrings(result, indentStr, field.Name, ": ")
 		}
 		err := generateFieldValue(result, field, indentStr, options)
 		if err != nil {
@@ -950,22 +1687,22 @@ func generateCompactStyleField(result *strings.Builder, field FieldInfo, indentS
 	}
 
 	// 处理数组/切片类型
-	indent := 0
+	indent := getIndentLevelFor(indentStr, options) + 1
 	hasVisibleChildren := false
 	if field.Field.Kind() == reflect.Slice || field.Field.Kind() == reflect.Array {
-		hasVisibleChildren = field.Field.Len() > 0
+		hasVisibleChildren = hasBlockItems(field.Field, field.FieldPath, options)
 		if hasVisibleChildren {
 			if field.Comment != "" {
-				result.WriteString(fmt.Sprintf("%s%s: # %s", indentStr, field.Name, field.Comment))
+				writeStrings(result, indentStr, field.Name, ": # ", field.Comment)
 			} else {
-				result.WriteString(fmt.Sprintf("%s%s: ", indentStr, field.Name))
+				writeStrings(result, indentStr, field.Name, ": ")
 			}
-			indent = 1
+			indent = getIndentLevelFor(indentStr, options) + 1
 		} else {
-			result.WriteString(fmt.Sprintf("%s%s: ", indentStr, field.Name))
+			writeStrings(result, indentStr, field.Name, ": ")
 		}
 	} else {
-		result.WriteString(fmt.Sprintf("%s%s: ", indentStr, field.Name))
+		writeStrings(result, indentStr, field.Name, ": ")
 	}
 
 	// 生成字段值
@@ -973,35 +1710,69 @@ func generateCompactStyleField(result *strings.Builder, field FieldInfo, indentS
 	if err != nil {
 		return err
 	}
-	fieldValue = strings.TrimRight(fieldValue, "\n")
+	fieldValue, blockBody := splitBlockScalar(strings.TrimRight(fieldValue, "\n"))
+	if !hasVisibleChildren {
+		fieldValue = strings.TrimLeft(fieldValue, " ")
+	}
 
 	// 输出最终结果
 	if field.Comment != "" && !hasVisibleChildren {
-		result.WriteString(fmt.Sprintf("%s # %s\n", fieldValue, field.Comment))
+		writeStrings(result, fieldValue, " # ", field.Comment, blockBody, "\n")
 	} else {
-		result.WriteString(fmt.Sprintf("%s\n", fieldValue))
+		writeStrings(result, fieldValue, blockBody, "\n")
 	}
 
 	return nil
 }
 
 // generateMinimalStyleField 生成最小风格字段
-func generateMinimalStyleField(v interface{}) (string, error) {
+func generateMinimalStyleField(v interface{}, options *Options) (string, error) {
 	//yaml 直接转field.Field 成yaml
-	yamlData, err := yaml.Marshal(v)
+	node, more, err := encodeNode(reflect.ValueOf(v), options.rootPath, 0, options)
 	if err != nil {
 		return "", err
 	}
-	return string(yamlData), nil
+	node.FootComment = more
+	// 最小风格不经过字段渲染流程，需要在节点树上过滤和排序字段、屏蔽或加密敏感值、替换文件引用、变量引用和占位文本、按格式输出字节切片、整数和浮点数、转换标量值、设置流式风格、兼容性引号和空值并记录警告；层级和元素个数在编码时截断
+	filterNode(node, reflect.ValueOf(v), "", options)
+	audienceNode(node, reflect.ValueOf(v), options)
+	orderNode(node, reflect.ValueOf(v), 0, options)
+	redactNode(node, reflect.ValueOf(v), options)
+	if err := encryptNode(node, reflect.ValueOf(v), options.rootPath, options); err != nil {
+		return "", err
+	}
+	substituteNode(node, reflect.ValueOf(v), options.rootPath, options)
+	if err := formatNode(node, reflect.ValueOf(v), "", options); err != nil {
+		return "", err
+	}
+	if err := transformNode(node, reflect.ValueOf(v), "", 0, options); err != nil {
+		return "", err
+	}
+	flowNode(node, options)
+	compatNode(node, options)
+	nullNode(node, reflect.ValueOf(v), options)
+	warnNode(node, reflect.ValueOf(v), options.rootPath, options)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(getIndentWidth(options))
+	if err := encoder.Encode(node); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
 }
 
 // generateVerboseStyleField 生成详细风格字段
 func generateVerboseStyleField(result *strings.Builder, field FieldInfo, indentStr string, options *Options) error {
 	if field.Comment != "" {
-		fieldTypeStr := field.Field.Type().String()
-		result.WriteString(fmt.Sprintf("%s# %s (%s)\n", indentStr, field.Comment, fieldTypeStr))
+		fieldTypeStr := fieldTypeString(field, options)
+		writeStrings(result, indentStr, "# ", field.Comment, " (", fieldTypeStr, ")\n")
 	}
-	result.WriteString(fmt.Sprintf("%s%s:", indentStr, field.Name))
+	writeExampleLine(result, field, indentStr)
+	writeStrings(result, indentStr, field.Name, ":")
 
 	return generateFieldValue(result, field, indentStr, options)
 }
@@ -1011,53 +1782,97 @@ func generateFieldValue(result *strings.Builder, field FieldInfo, indentStr stri
 	// 特殊处理切片类型，即使它们没有复杂的子元素
 	if field.HasChildren || field.Field.Kind() == reflect.Slice || field.Field.Kind() == reflect.Array {
 		//如果元素和数组为空就不需要换行
-		hasVisibleChildren := field.HasChildren ||
-			(field.Field.Kind() == reflect.Slice && field.Field.Len() > 0) ||
-			(field.Field.Kind() == reflect.Array && field.Field.Len() > 0)
+		hasVisibleChildren := field.HasChildren || hasBlockItems(field.Field, field.FieldPath, options)
 		if hasVisibleChildren {
 			result.WriteString("\n")
 		}
-		fieldValue, err := generateValue(field.Field, field.FieldPath, getIndentLevel(indentStr)+1, options)
+		indent := getIndentLevelFor(indentStr, options) + 1
+		if field.Field.Kind() != reflect.Slice && field.Field.Kind() != reflect.Array {
+			return writeValue(result, field.Field, field.FieldPath, indent, options)
+		}
+		// 列表需要整体调整元素注释的位置
+		fieldValue, err := generateValue(field.Field, field.FieldPath, indent, options)
 		if err != nil {
 			return err
 		}
-		if field.Field.Kind() == reflect.Slice || field.Field.Kind() == reflect.Array {
-			lines := strings.Split(fieldValue, "\n")
-			var commentLines, fieldLines []string
-			for _, line := range lines {
-				if strings.TrimSpace(line) != "" {
-					if strings.HasPrefix(strings.TrimSpace(line), "#") {
-						commentLines = append(commentLines, fmt.Sprintf("%s\n", line))
-					} else {
-						fieldLines = append(fieldLines, normalizeTrailingNewlines1(fmt.Sprintf("%s\n", line)))
-					}
-				}
-			}
-			if len(commentLines) > 0 {
-				result.WriteString(normalizeTrailingNewlines1(strings.Join(commentLines, "")))
-			}
-			if len(fieldLines) > 0 {
-				result.WriteString(normalizeTrailingNewlines1(strings.Join(fieldLines, "")))
-			}
-		} else {
-			result.WriteString(fieldValue)
-		}
+		writeElementComments(result, fieldValue, getIndentStr(indent, options))
 	} else {
-		fieldValue, err := generateValue(field.Field, field.FieldPath, 0, options)
+		fieldValue, err := generateValue(field.Field, field.FieldPath, getIndentLevelFor(indentStr, options)+1, options)
 		if err != nil {
 			return err
 		}
-		result.WriteString(" " + strings.TrimSpace(fieldValue))
+		writeStrings(result, " ", strings.TrimSpace(fieldValue), "\n")
 	}
 	return nil
 }
-func normalizeTrailingNewlines1(content string) string {
-	// 如果内容以多个换行符结尾，移除所有尾部的换行符并转为1个
-	if strings.HasSuffix(content, "\n") {
-		trimmed := strings.TrimRight(content, "\n")
-		return trimmed + "\n"
+
+// hoistElementComments 去掉列表中的空行，并把每个元素内的注释行提到该元素的 "-" 之前，分节横幅留在元素内
+func hoistElementComments(content string, itemIndent string) string {
+	var result strings.Builder
+	writeElementComments(&result, content, itemIndent)
+	return result.String()
+}
+
+// writeElementComments 与 hoistElementComments 相同，结果直接写入 result
+func writeElementComments(result *strings.Builder, content string, itemIndent string) {
+	var comments, body, pending []string
+	keyColumn := len(itemIndent) + 2
+	sectioned := false
+	writeLines := func(lines []string) {
+		for _, line := range lines {
+			writeStrings(result, line, "\n")
+		}
+	}
+	flush := func() {
+		writeLines(comments)
+		writeLines(body)
+		comments, body = comments[:0], body[:0]
 	}
-	return content
+
+	scanBlockLines(content, func(line string, inBlock bool) {
+		trimmed := strings.TrimSpace(line)
+		if inBlock {
+			// 块标量内容原样保留，包括其中的空行和以 "#" 开头的行
+			body = append(body, line)
+			return
+		}
+		if trimmed == "" {
+			return
+		}
+		if strings.HasPrefix(trimmed, "#") {
+			pending = append(pending, line)
+			return
+		}
+		// 紧挨在元素起始行之前的注释属于该元素
+		dash := strings.HasPrefix(line, itemIndent) && strings.HasPrefix(line[len(itemIndent):], "-")
+		if dash {
+			flush()
+			keyColumn = len(line) - len(strings.TrimLeft(line[len(itemIndent)+1:], " "))
+			sectioned = false
+		}
+		// 元素直属键的注释提前，更深层键（如动态映射中的键）和内层列表元素的注释留在原处；
+		// 分节横幅及其后的注释属于元素的映射，同样留在原处，首个键的横幅写在 "-" 之后
+		inner := len(body) > 0 && strings.HasPrefix(trimmed, "-")
+		for _, comment := range pending {
+			depth := len(comment) - len(strings.TrimLeft(comment, " "))
+			banner := depth == keyColumn && isSectionBanner(comment)
+			switch {
+			case dash && banner && !sectioned:
+				body = append(body, line[:keyColumn]+strings.TrimSpace(comment))
+				line = itemIndent + " " + line[len(itemIndent)+1:]
+				sectioned = true
+			case banner || sectioned || (len(body) > 0 && (inner || depth > keyColumn)):
+				body = append(body, comment)
+				sectioned = sectioned || banner
+			default:
+				comments = append(comments, comment)
+			}
+		}
+		pending = pending[:0]
+		body = append(body, line)
+	})
+	flush()
+	writeLines(pending)
 }
 
 // getIndentLevel 获取缩进级别
@@ -1065,6 +1880,30 @@ func getIndentLevel(indentStr string) int {
 	return len(indentStr) / 2
 }
 
+// getIndentWidth 获取每级缩进的空格数
+func getIndentWidth(options *Options) int {
+	if options != nil && options.Indent > 0 {
+		return options.Indent
+	}
+	return DefaultIndent
+}
+
+// indentSpaces 常用缩进宽度内直接截取，避免逐字段重复分配缩进字符串
+const indentSpaces = "                                                                "
+
+// getIndentStr 获取指定级别的缩进字符串
+func getIndentStr(indent int, options *Options) string {
+	if width := indent * getIndentWidth(options); width <= len(indentSpaces) {
+		return indentSpaces[:width]
+	}
+	return strings.Repeat(" ", indent*getIndentWidth(options))
+}
+
+// getIndentLevelFor 按配置的缩进宽度获取缩进级别
+func getIndentLevelFor(indentStr string, options *Options) int {
+	return len(indentStr) / getIndentWidth(options)
+}
+
 // isValidKeyName 验证键名是否符合YAML标准
 func isValidKeyName(key string) bool {
 	if key == "" {
@@ -1119,7 +1958,7 @@ func getFieldName(fieldType reflect.StructField) string {
 			return "-"
 		}
 		parts := strings.Split(yamlcTag, ",")
-		if parts[0] != "" && parts[0] != "-" && !strings.Contains(parts[0], "=") && isValidKeyName(parts[0]) {
+		if parts[0] != "" && parts[0] != "-" && !strings.Contains(parts[0], "=") && !isTagFlag(parts[0]) && isValidKeyName(parts[0]) {
 			return parts[0]
 		}
 	}
@@ -1136,123 +1975,213 @@ func buildFieldPath(fieldPath, fieldName string) string {
 	return fieldName
 }
 
-// generateMap 生成Map YAML
-func generateMap(val reflect.Value, fieldPath string, indent int, options *Options) (string, error) {
+// writeMap 生成Map YAML并写入 result
+func writeMap(result *strings.Builder, val reflect.Value, fieldPath string, indent int, options *Options) error {
 	if val.Len() == 0 {
-		return " {}", nil
+		result.WriteString(" {}")
+		return nil
 	}
 
-	var result strings.Builder
-	indentStr := strings.Repeat("  ", indent)
+	options = levelOptions(options)
+	fields := collectMapEntries(val, fieldPath, options)
+	if err := transformFields(fields, options); err != nil {
+		return err
+	}
+	if err := renderFields(result, fields, indent, options); err != nil {
+		return err
+	}
+	result.WriteString(moreItemsComment(val.Len(), getIndentStr(indent, options), fieldPath, options))
+	return nil
+}
 
-	iter := val.MapRange()
-	for iter.Next() {
-		key := iter.Key()
-		value := iter.Value()
+// collectMapEntries 将映射的键值对收集为字段信息，按键排序保证输出稳定
+// 键的路径为 父路径.键，可通过 WithComment 的路径（支持通配符）为其添加注释
+func collectMapEntries(val reflect.Value, fieldPath string, options *Options) []FieldInfo {
+	keys := val.MapKeys()
+	sortMapKeys(keys)
+	keys = keys[:itemLimit(len(keys), options)]
 
-		keyStr := fmt.Sprintf("%v", key.Interface())
-		if needsQuoting(keyStr) {
-			keyStr = fmt.Sprintf("%q", keyStr)
+	fields := make([]FieldInfo, 0, len(keys))
+	for _, key := range keys {
+		value := val.MapIndex(key)
+		if value.Kind() == reflect.Interface && !value.IsNil() {
+			value = value.Elem()
+		}
+		rawKey := mapKeyText(key)
+		entryPath := buildFieldPath(fieldPath, rawKey)
+		if skipUnsupported(value, entryPath, options) {
+			continue
 		}
 
-		result.WriteString(fmt.Sprintf("%s%s:", indentStr, keyStr))
+		keyStr := formatMapKey(key, options)
+		if keyStr != rawKey {
+			warn(options, WarningKeyQuoted, entryPath, "key %q quoted because it contains special characters", rawKey)
+		}
 
-		if hasChildren(value) {
-			result.WriteString("\n")
-			valueStr, err := generateValue(value, fieldPath, indent+1, options)
-			if err != nil {
-				return "", err
-			}
-			result.WriteString(valueStr)
-		} else {
-			valueStr, err := generateValue(value, fieldPath, indent+1, options)
-			if err != nil {
-				return "", err
-			}
-			result.WriteString(" " + strings.TrimSpace(valueStr) + "\n")
+		if isFieldFiltered(entryPath, options) {
+			continue
+		}
+		comment, _ := lookupComment(entryPath, options)
+		if comment != "" {
+			comment = flattenComment(comment, entryPath, options)
 		}
-	}
 
-	return result.String(), nil
+		secret := options.redactAll && isSensitiveName(rawKey)
+		if secret {
+			value = reflect.ValueOf(secretPlaceholder(options))
+			comment = secretComment(comment)
+		}
+
+		fieldType := reflect.StructField{Name: rawKey, Type: value.Type()}
+		info := FieldInfo{
+			Name:        keyStr,
+			Comment:     comment,
+			Field:       value,
+			FieldType:   fieldType,
+			HasChildren: hasChildren(value) && !exceedsDepth(value, options),
+			FieldPath:   entryPath,
+			Style:       getFieldStyle(fieldType, entryPath, options),
+			Depth:       options.level - 1,
+			Parent:      fieldPath,
+			secret:      secret,
+		}
+		applyIndexedOnly(&info, options)
+		logField(info, "", options)
+		fields = append(fields, info)
+	}
+	return fields
 }
 
-// generateSlice 生成Slice YAML
-func generateSlice(val reflect.Value, fieldPath string, indent int, options *Options) (string, error) {
+// writeSlice 生成Slice YAML并写入 result
+func writeSlice(result *strings.Builder, val reflect.Value, fieldPath string, indent int, options *Options) error {
 	if val.Len() == 0 {
-		return " []\n", nil
+		result.WriteString(" []\n")
+		return nil
+	}
+	if isFlowSlice(val, fieldPath, options) {
+		flow, err := generateFlowSlice(val, fieldPath, indent, options)
+		if err != nil {
+			return err
+		}
+		result.WriteString(flow)
+		return nil
 	}
 
-	var result strings.Builder
+	headOptions, tailOptions := sliceItemOptions(options)
 
-	indentStr := strings.Repeat("  ", indent)
+	limit := itemLimit(val.Len(), options)
+	for i := 0; i < limit; i++ {
+		if err := writeSliceItem(result, val, i, limit, fieldPath, indent, headOptions, tailOptions); err != nil {
+			return err
+		}
+	}
+	result.WriteString(moreItemsComment(val.Len(), getIndentStr(indent, options), fieldPath, options))
+	return nil
+}
 
-	for i := 0; i < val.Len(); i++ {
-		item := val.Index(i)
+// writeSliceItem 生成列表中第 i 个带 "-" 前缀的元素并写入 result，limit 为实际输出的元素个数，
+// tailOptions 用于非首个元素
+func writeSliceItem(result *strings.Builder, val reflect.Value, i, limit int, fieldPath string, indent int, options, tailOptions *Options) error {
+	item := val.Index(i)
+	itemPath := buildFieldPath(fieldPath, strconv.Itoa(i))
+	if skipUnsupported(item, itemPath, options) {
+		return nil
+	}
+	indentStr := getIndentStr(indent, options)
+	itemOptions := options
+	if i > 0 {
+		itemOptions = tailOptions
+	}
 
-		if hasChildren(item) {
-			// 对于结构体等复杂类型，生成值并添加 "-" 前缀
-			itemStr, err := generateValue(item, fieldPath, indent+1, options)
-			if err != nil {
-				return "", err
-			}
+	itemStr, err := generateValue(item, itemPath, indent+1, itemOptions)
+	if err != nil {
+		return err
+	}
 
-			// 第一个元素保留注释，其他元素去掉注释
-			keepComments := (i == 0)
-			formattedStr := addDashPrefix(itemStr, indentStr, keepComments, options)
-			result.WriteString(formattedStr)
+	nested := isNestedBlockList(item, itemPath, options)
+	if hasChildren(item) || nested {
+		if nested {
+			// 内层列表的首个元素以换行开头，"-" 需要与之写在同一行
+			itemStr = strings.TrimLeft(itemStr, "\n")
+		}
+		// 对于结构体、映射和内层列表等复杂类型，为生成的值添加 "-" 前缀，
+		// "-" 之后按缩进宽度补齐，内层内容与元素的首行对齐
+		start := result.Len()
+		writeDashPrefix(result, itemStr, indentStr, i > 0, options)
+		// 去掉空行后元素可能不再以换行结尾；最后一个元素后添加换行
+		if i == limit-1 || !strings.HasSuffix(result.String()[start:], "\n") {
+			result.WriteString("\n")
+		}
+		return nil
+	}
 
-			// 最后一个元素后添加换行
-			if i == val.Len()-1 {
-				result.WriteString("\n")
-			}
-		} else {
-			if i == 0 {
-				result.WriteString("\n")
-			}
-			// 简单类型，直接生成带 "- " 前缀的值
-			itemStr, err := generateValue(item, fieldPath, indent+1, options)
-			if err != nil {
-				return "", err
-			}
+	// 简单类型，直接生成带 "- " 前缀的值
+	if i == 0 {
+		result.WriteString("\n")
+	}
+	line := indentStr + "-"
+	if trimmedValue := strings.TrimSpace(itemStr); trimmedValue != "" {
+		line += " " + trimmedValue
+	}
+	result.WriteString(renderElementComment(line, indentStr, itemPath, options))
+	return nil
+}
 
-			trimmedValue := strings.TrimSpace(itemStr)
-			if trimmedValue != "" {
-				result.WriteString(fmt.Sprintf("%s- %s\n", indentStr, trimmedValue))
-			} else {
-				result.WriteString(fmt.Sprintf("%s-\n", indentStr))
-			}
-		}
+// isNestedBlockList 判断列表元素本身是否为按块风格生成的非空列表，如 [][]int 的元素
+func isNestedBlockList(item reflect.Value, itemPath string, options *Options) bool {
+	for (item.Kind() == reflect.Ptr || item.Kind() == reflect.Interface) && !item.IsNil() {
+		item = item.Elem()
 	}
+	return isListValue(item) && item.Len() > 0 && !isFlowSlice(item, itemPath, options)
+}
 
-	return result.String(), nil
+// isListValue 判断值是否按块风格列表生成（字节切片除外）
+func isListValue(val reflect.Value) bool {
+	return (val.Kind() == reflect.Slice || val.Kind() == reflect.Array) && !isByteSlice(val)
 }
 
-// addDashPrefix 为YAML列表项添加 "- " 前缀
-func addDashPrefix(content string, indentStr string, keepComments bool, options *Options) string {
-	lines := strings.Split(content, "\n")
+// renderElementComment 为简单列表元素附加按下标指定的注释（如 "tags.1"）
+// 行内类风格写在行尾，其余风格写在元素上方
+func renderElementComment(line, indentStr, itemPath string, options *Options) string {
+	comment, ok := lookupIndexedComment(itemPath, options)
+	if !ok || comment == "" {
+		return line + "\n"
+	}
+	comment = sanitizeComment(comment)
 
-	if !keepComments {
-		// 去掉注释行，只保留非空非注释行
-		var filteredLines []string
-		for _, line := range lines {
-			trimmedLine := strings.TrimSpace(line)
-			if trimmedLine != "" && !strings.HasPrefix(trimmedLine, "#") {
-				filteredLines = append(filteredLines, line)
-			}
-		}
-		lines = filteredLines
+	switch options.Style {
+	case StyleInline, StyleSmart, StyleCompact:
+		head, body := splitBlockScalar(line)
+		return fmt.Sprintf("%s # %s%s\n", head, comment, body)
+	case StyleMinimal:
+		return line + "\n"
+	default:
+		return fmt.Sprintf("%s# %s\n%s\n", indentStr, comment, line)
 	}
+}
 
-	// 找到第一个非空非注释行，为其添加 "- " 前缀
-	for i, line := range lines {
+// writeDashPrefix 为YAML列表项添加 "- " 前缀并写入 result
+func writeDashPrefix(result *strings.Builder, content string, indentStr string, dropBlank bool, options *Options) {
+	first, prefixed := true, false
+	scanBlockLines(content, func(line string, inBlock bool) {
 		trimmedLine := strings.TrimSpace(line)
-		if trimmedLine != "" && !strings.HasPrefix(trimmedLine, "#") {
-			lines[i] = fmt.Sprintf("%s- %s", indentStr, trimmedLine)
-			break
+		if dropBlank && !inBlock && trimmedLine == "" {
+			// 非首个元素去掉空行，保持列表紧凑
+			return
 		}
-	}
+		if !first {
+			result.WriteString("\n")
+		}
+		first = false
 
-	return strings.Join(lines, "\n")
+		// 第一个非空非注释行添加 "- " 前缀，"-" 之后补齐到一个缩进宽度，保证后续行与首个键对齐
+		if !prefixed && trimmedLine != "" && !strings.HasPrefix(trimmedLine, "#") {
+			prefixed = true
+			writeStrings(result, indentStr, "-", getIndentStr(1, options)[1:], trimmedLine)
+			return
+		}
+		result.WriteString(line)
+	})
 }
 
 // generateString 生成字符串YAML
@@ -1264,10 +2193,22 @@ func generateString(val reflect.Value, fieldPath string, indent int, options *Op
 		return "", fmt.Errorf("invalid string content: %w", err)
 	}
 
-	if needsQuoting(str) {
-		return fmt.Sprintf("%q", str), nil
+	// 多行字符串优先使用块标量，内容比所属键多缩进一级
+	indentStr := getIndentStr(indent, options)
+	if strings.Contains(str, "\n") {
+		if block, ok := blockScalar(str, options.blockStyle, indentStr, getFoldWidth(options)); ok {
+			logScalar(str, fieldPath, "block scalar", options)
+			return block, nil
+		}
+	} else if shouldFold(str, indentStr, options) {
+		if block, ok := blockScalar(str, BlockFolded, indentStr, getFoldWidth(options)); ok {
+			logScalar(str, fieldPath, "folded (long string)", options)
+			return block, nil
+		}
 	}
-	return str, nil
+
+	logScalar(str, fieldPath, "", options)
+	return quoteString(str, options), nil
 }
 
 // validateStringContent 验证字符串内容
@@ -1301,7 +2242,13 @@ func generateInt(val reflect.Value, fieldPath string, indent int, options *Optio
 		}
 	}
 
-	return fmt.Sprintf("%d", intVal), nil
+	if _, ok := intBases[options.valueFormat]; ok {
+		if intVal < 0 {
+			return formatIntBase(uint64(-intVal), true, options.valueFormat), nil
+		}
+		return formatIntBase(uint64(intVal), false, options.valueFormat), nil
+	}
+	return strconv.FormatInt(intVal, 10), nil
 }
 
 // generateUint 生成无符号整数YAML
@@ -1324,7 +2271,10 @@ func generateUint(val reflect.Value, fieldPath string, indent int, options *Opti
 		}
 	}
 
-	return fmt.Sprintf("%d", uintVal), nil
+	if _, ok := intBases[options.valueFormat]; ok {
+		return formatIntBase(uintVal, false, options.valueFormat), nil
+	}
+	return strconv.FormatUint(uintVal, 10), nil
 }
 
 // generateFloat 生成浮点数YAML
@@ -1333,18 +2283,13 @@ func generateFloat(val reflect.Value, fieldPath string, indent int, options *Opt
 
 	// 验证浮点数有效性
 	if isInvalidFloat(floatVal) {
+		if special, ok := formatSpecialFloat(floatVal); ok && options.SpecialFloats {
+			return special, nil
+		}
 		return "", fmt.Errorf("invalid float value: %f", floatVal)
 	}
 
-	// 根据类型确定精度
-	switch val.Kind() {
-	case reflect.Float32:
-		return fmt.Sprintf("%.7g", float32(floatVal)), nil
-	case reflect.Float64:
-		return fmt.Sprintf("%.15g", floatVal), nil
-	}
-
-	return fmt.Sprintf("%g", floatVal), nil
+	return formatFloat(floatVal, val.Kind(), options)
 }
 
 // isInvalidFloat 检查浮点数是否有效
@@ -1361,19 +2306,42 @@ func isInvalidFloat(f float64) bool {
 
 // generateBool 生成布尔值YAML
 func generateBool(val reflect.Value, fieldPath string, indent int, options *Options) (string, error) {
-	return fmt.Sprintf("%t", val.Bool()), nil
+	return strconv.FormatBool(val.Bool()), nil
 }
 
-// getComment 获取字段注释
-func getComment(field reflect.StructField, fieldPath string, options *Options) string {
-	// 1. 优先检查配置中的预设注释
-	for _, commentMap := range options.Comments {
-		if comment, exists := commentMap[fieldPath]; exists {
-			return sanitizeComment(comment)
-		}
+// resolveFieldComment 获取字段的注释：选项中的注释按合并方式与标签注释组合，都没有时使用Kubernetes规范键的默认注释
+func resolveFieldComment(plan fieldPlan, fieldPath string, options *Options) string {
+	comment, ok := getOptionComment(plan.fieldType, fieldPath, options)
+	if !ok {
+		comment = plan.tagComment
+	} else {
+		comment = mergeComment(comment, plan.tagComment, options)
 	}
+	if comment == "" {
+		comment = kubernetesComment(plan.name, options.level-1, options)
+	}
+	return comment
+}
+
+// getOptionComment 获取由选项决定的注释：优先检查配置中的预设注释，其次是为当前受众单独提供的注释
+func getOptionComment(field reflect.StructField, fieldPath string, options *Options) (string, bool) {
+	if comment, exists := lookupComment(fieldPath, options); exists {
+		return flattenComment(comment, fieldPath, options), true
+	}
+	if comment, exists := getAudienceComment(field, options); exists {
+		return flattenComment(comment, fieldPath, options), true
+	}
+	return "", false
+}
 
-	// 2. 检查yamlc标签中的注释
+// TagComment 返回结构体字段标签中的注释，读取规则与生成时相同，供扩展包复用
+func TagComment(field reflect.StructField) string {
+	return getTagComment(field)
+}
+
+// getTagComment 获取结构体标签中的注释，与选项无关，可按类型缓存
+func getTagComment(field reflect.StructField) string {
+	// 1. 检查yamlc标签中的注释
 	if yamlcTag := field.Tag.Get("yamlc"); yamlcTag != "" {
 		parts := strings.Split(yamlcTag, ",")
 		for _, part := range parts {
@@ -1383,12 +2351,12 @@ func getComment(field reflect.StructField, fieldPath string, options *Options) s
 		}
 	}
 
-	// 3. 检查comment标签
+	// 2. 检查comment标签
 	if comment := field.Tag.Get("comment"); comment != "" {
 		return sanitizeComment(comment)
 	}
 
-	// 4. 检查yaml标签中的注释
+	// 3. 检查yaml标签中的注释
 	if yamlTag := field.Tag.Get("yaml"); yamlTag != "" {
 		parts := strings.Split(yamlTag, ",")
 		for _, part := range parts {
@@ -1401,6 +2369,152 @@ func getComment(field reflect.StructField, fieldPath string, options *Options) s
 	return ""
 }
 
+// lookupComment 在配置的注释映射中查找路径对应的注释
+// 先按完整路径（含列表下标，如 "workExperience.0.company"）查找，
+// 未命中时再按去掉下标的通用路径（如 "workExperience.company"）查找
+func lookupComment(fieldPath string, options *Options) (string, bool) {
+	if comment, ok := lookupIndexedComment(fieldPath, options); ok {
+		return comment, true
+	}
+	if generic := stripIndexSegments(fieldPath); generic != fieldPath {
+		return lookupIndexedComment(generic, options)
+	}
+	return "", false
+}
+
+// lookupIndexedComment 按完整路径查找注释，按注释层的优先级依次查找，第一个命中的层生效
+// 每一层内先精确匹配，再按通配符（如 "servers.*.port"）匹配，多个通配符命中时取最具体的
+func lookupIndexedComment(fieldPath string, options *Options) (string, bool) {
+	for _, layer := range options.CommentLayers {
+		if comment, ok := lookupCommentMap(layer.Comments, fieldPath); ok {
+			return comment, true
+		}
+	}
+	for i := len(options.Comments) - 1; i >= 0; i-- {
+		if comment, ok := lookupCommentMap(options.Comments[i], fieldPath); ok {
+			return comment, true
+		}
+	}
+	return "", false
+}
+
+// lookupCommentMap 在单个注释映射中查找路径对应的注释，精确匹配优先于通配符
+func lookupCommentMap(commentMap map[string]string, fieldPath string) (string, bool) {
+	if comment, exists := commentMap[fieldPath]; exists {
+		return comment, true
+	}
+
+	bestPattern := ""
+	bestScore := -1
+	for pattern := range commentMap {
+		if !isPathPattern(pattern) || !matchPathGlob(pattern, fieldPath) {
+			continue
+		}
+		score := patternSpecificity(pattern)
+		if score > bestScore || (score == bestScore && pattern < bestPattern) {
+			bestPattern, bestScore = pattern, score
+		}
+	}
+	if bestScore >= 0 {
+		return commentMap[bestPattern], true
+	}
+	return "", false
+}
+
+// getExample 获取字段的示例值（yamlc标签中的example=）
+func getExample(field reflect.StructField) string {
+	if example, ok := getTagValue(field, "example"); ok {
+		return sanitizeComment(example)
+	}
+	return ""
+}
+
+// yamlcTagFlags yamlc标签中不带值的开关项，不能被当作字段名
+var yamlcTagFlags = map[string]bool{
+	"secret":   true,
+	"literal":  true,
+	"folded":   true,
+	"required": true,
+}
+
+// isTagFlag 判断标签片段是否为开关项
+func isTagFlag(part string) bool {
+	return yamlcTagFlags[strings.TrimSpace(part)]
+}
+
+// getTagValue 获取yamlc标签中 key=value 形式的值
+func getTagValue(field reflect.StructField, key string) (string, bool) {
+	yamlcTag := field.Tag.Get("yamlc")
+	if yamlcTag == "" {
+		return "", false
+	}
+	for yamlcTag != "" {
+		var part string
+		part, yamlcTag, _ = strings.Cut(yamlcTag, ",")
+		if strings.HasPrefix(part, key) && strings.HasPrefix(part[len(key):], "=") {
+			return part[len(key)+1:], true
+		}
+	}
+	return "", false
+}
+
+// hasTagFlag 检查yamlc标签中是否设置了开关项（flag 或 flag=true）
+func hasTagFlag(field reflect.StructField, flag string) bool {
+	yamlcTag := field.Tag.Get("yamlc")
+	if yamlcTag == "" {
+		return false
+	}
+	for yamlcTag != "" {
+		var part string
+		part, yamlcTag, _ = strings.Cut(yamlcTag, ",")
+		part = strings.TrimSpace(part)
+		if part == flag || (strings.HasPrefix(part, flag) && part[len(flag):] == "=true") {
+			return true
+		}
+	}
+	return false
+}
+
+// getSection 获取字段的分节标题（yamlc标签中的section=）
+func getSection(field reflect.StructField) string {
+	if section, ok := getTagValue(field, "section"); ok {
+		return sanitizeComment(section)
+	}
+	return ""
+}
+
+// writeSectionBanner 写入分节横幅，如 "# ---- Database Settings ----"，与当前层级中前面的内容 written 空一行
+func writeSectionBanner(result *strings.Builder, written string, section string, indentStr string) {
+	if written != "" && !strings.HasSuffix(written, "\n\n") {
+		result.WriteString("\n")
+	}
+	writeStrings(result, indentStr, "# ---- ", section, " ----\n")
+}
+
+// isSectionBanner 判断注释行是否为 writeSectionBanner 写出的分节横幅
+func isSectionBanner(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "# ---- ") && strings.HasSuffix(trimmed, " ----")
+}
+
+// commentWithExample 将示例值附加到注释末尾，用于行尾注释类风格
+func commentWithExample(field FieldInfo) string {
+	if field.Example == "" {
+		return field.Comment
+	}
+	if field.Comment == "" {
+		return "e.g. " + field.Example
+	}
+	return fmt.Sprintf("%s (e.g. %s)", field.Comment, field.Example)
+}
+
+// writeExampleLine 在字段上方写入注释掉的示例行，如 "# e.g. host: db.prod.internal"
+func writeExampleLine(result *strings.Builder, field FieldInfo, indentStr string) {
+	if field.Example != "" {
+		result.WriteString(fmt.Sprintf("%s# e.g. %s: %s\n", indentStr, field.Name, field.Example))
+	}
+}
+
 // sanitizeComment 清理注释内容
 func sanitizeComment(comment string) string {
 	// 移除注释中的换行符和制表符，替换为空格
@@ -1413,11 +2527,22 @@ func sanitizeComment(comment string) string {
 	return strings.Join(words, " ")
 }
 
+// flattenComment 清理单行注释，多行注释被合并为一行时记录警告
+func flattenComment(comment string, fieldPath string, options *Options) string {
+	if strings.ContainsAny(strings.TrimSpace(comment), "\n\r") {
+		warn(options, WarningCommentFlattened, fieldPath, "multi-line comment joined into a single line")
+	}
+	return sanitizeComment(comment)
+}
+
 // hasChildren 检查值是否有子元素
 func hasChildren(val reflect.Value) bool {
 	if !val.IsValid() {
 		return false
 	}
+	if isRawType(val.Type()) {
+		return rawHasChildren(val)
+	}
 
 	switch val.Kind() {
 	case reflect.Struct:
@@ -1454,6 +2579,9 @@ func isComplexType(val reflect.Value) bool {
 	if !val.IsValid() {
 		return false
 	}
+	if isRawType(val.Type()) {
+		return rawHasChildren(val)
+	}
 
 	switch val.Kind() {
 	case reflect.Struct, reflect.Map:
@@ -1470,137 +2598,107 @@ func isComplexType(val reflect.Value) bool {
 	}
 }
 
-// needsQuoting 检查字符串是否需要引号
-func needsQuoting(str string) bool {
-	if str == "" {
-		return true
-	}
-
-	// YAML保留字
-	yamlKeywords := map[string]bool{
-		"true": true, "false": true, "yes": true, "no": true,
-		"on": true, "off": true, "null": true, "nil": true,
-		"~": true, "True": true, "False": true, "TRUE": true, "FALSE": true,
-		"Yes": true, "No": true, "YES": true, "NO": true,
-		"On": true, "Off": true, "ON": true, "OFF": true,
-		"Null": true, "NULL": true, "Nil": true, "NIL": true,
-	}
-
-	if yamlKeywords[str] {
-		return true
-	}
-
-	// 检查是否为数字格式
-	if isNumericString(str) {
-		return true
-	}
-
-	// 检查特殊字符
-	if strings.ContainsAny(str, ":#{}[]&*!|>\"'%?@`") {
-		return true
-	}
-
-	// 检查换行符
-	if strings.Contains(str, "\n") {
-		return true
-	}
-
-	// 检查前后空格
-	if strings.HasPrefix(str, " ") || strings.HasSuffix(str, " ") {
-		return true
-	}
-
-	// 检查是否以数字开头的特殊格式
-	if len(str) > 0 && (str[0] == '+' || str[0] == '-') {
-		return true
-	}
-
-	return false
-}
-
-// isNumericString 检查字符串是否为数字格式
-func isNumericString(str string) bool {
-	if str == "" {
-		return false
-	}
-
-	// 简单的数字格式检查
-	for i, r := range str {
-		if i == 0 && (r == '+' || r == '-') {
-			continue
-		}
-		if !unicode.IsDigit(r) && r != '.' && r != 'e' && r != 'E' {
-			return false
+// SetGlobalStyle 设置默认配置的注释风格，其余默认选项保持不变
+// 需要在并发生成时使用不同风格，请为每个场景创建各自的 Config
+func SetGlobalStyle(style CommentStyle) {
+	for {
+		current := Default()
+		if defaultConfig.CompareAndSwap(current, current.withStyle(style)) {
+			return
 		}
 	}
-
-	// 更严格的检查：尝试解析为数字
-	return strings.ContainsAny(str, "0123456789")
-}
-
-// SetGlobalStyle 设置全局注释风格
-func SetGlobalStyle(style CommentStyle) {
-	GlobalCommentStyle = style
 }
 
 // GetStyleFromString 从字符串获取风格枚举
 func GetStyleFromString(styleStr string) CommentStyle {
-	switch strings.ToLower(styleStr) {
+	if style, ok := parseStyle(styleStr); ok {
+		return style
+	}
+	return StyleSmart
+}
+
+// parseStyle 解析风格名称，未知名称返回false
+func parseStyle(styleStr string) (CommentStyle, bool) {
+	switch strings.ToLower(strings.TrimSpace(styleStr)) {
 	case "top":
-		return StyleTop
+		return StyleTop, true
 	case "inline":
-		return StyleInline
+		return StyleInline, true
 	case "smart":
-		return StyleSmart
+		return StyleSmart, true
 	case "compact":
-		return StyleCompact
+		return StyleCompact, true
 	case "minimal":
-		return StyleMinimal
+		return StyleMinimal, true
 	case "verbose":
-		return StyleVerbose
+		return StyleVerbose, true
 	case "spaced":
-		return StyleSpaced
+		return StyleSpaced, true
 	case "grouped":
-		return StyleGrouped
+		return StyleGrouped, true
 	case "sectioned":
-		return StyleSectioned
+		return StyleSectioned, true
 	case "doc":
-		return StyleDoc
+		return StyleDoc, true
 	case "separate":
-		return StyleSeparate
+		return StyleSeparate, true
+	case "annotated":
+		return StyleAnnotated, true
+	case "commented-defaults":
+		return StyleCommentedDefaults, true
+	case "helm-docs":
+		return StyleHelmDocs, true
 	default:
-		return StyleSmart
+		if style, ok := lookupStyleName(styleStr); ok {
+			return style, true
+		}
+		return StyleSmart, false
 	}
 }
 
-// ValidateOptions 验证选项配置
+// ValidateOptions 验证选项配置，错误可用 errors.Is 判断：ErrNilOptions、ErrInvalidStyle 或 *ErrInvalidComment
 func ValidateOptions(options *Options) error {
 	if options == nil {
-		return fmt.Errorf("options cannot be nil")
+		return ErrNilOptions
 	}
 
-	// 验证注释风格范围
-	if int(options.Style) < 0 || int(options.Style) > int(StyleSeparate) {
-		return fmt.Errorf("invalid comment style: %d", options.Style)
+	// 验证注释风格为内置或已注册的风格
+	if _, ok := lookupStyle(options.Style); !ok {
+		return fmt.Errorf("%w: %d", ErrInvalidStyle, options.Style)
 	}
 
 	// 验证注释内容
 	for i, commentMap := range options.Comments {
 		if commentMap == nil {
-			return fmt.Errorf("comment map at index %d cannot be nil", i)
+			return &ErrInvalidComment{Err: fmt.Errorf("comment map at index %d cannot be nil", i)}
 		}
+		if err := validateCommentMap(commentMap, fmt.Sprintf("comment map at index %d", i)); err != nil {
+			return err
+		}
+	}
+	for i, layer := range options.CommentLayers {
+		if layer.Comments == nil {
+			return &ErrInvalidComment{Err: fmt.Errorf("comment layer at index %d cannot be nil", i)}
+		}
+		if err := validateCommentMap(layer.Comments, fmt.Sprintf("comment layer at index %d", i)); err != nil {
+			return err
+		}
+	}
 
-		for fieldPath, comment := range commentMap {
-			if fieldPath == "" {
-				return fmt.Errorf("field path cannot be empty in comment map at index %d", i)
-			}
+	return nil
+}
 
-			if err := validateCommentContent(comment); err != nil {
-				return fmt.Errorf("invalid comment for field %q: %w", fieldPath, err)
-			}
+// validateCommentMap 验证注释映射中的路径和注释内容，where 用于错误信息
+func validateCommentMap(commentMap map[string]string, where string) error {
+	for fieldPath, comment := range commentMap {
+		if fieldPath == "" {
+			return &ErrInvalidComment{Err: fmt.Errorf("field path cannot be empty in %s", where)}
 		}
-	}
 
+		if err := validateCommentContent(comment); err != nil {
+			return &ErrInvalidComment{Path: fieldPath, Err: err}
+		}
+	}
 	return nil
 }
 
@@ -1624,24 +2722,17 @@ func validateCommentContent(comment string) error {
 func GenWithValidation(v interface{}, opts ...Option) ([]byte, error) {
 	// 预验证输入
 	if v == nil {
-		return nil, fmt.Errorf("input value cannot be nil")
+		return nil, ErrNilInput
 	}
 
 	// 验证反射值
 	val := reflect.ValueOf(v)
 	if val.Kind() == reflect.Ptr && val.IsNil() {
-		return nil, fmt.Errorf("input pointer cannot be nil")
+		return nil, fmt.Errorf("%w: got nil %T", ErrNilInput, v)
 	}
 
 	// 构建和验证选项
-	options := &Options{
-		Style:    GlobalCommentStyle,
-		Comments: make([]map[string]string, 0),
-	}
-
-	for _, opt := range opts {
-		opt(options)
-	}
+	options := newOptions(opts...)
 
 	if err := ValidateOptions(options); err != nil {
 		return nil, fmt.Errorf("invalid options: %w", err)
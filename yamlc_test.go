@@ -7,6 +7,8 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+
+	"gopkg.in/yaml.v3"
 )
 
 // 测试数据结构
@@ -532,8 +534,8 @@ func TestCollectFieldInfo(t *testing.T) {
 		t.Fatal("No fields collected")
 	}
 
-	// 验证字段数量（应该排除私有字段和被忽略的字段）
-	expectedFieldCount := 9 // 导出的字段数量
+	// 验证字段数量（应该排除无标签字段和被忽略的字段）
+	expectedFieldCount := 12 // 带标签的导出字段数量
 	if len(fields) != expectedFieldCount {
 		t.Errorf("Expected %d fields, got %d", expectedFieldCount, len(fields))
 	}
@@ -541,7 +543,7 @@ func TestCollectFieldInfo(t *testing.T) {
 	// 验证字段信息
 	foundName := false
 	for _, field := range fields {
-		if field.Name == "Name" {
+		if field.Name == "name" {
 			foundName = true
 			if field.Comment != "用户姓名" {
 				t.Errorf("Expected comment '用户姓名', got '%s'", field.Comment)
@@ -566,7 +568,13 @@ func TestNeedsQuoting(t *testing.T) {
 		{"true", true},         // YAML关键字
 		{"123", true},          // 数字格式
 		{"hello world", false}, // 包含空格
-		{"hello:world", true},  // 包含特殊字符
+		{"hello:world", false}, // 冒号后没有空格时是普通字符
+		{"hello: world", true}, // 包含 ": "
+		{"- item", true},       // 以列表指示符开头
+		{"1.20", true},         // 会被解析为浮点数
+		{"1.2.3", false},       // 版本号
+		{"0x1F", true},         // 十六进制数字
+		{"张三", false},          // 中文
 		{"hello\nworld", true}, // 包含换行符
 		{" hello", true},       // 前导空格
 		{"hello ", true},       // 尾随空格
@@ -585,6 +593,7 @@ func TestNeedsQuoting(t *testing.T) {
 // 测试性能基准
 func BenchmarkGen(b *testing.B) {
 	user := createTestUser()
+	b.ReportAllocs()
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
@@ -597,6 +606,7 @@ func BenchmarkGen(b *testing.B) {
 
 func BenchmarkGenWithStyle(b *testing.B) {
 	user := createTestUser()
+	b.ReportAllocs()
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
@@ -606,6 +616,18 @@ func BenchmarkGenWithStyle(b *testing.B) {
 		}
 	}
 }
+func BenchmarkGenInline(b *testing.B) {
+	user := createTestUser()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := Gen(user, WithStyle(StyleInline))
+		if err != nil {
+			b.Fatalf("Gen with inline style failed: %v", err)
+		}
+	}
+}
 
 // 测试辅助函数
 func TestHelperFunctions(t *testing.T) {
@@ -632,3 +654,383 @@ func TestHelperFunctions(t *testing.T) {
 		t.Errorf("Expected indent level 2, got %d", level)
 	}
 }
+
+// 测试 example 标签
+func TestExampleTag(t *testing.T) {
+	type DB struct {
+		Host string `yaml:"host" yamlc:"comment=数据库地址,example=db.prod.internal"`
+		Port int    `yaml:"port" yamlc:"example=5432"`
+	}
+	db := &DB{Host: "localhost", Port: 3306}
+
+	data, err := Gen(db, WithStyle(StyleTop))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	yamlStr := string(data)
+	if !strings.Contains(yamlStr, "# 数据库地址\n# e.g. host: db.prod.internal\nhost: localhost\n") {
+		t.Errorf("Top style should render example as commented-out line, got:\n%s", yamlStr)
+	}
+
+	data, err = Gen(db, WithStyle(StyleCompact))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	yamlStr = string(data)
+	if !strings.Contains(yamlStr, "host: localhost # 数据库地址 (e.g. db.prod.internal)") {
+		t.Errorf("Compact style should append example to comment, got:\n%s", yamlStr)
+	}
+	if !strings.Contains(yamlStr, "port: 3306 # e.g. 5432") {
+		t.Errorf("Compact style should render example without comment, got:\n%s", yamlStr)
+	}
+}
+
+// 测试字段级风格覆盖
+func TestFieldStyleOverride(t *testing.T) {
+	type Limits struct {
+		CPU    int `yaml:"cpu"    yamlc:"comment=CPU核数"`
+		Memory int `yaml:"memory" yamlc:"comment=内存大小"`
+	}
+	type Service struct {
+		Name   string  `yaml:"name"   yamlc:"comment=服务名"`
+		Limits *Limits `yaml:"limits" yamlc:"comment=资源限制,style=inline"`
+		Port   int     `yaml:"port"   yamlc:"comment=端口,style=compact"`
+	}
+	svc := &Service{Name: "api", Limits: &Limits{CPU: 2, Memory: 512}, Port: 8080}
+
+	data, err := Gen(svc, WithStyle(StyleTop))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	yamlStr := string(data)
+	if !strings.Contains(yamlStr, "# 服务名\nname: api\n") {
+		t.Errorf("document style should stay top for untagged fields:\n%s", yamlStr)
+	}
+	for _, line := range strings.Split(yamlStr, "\n") {
+		if strings.HasPrefix(line, "  cpu:") && !strings.Contains(line, "# CPU核数") {
+			t.Errorf("subtree should use inline comments, got line %q", line)
+		}
+	}
+	if !strings.Contains(yamlStr, "port: 8080 # 端口") {
+		t.Errorf("scalar field should use compact comment:\n%s", yamlStr)
+	}
+}
+
+// 测试按路径覆盖风格
+func TestWithFieldStyleOverride(t *testing.T) {
+	user := createTestUser()
+
+	data, err := Gen(user, WithStyle(StyleTop), WithFieldStyleOverride("address.*", StyleCompact))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	yamlStr := string(data)
+	if !strings.Contains(yamlStr, "  city: 北京 # 城市") {
+		t.Errorf("address.* should use compact comments:\n%s", yamlStr)
+	}
+	if !strings.Contains(yamlStr, "  # 城市\n  city: \"\"") {
+		t.Errorf("address2.* should keep top comments:\n%s", yamlStr)
+	}
+}
+
+// 测试分节横幅
+func TestSectionTag(t *testing.T) {
+	type Config struct {
+		Name   string `yaml:"name"   yamlc:"comment=应用名"`
+		DBHost string `yaml:"dbHost" yamlc:"comment=数据库地址,section=Database Settings"`
+		DBPort int    `yaml:"dbPort" yamlc:"comment=数据库端口"`
+		Debug  bool   `yaml:"debug"  yamlc:"comment=调试模式,section=Logging"`
+	}
+	cfg := &Config{Name: "app", DBHost: "localhost", DBPort: 5432}
+
+	for _, style := range []CommentStyle{StyleTop, StyleSpaced} {
+		data, err := Gen(cfg, WithStyle(style))
+		if err != nil {
+			t.Fatalf("Gen failed: %v", err)
+		}
+		yamlStr := string(data)
+		if !strings.Contains(yamlStr, "\n\n# ---- Database Settings ----\n# 数据库地址\ndbHost: localhost\n") {
+			t.Errorf("style %s: database banner missing:\n%s", GetStyleString(int(style)), yamlStr)
+		}
+		if !strings.Contains(yamlStr, "# ---- Logging ----\n# 调试模式\ndebug: false") {
+			t.Errorf("style %s: logging banner missing:\n%s", GetStyleString(int(style)), yamlStr)
+		}
+	}
+}
+
+// 测试列表元素中的分节横幅留在元素的映射内
+func TestSectionTagInList(t *testing.T) {
+	type Database struct {
+		Host string `yaml:"host" yamlc:"comment=主机,section=Connection"`
+		Port int    `yaml:"port" yamlc:"comment=端口"`
+		User string `yaml:"user" yamlc:"section=Auth"`
+	}
+	type Config struct {
+		Databases []Database `yaml:"databases" yamlc:"comment=数据库"`
+	}
+	cfg := Config{Databases: []Database{{Host: "a", Port: 1}, {Host: "b", Port: 2}}}
+
+	expected := "# 数据库\ndatabases:\n" +
+		"  - # ---- Connection ----\n    # 主机\n    host: a\n    # 端口\n    port: 1\n    # ---- Auth ----\n    user: \"\"\n" +
+		"  - host: b\n    port: 2\n    user: \"\"\n"
+	for _, style := range []CommentStyle{StyleTop, StyleSpaced} {
+		data, err := Gen(cfg, WithStyle(style))
+		if err != nil {
+			t.Fatalf("Gen failed: %v", err)
+		}
+		if strings.TrimRight(string(data), "\n") != strings.TrimRight(expected, "\n") {
+			t.Errorf("style %s: expected:\n%s\ngot:\n%s", GetStyleString(int(style)), expected, data)
+		}
+		decoded, err := Unmarshal[Config](data)
+		if err != nil || !reflect.DeepEqual(decoded, cfg) {
+			t.Errorf("style %s: output should round-trip, got %+v (%v)", GetStyleString(int(style)), decoded, err)
+		}
+	}
+}
+
+type commentedTLS struct {
+	Cert string `yaml:"cert" yamlc:"comment=证书路径"`
+}
+
+func (commentedTLS) TypeComment() string {
+	return "TLS配置\n证书需为PEM格式"
+}
+
+// 测试结构体类型头部注释
+func TestTypeComment(t *testing.T) {
+	type Server struct {
+		TLS     commentedTLS `yaml:"tls"     yamlc:"comment=传输加密"`
+		Address *Address     `yaml:"address" yamlc:"comment=地址"`
+	}
+	server := &Server{TLS: commentedTLS{Cert: "/etc/cert.pem"}, Address: &Address{City: "北京"}}
+
+	data, err := Gen(server, WithTypeComment(map[string]string{"Address": "地址信息"}))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	yamlStr := string(data)
+	if !strings.Contains(yamlStr, "tls:\n  # TLS配置\n  # 证书需为PEM格式\n  # 证书路径\n  cert:") {
+		t.Errorf("TypeComment interface not rendered:\n%s", yamlStr)
+	}
+	if !strings.Contains(yamlStr, "address:\n  # 地址信息\n") {
+		t.Errorf("WithTypeComment not rendered:\n%s", yamlStr)
+	}
+
+	data, err = Gen(server.Address, WithTypeComment(map[string]string{"yamlc.Address": "顶层地址"}))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "# 顶层地址\n") {
+		t.Errorf("top-level type comment should head the document:\n%s", data)
+	}
+}
+
+// 测试自定义缩进宽度
+func TestWithIndent(t *testing.T) {
+	user := createTestUser()
+
+	for _, style := range GetAllStyle() {
+		t.Run(GetStyleString(int(style)), func(t *testing.T) {
+			expected, err := Gen(user, WithStyle(style))
+			if err != nil {
+				t.Fatalf("Gen failed: %v", err)
+			}
+			if !strings.Contains(string(expected), "\n  city: 北京") {
+				t.Errorf("nested fields should use the default 2-space indent:\n%s", expected)
+			}
+			data, err := Gen(user, WithStyle(style), WithIndent(4))
+			if err != nil {
+				t.Fatalf("Gen with indent failed: %v", err)
+			}
+
+			// 缩进宽度不影响解析结果
+			var want, got interface{}
+			if err := yaml.Unmarshal(expected, &want); err != nil {
+				t.Fatalf("unmarshal default output: %v", err)
+			}
+			if err := yaml.Unmarshal(data, &got); err != nil {
+				t.Fatalf("unmarshal indented output: %v", err)
+			}
+			if !reflect.DeepEqual(want, got) {
+				t.Errorf("indent changed document content:\n%s", data)
+			}
+			if !strings.Contains(string(data), "\n    city: 北京") {
+				t.Errorf("nested fields should use 4-space indent:\n%s", data)
+			}
+		})
+	}
+}
+
+// 测试按缩进宽度验证结构
+func TestValidateStructureIndent(t *testing.T) {
+	data := []byte("address:\n    street: 中关村大街1号\n    city: 北京\n")
+	if err := ValidateStructureIndent(data, 4); err != nil {
+		t.Errorf("ValidateStructureIndent should pass for 4-space YAML: %v", err)
+	}
+	if err := ValidateStructureIndent([]byte("address:\n  city: 北京\n"), 4); err == nil {
+		t.Error("ValidateStructureIndent should fail for 2-space YAML with width 4")
+	}
+}
+
+// 测试深层嵌套的结构验证
+func TestValidateStructureIndentDeepNesting(t *testing.T) {
+	for _, width := range []int{2, 4} {
+		var b strings.Builder
+		for level := 0; level < 8; level++ {
+			b.WriteString(strings.Repeat(" ", level*width) + fmt.Sprintf("level%d:\n", level))
+		}
+		b.WriteString(strings.Repeat(" ", 8*width) + "value: 1\n")
+		for level := 6; level >= 0; level-- {
+			b.WriteString(strings.Repeat(" ", level*width) + fmt.Sprintf("sibling%d: 2\n", level))
+		}
+		b.WriteString(strings.Repeat(" ", width) + "again:\n")
+		b.WriteString(strings.Repeat(" ", 2*width) + "deep: 3\n")
+
+		if err := ValidateStructureIndent([]byte(b.String()), width); err != nil {
+			t.Errorf("width %d: ValidateStructureIndent should pass for deeply nested YAML: %v\n%s", width, err, b.String())
+		}
+	}
+}
+
+// 测试字段排序
+func TestWithFieldOrder(t *testing.T) {
+	type Config struct {
+		Zone    string `yaml:"zone"    yamlc:"comment=区域,weight=3"`
+		Address string `yaml:"address" yamlc:"comment=地址,order=1"`
+		Mode    string `yaml:"mode"    yamlc:"comment=模式"`
+		Budget  int    `yaml:"budget"  yamlc:"comment=预算,weight=-1"`
+	}
+	cfg := &Config{Zone: "cn", Address: "a", Mode: "m", Budget: 1}
+
+	testCases := []struct {
+		order    FieldOrder
+		expected []string
+	}{
+		{OrderDeclared, []string{"zone", "address", "mode", "budget"}},
+		{OrderAlphabetical, []string{"address", "budget", "mode", "zone"}},
+		{OrderWeight, []string{"budget", "mode", "address", "zone"}},
+	}
+
+	for _, tc := range testCases {
+		for _, style := range []CommentStyle{StyleCompact, StyleMinimal} {
+			data, err := Gen(cfg, WithStyle(style), WithFieldOrder(tc.order))
+			if err != nil {
+				t.Fatalf("Gen failed: %v", err)
+			}
+			var keys []string
+			for _, line := range strings.Split(string(data), "\n") {
+				if idx := strings.Index(line, ":"); idx > 0 {
+					keys = append(keys, line[:idx])
+				}
+			}
+			if !reflect.DeepEqual(keys, tc.expected) {
+				t.Errorf("style %s, order %d: got %v, expected %v",
+					GetStyleString(int(style)), tc.order, keys, tc.expected)
+			}
+		}
+	}
+}
+
+// 测试映射键的路径注释
+func TestMapEntryComments(t *testing.T) {
+	type Server struct {
+		Host string `yaml:"host" yamlc:"comment=主机"`
+		Port int    `yaml:"port"`
+	}
+	type Config struct {
+		Servers map[string]*Server `yaml:"servers" yamlc:"comment=服务列表"`
+		Limits  map[string]int     `yaml:"limits"  yamlc:"comment=资源限制"`
+	}
+	cfg := &Config{
+		Servers: map[string]*Server{
+			"web": {Host: "10.0.0.1", Port: 80},
+			"api": {Host: "10.0.0.2", Port: 8080},
+		},
+		Limits: map[string]int{"cpu": 2, "memory": 512},
+	}
+	comments := map[string]string{
+		"servers.*.port": "监听端口",
+		"servers.api":    "接口服务",
+		"limits.cpu":     "CPU核数",
+		"limits.*":       "其他限制",
+	}
+
+	data, err := Gen(cfg, WithStyle(StyleTop), WithComment(comments))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	yamlStr := string(data)
+	for _, expected := range []string{
+		"  # 接口服务\n  api:\n",
+		"    # 监听端口\n    port: 8080\n",
+		"    # 监听端口\n    port: 80\n",
+		"  # CPU核数\n  cpu: 2\n",
+		"  # 其他限制\n  memory: 512\n",
+	} {
+		if !strings.Contains(yamlStr, expected) {
+			t.Errorf("expected %q in:\n%s", expected, yamlStr)
+		}
+	}
+	// 映射按键排序输出
+	if strings.Index(yamlStr, "api:") > strings.Index(yamlStr, "web:") {
+		t.Errorf("map keys should be sorted:\n%s", yamlStr)
+	}
+
+	for _, style := range GetAllStyle() {
+		if _, err := Gen(cfg, WithStyle(style), WithComment(comments)); err != nil {
+			t.Errorf("style %s failed: %v", GetStyleString(int(style)), err)
+		}
+	}
+}
+
+func TestSliceElementComments(t *testing.T) {
+	type Job struct {
+		Company  string `yaml:"company" comment:"公司名"`
+		Position string `yaml:"position" comment:"职位"`
+	}
+	type Profile struct {
+		Tags []string `yaml:"tags" comment:"标签"`
+		Jobs []Job    `yaml:"jobs" comment:"任职经历"`
+	}
+	profile := &Profile{
+		Tags: []string{"开发者", "后端"},
+		Jobs: []Job{{"co1", "dev"}, {"co2", "lead"}},
+	}
+	comments := map[string]string{
+		"tags.1":         "第二个标签",
+		"jobs.1.company": "现任公司",
+	}
+
+	data, err := Gen(profile, WithStyle(StyleTop), WithComment(comments))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	yamlStr := string(data)
+	for _, expected := range []string{
+		"  - 开发者\n  # 第二个标签\n  - 后端\n",
+		"    # 公司名\n    # 职位\n  - company: co1\n",
+		"    # 现任公司\n  - company: co2\n    position: lead\n",
+	} {
+		if !strings.Contains(yamlStr, expected) {
+			t.Errorf("expected %q in:\n%s", expected, yamlStr)
+		}
+	}
+
+	// 不带下标的路径仍作用于首个元素
+	data, err = Gen(profile, WithComment(map[string]string{"jobs.company": "雇主"}))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if yamlStr = string(data); strings.Count(yamlStr, "# 雇主") != 1 {
+		t.Errorf("generic element comment should appear once:\n%s", yamlStr)
+	}
+
+	data, err = Gen(profile, WithStyle(StyleCompact), WithComment(comments))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	if yamlStr = string(data); !strings.Contains(yamlStr, "  - 后端 # 第二个标签\n") ||
+		!strings.Contains(yamlStr, "company: co2 # 现任公司\n") {
+		t.Errorf("expected trailing element comments in:\n%s", yamlStr)
+	}
+}
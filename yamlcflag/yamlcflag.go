@@ -0,0 +1,276 @@
+// Package yamlcflag 让命令行参数与配置文件文档保持一致：按带yamlc标签的结构体注册pflag参数（cobra的 cmd.Flags() 同样适用），
+// 并可在生成的YAML注释中标注对应的 --参数名
+package yamlcflag
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"binrc.com/pkg/yamlc"
+	"github.com/spf13/pflag"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// flagField 可注册为命令行参数的字段
+type flagField struct {
+	field reflect.StructField
+	value reflect.Value
+	path  string // 配置路径，如 "database.port"
+	name  string // 参数名，如 "database-port"
+}
+
+// RegisterFlags 为结构体 v 中的每个配置项注册命令行参数，参数直接绑定到字段，解析后即更新结构体
+// 参数名由配置路径转换而来（"database.maxConns" 对应 "database-max-conns"），可用 flag 标签指定，flag:"-" 表示不注册；
+// 默认值取字段当前值，说明取标签注释，带secret标签的字段不在帮助中显示默认值。
+// 支持字符串、布尔、整数、浮点数、time.Duration 以及字符串、整数、布尔列表，其余类型的字段跳过
+func RegisterFlags(fs *pflag.FlagSet, v interface{}) error {
+	if fs == nil {
+		return fmt.Errorf("flag set cannot be nil")
+	}
+	val := reflect.ValueOf(v)
+	if v == nil || val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("value must be a non-nil pointer to struct, got %T", v)
+	}
+
+	fields := collectFields(val.Elem(), "", nil)
+	for _, f := range fields {
+		if fs.Lookup(f.name) != nil {
+			return fmt.Errorf("flag --%s for %q already registered", f.name, f.path)
+		}
+	}
+	for _, f := range fields {
+		register(fs, f)
+		if hasSecretTag(f.field) {
+			fs.Lookup(f.name).DefValue = ""
+		}
+	}
+	return nil
+}
+
+// FlagNames 返回结构体 v 中各配置路径对应的参数名（不含 "--"），与 RegisterFlags 注册的参数一致
+func FlagNames(v interface{}) (map[string]string, error) {
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Ptr && !val.IsNil() {
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("value must be a struct or pointer to struct, got %T", v)
+	}
+	names := make(map[string]string)
+	for _, f := range collectFields(val, "", nil) {
+		names[f.path] = f.name
+	}
+	return names, nil
+}
+
+// WithFlagNames 在生成的YAML中为每个可由命令行参数设置的配置项注释末尾标注参数名，如 "数据库端口 (--database-port)"
+// 等同于按路径设置 WithComment，之后再用 WithComment 设置的同一路径的注释优先；v 不是结构体时不做任何修改
+func WithFlagNames(v interface{}) yamlc.Option {
+	comments := make(map[string]string)
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Ptr && !val.IsNil() {
+		val = val.Elem()
+	}
+	if val.Kind() == reflect.Struct {
+		for _, f := range collectFields(val, "", nil) {
+			comment := "(--" + f.name + ")"
+			if tagComment := yamlc.TagComment(f.field); tagComment != "" {
+				comment = tagComment + " " + comment
+			}
+			comments[f.path] = comment
+		}
+	}
+	return yamlc.WithComment(comments)
+}
+
+// collectFields 递归收集结构体中可注册为参数的字段，内嵌（inline）字段展开到当前层级
+func collectFields(val reflect.Value, path string, fields []flagField) []flagField {
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() || field.Tag.Get("flag") == "-" {
+			continue
+		}
+		key, inline, skip := yamlKey(field)
+		if skip {
+			continue
+		}
+		value := val.Field(i)
+		fieldPath := path
+		if !inline {
+			fieldPath = joinPath(path, key)
+		}
+
+		if isLeaf(field.Type) {
+			name := field.Tag.Get("flag")
+			if name == "" {
+				name = flagName(fieldPath)
+			}
+			fields = append(fields, flagField{field: field, value: value, path: fieldPath, name: name})
+			continue
+		}
+		for value.Kind() == reflect.Ptr && !value.IsNil() {
+			value = value.Elem()
+		}
+		if value.Kind() == reflect.Struct && !reflect.PtrTo(value.Type()).Implements(textUnmarshalerType) {
+			fields = collectFields(value, fieldPath, fields)
+		}
+	}
+	return fields
+}
+
+// yamlKey 按yaml.v3的规则获取字段的键名
+func yamlKey(field reflect.StructField) (key string, inline, skip bool) {
+	key = strings.ToLower(field.Name)
+	tag := field.Tag.Get("yaml")
+	if tag == "" {
+		return key, false, false
+	}
+	parts := strings.Split(tag, ",")
+	if parts[0] == "-" {
+		return "", false, true
+	}
+	if parts[0] != "" {
+		key = parts[0]
+	}
+	for _, part := range parts[1:] {
+		if part == "inline" {
+			return key, true, false
+		}
+	}
+	return key, false, false
+}
+
+// isLeaf 判断字段类型是否有对应的pflag参数类型
+func isLeaf(typ reflect.Type) bool {
+	if typ == durationType {
+		return true
+	}
+	switch typ.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		switch typ.Elem().Kind() {
+		case reflect.String, reflect.Int, reflect.Bool:
+			return typ.Elem().Name() == typ.Elem().Kind().String()
+		}
+	}
+	return false
+}
+
+// register 按字段类型注册参数；命名类型（如 type Mode string）通过指针转换绑定到底层类型
+func register(fs *pflag.FlagSet, f flagField) {
+	usage := yamlc.TagComment(f.field)
+	ptr := f.value.Addr()
+	if f.value.Type() == durationType {
+		p := ptr.Interface().(*time.Duration)
+		fs.DurationVar(p, f.name, *p, usage)
+		return
+	}
+	switch f.value.Kind() {
+	case reflect.String:
+		p := ptr.Convert(reflect.TypeOf((*string)(nil))).Interface().(*string)
+		fs.StringVar(p, f.name, *p, usage)
+	case reflect.Bool:
+		p := ptr.Convert(reflect.TypeOf((*bool)(nil))).Interface().(*bool)
+		fs.BoolVar(p, f.name, *p, usage)
+	case reflect.Int:
+		p := ptr.Convert(reflect.TypeOf((*int)(nil))).Interface().(*int)
+		fs.IntVar(p, f.name, *p, usage)
+	case reflect.Int8:
+		p := ptr.Convert(reflect.TypeOf((*int8)(nil))).Interface().(*int8)
+		fs.Int8Var(p, f.name, *p, usage)
+	case reflect.Int16:
+		p := ptr.Convert(reflect.TypeOf((*int16)(nil))).Interface().(*int16)
+		fs.Int16Var(p, f.name, *p, usage)
+	case reflect.Int32:
+		p := ptr.Convert(reflect.TypeOf((*int32)(nil))).Interface().(*int32)
+		fs.Int32Var(p, f.name, *p, usage)
+	case reflect.Int64:
+		p := ptr.Convert(reflect.TypeOf((*int64)(nil))).Interface().(*int64)
+		fs.Int64Var(p, f.name, *p, usage)
+	case reflect.Uint:
+		p := ptr.Convert(reflect.TypeOf((*uint)(nil))).Interface().(*uint)
+		fs.UintVar(p, f.name, *p, usage)
+	case reflect.Uint8:
+		p := ptr.Convert(reflect.TypeOf((*uint8)(nil))).Interface().(*uint8)
+		fs.Uint8Var(p, f.name, *p, usage)
+	case reflect.Uint16:
+		p := ptr.Convert(reflect.TypeOf((*uint16)(nil))).Interface().(*uint16)
+		fs.Uint16Var(p, f.name, *p, usage)
+	case reflect.Uint32:
+		p := ptr.Convert(reflect.TypeOf((*uint32)(nil))).Interface().(*uint32)
+		fs.Uint32Var(p, f.name, *p, usage)
+	case reflect.Uint64:
+		p := ptr.Convert(reflect.TypeOf((*uint64)(nil))).Interface().(*uint64)
+		fs.Uint64Var(p, f.name, *p, usage)
+	case reflect.Float32:
+		p := ptr.Convert(reflect.TypeOf((*float32)(nil))).Interface().(*float32)
+		fs.Float32Var(p, f.name, *p, usage)
+	case reflect.Float64:
+		p := ptr.Convert(reflect.TypeOf((*float64)(nil))).Interface().(*float64)
+		fs.Float64Var(p, f.name, *p, usage)
+	case reflect.Slice:
+		switch f.value.Type().Elem().Kind() {
+		case reflect.String:
+			p := ptr.Convert(reflect.TypeOf((*[]string)(nil))).Interface().(*[]string)
+			fs.StringSliceVar(p, f.name, *p, usage)
+		case reflect.Int:
+			p := ptr.Convert(reflect.TypeOf((*[]int)(nil))).Interface().(*[]int)
+			fs.IntSliceVar(p, f.name, *p, usage)
+		case reflect.Bool:
+			p := ptr.Convert(reflect.TypeOf((*[]bool)(nil))).Interface().(*[]bool)
+			fs.BoolSliceVar(p, f.name, *p, usage)
+		}
+	}
+}
+
+// hasSecretTag 检查字段是否带有secret标签
+func hasSecretTag(field reflect.StructField) bool {
+	for _, part := range strings.Split(field.Tag.Get("yamlc"), ",") {
+		if part = strings.TrimSpace(part); part == "secret" || part == "secret=true" {
+			return true
+		}
+	}
+	return false
+}
+
+// joinPath 拼接配置路径
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// flagName 将配置路径转换为短横线分隔的小写参数名，如 "database.maxConns" 转换为 "database-max-conns"
+func flagName(path string) string {
+	runes := []rune(path)
+	var b strings.Builder
+	for i, r := range runes {
+		switch {
+		case r == '.' || r == '_' || r == ' ':
+			b.WriteByte('-')
+			continue
+		case unicode.IsUpper(r) && i > 0:
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('-')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
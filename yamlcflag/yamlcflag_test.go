@@ -0,0 +1,130 @@
+package yamlcflag
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"binrc.com/pkg/yamlc"
+	"github.com/spf13/pflag"
+)
+
+type logLevel string
+
+type database struct {
+	Host     string `yaml:"host"     yamlc:"comment=数据库地址"`
+	MaxConns int    `yaml:"maxConns" yamlc:"comment=最大连接数"`
+	Password string `yaml:"password" yamlc:"comment=密码,secret"`
+}
+
+type Common struct {
+	Debug bool `yaml:"debug" yamlc:"comment=调试模式"`
+}
+
+type cliConfig struct {
+	Common   `yaml:",inline"`
+	Name     string            `yaml:"name"     yamlc:"comment=服务名称"`
+	Level    logLevel          `yaml:"logLevel" yamlc:"comment=日志级别"`
+	Timeout  time.Duration     `yaml:"timeout"  yamlc:"comment=请求超时"`
+	Tags     []string          `yaml:"tags"     yamlc:"comment=标签"`
+	Port     int               `yaml:"port"     flag:"listen" yamlc:"comment=监听端口"`
+	Internal string            `yaml:"internal" flag:"-"`
+	Labels   map[string]string `yaml:"labels"`
+	Database database          `yaml:"database" yamlc:"comment=数据库配置"`
+}
+
+func newCLIConfig() *cliConfig {
+	return &cliConfig{
+		Name:     "demo",
+		Level:    "info",
+		Timeout:  30 * time.Second,
+		Port:     8080,
+		Database: database{Host: "localhost", MaxConns: 10, Password: "hunter2"},
+	}
+}
+
+func TestRegisterFlags(t *testing.T) {
+	cfg := newCLIConfig()
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	if err := RegisterFlags(fs, cfg); err != nil {
+		t.Fatalf("RegisterFlags failed: %v", err)
+	}
+
+	for _, name := range []string{"debug", "name", "log-level", "timeout", "tags", "listen", "database-host", "database-max-conns", "database-password"} {
+		if fs.Lookup(name) == nil {
+			t.Errorf("flag --%s not registered", name)
+		}
+	}
+	for _, name := range []string{"internal", "labels", "port"} {
+		if fs.Lookup(name) != nil {
+			t.Errorf("flag --%s should not be registered", name)
+		}
+	}
+	if usage := fs.Lookup("database-max-conns").Usage; usage != "最大连接数" {
+		t.Errorf("unexpected usage %q", usage)
+	}
+	if def := fs.Lookup("database-password").DefValue; def != "" {
+		t.Errorf("secret default leaked: %q", def)
+	}
+	if strings.Contains(fs.FlagUsages(), "hunter2") {
+		t.Errorf("secret default leaked in usage:\n%s", fs.FlagUsages())
+	}
+
+	err := fs.Parse([]string{"--debug", "--log-level=warn", "--timeout=5s", "--tags=a,b", "--listen=9090", "--database-max-conns=20"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !cfg.Debug || cfg.Level != "warn" || cfg.Timeout != 5*time.Second || cfg.Port != 9090 || cfg.Database.MaxConns != 20 {
+		t.Errorf("flags not applied: %+v", cfg)
+	}
+	if len(cfg.Tags) != 2 || cfg.Tags[1] != "b" {
+		t.Errorf("unexpected tags: %v", cfg.Tags)
+	}
+
+	if err := RegisterFlags(fs, newCLIConfig()); err == nil {
+		t.Error("expected error for duplicate flags")
+	}
+	if err := RegisterFlags(fs, *cfg); err == nil {
+		t.Error("expected error for non-pointer value")
+	}
+}
+
+func TestWithFlagNames(t *testing.T) {
+	names, err := FlagNames(newCLIConfig())
+	if err != nil {
+		t.Fatalf("FlagNames failed: %v", err)
+	}
+	if names["database.maxConns"] != "database-max-conns" || names["port"] != "listen" || names["debug"] != "debug" {
+		t.Errorf("unexpected names: %v", names)
+	}
+
+	data, err := yamlc.Gen(newCLIConfig(), yamlc.WithStyle(yamlc.StyleTop), WithFlagNames(newCLIConfig()))
+	if err != nil {
+		t.Fatalf("Gen failed: %v", err)
+	}
+	yamlStr := string(data)
+	for _, want := range []string{"# 最大连接数 (--database-max-conns)", "# 监听端口 (--listen)"} {
+		if !strings.Contains(yamlStr, want) {
+			t.Errorf("expected %q in output:\n%s", want, yamlStr)
+		}
+	}
+	if strings.Contains(yamlStr, "--internal") {
+		t.Errorf("unexpected flag annotation:\n%s", yamlStr)
+	}
+}
+
+func TestFlagName(t *testing.T) {
+	tests := map[string]string{
+		"name":              "name",
+		"logLevel":          "log-level",
+		"database.maxConns": "database-max-conns",
+		"max_conns":         "max-conns",
+		"APIKey":            "api-key",
+		"server.httpPort2":  "server-http-port2",
+	}
+	for path, want := range tests {
+		if got := flagName(path); got != want {
+			t.Errorf("flagName(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,336 @@
+// Package yamlcgen 从带注释的YAML文件反向生成Go结构体定义，
+// 字段带有 yaml 标签和取自文档注释的 yamlc 注释标签，便于在已有配置上接入yamlc
+package yamlcgen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPackage 默认的包名
+const DefaultPackage = "config"
+
+// DefaultTypeName 默认的顶层类型名
+const DefaultTypeName = "Config"
+
+// structDef 待输出的结构体定义
+type structDef struct {
+	name    string
+	comment string
+	fields  []fieldDef
+}
+
+// fieldDef 结构体字段
+type fieldDef struct {
+	name    string
+	key     string
+	typ     string
+	comment string
+}
+
+// generator 生成过程中的状态
+type generator struct {
+	structs []*structDef
+	names   map[string]bool
+}
+
+// Generate 读取YAML内容，生成包含顶层结构体及其嵌套结构体的Go源码
+// packageName 和 typeName 为空时分别使用 DefaultPackage 和 DefaultTypeName
+func Generate(data []byte, packageName, typeName string) ([]byte, error) {
+	if packageName == "" {
+		packageName = DefaultPackage
+	}
+	if typeName == "" {
+		typeName = DefaultTypeName
+	}
+
+	var doc yaml.Node
+	if err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("empty YAML document")
+		}
+		return nil, fmt.Errorf("YAML parsing error: %w", err)
+	}
+	root := resolveAlias(doc.Content[0])
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("top-level YAML node must be a mapping")
+	}
+
+	g := &generator{names: make(map[string]bool)}
+	g.structType(typeName, commentText(doc.HeadComment), []*yaml.Node{root})
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by yamlcgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n", packageName)
+	for _, def := range g.structs {
+		buf.WriteString("\n")
+		if def.comment != "" {
+			fmt.Fprintf(&buf, "// %s %s\n", def.name, def.comment)
+		}
+		fmt.Fprintf(&buf, "type %s struct {\n", def.name)
+		for _, field := range def.fields {
+			fmt.Fprintf(&buf, "\t%s %s `%s`\n", field.name, field.typ, fieldTag(field))
+		}
+		buf.WriteString("}\n")
+	}
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return source, nil
+}
+
+// structType 由一个或多个映射节点（如列表中的各个元素）生成结构体，返回类型名
+// 多个节点的键取并集，按首次出现的顺序排列
+func (g *generator) structType(name, comment string, nodes []*yaml.Node) string {
+	name = g.uniqueName(name)
+	def := &structDef{name: name, comment: comment}
+	g.structs = append(g.structs, def)
+
+	var keys []string
+	values := make(map[string][]*yaml.Node)
+	comments := make(map[string]string)
+	for _, node := range nodes {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode, valueNode := node.Content[i], resolveAlias(node.Content[i+1])
+			key := keyNode.Value
+			if _, ok := values[key]; !ok {
+				keys = append(keys, key)
+			}
+			values[key] = append(values[key], valueNode)
+			if comments[key] == "" {
+				comments[key] = nodeComment(keyNode, valueNode)
+			}
+		}
+	}
+
+	fieldNames := make(map[string]bool)
+	for _, key := range keys {
+		fieldName := exportedName(key)
+		for base, n := fieldName, 2; fieldNames[fieldName]; n++ {
+			fieldName = base + strconv.Itoa(n)
+		}
+		fieldNames[fieldName] = true
+		def.fields = append(def.fields, fieldDef{
+			name:    fieldName,
+			key:     key,
+			typ:     g.valueType(fieldName, values[key]),
+			comment: comments[key],
+		})
+	}
+	return name
+}
+
+// valueType 推断一组值节点共同的Go类型，name 用于命名嵌套结构体
+func (g *generator) valueType(name string, nodes []*yaml.Node) string {
+	var mappings, sequences, scalars []*yaml.Node
+	for _, node := range nodes {
+		switch node.Kind {
+		case yaml.MappingNode:
+			mappings = append(mappings, node)
+		case yaml.SequenceNode:
+			sequences = append(sequences, node)
+		case yaml.ScalarNode:
+			if node.Tag != "!!null" {
+				scalars = append(scalars, node)
+			}
+		}
+	}
+
+	switch {
+	case len(mappings) > 0 && len(sequences) == 0 && len(scalars) == 0:
+		if allEmpty(mappings) {
+			return "map[string]interface{}"
+		}
+		return g.structType(name, "", mappings)
+	case len(sequences) > 0 && len(mappings) == 0 && len(scalars) == 0:
+		var items []*yaml.Node
+		for _, sequence := range sequences {
+			for _, item := range sequence.Content {
+				items = append(items, resolveAlias(item))
+			}
+		}
+		if len(items) == 0 {
+			return "[]interface{}"
+		}
+		itemType := g.valueType(singular(name), items)
+		if strings.HasPrefix(itemType, "*") {
+			itemType = "interface{}"
+		}
+		return "[]" + itemType
+	case len(scalars) > 0 && len(mappings) == 0 && len(sequences) == 0:
+		typ := scalarType(scalars[0])
+		for _, scalar := range scalars[1:] {
+			other := scalarType(scalar)
+			if other == typ {
+				continue
+			}
+			if (typ == "int" && other == "float64") || (typ == "float64" && other == "int") {
+				typ = "float64"
+				continue
+			}
+			return "interface{}"
+		}
+		if len(scalars) < len(nodes) {
+			// 部分值为空，使用指针区分未设置
+			return "*" + typ
+		}
+		return typ
+	}
+	return "interface{}"
+}
+
+// uniqueName 返回未被使用的结构体类型名
+func (g *generator) uniqueName(name string) string {
+	unique := name
+	for n := 2; g.names[unique]; n++ {
+		unique = name + strconv.Itoa(n)
+	}
+	g.names[unique] = true
+	return unique
+}
+
+// scalarType 标量节点对应的Go类型
+func scalarType(node *yaml.Node) string {
+	switch node.ShortTag() {
+	case "!!int":
+		return "int"
+	case "!!float":
+		return "float64"
+	case "!!bool":
+		return "bool"
+	}
+	return "string"
+}
+
+// allEmpty 判断映射节点是否都没有键
+func allEmpty(nodes []*yaml.Node) bool {
+	for _, node := range nodes {
+		if len(node.Content) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveAlias 取出别名指向的节点
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+// nodeComment 获取键上方或行尾的注释，多行合并为一行
+func nodeComment(key, value *yaml.Node) string {
+	for _, comment := range []string{key.HeadComment, key.LineComment, value.LineComment} {
+		if text := commentText(comment); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+// commentText 去掉注释中的 "#" 前缀（以及helm-docs的 "-- " 前缀），多行合并为一行
+func commentText(comment string) string {
+	var parts []string
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#"))
+		line = strings.TrimSpace(strings.TrimPrefix(line, "-- "))
+		if line != "" {
+			parts = append(parts, line)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// fieldTag 生成字段标签；注释含逗号时改用 comment 标签，避免与yamlc标签的选项分隔符冲突
+func fieldTag(field fieldDef) string {
+	tag := "yaml:" + strconv.Quote(field.key)
+	if field.comment == "" {
+		return tag
+	}
+	comment := strings.ReplaceAll(field.comment, "`", "'")
+	if strings.Contains(comment, ",") {
+		return tag + " comment:" + strconv.Quote(comment)
+	}
+	return tag + " yamlc:" + strconv.Quote("comment="+comment)
+}
+
+// exportedName 将YAML键转换为导出的Go标识符，如 "max_conns" -> "MaxConns"
+func exportedName(key string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range key {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	name := b.String()
+	if name == "" {
+		return "Field"
+	}
+	if first := []rune(name)[0]; !unicode.IsUpper(first) {
+		// 以数字或无大小写的字符开头时加前缀，保证导出
+		name = "X" + name
+	}
+	return fixInitialisms(name)
+}
+
+// commonInitialisms Go命名中习惯全大写的缩写
+var commonInitialisms = []string{"Api", "Id", "Url", "Uri", "Http", "Https", "Ip", "Tls", "Dns", "Json", "Yaml", "Sql", "Ttl", "Cpu"}
+
+// fixInitialisms 将单词形式的常见缩写改为全大写，如 "ApiUrl" -> "APIURL"
+func fixInitialisms(name string) string {
+	initialisms := append([]string(nil), commonInitialisms...)
+	// 先替换较长的缩写，避免 "Http" 被 "Https" 的前缀截断
+	sort.Slice(initialisms, func(i, j int) bool { return len(initialisms[i]) > len(initialisms[j]) })
+
+	var b strings.Builder
+	for i := 0; i < len(name); {
+		matched := false
+		for _, word := range initialisms {
+			end := i + len(word)
+			if strings.HasPrefix(name[i:], word) && (end == len(name) || !unicode.IsLower(rune(name[end]))) {
+				b.WriteString(strings.ToUpper(word))
+				i = end
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			b.WriteByte(name[i])
+			i++
+		}
+	}
+	return b.String()
+}
+
+// singular 列表元素结构体的类型名，如 "Servers" -> "Server"
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 3:
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "ss"):
+		return name + "Item"
+	case strings.HasSuffix(name, "s") && len(name) > 1:
+		return strings.TrimSuffix(name, "s")
+	}
+	return name + "Item"
+}
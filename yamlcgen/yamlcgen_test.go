@@ -0,0 +1,94 @@
+package yamlcgen
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+const sampleYAML = `# 服务配置
+
+name: demo # 服务名称
+# 监听端口
+port: 8080
+debug: false
+ratio: 0.5
+# 上游服务器
+servers:
+  - host: a.example.com
+    # 权重, 越大越优先
+    weight: 1
+  - host: b.example.com
+    backup: true
+tags: [web, api]
+database:
+  # -- 连接地址
+  api_url: postgres://localhost
+  max-conns: 10
+labels: {}
+timeout: ~
+`
+
+func TestGenerate(t *testing.T) {
+	source, err := Generate([]byte(sampleYAML), "", "")
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	code := string(source)
+
+	expected := []string{
+		"// Code generated by yamlcgen. DO NOT EDIT.",
+		"package config",
+		"// Config 服务配置\ntype Config struct {",
+		"Name     string   `yaml:\"name\" yamlc:\"comment=服务名称\"`",
+		"Port     int      `yaml:\"port\" yamlc:\"comment=监听端口\"`",
+		"Debug    bool     `yaml:\"debug\"`",
+		"Ratio    float64  `yaml:\"ratio\"`",
+		"Servers  []Server `yaml:\"servers\" yamlc:\"comment=上游服务器\"`",
+		"Tags     []string `yaml:\"tags\"`",
+		"Database Database `yaml:\"database\"`",
+		"Labels   map[string]interface{} `yaml:\"labels\"`",
+		"Timeout  interface{} `yaml:\"timeout\"`",
+		"type Server struct {",
+		"Weight int    `yaml:\"weight\" comment:\"权重, 越大越优先\"`",
+		"Backup bool   `yaml:\"backup\"`",
+		"APIURL   string `yaml:\"api_url\" yamlc:\"comment=连接地址\"`",
+		"MaxConns int    `yaml:\"max-conns\"`",
+	}
+	normalized := strings.Join(strings.Fields(code), " ")
+	for _, want := range expected {
+		if !strings.Contains(normalized, strings.Join(strings.Fields(want), " ")) {
+			t.Errorf("expected %q in:\n%s", want, code)
+		}
+	}
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "config.go", source, parser.AllErrors); err != nil {
+		t.Errorf("generated code does not parse: %v\n%s", err, code)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	for _, input := range []string{"", "- a\n- b\n", "key: [unclosed\n"} {
+		if _, err := Generate([]byte(input), "config", "Config"); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+func TestExportedName(t *testing.T) {
+	cases := map[string]string{
+		"max_conns":   "MaxConns",
+		"log-level":   "LogLevel",
+		"http.port":   "HTTPPort",
+		"2fa":         "X2fa",
+		"user_id":     "UserID",
+		"identity":    "Identity",
+		"https_proxy": "HTTPSProxy",
+	}
+	for key, want := range cases {
+		if got := exportedName(key); got != want {
+			t.Errorf("exportedName(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
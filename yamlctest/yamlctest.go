@@ -0,0 +1,133 @@
+// Package yamlctest 提供测试配置结构体的辅助函数：往返测试、黄金文件比对和按风格的快照，
+// 供下游项目在自己的测试中使用。黄金文件可用 go test -update 重新生成
+package yamlctest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"binrc.com/pkg/yamlc"
+	"gopkg.in/yaml.v3"
+)
+
+// GoldenDir 黄金文件所在的目录，相对于测试运行时的工作目录（即被测包的目录）
+var GoldenDir = "testdata"
+
+// update 为true时 AssertGolden 重写黄金文件而不是比较
+var update = flag.Bool("update", false, "update yamlc golden files")
+
+// AssertRoundTrip 检查 v 生成的YAML有效，且解码回同一类型后与 v 相等
+// v 可以是结构体或其指针；带secret标签、被过滤或经过值转换的字段无法还原，应使用不含这类字段的类型测试
+func AssertRoundTrip(t testing.TB, v interface{}, opts ...yamlc.Option) {
+	t.Helper()
+	data, err := yamlc.Gen(v, opts...)
+	if err != nil {
+		t.Fatalf("yamlc.Gen failed: %v", err)
+		return
+	}
+	if err := yamlc.ValidateYAML(data); err != nil {
+		t.Fatalf("generated YAML is invalid: %v\n%s", err, data)
+		return
+	}
+
+	want := reflect.ValueOf(v)
+	for want.Kind() == reflect.Ptr {
+		if want.IsNil() {
+			t.Fatalf("value cannot be a nil pointer")
+			return
+		}
+		want = want.Elem()
+	}
+	got := reflect.New(want.Type())
+	if err := yaml.Unmarshal(data, got.Interface()); err != nil {
+		t.Fatalf("failed to unmarshal generated YAML into %s: %v\n%s", want.Type(), err, data)
+		return
+	}
+	if !reflect.DeepEqual(got.Elem().Interface(), want.Interface()) {
+		t.Errorf("round trip mismatch for %s:\ngot:  %+v\nwant: %+v\nYAML:\n%s", want.Type(), got.Elem().Interface(), want.Interface(), data)
+	}
+}
+
+// AssertGolden 比较 v 生成的YAML与黄金文件 GoldenDir/name.golden，使用 -update 时改为写入该文件
+func AssertGolden(t testing.TB, name string, v interface{}, opts ...yamlc.Option) {
+	t.Helper()
+	data, err := yamlc.Gen(v, opts...)
+	if err != nil {
+		t.Fatalf("yamlc.Gen failed: %v", err)
+		return
+	}
+	assertGoldenData(t, name, data)
+}
+
+// AssertGoldenStyles 对每种内置注释风格生成YAML，分别与黄金文件 GoldenDir/name.<风格名>.golden 比较，
+// 用于在结构体或标签改动后一次性检查所有风格的输出；opts 在风格之后应用
+func AssertGoldenStyles(t *testing.T, name string, v interface{}, opts ...yamlc.Option) {
+	t.Helper()
+	for _, style := range yamlc.GetAllStyle() {
+		styleName := yamlc.GetStyleString(int(style))
+		t.Run(styleName, func(t *testing.T) {
+			AssertGolden(t, name+"."+styleName, v, append([]yamlc.Option{yamlc.WithStyle(style)}, opts...)...)
+		})
+	}
+}
+
+// assertGoldenData 比较或更新黄金文件
+func assertGoldenData(t testing.TB, name string, data []byte) {
+	t.Helper()
+	path := GoldenPath(name)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create golden directory: %v", err)
+			return
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+		return
+	}
+	if !bytes.Equal(data, want) {
+		t.Errorf("output differs from %s (run with -update to accept):\n%s", path, lineDiff(want, data))
+	}
+}
+
+// GoldenPath 返回黄金文件的路径
+func GoldenPath(name string) string {
+	return filepath.Join(GoldenDir, name+".golden")
+}
+
+// lineDiff 列出不同的行，便于定位
+func lineDiff(want, got []byte) string {
+	wantLines := bytes.Split(want, []byte("\n"))
+	gotLines := bytes.Split(got, []byte("\n"))
+	var b bytes.Buffer
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g []byte
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if bytes.Equal(w, g) {
+			continue
+		}
+		if i < len(wantLines) {
+			fmt.Fprintf(&b, "%4d - %s\n", i+1, w)
+		}
+		if i < len(gotLines) {
+			fmt.Fprintf(&b, "%4d + %s\n", i+1, g)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,99 @@
+package yamlctest
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"binrc.com/pkg/yamlc"
+)
+
+type server struct {
+	Host string `yaml:"host" yamlc:"comment=主机"`
+	Port int    `yaml:"port" yamlc:"comment=端口"`
+}
+
+type appConfig struct {
+	Name    string   `yaml:"name"    yamlc:"comment=服务名称"`
+	Servers []server `yaml:"servers" yamlc:"comment=服务器列表"`
+}
+
+type secretConfig struct {
+	Name  string `yaml:"name"  yamlc:"comment=服务名称"`
+	Token string `yaml:"token" yamlc:"comment=令牌,secret"`
+}
+
+// recorder 记录失败信息而不让测试失败
+type recorder struct {
+	testing.TB
+	failures []string
+	fatal    bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+	r.fatal = true
+}
+
+func newAppConfig() *appConfig {
+	return &appConfig{Name: "demo", Servers: []server{{Host: "a", Port: 1}, {Host: "b", Port: 2}}}
+}
+
+func TestAssertRoundTrip(t *testing.T) {
+	for _, style := range yamlc.GetAllStyle() {
+		AssertRoundTrip(t, newAppConfig(), yamlc.WithStyle(style))
+	}
+	AssertRoundTrip(t, *newAppConfig())
+
+	// 敏感字段被屏蔽，无法还原
+	r := &recorder{}
+	AssertRoundTrip(r, &secretConfig{Name: "demo", Token: "t0ken"})
+	if len(r.failures) != 1 || !strings.Contains(r.failures[0], "round trip mismatch") {
+		t.Errorf("expected round trip mismatch, got %v", r.failures)
+	}
+
+	r = &recorder{}
+	AssertRoundTrip(r, (*appConfig)(nil))
+	if !r.fatal {
+		t.Error("expected fatal failure for nil pointer")
+	}
+}
+
+func TestAssertGolden(t *testing.T) {
+	defer func(dir string, updating bool) { GoldenDir, *update = dir, updating }(GoldenDir, *update)
+	GoldenDir = t.TempDir()
+	*update = false
+
+	r := &recorder{}
+	AssertGolden(r, "app", newAppConfig())
+	if !r.fatal || !strings.Contains(r.failures[0], "-update") {
+		t.Errorf("expected missing golden failure, got %v", r.failures)
+	}
+
+	*update = true
+	AssertGolden(t, "app", newAppConfig())
+	*update = false
+	if _, err := os.Stat(GoldenPath("app")); err != nil {
+		t.Fatalf("golden file not written: %v", err)
+	}
+	AssertGolden(t, "app", newAppConfig())
+
+	changed := newAppConfig()
+	changed.Name = "other"
+	r = &recorder{}
+	AssertGolden(r, "app", changed)
+	if len(r.failures) != 1 || !strings.Contains(r.failures[0], "+ name: other") {
+		t.Errorf("expected diff failure, got %v", r.failures)
+	}
+}
+
+func TestAssertGoldenStyles(t *testing.T) {
+	AssertGoldenStyles(t, "app", newAppConfig())
+}
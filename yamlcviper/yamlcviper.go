@@ -0,0 +1,114 @@
+// Package yamlcviper 连接yamlc与viper：把结构体的默认值注册到viper，
+// 并按viper中的当前设置生成带注释的配置模板，使模板与已注册的默认值保持一致
+//
+// 本包是独立的模块，只有导入本包的项目才会依赖viper
+package yamlcviper
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"binrc.com/pkg/yamlc"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// binding WriteDefaults 记录的结构体信息
+type binding struct {
+	typ  reflect.Type
+	tree map[string]interface{} // 结构体默认值的键树，保留键名原本的大小写
+}
+
+// bindings 每个viper实例对应的结构体，由 WriteDefaults 登记
+var bindings sync.Map // map[*viper.Viper]*binding
+
+// WriteDefaults 将结构体 v 的当前值作为默认值逐项注册到 vp，键名按 yaml 标签以 "." 连接，如 "database.port"
+// 同时登记结构体类型，之后 GenFromViper 生成的模板带有结构体上的注释
+func WriteDefaults(v interface{}, vp *viper.Viper) error {
+	if vp == nil {
+		return fmt.Errorf("viper instance cannot be nil")
+	}
+	typ := reflect.TypeOf(v)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return fmt.Errorf("value must be a struct or pointer to struct, got %T", v)
+	}
+
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal defaults: %w", err)
+	}
+	tree := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return fmt.Errorf("failed to decode defaults: %w", err)
+	}
+
+	walkLeaves(tree, "", func(key string, value interface{}) {
+		vp.SetDefault(key, value)
+	})
+	bindings.Store(vp, &binding{typ: typ, tree: tree})
+	return nil
+}
+
+// GenFromViper 按 vp 中的当前设置（默认值、配置文件、环境变量等合并后的结果）生成带注释的YAML
+// 已通过 WriteDefaults 登记结构体时，设置先解码到该结构体再生成，注释和键名的大小写取自结构体；
+// 否则直接输出 vp.AllSettings()，没有注释，且键名为viper统一转换后的小写
+func GenFromViper(vp *viper.Viper, opts ...yamlc.Option) ([]byte, error) {
+	if vp == nil {
+		return nil, fmt.Errorf("viper instance cannot be nil")
+	}
+	value, ok := bindings.Load(vp)
+	if !ok {
+		return yamlc.Gen(vp.AllSettings(), opts...)
+	}
+	b := value.(*binding)
+
+	settings := map[string]interface{}{}
+	walkLeaves(b.tree, "", func(key string, _ interface{}) {
+		setLeaf(settings, key, vp.Get(key))
+	})
+	data, err := yaml.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal settings: %w", err)
+	}
+	fresh := reflect.New(b.typ)
+	if err := yaml.Unmarshal(data, fresh.Interface()); err != nil {
+		return nil, fmt.Errorf("failed to decode settings into %s: %w", b.typ, err)
+	}
+	return yamlc.Gen(fresh.Interface(), opts...)
+}
+
+// walkLeaves 遍历键树的叶子，非空映射视为中间节点，其余值（包括列表和空映射）视为叶子
+func walkLeaves(tree map[string]interface{}, prefix string, fn func(key string, value interface{})) {
+	for key, value := range tree {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if child, ok := value.(map[string]interface{}); ok && len(child) > 0 {
+			walkLeaves(child, key, fn)
+			continue
+		}
+		fn(key, value)
+	}
+}
+
+// setLeaf 按 "." 分隔的键名在嵌套映射中设置值
+func setLeaf(tree map[string]interface{}, key string, value interface{}) {
+	for {
+		i := strings.IndexByte(key, '.')
+		if i < 0 {
+			tree[key] = value
+			return
+		}
+		child, ok := tree[key[:i]].(map[string]interface{})
+		if !ok {
+			child = map[string]interface{}{}
+			tree[key[:i]] = child
+		}
+		tree, key = child, key[i+1:]
+	}
+}
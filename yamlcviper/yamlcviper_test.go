@@ -0,0 +1,85 @@
+package yamlcviper
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type database struct {
+	Host string `yaml:"host" yamlc:"comment=数据库地址"`
+	Port int    `yaml:"port" yamlc:"comment=数据库端口"`
+}
+
+type appConfig struct {
+	Name     string        `yaml:"name"     yamlc:"comment=服务名称"`
+	Timeout  time.Duration `yaml:"timeout"  yamlc:"comment=请求超时"`
+	LogLevel string        `yaml:"logLevel" yamlc:"comment=日志级别"`
+	Tags     []string      `yaml:"tags"     yamlc:"comment=标签"`
+	Database database      `yaml:"database" yamlc:"comment=数据库配置"`
+}
+
+func TestWriteDefaults(t *testing.T) {
+	vp := viper.New()
+	cfg := &appConfig{Name: "demo", Timeout: 30 * time.Second, LogLevel: "info", Tags: []string{"web"}, Database: database{Host: "localhost", Port: 5432}}
+	if err := WriteDefaults(cfg, vp); err != nil {
+		t.Fatalf("WriteDefaults failed: %v", err)
+	}
+
+	if got := vp.GetString("name"); got != "demo" {
+		t.Errorf("name = %q", got)
+	}
+	if got := vp.GetInt("database.port"); got != 5432 {
+		t.Errorf("database.port = %d", got)
+	}
+	if got := vp.GetDuration("timeout"); got != 30*time.Second {
+		t.Errorf("timeout = %v", got)
+	}
+	if got := vp.GetString("logLevel"); got != "info" {
+		t.Errorf("logLevel = %q", got)
+	}
+
+	if err := WriteDefaults(cfg, nil); err == nil {
+		t.Error("expected error for nil viper")
+	}
+	if err := WriteDefaults("text", viper.New()); err == nil {
+		t.Error("expected error for non-struct value")
+	}
+}
+
+func TestGenFromViper(t *testing.T) {
+	vp := viper.New()
+	cfg := &appConfig{Name: "demo", Timeout: 30 * time.Second, LogLevel: "info", Database: database{Host: "localhost", Port: 5432}}
+	if err := WriteDefaults(cfg, vp); err != nil {
+		t.Fatalf("WriteDefaults failed: %v", err)
+	}
+	vp.Set("database.port", 6543)
+
+	data, err := GenFromViper(vp)
+	if err != nil {
+		t.Fatalf("GenFromViper failed: %v", err)
+	}
+	yamlStr := string(data)
+	for _, want := range []string{"name: demo", "logLevel: info", "port: 6543", "# 数据库端口", "# 日志级别"} {
+		if !strings.Contains(yamlStr, want) {
+			t.Errorf("expected %q in output:\n%s", want, yamlStr)
+		}
+	}
+
+	// 未登记结构体时直接输出viper的设置
+	plain := viper.New()
+	plain.SetDefault("server.port", 80)
+	data, err = GenFromViper(plain)
+	if err != nil {
+		t.Fatalf("GenFromViper failed: %v", err)
+	}
+	if !strings.Contains(string(data), "port: 80") {
+		t.Errorf("expected plain settings:\n%s", data)
+	}
+
+	if _, err := GenFromViper(nil); err == nil {
+		t.Error("expected error for nil viper")
+	}
+}